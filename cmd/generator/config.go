@@ -0,0 +1,359 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config 模块定义
+type Config struct {
+	Name    string   `yaml:"name"`    // 模块名称(大驼峰，例如：Product)
+	Comment string   `yaml:"comment"` // 模块说明(例如：商品)
+	Table   string   `yaml:"table"`   // 表名(不含前缀，默认为模块名称的蛇形命名)
+	Router  string   `yaml:"router"`  // 路由名称(默认为表名的复数形式)
+	Status  bool     `yaml:"status"`  // 是否包含状态字段(1:启用 2:停用)及启用/停用接口
+	Menu    Menu     `yaml:"menu"`    // 菜单初始化数据
+	Fields  []*Field `yaml:"fields"`  // 字段列表
+
+	Migration int64 `yaml:"-"` // 数据库迁移版本号(生成时根据已有的迁移文件确定)
+}
+
+// Menu 菜单初始化数据
+type Menu struct {
+	Name     string `yaml:"name"`     // 菜单名称(默认为模块说明)
+	Icon     string `yaml:"icon"`     // 菜单图标
+	Router   string `yaml:"router"`   // 前端访问路由
+	Sequence int    `yaml:"sequence"` // 排序值
+}
+
+// Field 字段定义
+type Field struct {
+	Name     string   `yaml:"name"`     // 字段名称(大驼峰)
+	Type     string   `yaml:"type"`     // 字段类型(支持：string/int/int64/float64)
+	Comment  string   `yaml:"comment"`  // 字段说明
+	Size     int      `yaml:"size"`     // 字段长度(仅string有效)
+	Required bool     `yaml:"required"` // 是否必填
+	Binding  string   `yaml:"binding"`  // 自定义校验规则(覆盖required)
+	Index    bool     `yaml:"index"`    // 是否创建索引
+	Unique   bool     `yaml:"unique"`   // 是否唯一(创建和更新时检查)
+	Query    []string `yaml:"query"`    // 查询方式(支持：eq/like)
+}
+
+var fieldTypes = map[string]bool{
+	"string":  true,
+	"int":     true,
+	"int64":   true,
+	"float64": true,
+}
+
+// LoadConfig 加载模块定义
+func LoadConfig(fpath string) (*Config, error) {
+	buf, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Config
+	err = yaml.Unmarshal(buf, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.fillAndCheck()
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Config) fillAndCheck() error {
+	if c.Name == "" || !unicode.IsUpper([]rune(c.Name)[0]) {
+		return errors.New("模块名称不能为空，并且必须以大写字母开头")
+	}
+	if c.Comment == "" {
+		c.Comment = c.Name
+	}
+	if c.Table == "" {
+		c.Table = toSnake(c.Name)
+	}
+	if c.Router == "" {
+		c.Router = toPlural(c.Table)
+	}
+	if c.Menu.Name == "" {
+		c.Menu.Name = c.Comment
+	}
+	if c.Menu.Router == "" {
+		c.Menu.Router = "/" + strings.Replace(c.Router, "_", "-", -1)
+	}
+	if len(c.Fields) == 0 {
+		return errors.New("字段列表不能为空")
+	}
+
+	var uniques int
+	for _, f := range c.Fields {
+		if f.Name == "" || !unicode.IsUpper([]rune(f.Name)[0]) {
+			return fmt.Errorf("字段名称[%s]必须以大写字母开头", f.Name)
+		}
+		switch f.Name {
+		case "RecordID", "Creator", "Version", "CreatedAt", "Status":
+			return fmt.Errorf("字段名称[%s]为保留字段", f.Name)
+		}
+		if f.Type == "" {
+			f.Type = "string"
+		}
+		if !fieldTypes[f.Type] {
+			return fmt.Errorf("字段[%s]的类型[%s]不被支持", f.Name, f.Type)
+		}
+		if f.Comment == "" {
+			f.Comment = f.Name
+		}
+		if f.Type == "string" && f.Size == 0 {
+			f.Size = 100
+		}
+		for _, q := range f.Query {
+			if q != "eq" && q != "like" {
+				return fmt.Errorf("字段[%s]的查询方式[%s]不被支持", f.Name, q)
+			} else if q == "like" && f.Type != "string" {
+				return fmt.Errorf("字段[%s]不是字符串类型，不支持模糊查询", f.Name)
+			}
+		}
+		if f.Unique {
+			uniques++
+			f.Index = true
+			if !f.HasQuery("eq") {
+				f.Query = append(f.Query, "eq")
+			}
+		}
+	}
+	if uniques > 1 {
+		return errors.New("最多只允许一个唯一字段")
+	}
+
+	if c.Status {
+		c.Fields = append(c.Fields, &Field{
+			Name:    "Status",
+			Type:    "int",
+			Comment: "状态(1:启用 2:停用)",
+			Binding: "required,max=2,min=1",
+			Index:   true,
+			Query:   []string{"eq"},
+		})
+	}
+	return nil
+}
+
+// Snake 模块名称的蛇形命名
+func (c *Config) Snake() string {
+	return toSnake(c.Name)
+}
+
+// Var 模块名称的小驼峰命名
+func (c *Config) Var() string {
+	return toLowerCamel(c.Name)
+}
+
+// UniqueField 获取唯一字段
+func (c *Config) UniqueField() *Field {
+	for _, f := range c.Fields {
+		if f.Unique {
+			return f
+		}
+	}
+	return nil
+}
+
+// Column 数据库列名
+func (f *Field) Column() string {
+	return toSnake(f.Name)
+}
+
+// JSON json字段名
+func (f *Field) JSON() string {
+	return toSnake(f.Name)
+}
+
+// HasQuery 检查是否支持指定的查询方式
+func (f *Field) HasQuery(q string) bool {
+	for _, v := range f.Query {
+		if v == q {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPQuery 获取HTTP查询参数对应的查询方式(优先使用模糊查询)
+func (f *Field) HTTPQuery() string {
+	if f.HasQuery("like") {
+		return "like"
+	} else if f.HasQuery("eq") {
+		return "eq"
+	}
+	return ""
+}
+
+// SwaggoType swaggo参数类型
+func (f *Field) SwaggoType() string {
+	switch f.Type {
+	case "int", "int64":
+		return "int"
+	case "float64":
+		return "number"
+	}
+	return "string"
+}
+
+// SchemaTag schema对象的结构体标签
+func (f *Field) SchemaTag() string {
+	var tags []string
+	tags = append(tags, fmt.Sprintf(`json:"%s"`, f.JSON()))
+
+	binding := f.Binding
+	if binding == "" && f.Required {
+		binding = "required"
+	}
+	if binding != "" {
+		tags = append(tags, fmt.Sprintf(`binding:"%s"`, binding))
+	}
+
+	required := f.Required || f.Binding != ""
+	tags = append(tags, fmt.Sprintf(`swaggo:"%t,%s"`, required, f.Comment))
+	return "`" + strings.Join(tags, " ") + "`"
+}
+
+// GormTag 实体的结构体标签
+func (f *Field) GormTag() string {
+	tag := fmt.Sprintf("column:%s;", f.Column())
+	if f.Type == "string" {
+		tag += fmt.Sprintf("size:%d;", f.Size)
+	}
+	if f.Index {
+		tag += "index;"
+	}
+	return fmt.Sprintf("`gorm:\"%s\"`", tag)
+}
+
+// SQLType 字段在迁移脚本中的数据类型(与gorm自动映射的类型一致)
+func (f *Field) SQLType(dialect string) string {
+	switch f.Type {
+	case "string":
+		return fmt.Sprintf("varchar(%d)", f.Size)
+	case "int":
+		if dialect == "mysql" {
+			return "int"
+		}
+		return "integer"
+	case "int64":
+		return "bigint"
+	case "float64":
+		switch dialect {
+		case "mysql":
+			return "double"
+		case "postgres":
+			return "numeric"
+		}
+		return "real"
+	}
+	return ""
+}
+
+// toSnake 转换为蛇形命名(RecordID => record_id)
+func toSnake(s string) string {
+	rs := []rune(s)
+	var buf []rune
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) ||
+				(i+1 < len(rs) && unicode.IsLower(rs[i+1]) && unicode.IsUpper(rs[i-1]))) {
+				buf = append(buf, '_')
+			}
+			buf = append(buf, unicode.ToLower(r))
+			continue
+		}
+		buf = append(buf, r)
+	}
+	return string(buf)
+}
+
+// toLowerCamel 转换为小驼峰命名(RecordID => recordID)
+func toLowerCamel(s string) string {
+	rs := []rune(s)
+	for i := range rs {
+		if !unicode.IsUpper(rs[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+			break
+		}
+		rs[i] = unicode.ToLower(rs[i])
+	}
+	return string(rs)
+}
+
+// toPlural 转换为复数形式
+func toPlural(s string) string {
+	switch {
+	case strings.HasSuffix(s, "y") && !strings.HasSuffix(s, "ay") && !strings.HasSuffix(s, "ey") && !strings.HasSuffix(s, "oy"):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	}
+	return s + "s"
+}
+
+// Var 字段名称的小驼峰命名(用作变量名)
+func (f *Field) Var() string {
+	v := toLowerCamel(f.Name)
+	if token.Lookup(v).IsKeyword() {
+		v += "Value"
+	}
+	return v
+}
+
+// ZeroValue 字段类型的零值
+func (f *Field) ZeroValue() string {
+	if f.Type == "string" {
+		return `""`
+	}
+	return "0"
+}
+
+// TestValue 测试用例中的字段值
+func (f *Field) TestValue() string {
+	switch f.Type {
+	case "string":
+		return `"test"`
+	case "float64":
+		return "1.5"
+	}
+	return "1"
+}
+
+// ParseURLParam 解析URL查询参数的表达式
+func (f *Field) ParseURLParam() string {
+	switch f.Type {
+	case "int":
+		return fmt.Sprintf(`util.S(c.URLParam("%s")).DefaultInt(0)`, f.JSON())
+	case "int64":
+		return fmt.Sprintf(`util.S(c.URLParam("%s")).DefaultInt64(0)`, f.JSON())
+	case "float64":
+		return fmt.Sprintf(`util.S(c.URLParam("%s")).DefaultFloat64(0)`, f.JSON())
+	}
+	return fmt.Sprintf(`c.URLParam("%s")`, f.JSON())
+}
+
+// NeedUtil 控制器中是否需要引入util包
+func (c *Config) NeedUtil() bool {
+	for _, f := range c.Fields {
+		if f.HTTPQuery() == "eq" && f.Type != "string" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// generatedHeader 生成文件的标记，只有包含该标记的文件才允许被重新生成覆盖
+const generatedHeader = "// Code generated by iris-admin generator. DO NOT EDIT."
+
+// generatedSQLHeader SQL生成文件的标记
+const generatedSQLHeader = "-- Code generated by iris-admin generator. DO NOT EDIT."
+
+// 模板中反引号的替代字符
+const backquote = "‵"
+
+// genFile 生成文件项
+type genFile struct {
+	Path     string // 相对于项目根目录的路径
+	Template string // 模板内容
+}
+
+// 数据库迁移文件目录(按数据库类型划分子目录)
+const migrationDir = "internal/app/model/impl/gorm/internal/migration/sql"
+
+// 数据库迁移文件名(版本号_名称.up.sql)
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.up\.sql$`)
+
+// insertion 插入代码项
+type insertion struct {
+	Path     string // 相对于项目根目录的路径
+	Marker   string // 插入位置的标记(插入到标记所在行之前)
+	Template string // 模板内容
+}
+
+func genFiles(c *Config) []genFile {
+	name := c.Snake()
+	return []genFile{
+		{"internal/app/schema/s_" + name + ".go", tplSchema},
+		{"internal/app/model/m_" + name + ".go", tplModel},
+		{"internal/app/model/impl/gorm/internal/entity/e_" + name + ".go", tplEntity},
+		{"internal/app/model/impl/gorm/internal/entity/e_" + name + "_test.go", tplEntityTest},
+		{"internal/app/model/impl/gorm/internal/model/m_" + name + ".go", tplGormModel},
+		{"internal/app/bll/b_" + name + ".go", tplBll},
+		{"internal/app/bll/impl/internal/b_" + name + ".go", tplBllImpl},
+		{"internal/app/routers/api/ctl/c_" + name + ".go", tplCtl},
+		{"internal/app/data_" + name + ".go", tplMenuData},
+		{migrationFile(c, "mysql", "up"), tplMigrationMySQLUp},
+		{migrationFile(c, "mysql", "down"), tplMigrationMySQLDown},
+		{migrationFile(c, "postgres", "up"), tplMigrationPostgresUp},
+		{migrationFile(c, "postgres", "down"), tplMigrationPostgresDown},
+		{migrationFile(c, "sqlite3", "up"), tplMigrationSqlite3Up},
+		{migrationFile(c, "sqlite3", "down"), tplMigrationSqlite3Down},
+	}
+}
+
+func migrationFile(c *Config, dialect, direction string) string {
+	return fmt.Sprintf("%s/%s/%04d_create_%s.%s.sql", migrationDir, dialect, c.Migration, c.Table, direction)
+}
+
+func isSQLFile(fpath string) bool {
+	return strings.HasSuffix(fpath, ".sql")
+}
+
+func insertions() []insertion {
+	return []insertion{
+		{"internal/app/model/impl/gorm/gorm.go", "// generator:migrate", "\t\tnew(entity.{{.Name}}),\n"},
+		{"internal/app/model/impl/gorm/gorm.go", "// generator:inject", "\tcontainer.Provide(imodel.New{{.Name}}, dig.As(new(model.I{{.Name}})))\n"},
+		{"internal/app/bll/impl/impl.go", "// generator:inject", "\tcontainer.Provide(internal.New{{.Name}}, dig.As(new(bll.I{{.Name}})))\n"},
+		{"internal/app/routers/api/ctl/ctl.go", "// generator:inject", "\tcontainer.Provide(New{{.Name}})\n"},
+		{"internal/app/routers/api/api.go", "// generator:ctl", "\t\tc{{.Name}} *ctl.{{.Name}},\n"},
+		{"internal/app/routers/api/api.go", "// generator:router", strings.TrimPrefix(tplRouter, "\n") + "\n"},
+	}
+}
+
+// Generator 代码生成器
+type Generator struct {
+	Root   string // 项目根目录
+	Force  bool   // 强制覆盖未标记为生成的文件
+	DryRun bool   // 仅输出将要执行的操作
+}
+
+func (g *Generator) render(text string, c *Config) ([]byte, error) {
+	text = strings.Replace(text, backquote, "`", -1)
+	tpl, err := template.New("").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, c)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Render 渲染生成文件的内容(包含生成标记，go文件进行格式化)
+func (g *Generator) Render(f genFile, c *Config) ([]byte, error) {
+	buf, err := g.render(f.Template, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if isSQLFile(f.Path) {
+		buf = bytes.TrimLeft(buf, "\n")
+		return append([]byte(generatedSQLHeader+"\n"), buf...), nil
+	}
+
+	buf = append([]byte(generatedHeader+"\n"), buf...)
+	return format.Source(buf)
+}
+
+// 检查文件是否允许被覆盖
+func (g *Generator) canOverwrite(fpath string) (bool, error) {
+	buf, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	header := generatedHeader
+	if isSQLFile(fpath) {
+		header = generatedSQLHeader
+	}
+	return g.Force || bytes.HasPrefix(buf, []byte(header)), nil
+}
+
+// 获取模块的数据库迁移版本号(已经生成过迁移文件时使用原有的版本号，否则使用最大版本号加1)
+func (g *Generator) migrationVersion(c *Config) (int64, error) {
+	files, err := ioutil.ReadDir(filepath.Join(g.Root, filepath.FromSlash(migrationDir), "mysql"))
+	if err != nil {
+		return 0, err
+	}
+
+	var max int64
+	for _, f := range files {
+		m := migrationFileRegexp.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, err
+		} else if m[2] == "create_"+c.Table {
+			return version, nil
+		} else if version > max {
+			max = version
+		}
+	}
+	return max + 1, nil
+}
+
+// Generate 执行代码生成
+func (g *Generator) Generate(c *Config) error {
+	version, err := g.migrationVersion(c)
+	if err != nil {
+		return err
+	}
+	c.Migration = version
+
+	for _, f := range genFiles(c) {
+		buf, err := g.Render(f, c)
+		if err != nil {
+			return fmt.Errorf("生成文件[%s]发生错误：%s", f.Path, err.Error())
+		}
+
+		fpath := filepath.Join(g.Root, filepath.FromSlash(f.Path))
+		ok, err := g.canOverwrite(fpath)
+		if err != nil {
+			return err
+		} else if !ok {
+			fmt.Printf("跳过文件(未包含生成标记)：%s\n", f.Path)
+			continue
+		}
+
+		fmt.Printf("生成文件：%s\n", f.Path)
+		if g.DryRun {
+			continue
+		}
+		err = ioutil.WriteFile(fpath, buf, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ins := range insertions() {
+		err := g.insert(ins, c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 在标记位置插入代码(如果已经存在则忽略)
+func (g *Generator) insert(ins insertion, c *Config) error {
+	code, err := g.render(ins.Template, c)
+	if err != nil {
+		return err
+	}
+
+	fpath := filepath.Join(g.Root, filepath.FromSlash(ins.Path))
+	buf, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return err
+	}
+
+	first := bytes.TrimSpace(bytes.SplitN(bytes.TrimSpace(code), []byte("\n"), 2)[0])
+	if bytes.Contains(buf, first) {
+		return nil
+	}
+
+	idx := bytes.Index(buf, []byte(ins.Marker))
+	if idx == -1 {
+		return fmt.Errorf("文件[%s]中未找到标记[%s]", ins.Path, ins.Marker)
+	}
+	// 定位到标记所在行的行首
+	idx = bytes.LastIndexByte(buf[:idx], '\n') + 1
+
+	var nbuf bytes.Buffer
+	nbuf.Write(buf[:idx])
+	nbuf.Write(code)
+	nbuf.Write(buf[idx:])
+
+	fmt.Printf("更新文件：%s\n", ins.Path)
+	if g.DryRun {
+		return nil
+	}
+	return ioutil.WriteFile(fpath, nbuf.Bytes(), 0644)
+}
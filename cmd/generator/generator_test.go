@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wanhello/iris-admin/pkg/migrate"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	c, err := LoadConfig("testdata/product.yaml")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "product", c.Table)
+	assert.Equal(t, "products", c.Router)
+	assert.Equal(t, "Status", c.Fields[len(c.Fields)-1].Name)
+
+	g := new(Generator)
+	for _, f := range genFiles(c) {
+		buf, err := g.Render(f, c)
+		if assert.Nil(t, err, f.Path) && isSQLFile(f.Path) {
+			assert.True(t, bytes.HasPrefix(buf, []byte(generatedSQLHeader+"\n")), f.Path)
+		}
+	}
+}
+
+// 复制项目到临时目录(不包含.git)
+func copyProject(t *testing.T, src string) string {
+	dst := t.TempDir()
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		} else if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dst, rel), buf, info.Mode())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+// 生成代码到项目副本，检查插入位置并编译生成后的项目
+func TestGenerateProject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping project generation in short mode")
+	}
+
+	c, err := LoadConfig("testdata/product.yaml")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	root := copyProject(t, filepath.Join("..", ".."))
+	g := &Generator{Root: root}
+	if !assert.Nil(t, g.Generate(c)) {
+		return
+	}
+	// 重复生成不重复插入代码
+	if !assert.Nil(t, g.Generate(c)) {
+		return
+	}
+
+	fset := token.NewFileSet()
+	for _, f := range genFiles(c) {
+		if isSQLFile(f.Path) {
+			continue
+		}
+		_, err := parser.ParseFile(fset, filepath.Join(root, filepath.FromSlash(f.Path)), nil, parser.AllErrors)
+		assert.Nil(t, err, f.Path)
+	}
+
+	// 生成的迁移文件可以被加载
+	for _, dialect := range []string{"mysql", "postgres", "sqlite3"} {
+		items, err := migrate.Load(os.DirFS(filepath.Join(root, filepath.FromSlash(migrationDir), dialect)))
+		if !assert.Nil(t, err, dialect) || !assert.NotEmpty(t, items, dialect) {
+			continue
+		}
+		last := items[len(items)-1]
+		assert.Equal(t, c.Migration, last.Version, dialect)
+		assert.Equal(t, "create_"+c.Table, last.Name, dialect)
+		assert.Contains(t, last.Down, "DROP TABLE", dialect)
+	}
+
+	for _, ins := range insertions() {
+		code, err := g.render(ins.Template, c)
+		if !assert.Nil(t, err, ins.Path) {
+			continue
+		}
+
+		fpath := filepath.Join(root, filepath.FromSlash(ins.Path))
+		buf, err := ioutil.ReadFile(fpath)
+		if !assert.Nil(t, err, ins.Path) {
+			continue
+		}
+		_, err = parser.ParseFile(fset, fpath, buf, parser.AllErrors)
+		assert.Nil(t, err, ins.Path)
+
+		assert.Equal(t, 1, bytes.Count(buf, code), "%s: %s", ins.Path, ins.Marker)
+		idx := bytes.Index(buf, []byte(ins.Marker))
+		if !assert.True(t, idx != -1, "%s: %s", ins.Path, ins.Marker) {
+			continue
+		}
+		idx = bytes.LastIndexByte(buf[:idx], '\n') + 1
+		assert.True(t, bytes.HasSuffix(buf[:idx], code), "%s: code not inserted before %s", ins.Path, ins.Marker)
+	}
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	for _, args := range [][]string{
+		{"build", "./..."},
+		{"vet", "./internal/app/..."},
+	} {
+		cmd := exec.Command(gobin, args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		assert.Nil(t, err, "go %s:\n%s", strings.Join(args, " "), out)
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "record_id", toSnake("RecordID"))
+	assert.Equal(t, "http_status", toSnake("HTTPStatus"))
+	assert.Equal(t, "recordID", toLowerCamel("RecordID"))
+	assert.Equal(t, "httpStatus", toLowerCamel("HTTPStatus"))
+	assert.Equal(t, "categories", toPlural("category"))
+	assert.Equal(t, "boxes", toPlural("box"))
+}
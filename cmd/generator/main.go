@@ -0,0 +1,59 @@
+/*
+Command generator 根据模块定义文件(YAML)生成业务模块的各层代码
+
+生成内容包括：schema、model接口、gorm实体及存储实现、bll接口及实现、控制器(包含swaggo注释)、
+菜单初始化数据、数据库迁移文件和实体测试，同时在依赖注入、数据表映射以及路由注册的标记位置追加代码。
+
+使用方式：
+
+	go run ./cmd/generator -f ./cmd/generator/testdata/product.yaml
+
+重新生成时只会覆盖首行包含生成标记的文件，如果需要手动维护某个文件，删除该文件首行的标记即可。
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+var (
+	defFile string
+	rootDir string
+	force   bool
+	dryRun  bool
+)
+
+func init() {
+	flag.StringVar(&defFile, "f", "", "module definition file(.yaml)")
+	flag.StringVar(&rootDir, "d", ".", "project root directory")
+	flag.BoolVar(&force, "force", false, "overwrite files without the generated header")
+	flag.BoolVar(&dryRun, "dry", false, "print the actions without writing files")
+}
+
+func main() {
+	flag.Parse()
+
+	if defFile == "" {
+		fmt.Fprintln(os.Stderr, "请使用-f指定模块定义文件")
+		os.Exit(1)
+	}
+
+	c, err := LoadConfig(defFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	g := &Generator{
+		Root:   rootDir,
+		Force:  force,
+		DryRun: dryRun,
+	}
+	err = g.Generate(c)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
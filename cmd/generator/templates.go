@@ -0,0 +1,906 @@
+package main
+
+// 模板中的反引号使用"‵"代替，解析模板前统一替换
+
+const tplSchema = `
+package schema
+
+import "time"
+
+// {{.Name}} {{.Comment}}对象
+type {{.Name}} struct {
+	RecordID string ‵json:"record_id" swaggo:"false,记录ID"‵
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.SchemaTag}}
+{{- end}}
+	Creator   string    ‵json:"creator" swaggo:"false,创建者"‵
+	Version   int       ‵json:"version" swaggo:"false,版本号"‵
+	CreatedAt time.Time ‵json:"created_at" swaggo:"false,创建时间"‵
+}
+
+// {{.Name}}QueryParam 查询条件
+type {{.Name}}QueryParam struct {
+{{- range .Fields}}
+{{- if .HasQuery "eq"}}
+	{{.Name}} {{.Type}} // {{.Comment}}
+{{- end}}
+{{- if .HasQuery "like"}}
+	Like{{.Name}} string // {{.Comment}}(模糊查询)
+{{- end}}
+{{- end}}
+}
+
+// {{.Name}}QueryOptions {{.Comment}}对象查询可选参数项
+type {{.Name}}QueryOptions struct {
+	PageParam   *PaginationParam // 分页参数
+	CursorParam *CursorParam     // 游标分页参数(指定时忽略分页参数)
+	QuerySpec   *QuerySpec       // 查询规格(排序、字段选择及过滤条件)
+}
+
+// {{.Name}}QueryResult {{.Comment}}对象查询结果
+type {{.Name}}QueryResult struct {
+	Data       []*{{.Name}}
+	PageResult *PaginationResult
+}
+`
+
+const tplModel = `
+package model
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// I{{.Name}} {{.Comment}}存储接口
+type I{{.Name}} interface {
+	// 查询数据
+	Query(ctx context.Context, params schema.{{.Name}}QueryParam, opts ...schema.{{.Name}}QueryOptions) (*schema.{{.Name}}QueryResult, error)
+	// 查询指定数据
+	Get(ctx context.Context, recordID string, opts ...schema.{{.Name}}QueryOptions) (*schema.{{.Name}}, error)
+	// 创建数据
+	Create(ctx context.Context, item schema.{{.Name}}) error
+	// 更新数据
+	Update(ctx context.Context, recordID string, item schema.{{.Name}}) error
+	// 删除数据
+	Delete(ctx context.Context, recordID string) error
+{{- if .Status}}
+	// 更新状态
+	UpdateStatus(ctx context.Context, recordID string, status int) error
+{{- end}}
+}
+`
+
+const tplEntity = `
+package entity
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+)
+
+// Get{{.Name}}DB 获取{{.Comment}}存储
+func Get{{.Name}}DB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getDBWithModel(ctx, defDB, {{.Name}}{})
+}
+
+// Get{{.Name}}ReadDB 获取{{.Comment}}查询存储(读写分离时使用从库)
+func Get{{.Name}}ReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, {{.Name}}{})
+}
+
+// Schema{{.Name}} {{.Comment}}对象
+type Schema{{.Name}} schema.{{.Name}}
+
+// To{{.Name}} 转换为{{.Comment}}实体
+func (a Schema{{.Name}}) To{{.Name}}() *{{.Name}} {
+	item := &{{.Name}}{
+		RecordID: a.RecordID,
+{{- range .Fields}}
+		{{.Name}}: &a.{{.Name}},
+{{- end}}
+		Creator: &a.Creator,
+		Version: &a.Version,
+	}
+	return item
+}
+
+// {{.Name}} {{.Comment}}实体
+type {{.Name}} struct {
+	Model
+	RecordID string ‵gorm:"column:record_id;size:36;index;"‵ // 记录内码
+{{- range .Fields}}
+	{{.Name}} *{{.Type}} {{.GormTag}} // {{.Comment}}
+{{- end}}
+	Creator *string ‵gorm:"column:creator;size:36;"‵ // 创建者
+	Version *int    ‵gorm:"column:version;default:1;"‵ // 版本号(每次更新递增)
+}
+
+func (a {{.Name}}) String() string {
+	return toString(a)
+}
+
+// TableName 表名
+func (a {{.Name}}) TableName() string {
+	return a.Model.TableName("{{.Table}}")
+}
+
+// ToSchema{{.Name}} 转换为{{.Comment}}对象
+func (a {{.Name}}) ToSchema{{.Name}}() *schema.{{.Name}} {
+	item := &schema.{{.Name}}{
+		RecordID: a.RecordID,
+{{- range .Fields}}
+		{{.Name}}: *a.{{.Name}},
+{{- end}}
+		Creator:   *a.Creator,
+		Version:   *a.Version,
+		CreatedAt: a.CreatedAt,
+	}
+	return item
+}
+
+// {{.Name}}s {{.Comment}}列表
+type {{.Name}}s []*{{.Name}}
+
+// ToSchema{{.Name}}s 转换为{{.Comment}}对象列表
+func (a {{.Name}}s) ToSchema{{.Name}}s() []*schema.{{.Name}} {
+	list := make([]*schema.{{.Name}}, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchema{{.Name}}()
+	}
+	return list
+}
+`
+
+const tplEntityTest = `
+package entity
+
+import (
+	"testing"
+
+	"github.com/wanhello/iris-admin/internal/app/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test{{.Name}}Convert(t *testing.T) {
+	item := schema.{{.Name}}{
+		RecordID: "test",
+{{- range .Fields}}
+		{{.Name}}: {{.TestValue}},
+{{- end}}
+		Creator: "root",
+		Version: 1,
+	}
+
+	eitem := Schema{{.Name}}(item).To{{.Name}}()
+	assert.Equal(t, item.RecordID, eitem.RecordID)
+
+	sitem := eitem.ToSchema{{.Name}}()
+	assert.Equal(t, item, *sitem)
+}
+`
+
+const tplGormModel = `
+package model
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+{{- if .Status}}
+
+	"github.com/jinzhu/gorm"
+{{- end}}
+)
+
+// New{{.Name}} 创建{{.Comment}}存储实例
+func New{{.Name}}(db *gormplus.DB) *{{.Name}} {
+	return &{{.Name}}{db}
+}
+
+// {{.Name}} {{.Comment}}存储
+type {{.Name}} struct {
+	db *gormplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的{{.Comment}}字段
+var {{.Var}}QueryColumns = QueryColumns{
+	"record_id": "record_id",
+{{- range .Fields}}
+	"{{.JSON}}": "{{.Column}}",
+{{- end}}
+	"creator":    "creator",
+	"version":    "version",
+	"created_at": "created_at",
+}
+
+func (a *{{.Name}}) getQueryOption(opts ...schema.{{.Name}}QueryOptions) schema.{{.Name}}QueryOptions {
+	var opt schema.{{.Name}}QueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *{{.Name}}) Query(ctx context.Context, params schema.{{.Name}}QueryParam, opts ...schema.{{.Name}}QueryOptions) (*schema.{{.Name}}QueryResult, error) {
+	db := entity.Get{{.Name}}ReadDB(ctx, a.db).DB
+{{- range .Fields}}
+{{- if .HasQuery "eq"}}
+	if v := params.{{.Name}}; v != {{.ZeroValue}} {
+		db = db.Where("{{.Column}}=?", v)
+	}
+{{- end}}
+{{- if .HasQuery "like"}}
+	if v := params.Like{{.Name}}; v != "" {
+		db = db.Where(likeQuery("{{.Column}}"), likeContains(v))
+	}
+{{- end}}
+{{- end}}
+	opt := a.getQueryOption(opts...)
+	db, err := WrapQuerySpec(db, opt.QuerySpec, {{.Var}}QueryColumns, "id DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	var list entity.{{.Name}}s
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(db, cp, opt.QuerySpec, {{.Var}}QueryColumns, CursorKey{Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(db, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	qr := &schema.{{.Name}}QueryResult{
+		PageResult: pr,
+		Data:       list.ToSchema{{.Name}}s(),
+	}
+
+	return qr, nil
+}
+
+// Get 查询指定数据
+func (a *{{.Name}}) Get(ctx context.Context, recordID string, opts ...schema.{{.Name}}QueryOptions) (*schema.{{.Name}}, error) {
+	db := entity.Get{{.Name}}ReadDB(ctx, a.db).Where("record_id=?", recordID)
+	var item entity.{{.Name}}
+	ok, err := a.db.FindOne(db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchema{{.Name}}(), nil
+}
+
+// Create 创建数据
+func (a *{{.Name}}) Create(ctx context.Context, item schema.{{.Name}}) error {
+	item.Version = 1
+	{{.Var}} := entity.Schema{{.Name}}(item).To{{.Name}}()
+	result := entity.Get{{.Name}}DB(ctx, a.db).Create({{.Var}})
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *{{.Name}}) Update(ctx context.Context, recordID string, item schema.{{.Name}}) error {
+	sitem := entity.Schema{{.Name}}(item)
+	sitem.Version = item.Version + 1
+	result := entity.Get{{.Name}}DB(ctx, a.db).Where("record_id=? AND version=?", recordID, item.Version).Omit("record_id", "creator").Updates(sitem.To{{.Name}}())
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	} else if result.RowsAffected == 0 {
+		return errors.ErrResourceConflict
+	}
+	return nil
+}
+
+// Delete 删除数据
+func (a *{{.Name}}) Delete(ctx context.Context, recordID string) error {
+	result := entity.Get{{.Name}}DB(ctx, a.db).Where("record_id=?", recordID).Delete(entity.{{.Name}}{})
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+{{- if .Status}}
+
+// UpdateStatus 更新状态
+func (a *{{.Name}}) UpdateStatus(ctx context.Context, recordID string, status int) error {
+	result := entity.Get{{.Name}}DB(ctx, a.db).Where("record_id=?", recordID).Updates(map[string]interface{}{
+		"status":  status,
+		"version": gorm.Expr("version+1"),
+	})
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+{{- end}}
+`
+
+const tplBll = `
+package bll
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// I{{.Name}} {{.Comment}}业务逻辑接口
+type I{{.Name}} interface {
+	// 查询数据
+	Query(ctx context.Context, params schema.{{.Name}}QueryParam, opts ...schema.{{.Name}}QueryOptions) (*schema.{{.Name}}QueryResult, error)
+	// 查询指定数据
+	Get(ctx context.Context, recordID string, opts ...schema.{{.Name}}QueryOptions) (*schema.{{.Name}}, error)
+	// 创建数据
+	Create(ctx context.Context, item schema.{{.Name}}) (*schema.{{.Name}}, error)
+	// 更新数据
+	Update(ctx context.Context, recordID string, item schema.{{.Name}}) (*schema.{{.Name}}, error)
+	// 删除数据
+	Delete(ctx context.Context, recordID string) error
+{{- if .Status}}
+	// 更新状态
+	UpdateStatus(ctx context.Context, recordID string, status int) error
+{{- end}}
+}
+`
+
+const tplBllImpl = `
+package internal
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
+	"github.com/wanhello/iris-admin/pkg/util"
+)
+
+// New{{.Name}} 创建{{.Comment}}
+func New{{.Name}}(m{{.Name}} model.I{{.Name}}) *{{.Name}} {
+	return &{{.Name}}{
+		{{.Name}}Model: m{{.Name}},
+	}
+}
+
+// {{.Name}} {{.Comment}}
+type {{.Name}} struct {
+	{{.Name}}Model model.I{{.Name}}
+}
+
+// Query 查询数据
+func (a *{{.Name}}) Query(ctx context.Context, params schema.{{.Name}}QueryParam, opts ...schema.{{.Name}}QueryOptions) (_ *schema.{{.Name}}QueryResult, err error) {
+	ctx, span := startSpan(ctx, "{{.Name}}.Query")
+	defer func() { tracing.End(span, err) }()
+
+	return a.{{.Name}}Model.Query(ctx, params, opts...)
+}
+
+// Get 查询指定数据
+func (a *{{.Name}}) Get(ctx context.Context, recordID string, opts ...schema.{{.Name}}QueryOptions) (_ *schema.{{.Name}}, err error) {
+	ctx, span := startSpan(ctx, "{{.Name}}.Get")
+	defer func() { tracing.End(span, err) }()
+
+	item, err := a.{{.Name}}Model.Get(ctx, recordID, opts...)
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	return item, nil
+}
+{{- with .UniqueField}}
+
+func (a *{{$.Name}}) check{{.Name}}(ctx context.Context, {{.Var}} {{.Type}}) error {
+	result, err := a.{{$.Name}}Model.Query(ctx, schema.{{$.Name}}QueryParam{
+		{{.Name}}: {{.Var}},
+	}, schema.{{$.Name}}QueryOptions{
+		PageParam: &schema.PaginationParam{PageSize: -1},
+	})
+	if err != nil {
+		return err
+	} else if result.PageResult.Total > 0 {
+		return errors.ErrResourceExists
+	}
+	return nil
+}
+{{- end}}
+
+func (a *{{.Name}}) getUpdate(ctx context.Context, recordID string) (*schema.{{.Name}}, error) {
+	return a.Get(ctx, recordID)
+}
+
+// Create 创建数据
+func (a *{{.Name}}) Create(ctx context.Context, item schema.{{.Name}}) (_ *schema.{{.Name}}, err error) {
+	ctx, span := startSpan(ctx, "{{.Name}}.Create")
+	defer func() { tracing.End(span, err) }()
+
+{{- with .UniqueField}}
+	err = a.check{{.Name}}(ctx, item.{{.Name}})
+	if err != nil {
+		return nil, err
+	}
+
+	item.RecordID = util.MustUUID()
+	err = a.{{$.Name}}Model.Create(ctx, item)
+{{- else}}
+	item.RecordID = util.MustUUID()
+	err = a.{{.Name}}Model.Create(ctx, item)
+{{- end}}
+	if err != nil {
+		return nil, err
+	}
+	return a.getUpdate(ctx, item.RecordID)
+}
+
+// Update 更新数据
+func (a *{{.Name}}) Update(ctx context.Context, recordID string, item schema.{{.Name}}) (_ *schema.{{.Name}}, err error) {
+	ctx, span := startSpan(ctx, "{{.Name}}.Update")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.{{.Name}}Model.Get(ctx, recordID)
+	if err != nil {
+		return nil, err
+	} else if oldItem == nil {
+		return nil, errors.ErrNotFound
+	}
+{{- with .UniqueField}} else if oldItem.{{.Name}} != item.{{.Name}} {
+		err := a.check{{.Name}}(ctx, item.{{.Name}})
+		if err != nil {
+			return nil, err
+		}
+	}
+{{- end}}
+
+	// 未指定版本号时以当前数据的版本号为准(不做并发检查)
+	if item.Version == 0 {
+		item.Version = oldItem.Version
+	}
+
+	err = a.{{.Name}}Model.Update(ctx, recordID, item)
+	if err != nil {
+		return nil, err
+	}
+	return a.getUpdate(ctx, recordID)
+}
+
+// Delete 删除数据
+func (a *{{.Name}}) Delete(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "{{.Name}}.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.{{.Name}}Model.Get(ctx, recordID)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.{{.Name}}Model.Delete(ctx, recordID)
+}
+{{- if .Status}}
+
+// UpdateStatus 更新状态
+func (a *{{.Name}}) UpdateStatus(ctx context.Context, recordID string, status int) (err error) {
+	ctx, span := startSpan(ctx, "{{.Name}}.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.{{.Name}}Model.Get(ctx, recordID)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.{{.Name}}Model.UpdateStatus(ctx, recordID, status)
+}
+{{- end}}
+`
+
+const tplCtl = `
+package ctl
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+{{- if .NeedUtil}}
+	"github.com/wanhello/iris-admin/pkg/util"
+{{- end}}
+
+	"github.com/kataras/iris"
+)
+
+// New{{.Name}} 创建{{.Comment}}控制器
+func New{{.Name}}(b{{.Name}} bll.I{{.Name}}) *{{.Name}} {
+	return &{{.Name}}{
+		{{.Name}}Bll: b{{.Name}},
+	}
+}
+
+// {{.Name}} {{.Comment}}
+// @Name {{.Name}}
+// @Description {{.Comment}}接口
+type {{.Name}} struct {
+	{{.Name}}Bll bll.I{{.Name}}
+}
+
+// Query 查询数据
+func (a *{{.Name}}) Query(c iris.Context) {
+	switch c.URLParam("q") {
+	case "page":
+		a.QueryPage(c)
+	default:
+		irisplus.ResError(c, errors.ErrUnknownQuery)
+	}
+}
+
+// QueryPage 查询分页数据
+// @Summary 查询分页数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param current query int true "分页索引" 1
+// @Param pageSize query int true "分页大小" 10
+{{- range .Fields}}
+{{- if eq .HTTPQuery "like"}}
+// @Param {{.JSON}} query string false "{{.Comment}}(模糊查询)"
+{{- else if eq .HTTPQuery "eq"}}
+// @Param {{.JSON}} query {{.SwaggoType}} false "{{.Comment}}"
+{{- end}}
+{{- end}}
+// @Param sort query string false "排序字段(多个以英文逗号分隔，字段前加-表示降序，例如：-created_at,name)"
+// @Param fields query string false "选择字段(多个以英文逗号分隔)"
+// @Param filter query string false "过滤条件(格式：字段:操作符:值，操作符：eq/ne/in/like/gte/lte/between，可指定多个)"
+// @Param cursor query string false "分页游标(指定时使用游标分页，首次查询为空)"
+// @Param limit query int false "游标分页的查询条数(最大1000)" 10
+// @Param total query string false "游标分页的总数查询方式(none:不查询 exact:精确查询 estimate:估算)"
+// @Success 200 []schema.{{.Name}} "查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
+// @Failure 400 schema.HTTPError "{error:{code:0,message:未知的查询类型}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/{{.Router}}?q=page
+func (a *{{.Name}}) QueryPage(c iris.Context) {
+	var params schema.{{.Name}}QueryParam
+{{- range .Fields}}
+{{- if eq .HTTPQuery "like"}}
+	params.Like{{.Name}} = c.URLParam("{{.JSON}}")
+{{- else if eq .HTTPQuery "eq"}}
+	params.{{.Name}} = {{.ParseURLParam}}
+{{- end}}
+{{- end}}
+
+	spec, err := irisplus.GetQuerySpec(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.{{.Name}}Bll.Query(irisplus.NewContext(c), params, schema.{{.Name}}QueryOptions{
+		PageParam:   irisplus.GetPaginationParam(c),
+		CursorParam: irisplus.GetCursorParam(c),
+		QuerySpec:   spec,
+	})
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	irisplus.ResPageFields(c, result.Data, result.PageResult, spec.Fields)
+}
+
+// Get 查询指定数据
+// @Summary 查询指定数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.{{.Name}}
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/{{.Router}}/{id}
+func (a *{{.Name}}) Get(c iris.Context) {
+	item, err := a.{{.Name}}Bll.Get(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.SetETag(c, item.Version)
+	irisplus.ResSuccess(c, item)
+}
+
+// Create 创建数据
+// @Summary 创建数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.{{.Name}} true
+// @Success 200 schema.{{.Name}}
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/{{.Router}}
+func (a *{{.Name}}) Create(c iris.Context) {
+	var item schema.{{.Name}}
+	if err := irisplus.ParseJSON(c, &item); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	item.Creator = irisplus.GetUserID(c)
+	nitem, err := a.{{.Name}}Bll.Create(irisplus.NewContext(c), item)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.SetETag(c, nitem.Version)
+	irisplus.ResSuccess(c, nitem)
+}
+
+// Update 更新数据
+// @Summary 更新数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Param If-Match header string false "数据版本号(ETag)"
+// @Param body body schema.{{.Name}} true
+// @Success 200 schema.{{.Name}}
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 409 schema.HTTPError "{error:{code:0,message:资源已被修改，请刷新后重试}}"
+// @Failure 412 schema.HTTPError "{error:{code:0,message:资源版本不匹配}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PUT /api/v1/{{.Router}}/{id}
+func (a *{{.Name}}) Update(c iris.Context) {
+	var item schema.{{.Name}}
+	if err := irisplus.ParseJSON(c, &item); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	ifMatch, err := irisplus.GetIfMatchVersion(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	} else if ifMatch > 0 {
+		item.Version = ifMatch
+	}
+
+	nitem, err := a.{{.Name}}Bll.Update(irisplus.NewContext(c), c.Params().Get("id"), item)
+	if err != nil {
+		// 通过If-Match指定的版本号不一致时响应412
+		if ifMatch > 0 && err == errors.ErrResourceConflict {
+			err = errors.ErrPreconditionFailed
+		}
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.SetETag(c, nitem.Version)
+	irisplus.ResSuccess(c, nitem)
+}
+
+// Delete 删除数据
+// @Summary 删除数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router DELETE /api/v1/{{.Router}}/{id}
+func (a *{{.Name}}) Delete(c iris.Context) {
+	err := a.{{.Name}}Bll.Delete(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
+{{- if .Status}}
+
+// Enable 启用数据
+// @Summary 启用数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PATCH /api/v1/{{.Router}}/{id}/enable
+func (a *{{.Name}}) Enable(c iris.Context) {
+	err := a.{{.Name}}Bll.UpdateStatus(irisplus.NewContext(c), c.Params().Get("id"), 1)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
+
+// Disable 禁用数据
+// @Summary 禁用数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PATCH /api/v1/{{.Router}}/{id}/disable
+func (a *{{.Name}}) Disable(c iris.Context) {
+	err := a.{{.Name}}Bll.UpdateStatus(irisplus.NewContext(c), c.Params().Get("id"), 2)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
+{{- end}}
+`
+
+const tplMenuData = `
+package app
+
+// {{.Var}}MenuData {{.Comment}}菜单初始化数据
+const {{.Var}}MenuData = ‵
+[
+  {
+    "name": "{{.Menu.Name}}",
+    "icon": "{{.Menu.Icon}}",
+    "router": "{{.Menu.Router}}",
+    "sequence": {{.Menu.Sequence}},
+    "actions": [
+      { "code": "add", "name": "新增" },
+      { "code": "edit", "name": "编辑" },
+      { "code": "del", "name": "删除" },
+      { "code": "query", "name": "查询" }
+    ],
+    "resources": [
+      {
+        "code": "query",
+        "name": "查询{{.Comment}}数据",
+        "method": "GET",
+        "path": "/api/v1/{{.Router}}"
+      },
+      {
+        "code": "get",
+        "name": "精确查询{{.Comment}}数据",
+        "method": "GET",
+        "path": "/api/v1/{{.Router}}/:id"
+      },
+      {
+        "code": "create",
+        "name": "创建{{.Comment}}数据",
+        "method": "POST",
+        "path": "/api/v1/{{.Router}}"
+      },
+      {
+        "code": "update",
+        "name": "更新{{.Comment}}数据",
+        "method": "PUT",
+        "path": "/api/v1/{{.Router}}/:id"
+      },
+      {
+        "code": "delete",
+        "name": "删除{{.Comment}}数据",
+        "method": "DELETE",
+        "path": "/api/v1/{{.Router}}/:id"
+{{- if .Status}}
+      },
+      {
+        "code": "enable",
+        "name": "启用{{.Comment}}数据",
+        "method": "PATCH",
+        "path": "/api/v1/{{.Router}}/:id/enable"
+      },
+      {
+        "code": "disable",
+        "name": "禁用{{.Comment}}数据",
+        "method": "PATCH",
+        "path": "/api/v1/{{.Router}}/:id/disable"
+{{- end}}
+      }
+    ]
+  }
+]
+‵
+
+func init() {
+	extraMenuData = append(extraMenuData, {{.Var}}MenuData)
+}
+`
+
+const tplRouter = `
+			// 注册/api/v1/{{.Router}}
+			v1.Get("/{{.Router}}", c{{.Name}}.Query)
+			v1.Get("/{{.Router}}/{id}", c{{.Name}}.Get)
+			v1.Post("/{{.Router}}", c{{.Name}}.Create)
+			v1.Put("/{{.Router}}/{id}", c{{.Name}}.Update)
+			v1.Delete("/{{.Router}}/{id}", c{{.Name}}.Delete)
+{{- if .Status}}
+			v1.Patch("/{{.Router}}/{id}/enable", c{{.Name}}.Enable)
+			v1.Patch("/{{.Router}}/{id}/disable", c{{.Name}}.Disable)
+{{- end}}
+`
+
+const tplMigrationMySQLUp = `
+CREATE TABLE IF NOT EXISTS {prefix}{{.Table}} (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  record_id varchar(36),
+{{- range .Fields}}
+  {{.Column}} {{.SQLType "mysql"}},
+{{- end}}
+  creator varchar(36),
+  version int DEFAULT 1,
+  PRIMARY KEY (id),
+  KEY idx_{prefix}{{.Table}}_deleted_at (deleted_at),
+  KEY idx_{prefix}{{.Table}}_record_id (record_id)
+{{- range .Fields}}{{if .Index}},
+  KEY idx_{prefix}{{$.Table}}_{{.Column}} ({{.Column}})
+{{- end}}{{end}}
+) ENGINE=InnoDB;
+`
+
+const tplMigrationMySQLDown = `
+DROP TABLE IF EXISTS {prefix}{{.Table}};
+`
+
+const tplMigrationPostgresUp = `
+CREATE TABLE IF NOT EXISTS "{prefix}{{.Table}}" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "record_id" varchar(36),
+{{- range .Fields}}
+  "{{.Column}}" {{.SQLType "postgres"}},
+{{- end}}
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}{{.Table}}_deleted_at ON "{prefix}{{.Table}}" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}{{.Table}}_record_id ON "{prefix}{{.Table}}" ("record_id");
+{{- range .Fields}}{{if .Index}}
+CREATE INDEX IF NOT EXISTS idx_{prefix}{{$.Table}}_{{.Column}} ON "{prefix}{{$.Table}}" ("{{.Column}}");
+{{- end}}{{end}}
+`
+
+const tplMigrationPostgresDown = `
+DROP TABLE IF EXISTS "{prefix}{{.Table}}";
+`
+
+const tplMigrationSqlite3Up = `
+CREATE TABLE IF NOT EXISTS "{prefix}{{.Table}}" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "record_id" varchar(36),
+{{- range .Fields}}
+  "{{.Column}}" {{.SQLType "sqlite3"}},
+{{- end}}
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}{{.Table}}_deleted_at ON "{prefix}{{.Table}}" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}{{.Table}}_record_id ON "{prefix}{{.Table}}" ("record_id");
+{{- range .Fields}}{{if .Index}}
+CREATE INDEX IF NOT EXISTS idx_{prefix}{{$.Table}}_{{.Column}} ON "{prefix}{{$.Table}}" ("{{.Column}}");
+{{- end}}{{end}}
+`
+
+const tplMigrationSqlite3Down = `
+DROP TABLE IF EXISTS "{prefix}{{.Table}}";
+`
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync/atomic"
@@ -28,7 +29,7 @@ var (
 
 
 func init() {
-	flag.StringVar(&configFile, "c", "", "config_file(.json,.yaml,.toml)")
+	flag.StringVar(&configFile, "c", "", "config_file(.json,.yaml,.toml), multiple files separated by commas, IRISADMIN_* env vars override")
 	flag.StringVar(&modelFile, "m", "", "Casbin access model config(.conf)")
 	flag.StringVar(&wwwDir, "www", "", "static directory")
 	flag.StringVar(&swaggerDir, "swagger", "", "swagger directory")
@@ -38,28 +39,65 @@ func init() {
 func main() {
 	flag.Parse()
 
-	if configFile == "" {
-		panic("请使用-c指定配置文件")
+	// 输出配置：server -c config.toml config print [--redacted]
+	if flag.Arg(0) == "config" {
+		err := app.ConfigCommand(os.Stdout, flag.Args()[1:], app.SetConfigFile(configFile), app.SetModelFile(modelFile))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 敏感信息加密：server secret genkey <file> 或 echo -n value | server -c config.toml secret encrypt
+	if flag.Arg(0) == "secret" {
+		err := app.SecretCommand(os.Stdout, os.Stdin, flag.Args()[1:], app.SetConfigFile(configFile))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 数据库版本迁移：server -c config.toml migrate up|down|status
+	if flag.Arg(0) == "migrate" {
+		err := app.Migrate(context.Background(), flag.Args()[1:], app.SetConfigFile(configFile))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
 	}
 
 	var state int32 = 1
-	sc := make(chan os.Signal)
+	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
 	ctx := logger.NewTraceIDContext(context.Background(), util.MustUUID())
 	span := logger.StartSpanWithCall(ctx)
 
-	call := app.Init(ctx,
+	opts := []app.Option{
 		app.SetConfigFile(configFile),
 		app.SetModelFile(modelFile),
 		app.SetWWWDir(wwwDir),
 		app.SetSwaggerDir(swaggerDir),
-		app.SetVersion(VERSION))
+		app.SetVersion(VERSION),
+	}
+	call := app.Init(ctx, opts...)
+
+	for sig := range sc {
+		// SIGHUP重新加载配置，不退出服务
+		if sig == syscall.SIGHUP {
+			span().Printf("获取到重新加载配置信号[%s]", sig.String())
+			if err := app.Reload(ctx, opts...); err != nil {
+				span().Errorf("重新加载配置发生错误：%s", err.Error())
+			}
+			continue
+		}
 
-	select {
-	case sig := <-sc:
 		atomic.StoreInt32(&state, 0)
 		span().Printf("获取到退出信号[%s]", sig.String())
+		break
 	}
 
 	if call != nil {
@@ -0,0 +1,733 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/health"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// 路由测试用例(路径中的{demo}、{menu}、{role}及{user}替换为测试数据的记录ID)
+type apiCase struct {
+	name   string
+	method string
+	path   string
+	token  string
+	body   interface{}
+	status int
+}
+
+func runAPICases(t *testing.T, s *testServer, r *strings.Replacer, tests []apiCase) {
+	for _, item := range tests {
+		w := s.request(t, item.method, r.Replace(item.path), item.token, item.body)
+		if w.Code != item.status {
+			t.Fatalf("%s: %s %s expected status %d, got %d: %s", item.name, item.method, item.path, item.status, w.Code, w.Body.String())
+		}
+	}
+}
+
+// 测试数据(通过root用户创建)
+type testFixtures struct {
+	Demo string
+	Menu string
+	Role string
+	User string
+}
+
+func (s *testServer) createFixtures(t *testing.T, token string) *testFixtures {
+	t.Helper()
+	var f testFixtures
+
+	w := s.request(t, http.MethodPost, "/api/v1/demos", token, schema.Demo{Code: "D001", Name: "fixture", Status: 1})
+	expectStatus(t, w, http.StatusOK)
+	var demo schema.Demo
+	decodeJSON(t, w, &demo)
+	f.Demo = demo.RecordID
+
+	w = s.request(t, http.MethodPost, "/api/v1/menus", token, schema.Menu{
+		Name:     "fixture",
+		Sequence: 1,
+		Resources: schema.MenuResources{
+			{Code: "query", Name: "查询", Method: "GET", Path: "/api/v1/demos"},
+		},
+	})
+	expectStatus(t, w, http.StatusOK)
+	var menu schema.Menu
+	decodeJSON(t, w, &menu)
+	f.Menu = menu.RecordID
+
+	w = s.request(t, http.MethodPost, "/api/v1/roles", token, schema.Role{
+		Name:     "fixture",
+		Sequence: 1,
+		Menus:    schema.RoleMenus{{MenuID: f.Menu, Resources: []string{"query"}}},
+	})
+	expectStatus(t, w, http.StatusOK)
+	var role schema.Role
+	decodeJSON(t, w, &role)
+	f.Role = role.RecordID
+
+	w = s.request(t, http.MethodPost, "/api/v1/users", token, schema.User{
+		UserName: "fixture",
+		RealName: "fixture",
+		Password: util.MD5HashString(testPassword),
+		Status:   1,
+		Roles:    schema.UserRoles{{RoleID: f.Role}},
+	})
+	expectStatus(t, w, http.StatusOK)
+	var user schema.User
+	decodeJSON(t, w, &user)
+	f.User = user.RecordID
+
+	return &f
+}
+
+func TestAPIRoutes(t *testing.T) {
+	s := newTestServer(t)
+	token := s.loginRoot(t)
+	f := s.createFixtures(t, token)
+	r := strings.NewReplacer("{demo}", f.Demo, "{menu}", f.Menu, "{role}", f.Role, "{user}", f.User)
+
+	batch := func(id string) schema.BatchParam {
+		return schema.BatchParam{RecordIDs: []string{id}}
+	}
+	menus := schema.RoleMenus{{MenuID: f.Menu, Resources: []string{"query"}}}
+	roles := schema.UserRoles{{RoleID: f.Role}}
+
+	// 删除后依次执行回收站查询、恢复、批量删除及彻底删除(用户、角色及菜单依次删除)
+	runAPICases(t, s, r, []apiCase{
+		{"current user", "GET", "/api/v1/pub/current/user", token, nil, 200},
+		{"current menu tree", "GET", "/api/v1/pub/current/menutree", token, nil, 200},
+		{"refresh token", "POST", "/api/v1/pub/refresh_token", token, nil, 200},
+
+		{"query demos", "GET", "/api/v1/demos?q=page", token, nil, 200},
+		{"unknown demo query", "GET", "/api/v1/demos", token, nil, 400},
+		{"query demo fields", "GET", "/api/v1/demos?q=page&fields=code&sort=-code&filter=name:like:%25", token, nil, 200},
+		{"invalid demo sort", "GET", "/api/v1/demos?q=page&sort=-", token, nil, 400},
+		{"unknown demo filter field", "GET", "/api/v1/demos?q=page&filter=password:eq:1", token, nil, 400},
+		{"invalid demo filter", "GET", "/api/v1/demos?q=page&filter=code:between:A", token, nil, 400},
+		{"get demo", "GET", "/api/v1/demos/{demo}", token, nil, 200},
+		{"get missing demo", "GET", "/api/v1/demos/none", token, nil, 404},
+		{"create demo", "POST", "/api/v1/demos", token, schema.Demo{Code: "D002", Name: "other", Status: 1}, 200},
+		{"update demo", "PUT", "/api/v1/demos/{demo}", token, schema.Demo{Code: "D001", Name: "updated", Status: 1}, 200},
+		{"disable demo", "PATCH", "/api/v1/demos/{demo}/disable", token, nil, 200},
+		{"enable demo", "PATCH", "/api/v1/demos/{demo}/enable", token, nil, 200},
+		{"batch disable demos", "POST", "/api/v1/demos/batch/disable", token, batch(f.Demo), 200},
+		{"batch enable demos", "POST", "/api/v1/demos/batch/enable", token, batch(f.Demo), 200},
+
+		{"query menus", "GET", "/api/v1/menus?q=page", token, nil, 200},
+		{"query menu tree", "GET", "/api/v1/menus?q=tree", token, nil, 200},
+		{"get menu", "GET", "/api/v1/menus/{menu}", token, nil, 200},
+		{"create menu", "POST", "/api/v1/menus", token, schema.Menu{Name: "other", Sequence: 2}, 200},
+		{"update menu", "PUT", "/api/v1/menus/{menu}", token, schema.Menu{
+			Name:     "updated",
+			Sequence: 1,
+			Resources: schema.MenuResources{
+				{Code: "query", Name: "查询", Method: "GET", Path: "/api/v1/demos"},
+			},
+		}, 200},
+
+		{"query roles", "GET", "/api/v1/roles?q=page", token, nil, 200},
+		{"select roles", "GET", "/api/v1/roles?q=select", token, nil, 200},
+		{"get role", "GET", "/api/v1/roles/{role}", token, nil, 200},
+		{"create role", "POST", "/api/v1/roles", token, schema.Role{Name: "other", Sequence: 2, Menus: menus}, 200},
+		{"update role", "PUT", "/api/v1/roles/{role}", token, schema.Role{Name: "updated", Sequence: 1, Menus: menus}, 200},
+
+		{"query users", "GET", "/api/v1/users?q=page", token, nil, 200},
+		{"get user", "GET", "/api/v1/users/{user}", token, nil, 200},
+		{"create user", "POST", "/api/v1/users", token, schema.User{
+			UserName: "other",
+			RealName: "other",
+			Password: util.MD5HashString(testPassword),
+			Status:   1,
+			Roles:    roles,
+		}, 200},
+		{"update user", "PUT", "/api/v1/users/{user}", token, schema.User{UserName: "fixture", RealName: "updated", Status: 1, Roles: roles}, 200},
+		{"disable user", "PATCH", "/api/v1/users/{user}/disable", token, nil, 200},
+		{"enable user", "PATCH", "/api/v1/users/{user}/enable", token, nil, 200},
+		{"batch disable users", "POST", "/api/v1/users/batch/disable", token, batch(f.User), 200},
+		{"batch enable users", "POST", "/api/v1/users/batch/enable", token, batch(f.User), 200},
+		{"batch assign roles", "POST", "/api/v1/users/batch/roles", token, schema.BatchUserRoleParam{
+			BatchParam: batch(f.User),
+			RoleIDs:    []string{f.Role},
+		}, 200},
+
+		{"search", "GET", "/api/v1/search?q=fixture", token, nil, 200},
+		{"delete role in use", "DELETE", "/api/v1/roles/{role}", token, nil, 400},
+	})
+
+	// 删除角色之前先删除同样使用该角色的其他用户
+	w := s.request(t, http.MethodGet, "/api/v1/users?q=page&user_name=other", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	var others struct {
+		List []*schema.User `json:"list"`
+	}
+	decodeJSON(t, w, &others)
+	if len(others.List) != 1 {
+		t.Fatalf("expected 1 user named other, got %d", len(others.List))
+	}
+	r = strings.NewReplacer("{demo}", f.Demo, "{menu}", f.Menu, "{role}", f.Role, "{user}", f.User, "{other}", others.List[0].RecordID)
+
+	// 分页查询使用查询参数指定的页大小
+	w = s.request(t, http.MethodGet, "/api/v1/users?q=page&current=2&pageSize=1", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	var page struct {
+		List       []*schema.User         `json:"list"`
+		Pagination *schema.HTTPPagination `json:"pagination"`
+	}
+	decodeJSON(t, w, &page)
+	if len(page.List) != 1 || page.Pagination == nil || page.Pagination.PageSize != 1 ||
+		page.Pagination.Current != 2 || page.Pagination.Total != 2 {
+		t.Fatalf("unexpected page %s", w.Body.String())
+	}
+
+	runAPICases(t, s, r, []apiCase{
+		{"delete other user", "DELETE", "/api/v1/users/{other}", token, nil, 200},
+		{"delete user", "DELETE", "/api/v1/users/{user}", token, nil, 200},
+		{"query recycled users", "GET", "/api/v1/recycle/users", token, nil, 200},
+		{"restore user", "PATCH", "/api/v1/recycle/users/{user}/restore", token, nil, 200},
+	})
+
+	// 恢复的用户重新加入检索索引
+	if !searchContains(t, s, token, "fixture", schema.SearchTypeUser, f.User) {
+		t.Fatal("restored user not found by search")
+	}
+
+	runAPICases(t, s, r, []apiCase{
+		{"batch delete users", "POST", "/api/v1/users/batch/delete", token, batch(f.User), 200},
+		{"purge user", "DELETE", "/api/v1/recycle/users/{user}", token, nil, 200},
+
+		{"delete role", "DELETE", "/api/v1/roles/{role}", token, nil, 200},
+		{"query recycled roles", "GET", "/api/v1/recycle/roles", token, nil, 200},
+		{"restore role", "PATCH", "/api/v1/recycle/roles/{role}/restore", token, nil, 200},
+		{"batch delete roles", "POST", "/api/v1/roles/batch/delete", token, batch(f.Role), 200},
+		{"purge role", "DELETE", "/api/v1/recycle/roles/{role}", token, nil, 200},
+
+		{"delete menu", "DELETE", "/api/v1/menus/{menu}", token, nil, 200},
+		{"query recycled menus", "GET", "/api/v1/recycle/menus", token, nil, 200},
+		{"restore menu", "PATCH", "/api/v1/recycle/menus/{menu}/restore", token, nil, 200},
+		{"delete restored menu", "DELETE", "/api/v1/menus/{menu}", token, nil, 200},
+		{"purge menu", "DELETE", "/api/v1/recycle/menus/{menu}", token, nil, 200},
+
+		{"delete demo", "DELETE", "/api/v1/demos/{demo}", token, nil, 200},
+		{"query recycled demos", "GET", "/api/v1/recycle/demos", token, nil, 200},
+		{"restore demo", "PATCH", "/api/v1/recycle/demos/{demo}/restore", token, nil, 200},
+		{"batch delete demos", "POST", "/api/v1/demos/batch/delete", token, batch(f.Demo), 200},
+		{"purge demo", "DELETE", "/api/v1/recycle/demos/{demo}", token, nil, 200},
+		{"get purged demo", "GET", "/api/v1/demos/{demo}", token, nil, 404},
+	})
+}
+
+func TestAPIPermission(t *testing.T) {
+	s := newTestServer(t)
+	root := s.loginRoot(t)
+	f := s.createFixtures(t, root)
+	r := strings.NewReplacer("{demo}", f.Demo, "{user}", f.User)
+
+	token := s.loginAs(t, "tester", "GET /api/v1/demos", "GET /api/v1/demos/:id")
+
+	runAPICases(t, s, r, []apiCase{
+		{"no token", "GET", "/api/v1/demos?q=page", "", nil, 401},
+		{"invalid token", "GET", "/api/v1/demos?q=page", "invalid", nil, 401},
+		{"public route without token", "GET", "/api/v1/pub/current/user", "", nil, 401},
+
+		{"allowed", "GET", "/api/v1/demos?q=page", token, nil, 200},
+		{"allowed path pattern", "GET", "/api/v1/demos/{demo}", token, nil, 200},
+		{"denied method", "POST", "/api/v1/demos", token, schema.Demo{Code: "D002", Name: "other", Status: 1}, 401},
+		{"denied sub path", "PATCH", "/api/v1/demos/{demo}/disable", token, nil, 401},
+		{"denied resource", "GET", "/api/v1/users/{user}", token, nil, 401},
+		{"denied recycle", "GET", "/api/v1/recycle/demos", token, nil, 401},
+
+		// 公共路由及检索不需要资源权限
+		{"current user", "GET", "/api/v1/pub/current/user", token, nil, 200},
+		{"current menu tree", "GET", "/api/v1/pub/current/menutree", token, nil, 200},
+		{"search", "GET", "/api/v1/search?q=fixture", token, nil, 200},
+
+		// 以测试数据中的用户登录(角色授权了查询demo的权限)
+		{"fixture user", "GET", "/api/v1/demos?q=page", s.login(t, "fixture", testPassword), nil, 200},
+	})
+
+	// 停用用户后不允许登录
+	runAPICases(t, s, r, []apiCase{
+		{"disable user", "PATCH", "/api/v1/users/{user}/disable", root, nil, 200},
+	})
+	expectStatus(t, s.loginRequest(t, "fixture", testPassword), http.StatusBadRequest)
+}
+
+func TestOptimisticLock(t *testing.T) {
+	s := newTestServer(t)
+	token := s.loginRoot(t)
+	f := s.createFixtures(t, token)
+	path := "/api/v1/demos/" + f.Demo
+
+	w := s.request(t, http.MethodGet, path, token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if v := w.Header().Get("ETag"); v != `"1"` {
+		t.Fatalf("unexpected etag %q", v)
+	}
+
+	update := func(ifMatch string, version int) *httptest.ResponseRecorder {
+		buf, err := util.JSONMarshal(schema.Demo{Code: "D001", Name: "updated", Status: 1, Version: version})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPut, path, bytes.NewReader(buf))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		return s.serve(req)
+	}
+
+	w = update(`"1"`, 0)
+	expectStatus(t, w, http.StatusOK)
+	if v := w.Header().Get("ETag"); v != `"2"` {
+		t.Fatalf("unexpected etag %q", v)
+	}
+
+	// If-Match指定的版本号过期时响应412，请求内容中的版本号过期时响应409
+	expectStatus(t, update(`"1"`, 0), http.StatusPreconditionFailed)
+	expectStatus(t, update("", 1), http.StatusConflict)
+	expectStatus(t, update("invalid", 2), http.StatusPreconditionFailed)
+
+	// If-Match优先于请求内容中的版本号
+	expectStatus(t, update(`W/"2"`, 1), http.StatusOK)
+	expectStatus(t, update("", 3), http.StatusOK)
+}
+
+func TestLoginCaptcha(t *testing.T) {
+	s := newTestServer(t)
+	root := config.GetGlobalConfig().Root
+
+	captchaID, code := s.captcha(t)
+	w := s.request(t, http.MethodGet, "/api/v1/pub/login/captcha?id="+captchaID, "", nil)
+	expectStatus(t, w, http.StatusOK)
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")) {
+		t.Fatal("expected png captcha image")
+	}
+
+	// 重新加载后原验证码失效
+	w = s.request(t, http.MethodGet, "/api/v1/pub/login/captcha?id="+captchaID+"&reload=1", "", nil)
+	expectStatus(t, w, http.StatusOK)
+	if digits := captchaStore.Get(captchaID, false); len(digits) != len(code) {
+		t.Fatalf("unexpected reloaded captcha: %v", digits)
+	}
+
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/login/captcha", "", nil), http.StatusBadRequest)
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/login/captcha?id=none", "", nil), http.StatusNotFound)
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/login/captcha?id=none&reload=1", "", nil), http.StatusBadRequest)
+
+	// 验证码错误后验证码失效
+	captchaID, code = s.captcha(t)
+	wrong := []byte(code)
+	wrong[0] = '0' + (wrong[0]-'0'+1)%10
+	login := schema.LoginParam{
+		UserName:    root.UserName,
+		Password:    util.MD5HashString(root.Password),
+		CaptchaID:   captchaID,
+		CaptchaCode: string(wrong),
+	}
+	expectStatus(t, s.request(t, http.MethodPost, "/api/v1/pub/login", "", login), http.StatusBadRequest)
+	login.CaptchaCode = code
+	expectStatus(t, s.request(t, http.MethodPost, "/api/v1/pub/login", "", login), http.StatusBadRequest)
+
+	// 验证码只能使用一次
+	captchaID, code = s.captcha(t)
+	login.CaptchaID = captchaID
+	login.CaptchaCode = code
+	expectStatus(t, s.request(t, http.MethodPost, "/api/v1/pub/login", "", login), http.StatusOK)
+	expectStatus(t, s.request(t, http.MethodPost, "/api/v1/pub/login", "", login), http.StatusBadRequest)
+
+	expectStatus(t, s.loginRequest(t, root.UserName, "wrong"), http.StatusBadRequest)
+	expectStatus(t, s.loginRequest(t, "none", testPassword), http.StatusBadRequest)
+}
+
+func TestTokenRevocation(t *testing.T) {
+	s := newTestServer(t)
+	token := s.loginAs(t, "tester")
+
+	// 刷新令牌后原令牌仍然有效
+	w := s.request(t, http.MethodPost, "/api/v1/pub/refresh_token", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	var info schema.LoginTokenInfo
+	decodeJSON(t, w, &info)
+	refreshed := info.AccessToken
+
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/current/user", token, nil), http.StatusOK)
+
+	// 登出后令牌失效
+	expectStatus(t, s.request(t, http.MethodPost, "/api/v1/pub/login/exit", token, nil), http.StatusOK)
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/current/user", token, nil), http.StatusUnauthorized)
+	expectStatus(t, s.request(t, http.MethodPost, "/api/v1/pub/refresh_token", token, nil), http.StatusUnauthorized)
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/current/user", refreshed, nil), http.StatusOK)
+
+	// 修改密码后使用新密码登录
+	expectStatus(t, s.request(t, http.MethodPut, "/api/v1/pub/current/password", refreshed, schema.UpdatePasswordParam{
+		OldPassword: util.MD5HashString(testPassword),
+		NewPassword: util.MD5HashString("654321"),
+	}), http.StatusOK)
+	expectStatus(t, s.loginRequest(t, "tester", testPassword), http.StatusBadRequest)
+	s.login(t, "tester", "654321")
+}
+
+func TestHealthCheck(t *testing.T) {
+	s := newTestServer(t)
+
+	var checker *health.Checker
+	if err := s.container.Invoke(func(c *health.Checker) { checker = c }); err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus(t, s.request(t, http.MethodGet, "/healthz", "", nil), http.StatusOK)
+
+	// HTTP服务启动前未就绪
+	expectStatus(t, s.request(t, http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable)
+
+	checker.SetReady(true)
+	w := s.request(t, http.MethodGet, "/readyz", "", nil)
+	expectStatus(t, w, http.StatusOK)
+	var report health.Report
+	decodeJSON(t, w, &report)
+	names := make(map[string]string)
+	for _, r := range report.Checks {
+		names[r.Name] = r.Status
+	}
+	if names["gorm"] != health.StatusUp || names["casbin"] != health.StatusUp {
+		t.Fatalf("unexpected checks %v", names)
+	}
+
+	// 关闭服务时不再就绪，存活检查不受影响
+	checker.SetReady(false)
+	expectStatus(t, s.request(t, http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable)
+	expectStatus(t, s.request(t, http.MethodGet, "/healthz", "", nil), http.StatusOK)
+}
+
+func TestRateLimiter(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.RateLimiter.Enable = true
+		cfg.RateLimiter.Store = "memory"
+		cfg.RateLimiter.Algorithm = "token_bucket"
+		cfg.RateLimiter.Count = 100
+		cfg.RateLimiter.Period = 60
+		cfg.RateLimiter.Policies = []config.RateLimiterPolicy{
+			{Name: "captcha", Method: "GET", Path: "/api/v1/pub/login/captchaid", Key: "ip", Count: 3, Period: 60},
+		}
+	})
+
+	// 登录时获取验证码ID消耗一次额度
+	token := s.loginRoot(t)
+
+	// 匿名请求按客户端IP限制，所有响应都返回额度
+	for i := 0; i < 2; i++ {
+		w := s.request(t, http.MethodGet, "/api/v1/pub/login/captchaid", "", nil)
+		expectStatus(t, w, http.StatusOK)
+		if w.Header().Get("RateLimit-Limit") != "3" || w.Header().Get("RateLimit-Remaining") != strconv.Itoa(1-i) {
+			t.Fatalf("unexpected rate limit headers %v", w.Header())
+		}
+	}
+	w := s.request(t, http.MethodGet, "/api/v1/pub/login/captchaid", "", nil)
+	expectStatus(t, w, http.StatusTooManyRequests)
+	if w.Header().Get("Retry-After") != "20" || w.Header().Get("RateLimit-Policy") != "3;w=60" {
+		t.Fatalf("unexpected rate limit headers %v", w.Header())
+	}
+
+	// 未匹配策略的请求使用默认策略
+	w = s.request(t, http.MethodPost, "/api/v1/pub/login/exit", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if w.Header().Get("RateLimit-Limit") != "100" || w.Header().Get("RateLimit-Policy") != "100;w=60" {
+		t.Fatalf("unexpected rate limit headers %v", w.Header())
+	}
+}
+
+// 查询缓存及请求频率限制共用redis客户端，redis不可用时敏感策略拒绝请求
+func TestRateLimiterRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Redis.Addr = mr.Addr()
+		cfg.Cache.Enable = true
+		cfg.Cache.Store = "redis"
+		cfg.RateLimiter.Enable = true
+		cfg.RateLimiter.Store = "redis"
+		cfg.RateLimiter.Policies = []config.RateLimiterPolicy{
+			{Name: "captcha", Method: "GET", Path: "/api/v1/pub/login/captchaid", Key: "ip", Count: 100, Sensitive: true},
+		}
+	})
+
+	token := s.loginRoot(t)
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/users?q=page", token, nil), http.StatusOK)
+
+	prefixes := make(map[string]bool)
+	for _, key := range mr.DB(config.GetGlobalConfig().Redis.DB).Keys() {
+		prefixes[strings.SplitN(key, "_", 2)[0]] = true
+	}
+	if !prefixes["cache"] || !prefixes["rate"] {
+		t.Fatalf("expected cache and rate limit keys in the shared redis db, got %v", mr.DB(config.GetGlobalConfig().Redis.DB).Keys())
+	}
+
+	mr.Close()
+	denied := testutil.ToFloat64(metrics.RateLimiterErrors.WithLabelValues("deny"))
+	expectStatus(t, s.request(t, http.MethodGet, "/api/v1/pub/login/captchaid", "", nil), http.StatusServiceUnavailable)
+	if v := testutil.ToFloat64(metrics.RateLimiterErrors.WithLabelValues("deny")); v != denied+1 {
+		t.Fatalf("expected rate limiter error to be counted, got %v", v)
+	}
+
+	// 其他策略使用后备的内存存储
+	w := s.request(t, http.MethodPost, "/api/v1/pub/login/exit", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if w.Header().Get("RateLimit-Limit") == "" {
+		t.Fatalf("expected fallback rate limit headers %v", w.Header())
+	}
+}
+
+func TestIPAccess(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.HTTP.TrustedProxies = []string{"192.0.2.1"}
+		cfg.IPAccess.Enable = true
+		cfg.IPAccess.Allow = []string{"10.0.0.0/8"}
+		cfg.IPAccess.Deny = []string{"10.0.0.13"}
+	})
+
+	// 存活检查不限制
+	expectStatus(t, s.requestFrom(t, http.MethodGet, "/healthz", "", ""), http.StatusOK)
+
+	const path = "/api/v1/pub/login/captchaid"
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, "", ""), http.StatusForbidden)
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, "", "10.1.1.1"), http.StatusOK)
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, "", "10.0.0.13"), http.StatusForbidden)
+	// 最左侧的地址可由客户端伪造，取第一个非可信代理的地址
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, "", "10.1.1.1, 8.8.8.8"), http.StatusForbidden)
+}
+
+// 按国家/地区限制访问，配置重新加载后重新读取地理位置数据库
+func TestIPAccessGeo(t *testing.T) {
+	geoFile := filepath.Join(t.TempDir(), "country.csv")
+	writeGeo := func(data string, modTime time.Time) {
+		if err := ioutil.WriteFile(geoFile, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(geoFile, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeGeo("198.51.100.0/24,US\n203.0.113.0,203.0.113.255,CN\n", time.Now().Add(-time.Hour))
+
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.HTTP.TrustedProxies = []string{"192.0.2.1"}
+		cfg.IPAccess.Enable = true
+		cfg.IPAccess.GeoDatabase = geoFile
+		cfg.IPAccess.GeoAllow = []string{"US", "CN"}
+		cfg.IPAccess.GeoDeny = []string{"CN"}
+	})
+
+	const path = "/api/v1/pub/login/captchaid"
+	for ip, status := range map[string]int{
+		"198.51.100.7": http.StatusOK,
+		// 禁止访问的国家/地区优先
+		"203.0.113.5": http.StatusForbidden,
+		// 未知国家/地区
+		"8.8.8.8": http.StatusForbidden,
+		// 内网地址不限制
+		"10.1.1.1": http.StatusOK,
+	} {
+		expectStatus(t, s.requestFrom(t, http.MethodGet, path, "", ip), status)
+	}
+
+	// 更新数据库及限制的国家/地区后重新加载配置
+	writeGeo("198.51.100.0/24,US\n203.0.113.0/24,CN\n8.8.8.0/24,US\n", time.Now())
+	next := *config.GetGlobalConfig()
+	next.IPAccess.GeoDeny = []string{"US"}
+	next.IPAccess.GeoAllowUnknown = true
+	if _, err := config.ReloadGlobalConfig(&next); err != nil {
+		t.Fatal(err)
+	}
+	for ip, status := range map[string]int{
+		"198.51.100.7": http.StatusForbidden,
+		"203.0.113.5":  http.StatusOK,
+		"8.8.8.8":      http.StatusForbidden,
+		"1.1.1.1":      http.StatusOK,
+	} {
+		expectStatus(t, s.requestFrom(t, http.MethodGet, path, "", ip), status)
+	}
+}
+
+func TestUserIPAccess(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.HTTP.TrustedProxies = []string{"192.0.2.1"}
+		cfg.IPAccess.Enable = true
+		cfg.IPAccess.CacheExpiration = 0
+	})
+
+	token := s.loginAs(t, "ipuser")
+	const path = "/api/v1/pub/current/user"
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, token, "10.2.2.2"), http.StatusOK)
+
+	err := s.container.Invoke(func(bUser bll.IUser) error {
+		ctx := icontext.NewPrimary(context.Background())
+		result, err := bUser.Query(ctx, schema.UserQueryParam{UserName: "ipuser"}, schema.UserQueryOptions{IncludeRoles: true})
+		if err != nil {
+			return err
+		}
+		item := result.Data[0]
+		item.AllowedCIDRs = []string{"10.1.0.0/16"}
+		_, err = bUser.Update(ctx, item.RecordID, *item)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, token, "10.1.1.1"), http.StatusOK)
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, token, "10.2.2.2"), http.StatusForbidden)
+
+	// root用户不限制
+	expectStatus(t, s.requestFrom(t, http.MethodGet, path, s.loginRoot(t), "10.2.2.2"), http.StatusOK)
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.HTTP.TrustedProxies = []string{"192.0.2.1"}
+		cfg.SecurityHeaders.Enable = true
+		cfg.SecurityHeaders.PermissionsPolicy = "camera=()"
+	})
+
+	w := s.request(t, http.MethodGet, "/api/v1/pub/login/captchaid", "", nil)
+	expectStatus(t, w, http.StatusOK)
+	h := w.Header()
+	if h.Get("Content-Security-Policy") != config.GetGlobalConfig().SecurityHeaders.ContentSecurityPolicy ||
+		h.Get("X-Frame-Options") != "DENY" || h.Get("X-Content-Type-Options") != "nosniff" ||
+		h.Get("Referrer-Policy") != "strict-origin-when-cross-origin" || h.Get("Permissions-Policy") != "camera=()" ||
+		h.Get("Cross-Origin-Opener-Policy") != "same-origin" {
+		t.Fatalf("unexpected security headers %v", h)
+	}
+	// http请求不设定HSTS
+	if v := h.Get("Strict-Transport-Security"); v != "" {
+		t.Fatalf("unexpected hsts header %q", v)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pub/login/captchaid", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = s.serve(req)
+	if v := w.Header().Get("Strict-Transport-Security"); v != "max-age=31536000; includeSubDomains" {
+		t.Fatalf("unexpected hsts header %q", v)
+	}
+}
+
+func TestCookieAuth(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.AuthCookie.Enable = true
+	})
+
+	root := config.GetGlobalConfig().Root
+	w := s.loginRequest(t, root.UserName, root.Password)
+	expectStatus(t, w, http.StatusOK)
+
+	cookies := w.Result().Cookies()
+	var token, csrf *http.Cookie
+	for _, c := range cookies {
+		switch c.Name {
+		case "access_token":
+			token = c
+		case "csrf_token":
+			csrf = c
+		}
+	}
+	if token == nil || !token.HttpOnly || !token.Secure || token.SameSite != http.SameSiteStrictMode {
+		t.Fatalf("unexpected token cookie %+v", token)
+	}
+	if csrf == nil || csrf.HttpOnly || csrf.Value == "" {
+		t.Fatalf("unexpected csrf cookie %+v", csrf)
+	}
+	expectNoAccessToken(t, w)
+
+	cookieRequest := func(method, path, csrfToken string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		if csrfToken != "" {
+			req.Header.Set("X-CSRF-Token", csrfToken)
+		}
+		return s.serve(req)
+	}
+
+	expectStatus(t, cookieRequest(http.MethodGet, "/api/v1/pub/current/user", ""), http.StatusOK)
+
+	// 刷新令牌同样只通过Cookie下发
+	w = cookieRequest(http.MethodPost, "/api/v1/pub/refresh_token", csrf.Value)
+	expectStatus(t, w, http.StatusOK)
+	expectNoAccessToken(t, w)
+	cookies = w.Result().Cookies()
+	for _, c := range cookies {
+		if c.Name == "csrf_token" {
+			csrf = c
+		}
+	}
+
+	// 非安全请求需要双重提交CSRF令牌
+	expectStatus(t, cookieRequest(http.MethodPost, "/api/v1/pub/login/exit", ""), http.StatusForbidden)
+	expectStatus(t, cookieRequest(http.MethodPost, "/api/v1/pub/login/exit", "invalid"), http.StatusForbidden)
+
+	w = cookieRequest(http.MethodPost, "/api/v1/pub/login/exit", csrf.Value)
+	expectStatus(t, w, http.StatusOK)
+	for _, c := range w.Result().Cookies() {
+		if c.Value != "" || c.MaxAge >= 0 {
+			t.Fatalf("cookie not cleared %+v", c)
+		}
+	}
+
+	// 登出后令牌失效
+	expectStatus(t, cookieRequest(http.MethodGet, "/api/v1/pub/current/user", ""), http.StatusUnauthorized)
+}
+
+// expectNoAccessToken 响应中不应包含访问令牌
+func expectNoAccessToken(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	var body map[string]interface{}
+	decodeJSON(t, w, &body)
+	if _, ok := body["access_token"]; ok {
+		t.Fatalf("unexpected access_token in body %v", body)
+	}
+	if body["token_type"] != "Bearer" || body["expires_at"] == nil {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
+
+// searchContains 检索结果中是否包含指定类型的记录
+func searchContains(t *testing.T, s *testServer, token, q, typ, recordID string) bool {
+	t.Helper()
+	w := s.request(t, http.MethodGet, "/api/v1/search?q="+q, token, nil)
+	expectStatus(t, w, http.StatusOK)
+	var result struct {
+		List []*schema.SearchGroup `json:"list"`
+	}
+	decodeJSON(t, w, &result)
+	for _, group := range result.List {
+		if group.Type != typ {
+			continue
+		}
+		for _, item := range group.Items {
+			if item.RecordID == recordID {
+				return true
+			}
+		}
+	}
+	return false
+}
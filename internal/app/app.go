@@ -3,9 +3,11 @@ package app
 import (
 	"context"
 	"os"
+	"strings"
 
 	"github.com/wanhello/iris-admin/internal/app/bll/impl"
 	"github.com/wanhello/iris-admin/internal/app/config"
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
 	"github.com/wanhello/iris-admin/pkg/auth"
 	"github.com/wanhello/iris-admin/pkg/logger"
 
@@ -24,7 +26,7 @@ type options struct {
 // Option 定义配置项
 type Option func(*options)
 
-// SetConfigFile 设定配置文件
+// SetConfigFile 设定配置文件(多个文件以逗号分隔，按顺序合并)
 func SetConfigFile(s string) Option {
 	return func(o *options) {
 		o.ConfigFile = s
@@ -59,6 +61,30 @@ func SetVersion(s string) Option {
 	}
 }
 
+// 获取配置文件列表(多个文件以逗号分隔)
+func (o *options) configFiles() []string {
+	var files []string
+	for _, s := range strings.Split(o.ConfigFile, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			files = append(files, s)
+		}
+	}
+	return files
+}
+
+// 使用启动参数覆盖配置文件中的配置项
+func applyOptions(cfg *config.Config, o *options) {
+	if v := o.ModelFile; v != "" {
+		cfg.CasbinModelConf = v
+	}
+	if v := o.WWWDir; v != "" {
+		cfg.WWW = v
+	}
+	if v := o.SwaggerDir; v != "" {
+		cfg.Swagger = v
+	}
+}
+
 func handleError(err error) {
 	if err != nil {
 		panic(err)
@@ -71,22 +97,17 @@ func Init(ctx context.Context, opts ...Option) func() {
 	for _, opt := range opts {
 		opt(&o)
 	}
-	err := config.LoadGlobalConfig(o.ConfigFile)
+	err := config.LoadGlobalConfig(o.configFiles()...)
 	handleError(err)
 
 	cfg := config.GetGlobalConfig()
 
+	// 日志中隐藏配置中的敏感信息
+	logger.SetSecrets(cfg.Secrets()...)
+
 	logger.Printf(ctx, "服务启动，运行模式：%s，版本号：%s，进程号：%d", cfg.RunMode, o.Version, os.Getpid())
 
-	if v := o.ModelFile; v != "" {
-		cfg.CasbinModelConf = v
-	}
-	if v := o.WWWDir; v != "" {
-		cfg.WWW = v
-	}
-	if v := o.SwaggerDir; v != "" {
-		cfg.Swagger = v
-	}
+	applyOptions(cfg, &o)
 
 	loggerCall, err := InitLogger()
 	handleError(err)
@@ -100,8 +121,29 @@ func Init(ctx context.Context, opts ...Option) func() {
 	// 创建依赖注入容器
 	container, containerCall := BuildContainer()
 
-	// 初始化数据
-	err = InitData(ctx, container)
+	// 初始化指标服务(在数据初始化之前注册数据库操作统计)
+	metricsCall, err := InitMetrics(ctx, container)
+	handleError(err)
+
+	// 初始化链路追踪
+	tracingCall, err := InitTracing(ctx, container, o.Version)
+	handleError(err)
+
+	// 初始化数据及重建全文检索索引(使用主库，避免读取从库的延迟数据)
+	err = InitData(icontext.NewPrimary(ctx), container)
+	handleError(err)
+
+	err = RebuildSearch(icontext.NewPrimary(ctx), container)
+	handleError(err)
+
+	// 回收站定时清理
+	recycleCall := InitRecycle(ctx, container)
+
+	// 监听配置文件变更
+	watchCall := InitConfigWatch(ctx, o)
+
+	// 初始化健康检查
+	healthCall, err := InitHealth(container)
 	handleError(err)
 
 	// 初始化HTTP服务
@@ -110,6 +152,21 @@ func Init(ctx context.Context, opts ...Option) func() {
 		if httpCall != nil {
 			httpCall()
 		}
+		if healthCall != nil {
+			healthCall()
+		}
+		if watchCall != nil {
+			watchCall()
+		}
+		if recycleCall != nil {
+			recycleCall()
+		}
+		if tracingCall != nil {
+			tracingCall()
+		}
+		if metricsCall != nil {
+			metricsCall()
+		}
 		if containerCall != nil {
 			containerCall()
 		}
@@ -140,10 +197,30 @@ func BuildContainer() (*dig.Container, func()) {
 		return auther
 	})
 
+	// 注入查询缓存及请求频率限制共用的redis客户端
+	redisCall, err := InitRedis(container)
+	handleError(err)
+
+	// 注入查询缓存
+	cacheCall, err := InitCache(container)
+	handleError(err)
+
+	// 注入请求频率限制
+	rateLimiterCall, err := InitRateLimiter(container)
+	handleError(err)
+
+	// 注入IP访问控制的国家/地区查询
+	err = InitCountryLookup(container)
+	handleError(err)
+
 	// 注入存储模块
 	storeCall, err := InitStore(container)
 	handleError(err)
 
+	// 注入全文检索
+	err = InitSearch(container)
+	handleError(err)
+
 	// 注入bll
 	err = impl.Inject(container)
 	handleError(err)
@@ -155,6 +232,15 @@ func BuildContainer() (*dig.Container, func()) {
 		if storeCall != nil {
 			storeCall()
 		}
+		if cacheCall != nil {
+			cacheCall()
+		}
+		if rateLimiterCall != nil {
+			rateLimiterCall()
+		}
+		if redisCall != nil {
+			redisCall()
+		}
 	}
 }
 
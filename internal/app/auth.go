@@ -2,6 +2,7 @@ package app
 
 import (
 	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
 	"github.com/wanhello/iris-admin/pkg/auth"
 	"github.com/wanhello/iris-admin/pkg/auth/jwtauth"
 	"github.com/wanhello/iris-admin/pkg/auth/jwtauth/store/buntdb"
@@ -17,6 +18,9 @@ func InitAuth() (auth.Auther, error) {
 	var opts []jwtauth.Option
 	opts = append(opts, jwtauth.SetExpired(cfg.Expired))
 	opts = append(opts, jwtauth.SetSigningKey([]byte(cfg.SigningKey)))
+	opts = append(opts, jwtauth.SetRevokedFunc(func(string) {
+		metrics.TokenBlacklistHits.Inc()
+	}))
 	opts = append(opts, jwtauth.SetKeyfunc(func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, auth.ErrInvalidToken
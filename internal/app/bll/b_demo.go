@@ -2,6 +2,7 @@ package bll
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -20,4 +21,16 @@ type IDemo interface {
 	Delete(ctx context.Context, recordID string) error
 	// 更新状态
 	UpdateStatus(ctx context.Context, recordID string, status int) error
+	// 批量删除数据
+	BatchDelete(ctx context.Context, params schema.BatchParam) (*schema.BatchResult, error)
+	// 批量更新状态
+	BatchUpdateStatus(ctx context.Context, params schema.BatchParam, status int) (*schema.BatchResult, error)
+	// 查询回收站数据
+	QueryDeleted(ctx context.Context, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error)
+	// 恢复回收站数据
+	Restore(ctx context.Context, recordID string) (*schema.Demo, error)
+	// 彻底删除回收站数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
 }
@@ -2,6 +2,8 @@ package bll
 
 import (
 	"context"
+	"time"
+
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
 
@@ -17,4 +19,12 @@ type IMenu interface {
 	Update(ctx context.Context, recordID string, item schema.Menu) (*schema.Menu, error)
 	// 删除数据
 	Delete(ctx context.Context, recordID string) error
+	// 查询回收站数据
+	QueryDeleted(ctx context.Context, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error)
+	// 恢复回收站数据
+	Restore(ctx context.Context, recordID string) (*schema.Menu, error)
+	// 彻底删除回收站数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
 }
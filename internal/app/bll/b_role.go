@@ -2,6 +2,7 @@ package bll
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -20,4 +21,14 @@ type IRole interface {
 	Delete(ctx context.Context, recordID string) error
 	// 加载权限策略
 	LoadPolicy(ctx context.Context, item schema.Role) error
+	// 批量删除数据
+	BatchDelete(ctx context.Context, params schema.BatchParam) (*schema.BatchResult, error)
+	// 查询回收站数据
+	QueryDeleted(ctx context.Context, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error)
+	// 恢复回收站数据
+	Restore(ctx context.Context, recordID string) (*schema.Role, error)
+	// 彻底删除回收站数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
 }
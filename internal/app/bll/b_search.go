@@ -0,0 +1,15 @@
+package bll
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// ISearch 全文检索业务逻辑接口
+type ISearch interface {
+	// 检索数据(按数据类型分组，仅返回当前用户有查询权限的数据类型)
+	Search(ctx context.Context, params schema.SearchParam) ([]*schema.SearchGroup, error)
+	// 重建检索索引
+	Rebuild(ctx context.Context) error
+}
@@ -2,6 +2,7 @@ package bll
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -24,4 +25,20 @@ type IUser interface {
 	UpdateStatus(ctx context.Context, recordID string, status int) error
 	// 加载权限策略
 	LoadPolicy(ctx context.Context, item schema.User) error
-}
\ No newline at end of file
+	// 获取允许访问的IP地址范围
+	GetAllowedCIDRs(ctx context.Context, recordID string) ([][]string, error)
+	// 批量删除数据
+	BatchDelete(ctx context.Context, params schema.BatchParam) (*schema.BatchResult, error)
+	// 批量更新状态
+	BatchUpdateStatus(ctx context.Context, params schema.BatchParam, status int) (*schema.BatchResult, error)
+	// 批量分配角色
+	BatchAssignRoles(ctx context.Context, params schema.BatchUserRoleParam) (*schema.BatchResult, error)
+	// 查询回收站数据
+	QueryDeleted(ctx context.Context, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error)
+	// 恢复回收站数据
+	Restore(ctx context.Context, recordID string) (*schema.User, error)
+	// 彻底删除回收站数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
+}
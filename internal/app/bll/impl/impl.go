@@ -22,5 +22,7 @@ func Inject(container *dig.Container) error {
 	container.Provide(internal.NewMenu, dig.As(new(bll.IMenu)))
 	container.Provide(internal.NewRole, dig.As(new(bll.IRole)))
 	container.Provide(internal.NewUser, dig.As(new(bll.IUser)))
+	container.Provide(internal.NewSearch, dig.As(new(bll.ISearch)))
+	// generator:inject
 	return nil
 }
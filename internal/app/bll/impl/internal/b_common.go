@@ -2,12 +2,18 @@ package internal
 
 import (
 	"context"
-	"github.com/wanhello/iris-admin/internal/app/model"
 
 	"github.com/wanhello/iris-admin/internal/app/config"
 	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model"
 	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
+	"github.com/wanhello/iris-admin/pkg/ipfilter"
+	"github.com/wanhello/iris-admin/pkg/logger"
 	"github.com/wanhello/iris-admin/pkg/util"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // GetRootUser 获取root用户
@@ -26,6 +32,19 @@ func CheckIsRootUser(ctx context.Context, userID string) bool {
 	return GetRootUser().RecordID == userID
 }
 
+// 校验允许访问的IP地址范围(支持CIDR格式及单个IP地址)
+func checkAllowedCIDRs(cidrs []string) error {
+	if _, err := ipfilter.ParseCIDRs(cidrs); err != nil {
+		return errors.ErrInvalidCIDR
+	}
+	return nil
+}
+
+// 开始业务逻辑的跟踪单元(name为"业务对象.方法名")
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "bll."+name)
+}
+
 // TransFunc 定义事务执行函数
 type TransFunc func(context.Context) error
 
@@ -46,3 +65,71 @@ func ExecTrans(ctx context.Context, transModel model.ITrans, fn TransFunc) error
 	}
 	return transModel.Commit(ctx, trans)
 }
+
+// BatchFunc 定义批量操作中单个数据项的执行函数
+type BatchFunc func(ctx context.Context, recordID string) error
+
+// ExecBatch 执行批量操作
+// 默认所有数据项在同一个事务中执行，任一项失败则全部回滚并返回错误；
+// 如果指定了ContinueOnError，则每个数据项在独立的事务中执行，并返回每项的执行结果
+func ExecBatch(ctx context.Context, transModel model.ITrans, params schema.BatchParam, fn BatchFunc) (*schema.BatchResult, error) {
+	// 去除重复的记录ID
+	var recordIDs []string
+	exists := make(map[string]bool)
+	for _, recordID := range params.RecordIDs {
+		if !exists[recordID] {
+			exists[recordID] = true
+			recordIDs = append(recordIDs, recordID)
+		}
+	}
+
+	result := new(schema.BatchResult)
+
+	if !params.ContinueOnError {
+		err := ExecTrans(ctx, transModel, func(ctx context.Context) error {
+			for _, recordID := range recordIDs {
+				err := fn(ctx, recordID)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, recordID := range recordIDs {
+			result.AddSuccess(recordID)
+		}
+		return result, nil
+	}
+
+	for _, recordID := range recordIDs {
+		err := ExecTrans(ctx, transModel, func(ctx context.Context) error {
+			return fn(ctx, recordID)
+		})
+		if err != nil {
+			result.AddFailure(recordID, batchErrorMessage(ctx, err))
+			continue
+		}
+		result.AddSuccess(recordID)
+	}
+	return result, nil
+}
+
+// 获取批量操作中数据项的错误信息(未定义错误码的错误只记录日志)
+func batchErrorMessage(ctx context.Context, err error) string {
+	if code, ok := errors.FromErrorCode(err); ok {
+		return code.Message
+	}
+	logger.Errorf(ctx, "批量操作发生错误：%+v", err)
+	return "服务器发生错误"
+}
+
+// 使查询缓存失效(数据变更后立即执行，失效失败只记录日志，缓存数据过期后自动失效)
+func invalidateCache(ctx context.Context, m model.ICache, namespaces ...string) {
+	if err := m.Invalidate(ctx, namespaces...); err != nil {
+		logger.Errorf(ctx, "查询缓存失效发生错误：%s", err.Error())
+	}
+}
@@ -0,0 +1,386 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model"
+	icache "github.com/wanhello/iris-admin/internal/app/model/impl/cache"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/fulltext"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"github.com/casbin/casbin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/dig"
+)
+
+type testBll struct {
+	Enforcer *casbin.Enforcer
+	Demo     *Demo
+	User     *User
+	Search   *Search
+	Models   struct {
+		Search model.ISearch
+		Demo   model.IDemo
+		User   model.IUser
+		Role   model.IRole
+	}
+}
+
+// 使用内存sqlite数据库构建业务逻辑(只允许一个连接，事务内的查询必须使用事务上下文)
+func newTestBll(t *testing.T) *testBll {
+	db, err := gormplus.New(&gormplus.Config{
+		DBType:       "sqlite3",
+		DSN:          ":memory:",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := gorm.AutoMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	e := casbin.NewEnforcer("../../../../../configs/model.conf", false)
+
+	container := dig.New()
+	container.Provide(func() *gormplus.DB { return db })
+	if err := icache.Inject(container, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := gorm.Inject(container); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &testBll{Enforcer: e}
+	err = container.Invoke(func(trans model.ITrans, mDemo model.IDemo, mMenu model.IMenu, mUser model.IUser, mRole model.IRole) {
+		search := fulltext.NewSearch()
+		b.Demo = NewDemo(trans, mDemo, search)
+		b.User = NewUser(e, trans, mUser, mRole, search, icache.NewCache(nil))
+		b.Search = NewSearch(e, search, mDemo, mMenu, mRole, mUser)
+		b.Models.Search = search
+		b.Models.Demo = mDemo
+		b.Models.User = mUser
+		b.Models.Role = mRole
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (b *testBll) demoStatus(t *testing.T, recordID string) int {
+	item, err := b.Models.Demo.Get(context.Background(), recordID)
+	if err != nil {
+		t.Fatal(err)
+	} else if item == nil {
+		t.Fatalf("demo %s not found", recordID)
+	}
+	return item.Status
+}
+
+func (b *testBll) userRoleIDs(t *testing.T, recordID string) []string {
+	item, err := b.Models.User.Get(context.Background(), recordID, schema.UserQueryOptions{
+		IncludeRoles: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if item == nil {
+		t.Fatalf("user %s not found", recordID)
+	}
+	roleIDs := item.Roles.ToRoleIDs()
+	sort.Strings(roleIDs)
+	return roleIDs
+}
+
+func (b *testBll) policyRoles(t *testing.T, recordID string) []string {
+	roles, err := b.Enforcer.GetRolesForUser(recordID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExecBatch(t *testing.T) {
+	b := newTestBll(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"d1", "d2"} {
+		err := b.Models.Demo.Create(ctx, schema.Demo{RecordID: id, Code: id, Name: id, Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 默认全部成功或全部回滚
+	_, err := b.Demo.BatchUpdateStatus(ctx, schema.BatchParam{
+		RecordIDs: []string{"d1", "missing", "d2"},
+	}, 2)
+	if err != errors.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	for _, id := range []string{"d1", "d2"} {
+		if status := b.demoStatus(t, id); status != 1 {
+			t.Errorf("%s: expected rollback to status 1, got %d", id, status)
+		}
+	}
+
+	// 逐项执行时返回每项的执行结果，成功的数据项不受失败项影响
+	result, err := b.Demo.BatchUpdateStatus(ctx, schema.BatchParam{
+		RecordIDs:       []string{"d1", "missing", "d1", "d2"},
+		ContinueOnError: true,
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 || result.Success != 2 || result.Failure != 1 {
+		t.Fatalf("unexpected result counts: %+v", result)
+	}
+	expected := []schema.BatchResultItem{
+		{RecordID: "d1", Success: true},
+		{RecordID: "missing", Message: errors.ErrNotFound.Error()},
+		{RecordID: "d2", Success: true},
+	}
+	for i, item := range result.Items {
+		if *item != expected[i] {
+			t.Errorf("item %d: expected %+v, got %+v", i, expected[i], *item)
+		}
+	}
+	for _, id := range []string{"d1", "d2"} {
+		if status := b.demoStatus(t, id); status != 2 {
+			t.Errorf("%s: expected status 2, got %d", id, status)
+		}
+	}
+}
+
+func TestBatchAssignRoles(t *testing.T) {
+	b := newTestBll(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"r1", "r2"} {
+		err := b.Models.Role.Create(ctx, schema.Role{RecordID: id, Name: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	users := []schema.User{
+		{RecordID: "u1", UserName: "u1", RealName: "u1", Status: 1},
+		{RecordID: "u2", UserName: "u2", RealName: "u2", Status: 2},
+	}
+	for _, item := range users {
+		item.Roles = schema.UserRoles{{RoleID: "r1"}}
+		if err := b.Models.User.Create(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+		if item.Status == 1 {
+			if err := b.User.LoadPolicy(ctx, item); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// 无效的角色不执行任何变更
+	_, err := b.User.BatchAssignRoles(ctx, schema.BatchUserRoleParam{
+		BatchParam: schema.BatchParam{RecordIDs: []string{"u1"}},
+		RoleIDs:    []string{"r2", "missing"},
+	})
+	if err != errors.ErrInvalidRole {
+		t.Fatalf("expected ErrInvalidRole, got %v", err)
+	}
+	if roleIDs := b.userRoleIDs(t, "u1"); !equalStrings(roleIDs, []string{"r1"}) {
+		t.Errorf("expected roles [r1], got %v", roleIDs)
+	}
+
+	// 任一用户不存在时全部回滚，权限策略不变
+	_, err = b.User.BatchAssignRoles(ctx, schema.BatchUserRoleParam{
+		BatchParam: schema.BatchParam{RecordIDs: []string{"u1", "missing"}},
+		RoleIDs:    []string{"r2"},
+	})
+	if err != errors.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if roleIDs := b.userRoleIDs(t, "u1"); !equalStrings(roleIDs, []string{"r1"}) {
+		t.Errorf("expected roles [r1] after rollback, got %v", roleIDs)
+	}
+	if roles := b.policyRoles(t, "u1"); !equalStrings(roles, []string{"r1"}) {
+		t.Errorf("expected policy roles [r1] after rollback, got %v", roles)
+	}
+
+	// 在已有角色的基础上追加(不重复)，只为启用的用户加载权限策略
+	result, err := b.User.BatchAssignRoles(ctx, schema.BatchUserRoleParam{
+		BatchParam: schema.BatchParam{RecordIDs: []string{"u1", "u2", "missing"}, ContinueOnError: true},
+		RoleIDs:    []string{"r1", "r2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success != 2 || result.Failure != 1 {
+		t.Fatalf("unexpected result counts: %+v", result)
+	}
+	for _, id := range []string{"u1", "u2"} {
+		if roleIDs := b.userRoleIDs(t, id); !equalStrings(roleIDs, []string{"r1", "r2"}) {
+			t.Errorf("%s: expected roles [r1 r2], got %v", id, roleIDs)
+		}
+	}
+
+	if roles := b.policyRoles(t, "u1"); !equalStrings(roles, []string{"r1", "r2"}) {
+		t.Errorf("expected policy roles [r1 r2], got %v", roles)
+	}
+	if roles := b.policyRoles(t, "u2"); len(roles) != 0 {
+		t.Errorf("expected no policy roles for disabled user, got %v", roles)
+	}
+}
+
+// 业务逻辑返回错误时跟踪单元记录错误及状态
+func TestSpanStatus(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer func() {
+		otel.SetTracerProvider(prev)
+		tp.Shutdown(context.Background())
+	}()
+
+	b := newTestBll(t)
+	ctx := context.Background()
+	if err := b.Models.Demo.Create(ctx, schema.Demo{RecordID: "d1", Code: "d1", Name: "d1", Status: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Demo.Get(ctx, "d1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Demo.Get(ctx, "missing"); err != errors.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 || spans[0].Name() != "bll.Demo.Get" || spans[1].Name() != "bll.Demo.Get" {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+	if s := spans[0].Status(); s.Code != codes.Unset {
+		t.Errorf("expected unset status, got %+v", s)
+	}
+	if s := spans[1].Status(); s.Code != codes.Error || s.Description != errors.ErrNotFound.Error() {
+		t.Errorf("expected error status, got %+v", s)
+	}
+	if events := spans[1].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("expected recorded error, got %+v", events)
+	}
+}
+
+// 按游标分批重建检索索引
+func TestSearchRebuild(t *testing.T) {
+	defer func(size int) { searchRebuildBatchSize = size }(searchRebuildBatchSize)
+	searchRebuildBatchSize = 2
+
+	b := newTestBll(t)
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("d%d", i)
+		err := b.Models.Demo.Create(ctx, schema.Demo{RecordID: id, Code: id, Name: "widget " + id, Status: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	user := schema.User{RecordID: "u1", UserName: "widget_user", RealName: "u1", Status: 1}
+	if err := b.Models.User.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Search.Rebuild(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := b.Models.Search.Search(ctx, schema.SearchParam{Query: "widget", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(result[schema.SearchTypeDemo]); n != 5 {
+		t.Errorf("expected 5 demos in index, got %d", n)
+	}
+	if n := len(result[schema.SearchTypeUser]); n != 1 {
+		t.Errorf("expected 1 user in index, got %d", n)
+	}
+}
+
+// 角色限定的范围取并集，再与用户自身限定的范围取交集
+func TestGetAllowedCIDRs(t *testing.T) {
+	b := newTestBll(t)
+	ctx := context.Background()
+
+	roles := []schema.Role{
+		{RecordID: "r1", Name: "r1", AllowedCIDRs: []string{"10.1.0.0/16"}},
+		{RecordID: "r2", Name: "r2", AllowedCIDRs: []string{"10.2.0.0/16"}},
+		{RecordID: "r3", Name: "r3"},
+	}
+	for _, item := range roles {
+		if err := b.Models.Role.Create(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, item := range []struct {
+		cidrs    []string
+		roleIDs  []string
+		expected [][]string
+	}{
+		{nil, nil, nil},
+		{[]string{"10.1.1.0/24"}, nil, [][]string{{"10.1.1.0/24"}}},
+		{nil, []string{"r1", "r2"}, [][]string{{"10.1.0.0/16", "10.2.0.0/16"}}},
+		{[]string{"10.1.1.0/24"}, []string{"r1", "r2"}, [][]string{{"10.1.1.0/24"}, {"10.1.0.0/16", "10.2.0.0/16"}}},
+		{nil, []string{"r1", "r3"}, nil},
+		{[]string{"10.1.1.0/24"}, []string{"r1", "r3"}, [][]string{{"10.1.1.0/24"}}},
+	} {
+		user := schema.User{
+			RecordID:     util.MustUUID(),
+			UserName:     util.MustUUID(),
+			RealName:     "user",
+			Status:       1,
+			AllowedCIDRs: item.cidrs,
+		}
+		for _, roleID := range item.roleIDs {
+			user.Roles = append(user.Roles, &schema.UserRole{RoleID: roleID})
+		}
+		if err := b.Models.User.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := b.User.GetAllowedCIDRs(ctx, user.RecordID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, list := range result {
+			sort.Strings(list)
+		}
+		if fmt.Sprint(result) != fmt.Sprint(item.expected) {
+			t.Errorf("cidrs %v roles %v: expected %v, got %v", item.cidrs, item.roleIDs, item.expected, result)
+		}
+	}
+}
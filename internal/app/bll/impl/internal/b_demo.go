@@ -2,32 +2,49 @@ package internal
 
 import (
 	"context"
+	"time"
+
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model"
 	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
 	"github.com/wanhello/iris-admin/pkg/util"
 )
 
 
 // NewDemo 创建demo
-func NewDemo(mDemo model.IDemo) *Demo {
+func NewDemo(
+	trans model.ITrans,
+	mDemo model.IDemo,
+	mSearch model.ISearch,
+) *Demo {
 	return &Demo{
-		DemoModel: mDemo,
+		TransModel:  trans,
+		DemoModel:   mDemo,
+		SearchModel: mSearch,
 	}
 }
 
 // Demo 示例程序
 type Demo struct {
-	DemoModel model.IDemo
+	TransModel  model.ITrans
+	DemoModel   model.IDemo
+	SearchModel model.ISearch
 }
 
 // Query 查询数据
-func (a *Demo) Query(ctx context.Context, params schema.DemoQueryParam, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
+func (a *Demo) Query(ctx context.Context, params schema.DemoQueryParam, opts ...schema.DemoQueryOptions) (_ *schema.DemoQueryResult, err error) {
+	ctx, span := startSpan(ctx, "Demo.Query")
+	defer func() { tracing.End(span, err) }()
+
 	return a.DemoModel.Query(ctx, params, opts...)
 }
 
 // Get 查询指定数据
-func (a *Demo) Get(ctx context.Context, recordID string, opts ...schema.DemoQueryOptions) (*schema.Demo, error) {
+func (a *Demo) Get(ctx context.Context, recordID string, opts ...schema.DemoQueryOptions) (_ *schema.Demo, err error) {
+	ctx, span := startSpan(ctx, "Demo.Get")
+	defer func() { tracing.End(span, err) }()
+
 	item, err := a.DemoModel.Get(ctx, recordID, opts...)
 	if err != nil {
 		return nil, err
@@ -53,12 +70,21 @@ func (a *Demo) checkCode(ctx context.Context, code string) error {
 }
 
 func (a *Demo) getUpdate(ctx context.Context, recordID string) (*schema.Demo, error) {
-	return a.Get(ctx, recordID)
+	nitem, err := a.Get(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	indexSearch(ctx, a.SearchModel, newDemoSearchDocument(nitem))
+	return nitem, nil
 }
 
 // Create 创建数据
-func (a *Demo) Create(ctx context.Context, item schema.Demo) (*schema.Demo, error) {
-	err := a.checkCode(ctx, item.Code)
+func (a *Demo) Create(ctx context.Context, item schema.Demo) (_ *schema.Demo, err error) {
+	ctx, span := startSpan(ctx, "Demo.Create")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.checkCode(ctx, item.Code)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +98,10 @@ func (a *Demo) Create(ctx context.Context, item schema.Demo) (*schema.Demo, erro
 }
 
 // Update 更新数据
-func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) (*schema.Demo, error) {
+func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) (_ *schema.Demo, err error) {
+	ctx, span := startSpan(ctx, "Demo.Update")
+	defer func() { tracing.End(span, err) }()
+
 	oldItem, err := a.DemoModel.Get(ctx, recordID)
 	if err != nil {
 		return nil, err
@@ -85,6 +114,11 @@ func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) (*
 		}
 	}
 
+	// 未指定版本号时以当前数据的版本号为准(不做并发检查)
+	if item.Version == 0 {
+		item.Version = oldItem.Version
+	}
+
 	err = a.DemoModel.Update(ctx, recordID, item)
 	if err != nil {
 		return nil, err
@@ -93,7 +127,20 @@ func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) (*
 }
 
 // Delete 删除数据
-func (a *Demo) Delete(ctx context.Context, recordID string) error {
+func (a *Demo) Delete(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "Demo.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.delete(ctx, recordID)
+	if err != nil {
+		return err
+	}
+
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeDemo, recordID)
+	return nil
+}
+
+func (a *Demo) delete(ctx context.Context, recordID string) error {
 	oldItem, err := a.DemoModel.Get(ctx, recordID)
 	if err != nil {
 		return err
@@ -105,7 +152,10 @@ func (a *Demo) Delete(ctx context.Context, recordID string) error {
 }
 
 // UpdateStatus 更新状态
-func (a *Demo) UpdateStatus(ctx context.Context, recordID string, status int) error {
+func (a *Demo) UpdateStatus(ctx context.Context, recordID string, status int) (err error) {
+	ctx, span := startSpan(ctx, "Demo.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
 	oldItem, err := a.DemoModel.Get(ctx, recordID)
 	if err != nil {
 		return err
@@ -115,3 +165,83 @@ func (a *Demo) UpdateStatus(ctx context.Context, recordID string, status int) er
 
 	return a.DemoModel.UpdateStatus(ctx, recordID, status)
 }
+
+// BatchDelete 批量删除数据
+func (a *Demo) BatchDelete(ctx context.Context, params schema.BatchParam) (_ *schema.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "Demo.BatchDelete")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := ExecBatch(ctx, a.TransModel, params, a.delete)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeDemo, result.SuccessIDs()...)
+	return result, nil
+}
+
+// BatchUpdateStatus 批量更新状态
+func (a *Demo) BatchUpdateStatus(ctx context.Context, params schema.BatchParam, status int) (_ *schema.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "Demo.BatchUpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	return ExecBatch(ctx, a.TransModel, params, func(ctx context.Context, recordID string) error {
+		return a.UpdateStatus(ctx, recordID, status)
+	})
+}
+
+// QueryDeleted 查询回收站数据
+func (a *Demo) QueryDeleted(ctx context.Context, opts ...schema.DemoQueryOptions) (_ *schema.DemoQueryResult, err error) {
+	ctx, span := startSpan(ctx, "Demo.QueryDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	return a.DemoModel.QueryDeleted(ctx, opts...)
+}
+
+// Restore 恢复回收站数据
+func (a *Demo) Restore(ctx context.Context, recordID string) (_ *schema.Demo, err error) {
+	ctx, span := startSpan(ctx, "Demo.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.DemoModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return nil, err
+	} else if oldItem == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	// 编号已经被其他数据使用时不允许恢复
+	err = a.checkCode(ctx, oldItem.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.DemoModel.Restore(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	return a.getUpdate(ctx, recordID)
+}
+
+// Purge 彻底删除回收站数据
+func (a *Demo) Purge(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "Demo.Purge")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.DemoModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.DemoModel.Purge(ctx, recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Demo) PurgeBefore(ctx context.Context, deletedAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "Demo.PurgeBefore")
+	defer func() { tracing.End(span, err) }()
+
+	return a.DemoModel.PurgeBefore(ctx, deletedAt)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model"
 	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
 	"github.com/wanhello/iris-admin/pkg/auth"
 	"github.com/wanhello/iris-admin/pkg/util"
 
@@ -22,25 +23,31 @@ func NewLogin(
 	mUser model.IUser,
 	mRole model.IRole,
 	mMenu model.IMenu,
+	mCache model.ICache,
 ) *Login {
 	return &Login{
-		Auth:      a,
-		UserModel: mUser,
-		RoleModel: mRole,
-		MenuModel: mMenu,
+		Auth:       a,
+		UserModel:  mUser,
+		RoleModel:  mRole,
+		MenuModel:  mMenu,
+		CacheModel: mCache,
 	}
 }
 
 // Login 登录管理
 type Login struct {
-	UserModel model.IUser
-	RoleModel model.IRole
-	MenuModel model.IMenu
-	Auth      auth.Auther
+	UserModel  model.IUser
+	RoleModel  model.IRole
+	MenuModel  model.IMenu
+	CacheModel model.ICache
+	Auth       auth.Auther
 }
 
 // GetCaptcha 获取图形验证码信息
-func (a *Login) GetCaptcha(ctx context.Context, length int) (*schema.LoginCaptcha, error) {
+func (a *Login) GetCaptcha(ctx context.Context, length int) (_ *schema.LoginCaptcha, err error) {
+	_, span := startSpan(ctx, "Login.GetCaptcha")
+	defer func() { tracing.End(span, err) }()
+
 	captchaID := captcha.NewLen(length)
 	item := &schema.LoginCaptcha{
 		CaptchaID: captchaID,
@@ -49,8 +56,11 @@ func (a *Login) GetCaptcha(ctx context.Context, length int) (*schema.LoginCaptch
 }
 
 // ResCaptcha 生成并响应图形验证码
-func (a *Login) ResCaptcha(ctx context.Context, w http.ResponseWriter, captchaID string, width, height int) error {
-	err := captcha.WriteImage(w, captchaID, width, height)
+func (a *Login) ResCaptcha(ctx context.Context, w http.ResponseWriter, captchaID string, width, height int) (err error) {
+	_, span := startSpan(ctx, "Login.ResCaptcha")
+	defer func() { tracing.End(span, err) }()
+
+	err = captcha.WriteImage(w, captchaID, width, height)
 	if err != nil {
 		if err == captcha.ErrNotFound {
 			return errors.ErrNotFound
@@ -65,7 +75,10 @@ func (a *Login) ResCaptcha(ctx context.Context, w http.ResponseWriter, captchaID
 }
 
 // Verify 登录验证
-func (a *Login) Verify(ctx context.Context, userName, password string) (*schema.User, error) {
+func (a *Login) Verify(ctx context.Context, userName, password string) (_ *schema.User, err error) {
+	ctx, span := startSpan(ctx, "Login.Verify")
+	defer func() { tracing.End(span, err) }()
+
 	// 检查是否是超级用户
 	root := GetRootUser()
 	if userName == root.UserName && root.Password == password {
@@ -92,7 +105,10 @@ func (a *Login) Verify(ctx context.Context, userName, password string) (*schema.
 }
 
 // GenerateToken 生成令牌
-func (a *Login) GenerateToken(ctx context.Context, userID string) (*schema.LoginTokenInfo, error) {
+func (a *Login) GenerateToken(ctx context.Context, userID string) (_ *schema.LoginTokenInfo, err error) {
+	_, span := startSpan(ctx, "Login.GenerateToken")
+	defer func() { tracing.End(span, err) }()
+
 	tokenInfo, err := a.Auth.GenerateToken(userID)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -107,8 +123,11 @@ func (a *Login) GenerateToken(ctx context.Context, userID string) (*schema.Login
 }
 
 // DestroyToken 销毁令牌
-func (a *Login) DestroyToken(ctx context.Context, tokenString string) error {
-	err := a.Auth.DestroyToken(tokenString)
+func (a *Login) DestroyToken(ctx context.Context, tokenString string) (err error) {
+	_, span := startSpan(ctx, "Login.DestroyToken")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.Auth.DestroyToken(tokenString)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -128,7 +147,10 @@ func (a *Login) getAndCheckUser(ctx context.Context, userID string, opts ...sche
 }
 
 // GetLoginInfo 获取当前用户登录信息
-func (a *Login) GetLoginInfo(ctx context.Context, userID string) (*schema.UserLoginInfo, error) {
+func (a *Login) GetLoginInfo(ctx context.Context, userID string) (_ *schema.UserLoginInfo, err error) {
+	ctx, span := startSpan(ctx, "Login.GetLoginInfo")
+	defer func() { tracing.End(span, err) }()
+
 	if isRoot := CheckIsRootUser(ctx, userID); isRoot {
 		root := GetRootUser()
 		loginInfo := &schema.UserLoginInfo{
@@ -163,7 +185,10 @@ func (a *Login) GetLoginInfo(ctx context.Context, userID string) (*schema.UserLo
 }
 
 // QueryUserMenuTree 查询当前用户的权限菜单树
-func (a *Login) QueryUserMenuTree(ctx context.Context, userID string) ([]*schema.MenuTree, error) {
+func (a *Login) QueryUserMenuTree(ctx context.Context, userID string) (_ []*schema.MenuTree, err error) {
+	ctx, span := startSpan(ctx, "Login.QueryUserMenuTree")
+	defer func() { tracing.End(span, err) }()
+
 	isRoot := CheckIsRootUser(ctx, userID)
 	// 如果是root用户，则查询所有显示的菜单树
 	if isRoot {
@@ -229,7 +254,10 @@ func (a *Login) QueryUserMenuTree(ctx context.Context, userID string) ([]*schema
 }
 
 // UpdatePassword 更新当前用户登录密码
-func (a *Login) UpdatePassword(ctx context.Context, userID string, params schema.UpdatePasswordParam) error {
+func (a *Login) UpdatePassword(ctx context.Context, userID string, params schema.UpdatePasswordParam) (err error) {
+	ctx, span := startSpan(ctx, "Login.UpdatePassword")
+	defer func() { tracing.End(span, err) }()
+
 	if CheckIsRootUser(ctx, userID) {
 		return errors.ErrLoginNotAllowModifyPwd
 	}
@@ -242,6 +270,12 @@ func (a *Login) UpdatePassword(ctx context.Context, userID string, params schema
 	}
 
 	params.NewPassword = util.SHA1HashString(params.NewPassword)
-	return a.UserModel.UpdatePassword(ctx, userID, params.NewPassword)
+	err = a.UserModel.UpdatePassword(ctx, userID, params.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	invalidateCache(ctx, a.CacheModel, model.CacheUser)
+	return nil
 }
 
@@ -2,10 +2,12 @@ package internal
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model"
 	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
 	"github.com/wanhello/iris-admin/pkg/util"
 
 )
@@ -14,26 +16,38 @@ import (
 func NewMenu(
 	trans model.ITrans,
 	mMenu model.IMenu,
+	mSearch model.ISearch,
+	mCache model.ICache,
 ) *Menu {
 	return &Menu{
-		TransModel: trans,
-		MenuModel:  mMenu,
+		TransModel:  trans,
+		MenuModel:   mMenu,
+		SearchModel: mSearch,
+		CacheModel:  mCache,
 	}
 }
 
 // Menu 菜单管理
 type Menu struct {
-	TransModel model.ITrans
-	MenuModel  model.IMenu
+	TransModel  model.ITrans
+	MenuModel   model.IMenu
+	SearchModel model.ISearch
+	CacheModel  model.ICache
 }
 
 // Query 查询数据
-func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (_ *schema.MenuQueryResult, err error) {
+	ctx, span := startSpan(ctx, "Menu.Query")
+	defer func() { tracing.End(span, err) }()
+
 	return a.MenuModel.Query(ctx, params, opts...)
 }
 
 // Get 查询指定数据
-func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQueryOptions) (*schema.Menu, error) {
+func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQueryOptions) (_ *schema.Menu, err error) {
+	ctx, span := startSpan(ctx, "Menu.Get")
+	defer func() { tracing.End(span, err) }()
+
 	item, err := a.MenuModel.Get(ctx, recordID, opts...)
 	if err != nil {
 		return nil, err
@@ -71,14 +85,23 @@ func (a *Menu) getParentPath(ctx context.Context, parentID string) (string, erro
 }
 
 func (a *Menu) getUpdate(ctx context.Context, recordID string) (*schema.Menu, error) {
-	return a.Get(ctx, recordID, schema.MenuQueryOptions{
+	nitem, err := a.Get(ctx, recordID, schema.MenuQueryOptions{
 		IncludeActions:   true,
 		IncludeResources: true,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexSearch(ctx, a.SearchModel, newMenuSearchDocument(nitem))
+	return nitem, nil
 }
 
 // Create 创建数据
-func (a *Menu) Create(ctx context.Context, item schema.Menu) (*schema.Menu, error) {
+func (a *Menu) Create(ctx context.Context, item schema.Menu) (_ *schema.Menu, err error) {
+	ctx, span := startSpan(ctx, "Menu.Create")
+	defer func() { tracing.End(span, err) }()
+
 	parentPath, err := a.getParentPath(ctx, item.ParentID)
 	if err != nil {
 		return nil, err
@@ -90,12 +113,16 @@ func (a *Menu) Create(ctx context.Context, item schema.Menu) (*schema.Menu, erro
 	if err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheMenu)
 
 	return a.getUpdate(ctx, item.RecordID)
 }
 
 // Update 更新数据
-func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) (*schema.Menu, error) {
+func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) (_ *schema.Menu, err error) {
+	ctx, span := startSpan(ctx, "Menu.Update")
+	defer func() { tracing.End(span, err) }()
+
 	if recordID == item.ParentID {
 		return nil, errors.ErrInvalidParent
 	}
@@ -108,6 +135,11 @@ func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) (*
 	}
 	item.ParentPath = oldItem.ParentPath
 
+	// 未指定版本号时以当前数据的版本号为准(不做并发检查)
+	if item.Version == 0 {
+		item.Version = oldItem.Version
+	}
+
 	err = ExecTrans(ctx, a.TransModel, func(ctx context.Context) error {
 		// 如果父级更新，需要更新当前节点及节点下级的父级路径
 		if item.ParentID != oldItem.ParentID {
@@ -140,11 +172,15 @@ func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) (*
 	if err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheMenu)
 	return a.getUpdate(ctx, recordID)
 }
 
 // Delete 删除数据
-func (a *Menu) Delete(ctx context.Context, recordID string) error {
+func (a *Menu) Delete(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "Menu.Delete")
+	defer func() { tracing.End(span, err) }()
+
 	oldItem, err := a.MenuModel.Get(ctx, recordID)
 	if err != nil {
 		return err
@@ -161,5 +197,81 @@ func (a *Menu) Delete(ctx context.Context, recordID string) error {
 		return errors.ErrNotAllowDeleteWithChild
 	}
 
-	return a.MenuModel.Delete(ctx, recordID)
+	err = a.MenuModel.Delete(ctx, recordID)
+	if err != nil {
+		return err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheMenu)
+
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeMenu, recordID)
+	return nil
+}
+
+// QueryDeleted 查询回收站数据
+func (a *Menu) QueryDeleted(ctx context.Context, opts ...schema.MenuQueryOptions) (_ *schema.MenuQueryResult, err error) {
+	ctx, span := startSpan(ctx, "Menu.QueryDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	return a.MenuModel.QueryDeleted(ctx, opts...)
+}
+
+// Restore 恢复回收站数据
+func (a *Menu) Restore(ctx context.Context, recordID string) (_ *schema.Menu, err error) {
+	ctx, span := startSpan(ctx, "Menu.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.MenuModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return nil, err
+	} else if oldItem == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	// 父级节点不存在时不允许恢复
+	parentPath, err := a.getParentPath(ctx, oldItem.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ExecTrans(ctx, a.TransModel, func(ctx context.Context) error {
+		err := a.MenuModel.Restore(ctx, recordID)
+		if err != nil {
+			return err
+		}
+
+		// 删除期间父级节点被移动过，需要更新父级路径
+		if parentPath != oldItem.ParentPath {
+			return a.MenuModel.UpdateParentPath(ctx, recordID, parentPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheMenu)
+
+	return a.getUpdate(ctx, recordID)
+}
+
+// Purge 彻底删除回收站数据
+func (a *Menu) Purge(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "Menu.Purge")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.MenuModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.MenuModel.Purge(ctx, recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Menu) PurgeBefore(ctx context.Context, deletedAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "Menu.PurgeBefore")
+	defer func() { tracing.End(span, err) }()
+
+	return a.MenuModel.PurgeBefore(ctx, deletedAt)
 }
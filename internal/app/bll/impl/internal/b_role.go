@@ -2,10 +2,13 @@ package internal
 
 import (
 	"context"
+	"time"
+
 	"github.com/casbin/casbin"
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model"
 	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
 	"github.com/wanhello/iris-admin/pkg/util"
 
 )
@@ -14,33 +17,48 @@ import (
 // NewRole 创建角色管理实例
 func NewRole(
 	e *casbin.Enforcer,
+	trans model.ITrans,
 	mRole model.IRole,
 	mMenu model.IMenu,
 	mUser model.IUser,
+	mSearch model.ISearch,
+	mCache model.ICache,
 ) *Role {
 	return &Role{
-		Enforcer:  e,
-		RoleModel: mRole,
-		MenuModel: mMenu,
-		UserModel: mUser,
+		Enforcer:    e,
+		TransModel:  trans,
+		RoleModel:   mRole,
+		MenuModel:   mMenu,
+		UserModel:   mUser,
+		SearchModel: mSearch,
+		CacheModel:  mCache,
 	}
 }
 
 // Role 角色管理
 type Role struct {
-	Enforcer  *casbin.Enforcer
-	RoleModel model.IRole
-	MenuModel model.IMenu
-	UserModel model.IUser
+	Enforcer    *casbin.Enforcer
+	TransModel  model.ITrans
+	RoleModel   model.IRole
+	MenuModel   model.IMenu
+	UserModel   model.IUser
+	SearchModel model.ISearch
+	CacheModel  model.ICache
 }
 
 // Query 查询数据
-func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (_ *schema.RoleQueryResult, err error) {
+	ctx, span := startSpan(ctx, "Role.Query")
+	defer func() { tracing.End(span, err) }()
+
 	return a.RoleModel.Query(ctx, params, opts...)
 }
 
 // Get 查询指定数据
-func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQueryOptions) (*schema.Role, error) {
+func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQueryOptions) (_ *schema.Role, err error) {
+	ctx, span := startSpan(ctx, "Role.Get")
+	defer func() { tracing.End(span, err) }()
+
 	item, err := a.RoleModel.Get(ctx, recordID, opts...)
 	if err != nil {
 		return nil, err
@@ -77,12 +95,22 @@ func (a *Role) getUpdate(ctx context.Context, recordID string) (*schema.Role, er
 	if err != nil {
 		return nil, err
 	}
+
+	indexSearch(ctx, a.SearchModel, newRoleSearchDocument(nitem))
 	return nitem, nil
 }
 
 // Create 创建数据
-func (a *Role) Create(ctx context.Context, item schema.Role) (*schema.Role, error) {
-	err := a.checkName(ctx, item.Name)
+func (a *Role) Create(ctx context.Context, item schema.Role) (_ *schema.Role, err error) {
+	ctx, span := startSpan(ctx, "Role.Create")
+	defer func() { tracing.End(span, err) }()
+
+	err = checkAllowedCIDRs(item.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.checkName(ctx, item.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -92,12 +120,21 @@ func (a *Role) Create(ctx context.Context, item schema.Role) (*schema.Role, erro
 	if err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheRole)
 
 	return a.getUpdate(ctx, item.RecordID)
 }
 
 // Update 更新数据
-func (a *Role) Update(ctx context.Context, recordID string, item schema.Role) (*schema.Role, error) {
+func (a *Role) Update(ctx context.Context, recordID string, item schema.Role) (_ *schema.Role, err error) {
+	ctx, span := startSpan(ctx, "Role.Update")
+	defer func() { tracing.End(span, err) }()
+
+	err = checkAllowedCIDRs(item.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
 	oldItem, err := a.RoleModel.Get(ctx, recordID)
 	if err != nil {
 		return nil, err
@@ -110,16 +147,37 @@ func (a *Role) Update(ctx context.Context, recordID string, item schema.Role) (*
 		}
 	}
 
+	// 未指定版本号时以当前数据的版本号为准(不做并发检查)
+	if item.Version == 0 {
+		item.Version = oldItem.Version
+	}
+
 	err = a.RoleModel.Update(ctx, recordID, item)
 	if err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheRole)
 
 	return a.getUpdate(ctx, recordID)
 }
 
 // Delete 删除数据
-func (a *Role) Delete(ctx context.Context, recordID string) error {
+func (a *Role) Delete(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "Role.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.delete(ctx, recordID)
+	if err != nil {
+		return err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheRole)
+
+	a.Enforcer.DeletePermissionsForUser(recordID)
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeRole, recordID)
+	return nil
+}
+
+func (a *Role) delete(ctx context.Context, recordID string) error {
 	oldItem, err := a.RoleModel.Get(ctx, recordID)
 	if err != nil {
 		return err
@@ -139,17 +197,32 @@ func (a *Role) Delete(ctx context.Context, recordID string) error {
 		return errors.ErrResourceNotAllowDelete
 	}
 
-	err = a.RoleModel.Delete(ctx, recordID)
+	return a.RoleModel.Delete(ctx, recordID)
+}
+
+// BatchDelete 批量删除数据
+func (a *Role) BatchDelete(ctx context.Context, params schema.BatchParam) (_ *schema.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "Role.BatchDelete")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := ExecBatch(ctx, a.TransModel, params, a.delete)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheRole)
 
-	a.Enforcer.DeletePermissionsForUser(recordID)
-	return nil
+	for _, recordID := range result.SuccessIDs() {
+		a.Enforcer.DeletePermissionsForUser(recordID)
+	}
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeRole, result.SuccessIDs()...)
+	return result, nil
 }
 
 // LoadPolicy 加载角色权限策略
-func (a *Role) LoadPolicy(ctx context.Context, item schema.Role) error {
+func (a *Role) LoadPolicy(ctx context.Context, item schema.Role) (err error) {
+	ctx, span := startSpan(ctx, "Role.LoadPolicy")
+	defer func() { tracing.End(span, err) }()
+
 	result, err := a.MenuModel.Query(ctx, schema.MenuQueryParam{
 		RecordIDs: item.Menus.ToMenuIDs(),
 	}, schema.MenuQueryOptions{
@@ -181,3 +254,60 @@ func (a *Role) LoadPolicy(ctx context.Context, item schema.Role) error {
 	return nil
 }
 
+// QueryDeleted 查询回收站数据
+func (a *Role) QueryDeleted(ctx context.Context, opts ...schema.RoleQueryOptions) (_ *schema.RoleQueryResult, err error) {
+	ctx, span := startSpan(ctx, "Role.QueryDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	return a.RoleModel.QueryDeleted(ctx, opts...)
+}
+
+// Restore 恢复回收站数据
+func (a *Role) Restore(ctx context.Context, recordID string) (_ *schema.Role, err error) {
+	ctx, span := startSpan(ctx, "Role.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.RoleModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return nil, err
+	} else if oldItem == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	// 角色名称已经被其他数据使用时不允许恢复
+	err = a.checkName(ctx, oldItem.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.RoleModel.Restore(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheRole)
+
+	return a.getUpdate(ctx, recordID)
+}
+
+// Purge 彻底删除回收站数据
+func (a *Role) Purge(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "Role.Purge")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.RoleModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.RoleModel.Purge(ctx, recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Role) PurgeBefore(ctx context.Context, deletedAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "Role.PurgeBefore")
+	defer func() { tracing.End(span, err) }()
+
+	return a.RoleModel.PurgeBefore(ctx, deletedAt)
+}
@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
+	"github.com/wanhello/iris-admin/pkg/logger"
+
+	"github.com/casbin/casbin"
+)
+
+// 检索数据类型对应的列表查询资源(用于校验数据类型的访问权限)
+var searchTypeResources = map[string]string{
+	schema.SearchTypeDemo: "/api/v1/demos",
+	schema.SearchTypeMenu: "/api/v1/menus",
+	schema.SearchTypeRole: "/api/v1/roles",
+	schema.SearchTypeUser: "/api/v1/users",
+}
+
+// NewSearch 创建全文检索
+func NewSearch(
+	e *casbin.Enforcer,
+	mSearch model.ISearch,
+	mDemo model.IDemo,
+	mMenu model.IMenu,
+	mRole model.IRole,
+	mUser model.IUser,
+) *Search {
+	return &Search{
+		Enforcer:    e,
+		SearchModel: mSearch,
+		DemoModel:   mDemo,
+		MenuModel:   mMenu,
+		RoleModel:   mRole,
+		UserModel:   mUser,
+	}
+}
+
+// Search 全文检索
+type Search struct {
+	Enforcer    *casbin.Enforcer
+	SearchModel model.ISearch
+	DemoModel   model.IDemo
+	MenuModel   model.IMenu
+	RoleModel   model.IRole
+	UserModel   model.IUser
+}
+
+// Search 检索数据(仅检索当前用户有查询权限的数据类型)
+func (a *Search) Search(ctx context.Context, params schema.SearchParam) (_ []*schema.SearchGroup, err error) {
+	ctx, span := startSpan(ctx, "Search.Search")
+	defer func() { tracing.End(span, err) }()
+
+	types := params.Types
+	if len(types) == 0 {
+		types = schema.SearchTypes
+	}
+
+	params.Types = nil
+	for _, typ := range types {
+		if _, ok := searchTypeResources[typ]; !ok {
+			continue
+		}
+
+		allowed, err := a.checkPermission(ctx, typ)
+		if err != nil {
+			return nil, err
+		} else if allowed {
+			params.Types = append(params.Types, typ)
+		}
+	}
+
+	if len(params.Types) == 0 {
+		return []*schema.SearchGroup{}, nil
+	}
+
+	result, err := a.SearchModel.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*schema.SearchGroup, 0, len(result))
+	for _, typ := range params.Types {
+		if items, ok := result[typ]; ok {
+			groups = append(groups, &schema.SearchGroup{
+				Type:  typ,
+				Items: items,
+			})
+		}
+	}
+	return groups, nil
+}
+
+// 检查当前用户是否有数据类型的查询权限
+func (a *Search) checkPermission(ctx context.Context, typ string) (bool, error) {
+	if !config.GetGlobalConfig().EnableCasbin {
+		return true, nil
+	}
+
+	userID, _ := icontext.FromUserID(ctx)
+	if CheckIsRootUser(ctx, userID) {
+		return true, nil
+	}
+	return a.Enforcer.EnforceSafe(userID, searchTypeResources[typ], "GET")
+}
+
+// 重建检索索引时每批查询的数据条数
+var searchRebuildBatchSize = 500
+
+// Rebuild 重建检索索引(按游标分批查询各类数据并写入索引，避免一次加载全部数据)
+func (a *Search) Rebuild(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "Search.Rebuild")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.rebuild(ctx, func(cp *schema.CursorParam) ([]*schema.SearchDocument, *schema.PaginationResult, error) {
+		result, err := a.DemoModel.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{CursorParam: cp})
+		if err != nil {
+			return nil, nil, err
+		}
+		docs := make([]*schema.SearchDocument, len(result.Data))
+		for i, item := range result.Data {
+			docs[i] = newDemoSearchDocument(item)
+		}
+		return docs, result.PageResult, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = a.rebuild(ctx, func(cp *schema.CursorParam) ([]*schema.SearchDocument, *schema.PaginationResult, error) {
+		result, err := a.MenuModel.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{CursorParam: cp})
+		if err != nil {
+			return nil, nil, err
+		}
+		docs := make([]*schema.SearchDocument, len(result.Data))
+		for i, item := range result.Data {
+			docs[i] = newMenuSearchDocument(item)
+		}
+		return docs, result.PageResult, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = a.rebuild(ctx, func(cp *schema.CursorParam) ([]*schema.SearchDocument, *schema.PaginationResult, error) {
+		result, err := a.RoleModel.Query(ctx, schema.RoleQueryParam{}, schema.RoleQueryOptions{CursorParam: cp})
+		if err != nil {
+			return nil, nil, err
+		}
+		docs := make([]*schema.SearchDocument, len(result.Data))
+		for i, item := range result.Data {
+			docs[i] = newRoleSearchDocument(item)
+		}
+		return docs, result.PageResult, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.rebuild(ctx, func(cp *schema.CursorParam) ([]*schema.SearchDocument, *schema.PaginationResult, error) {
+		result, err := a.UserModel.Query(ctx, schema.UserQueryParam{}, schema.UserQueryOptions{CursorParam: cp})
+		if err != nil {
+			return nil, nil, err
+		}
+		docs := make([]*schema.SearchDocument, len(result.Data))
+		for i, item := range result.Data {
+			docs[i] = newUserSearchDocument(item)
+		}
+		return docs, result.PageResult, nil
+	})
+}
+
+// 按游标分批查询数据并写入检索索引，直到没有下一页
+func (a *Search) rebuild(ctx context.Context, query func(*schema.CursorParam) ([]*schema.SearchDocument, *schema.PaginationResult, error)) error {
+	cp := &schema.CursorParam{
+		Limit: searchRebuildBatchSize,
+		Total: schema.CursorTotalNone,
+	}
+	for {
+		docs, pr, err := query(cp)
+		if err != nil {
+			return err
+		}
+
+		if len(docs) > 0 {
+			err = a.SearchModel.Index(ctx, docs...)
+			if err != nil {
+				return err
+			}
+		}
+
+		if pr == nil || pr.Cursor == nil || pr.Cursor.Next == "" {
+			return nil
+		}
+		cp.Cursor = pr.Cursor.Next
+	}
+}
+
+// 更新检索索引(检索索引由业务数据派生，更新失败只记录日志)
+func indexSearch(ctx context.Context, m model.ISearch, docs ...*schema.SearchDocument) {
+	if err := m.Index(ctx, docs...); err != nil {
+		logger.Errorf(ctx, "更新检索索引发生错误：%s", err.Error())
+	}
+}
+
+// 删除检索索引
+func deleteSearch(ctx context.Context, m model.ISearch, typ string, recordIDs ...string) {
+	if len(recordIDs) == 0 {
+		return
+	}
+	if err := m.Delete(ctx, typ, recordIDs...); err != nil {
+		logger.Errorf(ctx, "删除检索索引发生错误：%s", err.Error())
+	}
+}
+
+func newDemoSearchDocument(item *schema.Demo) *schema.SearchDocument {
+	return &schema.SearchDocument{
+		Type:     schema.SearchTypeDemo,
+		RecordID: item.RecordID,
+		Title:    item.Name,
+		Contents: []string{item.Code, item.Memo},
+	}
+}
+
+func newMenuSearchDocument(item *schema.Menu) *schema.SearchDocument {
+	return &schema.SearchDocument{
+		Type:     schema.SearchTypeMenu,
+		RecordID: item.RecordID,
+		Title:    item.Name,
+		Contents: []string{item.Router},
+	}
+}
+
+func newRoleSearchDocument(item *schema.Role) *schema.SearchDocument {
+	return &schema.SearchDocument{
+		Type:     schema.SearchTypeRole,
+		RecordID: item.RecordID,
+		Title:    item.Name,
+		Contents: []string{item.Memo},
+	}
+}
+
+func newUserSearchDocument(item *schema.User) *schema.SearchDocument {
+	return &schema.SearchDocument{
+		Type:     schema.SearchTypeUser,
+		RecordID: item.RecordID,
+		Title:    item.UserName,
+		Contents: []string{item.RealName, item.Email, item.Phone},
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"github.com/wanhello/iris-admin/internal/app/model"
-
+	"github.com/wanhello/iris-admin/internal/app/tracing"
 )
 
 // NewTrans 创建角色管理实例
@@ -20,6 +20,9 @@ type Trans struct {
 }
 
 // Exec 执行事务
-func (a *Trans) Exec(ctx context.Context, fn func(context.Context) error) error {
+func (a *Trans) Exec(ctx context.Context, fn func(context.Context) error) (err error) {
+	ctx, span := startSpan(ctx, "Trans.Exec")
+	defer func() { tracing.End(span, err) }()
+
 	return ExecTrans(ctx, a.TransModel, fn)
 }
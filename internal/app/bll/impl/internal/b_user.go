@@ -2,10 +2,12 @@ package internal
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model"
 	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
 	"github.com/wanhello/iris-admin/pkg/util"
 
 	"github.com/casbin/casbin"
@@ -14,30 +16,45 @@ import (
 // NewUser 创建菜单管理实例
 func NewUser(
 	e *casbin.Enforcer,
+	trans model.ITrans,
 	mUser model.IUser,
 	mRole model.IRole,
+	mSearch model.ISearch,
+	mCache model.ICache,
 ) *User {
 	return &User{
-		Enforcer:  e,
-		UserModel: mUser,
-		RoleModel: mRole,
+		Enforcer:    e,
+		TransModel:  trans,
+		UserModel:   mUser,
+		RoleModel:   mRole,
+		SearchModel: mSearch,
+		CacheModel:  mCache,
 	}
 }
 
 // User 用户管理
 type User struct {
-	Enforcer  *casbin.Enforcer
-	UserModel model.IUser
-	RoleModel model.IRole
+	Enforcer    *casbin.Enforcer
+	TransModel  model.ITrans
+	UserModel   model.IUser
+	RoleModel   model.IRole
+	SearchModel model.ISearch
+	CacheModel  model.ICache
 }
 
 // Query 查询数据
-func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (_ *schema.UserQueryResult, err error) {
+	ctx, span := startSpan(ctx, "User.Query")
+	defer func() { tracing.End(span, err) }()
+
 	return a.UserModel.Query(ctx, params, opts...)
 }
 
 // QueryShow 查询显示项数据
-func (a *User) QueryShow(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserShowQueryResult, error) {
+func (a *User) QueryShow(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (_ *schema.UserShowQueryResult, err error) {
+	ctx, span := startSpan(ctx, "User.QueryShow")
+	defer func() { tracing.End(span, err) }()
+
 	userResult, err := a.UserModel.Query(ctx, params, opts...)
 	if err != nil {
 		return nil, err
@@ -63,7 +80,10 @@ func (a *User) QueryShow(ctx context.Context, params schema.UserQueryParam, opts
 }
 
 // Get 查询指定数据
-func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQueryOptions) (*schema.User, error) {
+func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQueryOptions) (_ *schema.User, err error) {
+	ctx, span := startSpan(ctx, "User.Get")
+	defer func() { tracing.End(span, err) }()
+
 	item, err := a.UserModel.Get(ctx, recordID, opts...)
 	if err != nil {
 		return nil, err
@@ -103,16 +123,26 @@ func (a *User) getUpdate(ctx context.Context, recordID string) (*schema.User, er
 	if err != nil {
 		return nil, err
 	}
+
+	indexSearch(ctx, a.SearchModel, newUserSearchDocument(nitem))
 	return nitem, nil
 }
 
 // Create 创建数据
-func (a *User) Create(ctx context.Context, item schema.User) (*schema.User, error) {
+func (a *User) Create(ctx context.Context, item schema.User) (_ *schema.User, err error) {
+	ctx, span := startSpan(ctx, "User.Create")
+	defer func() { tracing.End(span, err) }()
+
 	if item.Password == "" {
 		return nil, errors.ErrUserNotEmptyPwd
 	}
 
-	err := a.checkUserName(ctx, item.UserName)
+	err = checkAllowedCIDRs(item.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.checkUserName(ctx, item.UserName)
 	if err != nil {
 		return nil, err
 	}
@@ -123,12 +153,21 @@ func (a *User) Create(ctx context.Context, item schema.User) (*schema.User, erro
 	if err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
 
 	return a.getUpdate(ctx, item.RecordID)
 }
 
 // Update 更新数据
-func (a *User) Update(ctx context.Context, recordID string, item schema.User) (*schema.User, error) {
+func (a *User) Update(ctx context.Context, recordID string, item schema.User) (_ *schema.User, err error) {
+	ctx, span := startSpan(ctx, "User.Update")
+	defer func() { tracing.End(span, err) }()
+
+	err = checkAllowedCIDRs(item.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
 	oldItem, err := a.UserModel.Get(ctx, recordID)
 	if err != nil {
 		return nil, err
@@ -141,6 +180,11 @@ func (a *User) Update(ctx context.Context, recordID string, item schema.User) (*
 		}
 	}
 
+	// 未指定版本号时以当前数据的版本号为准(不做并发检查)
+	if item.Version == 0 {
+		item.Version = oldItem.Version
+	}
+
 	if item.Password != "" {
 		item.Password = util.SHA1HashString(item.Password)
 	}
@@ -149,12 +193,27 @@ func (a *User) Update(ctx context.Context, recordID string, item schema.User) (*
 	if err != nil {
 		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
 
 	return a.getUpdate(ctx, recordID)
 }
 
 // Delete 删除数据
-func (a *User) Delete(ctx context.Context, recordID string) error {
+func (a *User) Delete(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "User.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.delete(ctx, recordID)
+	if err != nil {
+		return err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
+	a.Enforcer.DeleteUser(recordID)
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeUser, recordID)
+	return nil
+}
+
+func (a *User) delete(ctx context.Context, recordID string) error {
 	oldItem, err := a.UserModel.Get(ctx, recordID)
 	if err != nil {
 		return err
@@ -162,47 +221,299 @@ func (a *User) Delete(ctx context.Context, recordID string) error {
 		return errors.ErrNotFound
 	}
 
-	err = a.UserModel.Delete(ctx, recordID)
+	return a.UserModel.Delete(ctx, recordID)
+}
+
+// UpdateStatus 更新状态
+func (a *User) UpdateStatus(ctx context.Context, recordID string, status int) (err error) {
+	ctx, span := startSpan(ctx, "User.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	err = a.updateStatus(ctx, recordID, status)
 	if err != nil {
 		return err
 	}
-	a.Enforcer.DeleteUser(recordID)
-	return nil
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
+
+	return a.loadStatusPolicy(ctx, status, recordID)
 }
 
-// UpdateStatus 更新状态
-func (a *User) UpdateStatus(ctx context.Context, recordID string, status int) error {
-	oldItem, err := a.UserModel.Get(ctx, recordID, schema.UserQueryOptions{
-		IncludeRoles: true,
-	})
+func (a *User) updateStatus(ctx context.Context, recordID string, status int) error {
+	oldItem, err := a.UserModel.Get(ctx, recordID)
 	if err != nil {
 		return err
 	} else if oldItem == nil {
 		return errors.ErrNotFound
 	}
 
-	err = a.UserModel.UpdateStatus(ctx, recordID, status)
+	return a.UserModel.UpdateStatus(ctx, recordID, status)
+}
+
+// 根据用户状态加载权限策略(停用则删除用户的权限策略)
+func (a *User) loadStatusPolicy(ctx context.Context, status int, recordIDs ...string) error {
+	if status == 2 {
+		for _, recordID := range recordIDs {
+			a.Enforcer.DeleteUser(recordID)
+		}
+		return nil
+	}
+	return a.loadPolicies(ctx, recordIDs...)
+}
+
+// 加载指定用户(仅启用状态)的权限策略
+func (a *User) loadPolicies(ctx context.Context, recordIDs ...string) error {
+	if len(recordIDs) == 0 {
+		return nil
+	}
+
+	result, err := a.UserModel.Query(ctx, schema.UserQueryParam{
+		RecordIDs: recordIDs,
+		Status:    1,
+	}, schema.UserQueryOptions{
+		IncludeRoles: true,
+	})
 	if err != nil {
 		return err
 	}
 
-	if status == 2 {
+	for _, item := range result.Data {
+		err := a.LoadPolicy(ctx, *item)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchDelete 批量删除数据
+func (a *User) BatchDelete(ctx context.Context, params schema.BatchParam) (_ *schema.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "User.BatchDelete")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := ExecBatch(ctx, a.TransModel, params, a.delete)
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
+
+	for _, recordID := range result.SuccessIDs() {
 		a.Enforcer.DeleteUser(recordID)
-	} else {
-		err = a.LoadPolicy(ctx, *oldItem)
+	}
+	deleteSearch(ctx, a.SearchModel, schema.SearchTypeUser, result.SuccessIDs()...)
+	return result, nil
+}
+
+// BatchUpdateStatus 批量更新状态
+func (a *User) BatchUpdateStatus(ctx context.Context, params schema.BatchParam, status int) (_ *schema.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "User.BatchUpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := ExecBatch(ctx, a.TransModel, params, func(ctx context.Context, recordID string) error {
+		return a.updateStatus(ctx, recordID, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
+
+	err = a.loadStatusPolicy(ctx, status, result.SuccessIDs()...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BatchAssignRoles 批量分配角色(在用户已有角色的基础上追加)
+func (a *User) BatchAssignRoles(ctx context.Context, params schema.BatchUserRoleParam) (_ *schema.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "User.BatchAssignRoles")
+	defer func() { tracing.End(span, err) }()
+
+	roleResult, err := a.RoleModel.Query(ctx, schema.RoleQueryParam{
+		RecordIDs: params.RoleIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roleMap := roleResult.Data.ToMap()
+	for _, roleID := range params.RoleIDs {
+		if _, ok := roleMap[roleID]; !ok {
+			return nil, errors.ErrInvalidRole
+		}
+	}
+
+	result, err := ExecBatch(ctx, a.TransModel, params.BatchParam, func(ctx context.Context, recordID string) error {
+		item, err := a.UserModel.Get(ctx, recordID, schema.UserQueryOptions{
+			IncludeRoles: true,
+		})
 		if err != nil {
 			return err
+		} else if item == nil {
+			return errors.ErrNotFound
+		}
+
+		roleIDs := item.Roles.ToRoleIDs()
+		for _, roleID := range params.RoleIDs {
+			exists := false
+			for _, id := range roleIDs {
+				if id == roleID {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				item.Roles = append(item.Roles, &schema.UserRole{RoleID: roleID})
+				roleIDs = append(roleIDs, roleID)
+			}
 		}
+
+		// 不更新密码
+		item.Password = ""
+		return a.UserModel.Update(ctx, recordID, *item)
+	})
+	if err != nil {
+		return nil, err
 	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
 
-	return nil
+	err = a.loadPolicies(ctx, result.SuccessIDs()...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // LoadPolicy 加载用户权限策略
-func (a *User) LoadPolicy(ctx context.Context, item schema.User) error {
+func (a *User) LoadPolicy(ctx context.Context, item schema.User) (err error) {
+	_, span := startSpan(ctx, "User.LoadPolicy")
+	defer func() { tracing.End(span, err) }()
+
 	a.Enforcer.DeleteRolesForUser(item.RecordID)
 	for _, roleID := range item.Roles.ToRoleIDs() {
 		a.Enforcer.AddRoleForUser(item.RecordID, roleID)
 	}
 	return nil
 }
+
+// GetAllowedCIDRs 获取允许访问的IP地址范围，root用户不限制
+// 返回用户限定的范围及其角色限定范围的并集(访问IP需要同时满足返回的每一个列表，未限定的不返回)：
+// 用户通过任一角色允许的范围访问即可，存在未限定范围的角色时不按角色限制；用户自身限定的范围始终需要满足
+func (a *User) GetAllowedCIDRs(ctx context.Context, recordID string) (_ [][]string, err error) {
+	ctx, span := startSpan(ctx, "User.GetAllowedCIDRs")
+	defer func() { tracing.End(span, err) }()
+
+	if CheckIsRootUser(ctx, recordID) {
+		return nil, nil
+	}
+
+	item, err := a.UserModel.Get(ctx, recordID, schema.UserQueryOptions{
+		IncludeRoles: true,
+	})
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, errors.ErrInvalidUser
+	}
+
+	var result [][]string
+	if len(item.AllowedCIDRs) > 0 {
+		result = append(result, item.AllowedCIDRs)
+	}
+
+	roleIDs := item.Roles.ToRoleIDs()
+	if len(roleIDs) == 0 {
+		return result, nil
+	}
+
+	roleResult, err := a.RoleModel.Query(ctx, schema.RoleQueryParam{
+		RecordIDs: roleIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var roleCIDRs []string
+	for _, role := range roleResult.Data {
+		if len(role.AllowedCIDRs) == 0 {
+			return result, nil
+		}
+		roleCIDRs = append(roleCIDRs, role.AllowedCIDRs...)
+	}
+	if len(roleCIDRs) > 0 {
+		result = append(result, roleCIDRs)
+	}
+	return result, nil
+}
+
+// QueryDeleted 查询回收站数据
+func (a *User) QueryDeleted(ctx context.Context, opts ...schema.UserQueryOptions) (_ *schema.UserQueryResult, err error) {
+	ctx, span := startSpan(ctx, "User.QueryDeleted")
+	defer func() { tracing.End(span, err) }()
+
+	return a.UserModel.QueryDeleted(ctx, opts...)
+}
+
+// Restore 恢复回收站数据
+func (a *User) Restore(ctx context.Context, recordID string) (_ *schema.User, err error) {
+	ctx, span := startSpan(ctx, "User.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.UserModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return nil, err
+	} else if oldItem == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	// 用户名已经被其他数据使用时不允许恢复
+	err = a.checkUserName(ctx, oldItem.UserName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.UserModel.Restore(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(ctx, a.CacheModel, model.CacheUser, model.CacheRole)
+
+	nitem, err := a.Get(ctx, recordID, schema.UserQueryOptions{
+		IncludeRoles: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexSearch(ctx, a.SearchModel, newUserSearchDocument(nitem))
+
+	// 停用的用户不加载权限策略
+	if nitem.Status == 1 {
+		err = a.LoadPolicy(ctx, *nitem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nitem, nil
+}
+
+// Purge 彻底删除回收站数据
+func (a *User) Purge(ctx context.Context, recordID string) (err error) {
+	ctx, span := startSpan(ctx, "User.Purge")
+	defer func() { tracing.End(span, err) }()
+
+	oldItem, err := a.UserModel.GetDeleted(ctx, recordID)
+	if err != nil {
+		return err
+	} else if oldItem == nil {
+		return errors.ErrNotFound
+	}
+
+	return a.UserModel.Purge(ctx, recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *User) PurgeBefore(ctx context.Context, deletedAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "User.PurgeBefore")
+	defer func() { tracing.End(span, err) }()
+
+	return a.UserModel.PurgeBefore(ctx, deletedAt)
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	icache "github.com/wanhello/iris-admin/internal/app/model/impl/cache"
+	"github.com/wanhello/iris-admin/pkg/cache"
+	"github.com/wanhello/iris-admin/pkg/logger"
+	"github.com/wanhello/iris-admin/pkg/redisplus"
+
+	"go.uber.org/dig"
+)
+
+// InitCache 初始化查询缓存(未启用时不装饰存储)
+func InitCache(container *dig.Container) (func(), error) {
+	cfg := config.GetGlobalConfig().Cache
+	if !cfg.Enable {
+		return nil, icache.Inject(container, nil)
+	}
+
+	var store cache.Store
+	switch cfg.Store {
+	case "redis":
+		err := container.Invoke(func(cli *redisplus.Client) {
+			store = cache.NewRedisStore(cli.WithPrefix(cfg.RedisPrefix))
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		store = cache.NewMemoryStore(cfg.MemorySize)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := cache.New(store,
+		cache.SetExpiration(time.Duration(cfg.Expiration)*time.Second),
+		cache.SetErrorHandler(func(err error) {
+			logger.Warnf(ctx, "查询缓存发生错误：%s", err.Error())
+		}),
+	)
+
+	err := icache.Inject(container, c)
+	if err != nil {
+		cancel()
+		c.Close()
+		return nil, err
+	}
+
+	if cfg.StatsInterval > 0 {
+		go logCacheStats(ctx, c, time.Duration(cfg.StatsInterval)*time.Second)
+	}
+
+	return func() {
+		cancel()
+		c.Close()
+	}, nil
+}
+
+// 定时输出查询缓存的命中统计
+func logCacheStats(ctx context.Context, c *cache.Cache, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, s := range c.Stats() {
+			logger.Printf(ctx, "查询缓存[%s]：命中%d次，未命中%d次，失效%d次，命中率%.2f%%",
+				s.Namespace, s.Hits, s.Misses, s.Invalidations, s.HitRate*100)
+		}
+	}
+}
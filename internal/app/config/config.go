@@ -2,67 +2,81 @@ package config
 
 import (
 	"fmt"
-	"github.com/BurntSushi/toml"
+	"strings"
+	"sync/atomic"
 )
 
 var (
-	global *Config
+	// 全局配置(重新加载时整体替换)
+	global atomic.Value
 )
 
-// LoadGlobalConfig 加载全局配置
-func LoadGlobalConfig(fpath string) error {
-	c, err := ParseConfig(fpath)
+// LoadGlobalConfig 加载全局配置(多个配置文件按顺序合并)
+func LoadGlobalConfig(fpaths ...string) error {
+	c, err := ParseConfig(fpaths...)
 	if err != nil {
 		return err
 	}
-	global = c
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	global.Store(c)
 	return nil
 }
 
 // GetGlobalConfig 获取全局配置
 func GetGlobalConfig() *Config {
-	if global == nil {
+	c, _ := global.Load().(*Config)
+	if c == nil {
 		return &Config{}
 	}
-	return global
+	return c
 }
 
-// ParseConfig 解析配置文件
-func ParseConfig(fpath string) (*Config, error) {
-	var c Config
-	_, err := toml.DecodeFile(fpath, &c)
-	if err != nil {
-		return nil, err
-	}
-	return &c, nil
-}
-
-
 // Config 配置参数
 type Config struct {
-	RunMode         string      `toml:"run_mode"`
-	CasbinModelConf string      `toml:"casbin_model_conf"`
-	WWW             string      `toml:"www"`
-	Swagger         string      `toml:"swagger"`
-	Store           string      `toml:"store"`
-	AllowInitMenu   bool        `toml:"allow_init_menu"`
-	EnableCasbin    bool        `toml:"enable_casbin"`
-	Log             Log         `toml:"log"`
-	LogGormHook     LogGormHook `toml:"log_gorm_hook"`
-	Root            Root        `toml:"root"`
-	JWTAuth         JWTAuth     `toml:"jwt_auth"`
-	HTTP            HTTP        `toml:"http"`
-	Monitor         Monitor     `toml:"monitor"`
-	Captcha         Captcha     `toml:"captcha"`
-	RateLimiter     RateLimiter `toml:"rate_limiter"`
-	CORS            CORS        `toml:"cors"`
-	Redis           Redis       `toml:"redis"`
-	Gorm            Gorm        `toml:"gorm"`
-	MySQL           MySQL       `toml:"mysql"`
-	Postgres        Postgres    `toml:"postgres"`
-	Sqlite3         Sqlite3     `toml:"sqlite3"`
-}
+	RunMode         string          `toml:"run_mode"`
+	CasbinModelConf string          `toml:"casbin_model_conf"`
+	WWW             string          `toml:"www"`
+	Swagger         string          `toml:"swagger"`
+	Store           string          `toml:"store"`
+	AllowInitMenu   bool            `toml:"allow_init_menu"`
+	EnableCasbin    bool            `toml:"enable_casbin"`
+	MasterKeyFile   string          `toml:"master_key_file"`
+	Log             Log             `toml:"log"`
+	LogGormHook     LogGormHook     `toml:"log_gorm_hook"`
+	LogFile         LogFile         `toml:"log_file"`
+	LogSyslogHook   LogSyslogHook   `toml:"log_syslog_hook"`
+	LogHTTPHook     LogHTTPHook     `toml:"log_http_hook"`
+	AccessLog       AccessLog       `toml:"access_log"`
+	Root            Root            `toml:"root"`
+	JWTAuth         JWTAuth         `toml:"jwt_auth"`
+	AuthCookie      AuthCookie      `toml:"auth_cookie"`
+	HTTP            HTTP            `toml:"http"`
+	Monitor         Monitor         `toml:"monitor"`
+	Metrics         Metrics         `toml:"metrics"`
+	Tracing         Tracing         `toml:"tracing"`
+	Health          Health          `toml:"health"`
+	Reload          Reload          `toml:"reload"`
+	Captcha         Captcha         `toml:"captcha"`
+	RateLimiter     RateLimiter     `toml:"rate_limiter"`
+	IPAccess        IPAccess        `toml:"ip_access"`
+	SecurityHeaders SecurityHeaders `toml:"security_headers"`
+	CORS            CORS            `toml:"cors"`
+	Recycle         Recycle         `toml:"recycle"`
+	Search          Search          `toml:"search"`
+	Cache           Cache           `toml:"cache"`
+	Redis           Redis           `toml:"redis"`
+	Gorm            Gorm            `toml:"gorm"`
+	MySQL           MySQL           `toml:"mysql"`
+	Postgres        Postgres        `toml:"postgres"`
+	Sqlite3         Sqlite3         `toml:"sqlite3"`
+	Mongo           Mongo           `toml:"mongo"`
+	Bolt            Bolt            `toml:"bolt"`
 
+	// 从引用中解析的敏感信息(需要在日志及输出中隐藏)
+	secrets []string
+}
 
 // Log 日志配置参数
 type Log struct {
@@ -85,6 +99,67 @@ type LogGormHook struct {
 	Table        string `toml:"table"`
 }
 
+// LogFile 日志文件切割配置
+type LogFile struct {
+	MaxSize    int    `toml:"max_size"`
+	MaxBackups int    `toml:"max_backups"`
+	MaxAge     int    `toml:"max_age"`
+	Compress   bool   `toml:"compress"`
+	LocalTime  bool   `toml:"local_time"`
+	RotateTime string `toml:"rotate_time"`
+}
+
+// LogSyslogHook 日志syslog钩子配置
+type LogSyslogHook struct {
+	Network  string `toml:"network"`
+	Addr     string `toml:"addr"`
+	Tag      string `toml:"tag"`
+	Facility string `toml:"facility"`
+}
+
+// LogHTTPHook 日志http钩子配置(elasticsearch/loki)
+type LogHTTPHook struct {
+	URL           string   `toml:"url"`
+	Format        string   `toml:"format"`
+	Index         string   `toml:"index"`
+	Labels        []string `toml:"labels"`
+	Username      string   `toml:"username"`
+	Password      string   `toml:"password"`
+	BatchSize     int      `toml:"batch_size"`
+	FlushInterval int      `toml:"flush_interval"`
+	MaxBuffer     int      `toml:"max_buffer"`
+	MaxRetries    int      `toml:"max_retries"`
+	RetryWait     int      `toml:"retry_wait"`
+	Timeout       int      `toml:"timeout"`
+}
+
+// LabelMap 获取loki日志流标签(配置格式为key=value)
+func (a LogHTTPHook) LabelMap() map[string]string {
+	m := make(map[string]string, len(a.Labels))
+	for _, label := range a.Labels {
+		if i := strings.Index(label, "="); i > 0 {
+			m[strings.TrimSpace(label[:i])] = strings.TrimSpace(label[i+1:])
+		}
+	}
+	return m
+}
+
+// AccessLog 访问日志配置参数
+type AccessLog struct {
+	RedactHeaders []string            `toml:"redact_headers"`
+	RedactFields  []string            `toml:"redact_fields"`
+	MaxBodySize   int                 `toml:"max_body_size"`
+	ResponseBody  bool                `toml:"response_body"`
+	Sampling      []AccessLogSampling `toml:"sampling"`
+}
+
+// AccessLogSampling 访问日志采样规则
+type AccessLogSampling struct {
+	Method string  `toml:"method"`
+	Path   string  `toml:"path"`
+	Rate   float64 `toml:"rate"`
+}
+
 // Root root用户
 type Root struct {
 	UserName string `toml:"user_name"`
@@ -103,11 +178,24 @@ type JWTAuth struct {
 	RedisPrefix   string `toml:"redis_prefix"`
 }
 
+// AuthCookie Cookie令牌配置参数
+type AuthCookie struct {
+	Enable         bool   `toml:"enable"`
+	Name           string `toml:"name"`
+	Domain         string `toml:"domain"`
+	Path           string `toml:"path"`
+	Secure         bool   `toml:"secure"`
+	SameSite       string `toml:"same_site"`
+	CSRFCookieName string `toml:"csrf_cookie_name"`
+	CSRFHeaderName string `toml:"csrf_header_name"`
+}
+
 // HTTP http配置参数
 type HTTP struct {
-	Host            string `toml:"host"`
-	Port            int    `toml:"port"`
-	ShutdownTimeout int    `toml:"shutdown_timeout"`
+	Host            string   `toml:"host"`
+	Port            int      `toml:"port"`
+	ShutdownTimeout int      `toml:"shutdown_timeout"`
+	TrustedProxies  []string `toml:"trusted_proxies"`
 }
 
 // Monitor 监控配置参数
@@ -117,6 +205,37 @@ type Monitor struct {
 	ConfigDir string `toml:"config_dir"`
 }
 
+// Metrics prometheus指标配置参数
+type Metrics struct {
+	Enable bool   `toml:"enable"`
+	Addr   string `toml:"addr"`
+	Path   string `toml:"path"`
+}
+
+// Tracing 链路追踪配置参数
+type Tracing struct {
+	Enable      bool    `toml:"enable"`
+	ServiceName string  `toml:"service_name"`
+	Exporter    string  `toml:"exporter"`
+	Endpoint    string  `toml:"endpoint"`
+	URLPath     string  `toml:"url_path"`
+	Insecure    bool    `toml:"insecure"`
+	OutputFile  string  `toml:"output_file"`
+	SampleRatio float64 `toml:"sample_ratio"`
+}
+
+// Health 健康检查配置参数
+type Health struct {
+	Timeout       int `toml:"timeout"`
+	ShutdownDelay int `toml:"shutdown_delay"`
+}
+
+// Reload 配置热加载参数
+type Reload struct {
+	Watch         bool `toml:"watch"`
+	WatchInterval int  `toml:"watch_interval"`
+}
+
 // Captcha 图形验证码配置参数
 type Captcha struct {
 	Store       string `toml:"store"`
@@ -129,9 +248,54 @@ type Captcha struct {
 
 // RateLimiter 请求频率限制配置参数
 type RateLimiter struct {
-	Enable  bool  `toml:"enable"`
-	Count   int64 `toml:"count"`
-	RedisDB int   `toml:"redis_db"`
+	Enable      bool                `toml:"enable"`
+	Store       string              `toml:"store"`
+	Algorithm   string              `toml:"algorithm"`
+	Count       int64               `toml:"count"`
+	Period      int                 `toml:"period"`
+	Burst       int64               `toml:"burst"`
+	RedisPrefix string              `toml:"redis_prefix"`
+	RedisRetry  int                 `toml:"redis_retry"`
+	Policies    []RateLimiterPolicy `toml:"policies"`
+}
+
+// RateLimiterPolicy 请求频率限制策略
+type RateLimiterPolicy struct {
+	Name      string   `toml:"name"`
+	Method    string   `toml:"method"`
+	Path      string   `toml:"path"`
+	Roles     []string `toml:"roles"`
+	Key       string   `toml:"key"`
+	Count     int64    `toml:"count"`
+	Period    int      `toml:"period"`
+	Burst     int64    `toml:"burst"`
+	Sensitive bool     `toml:"sensitive"`
+}
+
+// IPAccess IP访问控制配置参数
+type IPAccess struct {
+	Enable          bool     `toml:"enable"`
+	Allow           []string `toml:"allow"`
+	Deny            []string `toml:"deny"`
+	GeoDatabase     string   `toml:"geo_database"`
+	GeoAllow        []string `toml:"geo_allow"`
+	GeoDeny         []string `toml:"geo_deny"`
+	GeoAllowUnknown bool     `toml:"geo_allow_unknown"`
+	CacheExpiration int      `toml:"cache_expiration"`
+}
+
+// SecurityHeaders 安全响应头配置参数
+type SecurityHeaders struct {
+	Enable                  bool   `toml:"enable"`
+	ContentSecurityPolicy   string `toml:"content_security_policy"`
+	HSTSMaxAge              int    `toml:"hsts_max_age"`
+	HSTSIncludeSubdomains   bool   `toml:"hsts_include_subdomains"`
+	HSTSPreload             bool   `toml:"hsts_preload"`
+	FrameOptions            string `toml:"frame_options"`
+	ContentTypeNosniff      bool   `toml:"content_type_nosniff"`
+	ReferrerPolicy          string `toml:"referrer_policy"`
+	PermissionsPolicy       string `toml:"permissions_policy"`
+	CrossOriginOpenerPolicy string `toml:"cross_origin_opener_policy"`
 }
 
 // CORS 跨域请求配置参数
@@ -144,10 +308,34 @@ type CORS struct {
 	MaxAge           int      `toml:"max_age"`
 }
 
+// Recycle 回收站配置参数
+type Recycle struct {
+	Enable        bool `toml:"enable"`
+	RetentionDays int  `toml:"retention_days"`
+	Interval      int  `toml:"interval"`
+}
+
+// Search 全文检索配置参数
+type Search struct {
+	Engine string `toml:"engine"`
+	Limit  int    `toml:"limit"`
+}
+
+// Cache 查询缓存配置参数
+type Cache struct {
+	Enable        bool   `toml:"enable"`
+	Store         string `toml:"store"`
+	Expiration    int    `toml:"expiration"`
+	MemorySize    int    `toml:"memory_size"`
+	RedisPrefix   string `toml:"redis_prefix"`
+	StatsInterval int    `toml:"stats_interval"`
+}
+
 // Redis redis配置参数
 type Redis struct {
 	Addr     string `toml:"addr"`
 	Password string `toml:"password"`
+	DB       int    `toml:"db"`
 }
 
 // Gorm gorm配置参数
@@ -158,6 +346,11 @@ type Gorm struct {
 	MaxOpenConns int    `toml:"max_open_conns"`
 	MaxIdleConns int    `toml:"max_idle_conns"`
 	TablePrefix  string `toml:"table_prefix"`
+	AutoMigrate  bool   `toml:"auto_migrate"`
+
+	Replicas             []string `toml:"replicas"`
+	ReplicaCheckInterval int      `toml:"replica_check_interval"`
+	ReplicaMaxLag        int      `toml:"replica_max_lag"`
 }
 
 // MySQL mysql配置参数
@@ -183,7 +376,7 @@ type Postgres struct {
 	User     string `toml:"user"`
 	Password string `toml:"password"`
 	DBName   string `toml:"db_name"`
-	SSLMode   string `toml:"sslmode"`
+	SSLMode  string `toml:"sslmode"`
 }
 
 // DSN 数据库连接串
@@ -201,3 +394,18 @@ type Sqlite3 struct {
 func (a Sqlite3) DSN() string {
 	return a.Path
 }
+
+// Mongo mongo配置参数
+type Mongo struct {
+	URI              string `toml:"uri"`
+	Database         string `toml:"database"`
+	CollectionPrefix string `toml:"collection_prefix"`
+	Timeout          int    `toml:"timeout"`
+}
+
+// Bolt bolt配置参数
+type Bolt struct {
+	Path         string `toml:"path"`
+	BucketPrefix string `toml:"bucket_prefix"`
+	Timeout      int    `toml:"timeout"`
+}
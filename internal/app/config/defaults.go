@@ -0,0 +1,189 @@
+package config
+
+// 默认配置(配置文件及环境变量中未指定的配置项使用默认值，敏感信息不提供默认值)
+func defaultConfig() *Config {
+	return &Config{
+		RunMode:       "release",
+		Store:         "gorm",
+		AllowInitMenu: true,
+		EnableCasbin:  true,
+		Log: Log{
+			Level:         4,
+			Format:        "text",
+			Output:        "stdout",
+			HookMaxThread: 1,
+			HookMaxBuffer: 512,
+		},
+		LogGormHook: LogGormHook{
+			DBType:       "sqlite3",
+			MaxLifetime:  7200,
+			MaxOpenConns: 1,
+			MaxIdleConns: 1,
+			Table:        "g_logger",
+		},
+		LogFile: LogFile{
+			MaxSize:   100,
+			MaxAge:    30,
+			Compress:  true,
+			LocalTime: true,
+		},
+		LogSyslogHook: LogSyslogHook{
+			Tag:      "iris-admin",
+			Facility: "local0",
+		},
+		LogHTTPHook: LogHTTPHook{
+			Format:        "elasticsearch",
+			Index:         "iris-admin-logs",
+			Labels:        []string{"app=iris-admin"},
+			BatchSize:     100,
+			FlushInterval: 5,
+			MaxBuffer:     10000,
+			MaxRetries:    3,
+			RetryWait:     1,
+			Timeout:       10,
+		},
+		AccessLog: AccessLog{
+			RedactHeaders: []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+			RedactFields:  []string{"password", "old_password", "new_password", "access_token", "refresh_token"},
+			MaxBodySize:   4096,
+			ResponseBody:  true,
+		},
+		Root: Root{
+			UserName: "root",
+			RealName: "超级管理员",
+		},
+		JWTAuth: JWTAuth{
+			SigningMethod: "HS512",
+			Expired:       7200,
+			Store:         "file",
+			FilePath:      "data/jwt_auth.db",
+			RedisDB:       10,
+			RedisPrefix:   "auth_",
+		},
+		AuthCookie: AuthCookie{
+			Name:           "access_token",
+			Path:           "/",
+			Secure:         true,
+			SameSite:       "strict",
+			CSRFCookieName: "csrf_token",
+			CSRFHeaderName: "X-CSRF-Token",
+		},
+		HTTP: HTTP{
+			Host:            "0.0.0.0",
+			Port:            10088,
+			ShutdownTimeout: 30,
+		},
+		Monitor: Monitor{
+			Addr: "127.0.0.1:16060",
+		},
+		Metrics: Metrics{
+			Addr: "127.0.0.1:19090",
+			Path: "/metrics",
+		},
+		Tracing: Tracing{
+			ServiceName: "iris-admin",
+			Exporter:    "otlp",
+			Endpoint:    "127.0.0.1:4318",
+			Insecure:    true,
+			OutputFile:  "data/trace.log",
+			SampleRatio: 1,
+		},
+		Health: Health{
+			Timeout: 3,
+		},
+		Reload: Reload{
+			WatchInterval: 5,
+		},
+		Captcha: Captcha{
+			Store:       "memory",
+			Length:      4,
+			Width:       300,
+			Height:      120,
+			RedisDB:     10,
+			RedisPrefix: "captcha_",
+		},
+		RateLimiter: RateLimiter{
+			Store:       "redis",
+			Algorithm:   "sliding_window",
+			Count:       300,
+			Period:      60,
+			RedisPrefix: "rate_limit_",
+			RedisRetry:  5,
+		},
+		IPAccess: IPAccess{
+			CacheExpiration: 60,
+		},
+		SecurityHeaders: SecurityHeaders{
+			ContentSecurityPolicy:   "default-src 'self'; img-src 'self' data:; style-src 'self' 'unsafe-inline'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'",
+			HSTSMaxAge:              31536000,
+			HSTSIncludeSubdomains:   true,
+			FrameOptions:            "DENY",
+			ContentTypeNosniff:      true,
+			ReferrerPolicy:          "strict-origin-when-cross-origin",
+			CrossOriginOpenerPolicy: "same-origin",
+		},
+		CORS: CORS{
+			AllowOrigins:     []string{"*"},
+			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH"},
+			AllowCredentials: true,
+			MaxAge:           7200,
+		},
+		Recycle: Recycle{
+			RetentionDays: 30,
+			Interval:      3600,
+		},
+		Search: Search{
+			Engine: "memory",
+			Limit:  10,
+		},
+		Cache: Cache{
+			Store:         "memory",
+			Expiration:    60,
+			MemorySize:    10000,
+			RedisPrefix:   "cache_",
+			StatsInterval: 300,
+		},
+		Redis: Redis{
+			Addr: "127.0.0.1:6379",
+			DB:   10,
+		},
+		Gorm: Gorm{
+			DBType:               "sqlite3",
+			MaxLifetime:          7200,
+			MaxOpenConns:         150,
+			MaxIdleConns:         50,
+			TablePrefix:          "g_",
+			AutoMigrate:          true,
+			ReplicaCheckInterval: 10,
+			ReplicaMaxLag:        30,
+		},
+		MySQL: MySQL{
+			Host:       "127.0.0.1",
+			Port:       3306,
+			User:       "root",
+			DBName:     "ginadmin",
+			Parameters: "charset=utf8mb4&parseTime=True&loc=Local&allowNativePasswords=true",
+		},
+		Postgres: Postgres{
+			Host:    "127.0.0.1",
+			Port:    5432,
+			User:    "root",
+			DBName:  "ginadmin",
+			SSLMode: "disable",
+		},
+		Sqlite3: Sqlite3{
+			Path: "data/ginadmin.db",
+		},
+		Mongo: Mongo{
+			URI:              "mongodb://127.0.0.1:27017",
+			Database:         "iris_admin",
+			CollectionPrefix: "g_",
+			Timeout:          10,
+		},
+		Bolt: Bolt{
+			Path:         "data/iris-admin.bolt.db",
+			BucketPrefix: "g_",
+			Timeout:      1,
+		},
+	}
+}
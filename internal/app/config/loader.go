@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix 环境变量前缀
+// 配置项对应的环境变量为前缀加上以"_"连接的大写键名，例如：IRISADMIN_HTTP_PORT、IRISADMIN_JWT_AUTH_STORE；
+// 在环境变量名后加上_FILE则从指定的文件中读取配置项的值(如密码等敏感信息)，例如：IRISADMIN_MYSQL_PASSWORD_FILE
+const EnvPrefix = "IRISADMIN_"
+
+// ParseConfig 解析配置
+// 依次合并默认配置、配置文件(按顺序，支持.toml/.yaml/.yml/.json)及环境变量，最后解析配置项中的敏感信息引用
+func ParseConfig(fpaths ...string) (*Config, error) {
+	c := defaultConfig()
+	for _, fpath := range fpaths {
+		m, err := readFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		if err := decode(m, c); err != nil {
+			return nil, fmt.Errorf("%s: %s", fpath, err.Error())
+		}
+	}
+
+	m, err := readEnv(os.Environ())
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(m, c); err != nil {
+		return nil, fmt.Errorf("environment: %s", err.Error())
+	}
+
+	if err := resolveSecrets(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// 读取配置文件(根据扩展名解析)
+func readFile(fpath string) (map[string]interface{}, error) {
+	buf, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(fpath)); ext {
+	case ".toml":
+		_, err = toml.Decode(string(buf), &m)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(buf, &m)
+	case ".json":
+		d := json.NewDecoder(bytes.NewReader(buf))
+		d.UseNumber()
+		err = d.Decode(&m)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file type %q", fpath, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", fpath, err.Error())
+	}
+	return m, nil
+}
+
+// 将配置数据合并到配置参数中(列表类型的配置项整体替换，未知的配置项返回错误)
+func decode(m map[string]interface{}, c *Config) error {
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          "toml",
+		WeaklyTypedInput: true,
+		ZeroFields:       true,
+		ErrorUnused:      true,
+		Result:           c,
+	})
+	if err != nil {
+		return err
+	}
+	return d.Decode(m)
+}
+
+// 读取以EnvPrefix为前缀的环境变量
+func readEnv(environ []string) (map[string]interface{}, error) {
+	keys := envKeys(reflect.TypeOf(Config{}), "", nil)
+
+	m := make(map[string]interface{})
+	for _, kv := range environ {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 || !strings.HasPrefix(kv[:i], EnvPrefix) {
+			continue
+		}
+		name, value := kv[len(EnvPrefix):i], kv[i+1:]
+
+		if strings.HasSuffix(name, "_FILE") {
+			if _, ok := keys[name]; !ok {
+				name = strings.TrimSuffix(name, "_FILE")
+				buf, err := ioutil.ReadFile(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s%s_FILE: %s", EnvPrefix, name, err.Error())
+				}
+				value = strings.TrimRight(string(buf), "\r\n")
+			}
+		}
+
+		key, ok := keys[name]
+		if !ok {
+			return nil, fmt.Errorf("%s%s: unknown config key", EnvPrefix, name)
+		}
+
+		var v interface{} = value
+		if key.slice {
+			var items []string
+			for _, s := range strings.Split(value, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					items = append(items, s)
+				}
+			}
+			v = items
+		}
+		setPath(m, key.path, v)
+	}
+	return m, nil
+}
+
+type envKey struct {
+	path  []string
+	slice bool
+}
+
+// 获取配置项对应的环境变量名(不含前缀)
+func envKeys(t reflect.Type, prefix string, path []string) map[string]envKey {
+	keys := make(map[string]envKey)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		p := append(append([]string{}, path...), name)
+		envName := prefix + strings.ToUpper(name)
+
+		if field.Type.Kind() == reflect.Struct {
+			for k, v := range envKeys(field.Type, envName+"_", p) {
+				keys[k] = v
+			}
+			continue
+		}
+		// 只支持字符串列表(以逗号分隔)
+		slice := field.Type.Kind() == reflect.Slice
+		if slice && field.Type.Elem().Kind() != reflect.String {
+			continue
+		}
+		keys[envName] = envKey{path: p, slice: slice}
+	}
+	return keys
+}
+
+func setPath(m map[string]interface{}, path []string, v interface{}) {
+	for _, p := range path[:len(path)-1] {
+		sub, ok := m[p].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[p] = sub
+		}
+		m = sub
+	}
+	m[path[len(path)-1]] = v
+}
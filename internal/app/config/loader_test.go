@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wanhello/iris-admin/pkg/secret"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseConfig(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := writeFile(t, dir, "override.yaml", `
+run_mode: test
+http:
+  port: 8080
+cors:
+  allow_methods: [GET]
+`)
+	jsonFile := writeFile(t, dir, "override.json", `{"http": {"shutdown_timeout": 5}, "jwt_auth": {"expired": 60}}`)
+	secretFile := writeFile(t, dir, "signing_key", "secret-key\n")
+
+	t.Setenv("IRISADMIN_HTTP_PORT", "9090")
+	t.Setenv("IRISADMIN_ENABLE_CASBIN", "false")
+	t.Setenv("IRISADMIN_GORM_REPLICAS", "a, b")
+	t.Setenv("IRISADMIN_JWT_AUTH_SIGNING_KEY_FILE", secretFile)
+
+	c, err := ParseConfig("../../../configs/config.toml", yamlFile, jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.RunMode != "test" || c.HTTP.ShutdownTimeout != 5 || c.JWTAuth.Expired != 60 {
+		t.Fatalf("files not merged: %+v %+v", c.HTTP, c.JWTAuth)
+	}
+	// 环境变量优先于配置文件
+	if c.HTTP.Port != 9090 || c.EnableCasbin {
+		t.Fatalf("environment not applied: port=%d enable_casbin=%v", c.HTTP.Port, c.EnableCasbin)
+	}
+	// 列表整体替换
+	if !reflect.DeepEqual(c.CORS.AllowMethods, []string{"GET"}) {
+		t.Fatalf("unexpected allow_methods %v", c.CORS.AllowMethods)
+	}
+	if !reflect.DeepEqual(c.Gorm.Replicas, []string{"a", "b"}) {
+		t.Fatalf("unexpected replicas %v", c.Gorm.Replicas)
+	}
+	if c.JWTAuth.SigningKey != "secret-key" {
+		t.Fatalf("unexpected signing key %q", c.JWTAuth.SigningKey)
+	}
+	// 未指定的配置项使用配置文件或默认值
+	if c.Captcha.Length != 4 || c.Tracing.ServiceName != "iris-admin" {
+		t.Fatalf("unexpected defaults %+v %+v", c.Captcha, c.Tracing)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Redacted().Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "secret-key") || !strings.Contains(buf.String(), redactedValue) {
+		t.Fatalf("secrets not redacted:\n%s", buf.String())
+	}
+	if c.JWTAuth.SigningKey != "secret-key" {
+		t.Fatal("Redacted should not modify the original config")
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	unknown := writeFile(t, dir, "unknown.toml", "[jwt_auth]\nstroe = \"file\"\n")
+	if _, err := ParseConfig(unknown); err == nil || !strings.Contains(err.Error(), "stroe") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+
+	t.Setenv("IRISADMIN_HTTP_PROT", "1")
+	if _, err := ParseConfig(); err == nil || !strings.Contains(err.Error(), "IRISADMIN_HTTP_PROT") {
+		t.Fatalf("expected unknown env error, got %v", err)
+	}
+
+	invalid := writeFile(t, dir, "invalid.toml", "[jwt_auth]\nstore = \"files\"\n")
+	c, err := parseWithoutEnv(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr, ok := c.Validate().(ValidationError)
+	if !ok || len(verr) != 2 {
+		t.Fatalf("expected 2 validation errors, got %v", c.Validate())
+	}
+	if !strings.Contains(verr.Error(), "jwt_auth.store") || !strings.Contains(verr.Error(), "jwt_auth.signing_key") {
+		t.Fatalf("unexpected validation error %q", verr.Error())
+	}
+
+	// 无效的限流策略
+	policy := writeFile(t, dir, "policy.toml", `
+[jwt_auth]
+signing_key = "key"
+[rate_limiter]
+enable = true
+[[rate_limiter.policies]]
+name = "login"
+method = "POST"
+path = "/api/v1/pub/login"
+count = 0
+[[rate_limiter.policies]]
+name = "export"
+method = "FETCH"
+path = "api/v1/export"
+count = 10
+`)
+	c, err = parseWithoutEnv(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verr, ok = c.Validate().(ValidationError)
+	if !ok || len(verr) != 3 {
+		t.Fatalf("expected 3 validation errors, got %v", c.Validate())
+	}
+	for _, key := range []string{"policies[0].count", "policies[1].method", "policies[1].path"} {
+		if !strings.Contains(verr.Error(), key) {
+			t.Fatalf("expected %s in validation error %q", key, verr.Error())
+		}
+	}
+}
+
+// 只合并默认配置及配置文件
+func parseWithoutEnv(fpath string) (*Config, error) {
+	c := defaultConfig()
+	m, err := readFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	return c, decode(m, c)
+}
+
+func TestResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	key, err := secret.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writeFile(t, dir, "master.key", secret.EncodeKey(key))
+	sealed, err := secret.Seal(key, []byte("sealed-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pwFile := writeFile(t, dir, "db_password", "file-password\n")
+
+	t.Setenv("CONFIG_TEST_REDIS_PASSWORD", "env-password")
+	cfgFile := writeFile(t, dir, "config.toml", `
+master_key_file = "`+keyFile+`"
+[jwt_auth]
+signing_key = "`+sealed+`"
+[mysql]
+password = "file://`+pwFile+`"
+[redis]
+password = "env:CONFIG_TEST_REDIS_PASSWORD"
+[log_gorm_hook]
+table = "env:CONFIG_TEST_TABLE"
+`)
+	if _, err := ParseConfig(cfgFile); err == nil || !strings.Contains(err.Error(), "log_gorm_hook.table") {
+		t.Fatalf("expected unresolved reference error, got %v", err)
+	}
+
+	t.Setenv("CONFIG_TEST_TABLE", "g_logger_table")
+	c, err := ParseConfig(cfgFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.JWTAuth.SigningKey != "sealed-key" || c.MySQL.Password != "file-password" || c.Redis.Password != "env-password" {
+		t.Fatalf("references not resolved: %q %q %q", c.JWTAuth.SigningKey, c.MySQL.Password, c.Redis.Password)
+	}
+
+	// 从引用中解析的值都作为敏感信息
+	secrets := strings.Join(c.Secrets(), ",")
+	if !strings.Contains(secrets, "g_logger_table") || !strings.Contains(secrets, "sealed-key") {
+		t.Fatalf("unexpected secrets %q", secrets)
+	}
+	if r := c.Redacted(); r.LogGormHook.Table != redactedValue || r.MySQL.Password != redactedValue {
+		t.Fatalf("references not redacted: %+v %+v", r.LogGormHook, r.MySQL)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// 隐藏敏感信息后的值
+const redactedValue = "******"
+
+// Redacted 获取隐藏敏感信息(密码、签名密钥、从引用中解析的值及可能包含认证信息的连接串)后的配置副本
+func (c *Config) Redacted() *Config {
+	r := *c
+	cloneStringSlices(reflect.ValueOf(&r).Elem())
+
+	secrets := c.Secrets()
+	walkStrings(reflect.ValueOf(&r).Elem(), "", func(key string, s *string) {
+		if *s == "" {
+			return
+		}
+		if key == "mongo.uri" || strings.HasPrefix(key, "gorm.replicas[") {
+			*s = redactedValue
+			return
+		}
+		for _, v := range secrets {
+			if strings.Contains(*s, v) {
+				*s = redactedValue
+				return
+			}
+		}
+	})
+	return &r
+}
+
+// 复制字符串列表类型的配置项(避免修改副本时影响原配置)
+func cloneStringSlices(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+		switch {
+		case fv.Kind() == reflect.Struct:
+			cloneStringSlices(fv)
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String && !fv.IsNil():
+			fv.Set(reflect.AppendSlice(reflect.MakeSlice(fv.Type(), 0, fv.Len()), fv))
+		}
+	}
+}
+
+// Encode 以TOML格式输出配置
+func (c *Config) Encode(w io.Writer) error {
+	return toml.NewEncoder(w).Encode(c)
+}
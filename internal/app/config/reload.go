@@ -0,0 +1,131 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// 可在线更新的配置项(其余配置项变更后需要重启服务才能生效)
+var liveKeys = map[string]bool{
+	"log.level":                   true,
+	"log.format":                  true,
+	"rate_limiter.algorithm":      true,
+	"rate_limiter.count":          true,
+	"rate_limiter.period":         true,
+	"rate_limiter.burst":          true,
+	"rate_limiter.policies":       true,
+	"ip_access.allow":             true,
+	"ip_access.deny":              true,
+	"ip_access.geo_database":      true,
+	"ip_access.geo_allow":         true,
+	"ip_access.geo_deny":          true,
+	"ip_access.geo_allow_unknown": true,
+	"cors.allow_origins":          true,
+	"cors.allow_methods":          true,
+	"cors.allow_headers":          true,
+	"cors.allow_credentials":      true,
+	"cors.max_age":                true,
+	"captcha.length":              true,
+	"captcha.width":               true,
+	"captcha.height":              true,
+	"enable_casbin":               true,
+	"access_log.redact_headers":   true,
+	"access_log.redact_fields":    true,
+	"access_log.max_body_size":    true,
+	"access_log.response_body":    true,
+	"access_log.sampling":         true,
+
+	// 安全响应头(启用状态需要重启服务)
+	"security_headers.content_security_policy":    true,
+	"security_headers.hsts_max_age":               true,
+	"security_headers.hsts_include_subdomains":    true,
+	"security_headers.hsts_preload":               true,
+	"security_headers.frame_options":              true,
+	"security_headers.content_type_nosniff":       true,
+	"security_headers.referrer_policy":            true,
+	"security_headers.permissions_policy":         true,
+	"security_headers.cross_origin_opener_policy": true,
+}
+
+var (
+	reloadLock    sync.Mutex
+	listenersLock sync.RWMutex
+	listeners     []func(*Config)
+)
+
+// ReloadResult 配置重新加载结果
+type ReloadResult struct {
+	Applied []string // 已生效的配置项
+	Restart []string // 需要重启服务才能生效的配置项
+}
+
+// OnReload 注册配置重新加载后的通知(用于更新已初始化的模块，如日志级别)
+func OnReload(fn func(c *Config)) {
+	listenersLock.Lock()
+	defer listenersLock.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// ReloadGlobalConfig 重新加载全局配置
+// 校验通过后，在当前配置的副本上应用可在线更新的配置项并整体替换全局配置，
+// 其余变更的配置项保持原值，在结果中返回(需要重启服务)
+func ReloadGlobalConfig(c *Config) (*ReloadResult, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	current := GetGlobalConfig()
+	next := *current
+	result := new(ReloadResult)
+	diffConfig("", reflect.ValueOf(current).Elem(), reflect.ValueOf(c).Elem(), reflect.ValueOf(&next).Elem(), result)
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	global.Store(&next)
+
+	listenersLock.RLock()
+	fns := make([]func(*Config), len(listeners))
+	copy(fns, listeners)
+	listenersLock.RUnlock()
+
+	for _, fn := range fns {
+		fn(&next)
+	}
+	return result, nil
+}
+
+// 比较配置项(嵌套的配置结构以"."连接键名)，将变更的可在线更新配置项写入dst
+func diffConfig(prefix string, from, to, dst reflect.Value, result *ReloadResult) {
+	t := from.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		key := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
+		key = prefix + key
+
+		if field.Type.Kind() == reflect.Struct {
+			diffConfig(key+".", from.Field(i), to.Field(i), dst.Field(i), result)
+			continue
+		}
+
+		if reflect.DeepEqual(from.Field(i).Interface(), to.Field(i).Interface()) {
+			continue
+		}
+		if liveKeys[key] {
+			dst.Field(i).Set(to.Field(i))
+			result.Applied = append(result.Applied, key)
+		} else {
+			result.Restart = append(result.Restart, key)
+		}
+	}
+}
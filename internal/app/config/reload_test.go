@@ -0,0 +1,69 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReloadGlobalConfig(t *testing.T) {
+	if err := LoadGlobalConfig("../../../configs/config.toml"); err != nil {
+		t.Fatal(err)
+	}
+	current := GetGlobalConfig()
+
+	var notified *Config
+	OnReload(func(c *Config) {
+		notified = c
+	})
+
+	// 校验失败时不替换配置
+	c, err := ParseConfig("../../../configs/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Log.Level = 99
+	c.RateLimiter.Count = 1
+	if _, err := ReloadGlobalConfig(c); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if GetGlobalConfig() != current || notified != nil {
+		t.Fatal("config should not be replaced when validation fails")
+	}
+
+	c.Log.Level = 2
+	c.Store = "bolt"
+	c.CORS.AllowOrigins = []string{"https://example.com"}
+	result, err := ReloadGlobalConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied := []string{"log.level", "rate_limiter.count", "cors.allow_origins"}
+	if !reflect.DeepEqual(result.Applied, applied) {
+		t.Fatalf("unexpected applied keys %v", result.Applied)
+	}
+	if !reflect.DeepEqual(result.Restart, []string{"store"}) {
+		t.Fatalf("unexpected restart keys %v", result.Restart)
+	}
+
+	next := GetGlobalConfig()
+	if next == current || notified != next {
+		t.Fatal("expected config to be replaced and listeners notified")
+	}
+	if next.Log.Level != 2 || next.RateLimiter.Count != 1 || next.CORS.AllowOrigins[0] != "https://example.com" {
+		t.Fatalf("live keys not applied: %+v", next)
+	}
+	if next.Store != current.Store {
+		t.Fatalf("restart key should keep the current value, got %q", next.Store)
+	}
+	if current.Log.Level == 2 {
+		t.Fatal("current config should not be modified")
+	}
+
+	// 没有变更时不替换配置
+	notified = nil
+	result, err = ReloadGlobalConfig(c)
+	if err != nil || len(result.Applied) != 0 || GetGlobalConfig() != next || notified != nil {
+		t.Fatalf("unexpected reload result %+v: %v", result, err)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/wanhello/iris-admin/pkg/secret"
+)
+
+// 解析配置项中的敏感信息引用(file://、env:及使用主密钥加密的enc:)，并记录解析出的值
+func resolveSecrets(c *Config) error {
+	var key []byte
+	if c.MasterKeyFile != "" {
+		k, err := secret.ReadKeyFile(c.MasterKeyFile)
+		if err != nil {
+			return fmt.Errorf("master_key_file: %s", err.Error())
+		}
+		key = k
+	}
+
+	r := secret.NewResolver(key)
+	var err error
+	walkStrings(reflect.ValueOf(c).Elem(), "", func(key string, s *string) {
+		if err != nil || !secret.IsRef(*s) {
+			return
+		}
+		v, rerr := r.Resolve(*s)
+		if rerr != nil {
+			err = fmt.Errorf("%s: %s", key, rerr.Error())
+			return
+		}
+		*s = v
+		c.secrets = append(c.secrets, v)
+	})
+	return err
+}
+
+// 遍历配置中的字符串及字符串列表类型的配置项
+func walkStrings(v reflect.Value, prefix string, fn func(key string, s *string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		key := prefix + strings.Split(field.Tag.Get("toml"), ",")[0]
+
+		fv := v.Field(i)
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			walkStrings(fv, key+".", fn)
+		case field.Type.Kind() == reflect.String:
+			fn(key, fv.Addr().Interface().(*string))
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+			for j := 0; j < fv.Len(); j++ {
+				fn(fmt.Sprintf("%s[%d]", key, j), fv.Index(j).Addr().Interface().(*string))
+			}
+		}
+	}
+}
+
+// Secrets 获取需要在日志中隐藏的敏感信息(密码、签名密钥及从引用中解析的值)
+func (c *Config) Secrets() []string {
+	values := []string{
+		c.Root.Password,
+		c.JWTAuth.SigningKey,
+		c.Redis.Password,
+		c.LogHTTPHook.Password,
+		c.MySQL.Password,
+		c.Postgres.Password,
+	}
+	if u, err := url.Parse(c.Mongo.URI); err == nil && u.User != nil {
+		if p, ok := u.User.Password(); ok {
+			values = append(values, p)
+		}
+	}
+	values = append(values, c.secrets...)
+
+	var result []string
+	exists := make(map[string]bool)
+	for _, v := range values {
+		if v != "" && !exists[v] {
+			exists[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
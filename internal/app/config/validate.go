@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wanhello/iris-admin/pkg/ipfilter"
+)
+
+// ValidationError 配置校验错误(包含所有未通过校验的配置项)
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid config: " + strings.Join(e, "; ")
+}
+
+type validator struct {
+	errs ValidationError
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Sprintf(format, args...))
+}
+
+// 校验配置项的值是否在允许的范围内
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, s := range allowed {
+		if value == s {
+			return
+		}
+	}
+	v.addf("%s: %q is not one of [%s]", key, value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) required(key, value string) {
+	if value == "" {
+		v.addf("%s: is required", key)
+	}
+}
+
+func (v *validator) positive(key string, value int64) {
+	if value <= 0 {
+		v.addf("%s: must be greater than 0, got %d", key, value)
+	}
+}
+
+// 校验IP地址范围列表
+func (v *validator) cidrs(key string, values []string) {
+	for i, s := range values {
+		if _, err := ipfilter.ParseCIDR(s); err != nil {
+			v.addf("%s[%d]: %v", key, i, err)
+		}
+	}
+}
+
+// 校验国家/地区代码列表(ISO 3166-1两位大写字母代码)
+func (v *validator) countries(key string, values []string) {
+	for i, s := range values {
+		if len(s) != 2 || s[0] < 'A' || s[0] > 'Z' || s[1] < 'A' || s[1] > 'Z' {
+			v.addf("%s[%d]: %q is not an ISO 3166-1 alpha-2 country code", key, i, s)
+		}
+	}
+}
+
+// Validate 校验配置参数(加载及重新加载配置时调用，返回所有未通过校验的配置项)
+func (c *Config) Validate() error {
+	v := new(validator)
+
+	v.oneOf("run_mode", c.RunMode, "debug", "test", "release")
+	v.oneOf("store", c.Store, "gorm", "mongo", "bolt")
+
+	// 对应logrus的日志级别(0:panic - 6:trace)
+	if c.Log.Level < 0 || c.Log.Level > 6 {
+		v.addf("log.level: must be between 0 and 6, got %d", c.Log.Level)
+	}
+	v.oneOf("log.format", c.Log.Format, "", "text", "json")
+	v.oneOf("log.output", c.Log.Output, "", "stdout", "stderr", "file")
+	if c.Log.Output == "file" {
+		v.required("log.output_file", c.Log.OutputFile)
+	}
+	if c.Log.Output == "file" {
+		v.oneOf("log_file.rotate_time", c.LogFile.RotateTime, "", "hourly", "daily")
+	}
+	if c.Log.EnableHook {
+		v.oneOf("log.hook", c.Log.Hook, "gorm", "syslog", "http")
+	}
+	if c.Log.EnableHook && c.Log.Hook == "gorm" {
+		v.oneOf("log_gorm_hook.db_type", c.LogGormHook.DBType, "mysql", "sqlite3", "postgres")
+	}
+	if c.Log.EnableHook && c.Log.Hook == "syslog" {
+		v.oneOf("log_syslog_hook.network", c.LogSyslogHook.Network, "", "udp", "tcp", "unix", "unixgram")
+		v.oneOf("log_syslog_hook.facility", c.LogSyslogHook.Facility, "kern", "user", "mail", "daemon", "auth",
+			"syslog", "lpr", "news", "uucp", "cron", "authpriv", "ftp",
+			"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7")
+	}
+	if h := c.LogHTTPHook; c.Log.EnableHook && c.Log.Hook == "http" {
+		v.required("log_http_hook.url", h.URL)
+		v.oneOf("log_http_hook.format", h.Format, "elasticsearch", "loki")
+		if h.Format == "elasticsearch" {
+			v.required("log_http_hook.index", h.Index)
+		}
+		for i, label := range h.Labels {
+			if !strings.Contains(label, "=") || strings.HasPrefix(label, "=") {
+				v.addf("log_http_hook.labels[%d]: %q is not in key=value format", i, label)
+			}
+		}
+		v.positive("log_http_hook.batch_size", int64(h.BatchSize))
+		v.positive("log_http_hook.flush_interval", int64(h.FlushInterval))
+		v.positive("log_http_hook.max_buffer", int64(h.MaxBuffer))
+		if h.MaxRetries < 0 {
+			v.addf("log_http_hook.max_retries: must not be negative, got %d", h.MaxRetries)
+		}
+	}
+
+	for i, item := range c.AccessLog.Sampling {
+		v.required(fmt.Sprintf("access_log.sampling[%d].path", i), item.Path)
+		if item.Rate < 0 || item.Rate > 1 {
+			v.addf("access_log.sampling[%d].rate: must be between 0 and 1, got %v", i, item.Rate)
+		}
+	}
+
+	v.required("root.user_name", c.Root.UserName)
+
+	v.oneOf("jwt_auth.signing_method", c.JWTAuth.SigningMethod, "HS256", "HS384", "HS512")
+	v.required("jwt_auth.signing_key", c.JWTAuth.SigningKey)
+	v.positive("jwt_auth.expired", int64(c.JWTAuth.Expired))
+	v.oneOf("jwt_auth.store", c.JWTAuth.Store, "file", "redis")
+	if c.JWTAuth.Store == "file" {
+		v.required("jwt_auth.file_path", c.JWTAuth.FilePath)
+	}
+
+	if a := c.AuthCookie; a.Enable {
+		v.required("auth_cookie.name", a.Name)
+		v.required("auth_cookie.csrf_cookie_name", a.CSRFCookieName)
+		v.required("auth_cookie.csrf_header_name", a.CSRFHeaderName)
+		v.oneOf("auth_cookie.same_site", a.SameSite, "strict", "lax", "none")
+		if a.SameSite == "none" && !a.Secure {
+			v.addf("auth_cookie.secure: must be enabled when same_site is none")
+		}
+	}
+
+	if c.HTTP.Port < 0 || c.HTTP.Port > 65535 {
+		v.addf("http.port: must be between 0 and 65535, got %d", c.HTTP.Port)
+	}
+	v.cidrs("http.trusted_proxies", c.HTTP.TrustedProxies)
+
+	if c.Tracing.Enable {
+		v.oneOf("tracing.exporter", c.Tracing.Exporter, "otlp", "stdout", "file")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio: must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	if c.Reload.Watch {
+		v.positive("reload.watch_interval", int64(c.Reload.WatchInterval))
+	}
+
+	v.oneOf("captcha.store", c.Captcha.Store, "memory", "redis")
+	v.positive("captcha.length", int64(c.Captcha.Length))
+	v.positive("captcha.width", int64(c.Captcha.Width))
+	v.positive("captcha.height", int64(c.Captcha.Height))
+
+	if r := c.RateLimiter; r.Enable {
+		v.oneOf("rate_limiter.store", r.Store, "memory", "redis")
+		v.oneOf("rate_limiter.algorithm", r.Algorithm, "token_bucket", "sliding_window")
+		v.positive("rate_limiter.count", r.Count)
+		v.positive("rate_limiter.period", int64(r.Period))
+
+		names := make(map[string]bool)
+		for i, p := range r.Policies {
+			key := fmt.Sprintf("rate_limiter.policies[%d]", i)
+			v.required(key+".name", p.Name)
+			if names[p.Name] {
+				v.addf("%s.name: duplicate policy name %q", key, p.Name)
+			}
+			names[p.Name] = true
+			v.oneOf(key+".method", strings.ToUpper(p.Method), "", "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS")
+			if p.Path != "" && !strings.HasPrefix(p.Path, "/") {
+				v.addf("%s.path: %q must start with /", key, p.Path)
+			}
+			for j, role := range p.Roles {
+				if role == "" {
+					v.addf("%s.roles[%d]: must not be empty", key, j)
+				}
+			}
+			v.oneOf(key+".key", p.Key, "", "user", "ip")
+			v.positive(key+".count", p.Count)
+			if p.Period < 0 || p.Burst < 0 {
+				v.addf("%s: period and burst must not be negative", key)
+			}
+		}
+	}
+
+	if c.IPAccess.Enable {
+		v.cidrs("ip_access.allow", c.IPAccess.Allow)
+		v.cidrs("ip_access.deny", c.IPAccess.Deny)
+		v.countries("ip_access.geo_allow", c.IPAccess.GeoAllow)
+		v.countries("ip_access.geo_deny", c.IPAccess.GeoDeny)
+		if len(c.IPAccess.GeoAllow) > 0 || len(c.IPAccess.GeoDeny) > 0 {
+			v.required("ip_access.geo_database", c.IPAccess.GeoDatabase)
+		}
+		if name := c.IPAccess.GeoDatabase; name != "" {
+			if _, err := os.Stat(name); err != nil {
+				v.addf("ip_access.geo_database: %v", err)
+			}
+		}
+		if c.IPAccess.CacheExpiration < 0 {
+			v.addf("ip_access.cache_expiration: must not be negative, got %d", c.IPAccess.CacheExpiration)
+		}
+	}
+
+	if h := c.SecurityHeaders; h.Enable {
+		v.oneOf("security_headers.frame_options", h.FrameOptions, "", "DENY", "SAMEORIGIN")
+		if h.HSTSMaxAge < 0 {
+			v.addf("security_headers.hsts_max_age: must not be negative, got %d", h.HSTSMaxAge)
+		}
+	}
+
+	v.oneOf("search.engine", c.Search.Engine, "memory", "db")
+
+	if c.Cache.Enable {
+		v.oneOf("cache.store", c.Cache.Store, "memory", "redis")
+	}
+
+	if c.Store == "gorm" {
+		v.oneOf("gorm.db_type", c.Gorm.DBType, "mysql", "sqlite3", "postgres")
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+)
+
+// ConfigCommand 执行配置命令
+// 支持的命令：
+//
+//	print [--redacted]  输出合并默认配置、配置文件及环境变量后的配置(--redacted隐藏敏感信息)
+func ConfigCommand(w io.Writer, args []string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("未知的配置命令：%v", args)
+	}
+
+	redacted := false
+	for _, arg := range args[1:] {
+		switch arg {
+		case "--redacted", "-redacted":
+			redacted = true
+		default:
+			return fmt.Errorf("未知的参数：%s", arg)
+		}
+	}
+
+	cfg, err := config.ParseConfig(o.configFiles()...)
+	if err != nil {
+		return err
+	}
+	applyOptions(cfg, &o)
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if redacted {
+		cfg = cfg.Redacted()
+	}
+	return cfg.Encode(w)
+}
@@ -7,6 +7,7 @@ import (
 // 定义全局上下文中的键
 type (
 	transCtx   struct{}
+	primaryCtx struct{}
 	userIDCtx  struct{}
 	traceIDCtx struct{}
 )
@@ -22,6 +23,17 @@ func FromTrans(ctx context.Context) (interface{}, bool) {
 	return v, v != nil
 }
 
+// NewPrimary 创建强制使用主库的上下文(读写分离时查询也使用主库)
+func NewPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtx{}, true)
+}
+
+// FromPrimary 从上下文中获取是否强制使用主库
+func FromPrimary(ctx context.Context) bool {
+	v, ok := ctx.Value(primaryCtx{}).(bool)
+	return ok && v
+}
+
 // NewUserID 创建用户ID的上下文
 func NewUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, userIDCtx{}, userID)
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/wanhello/iris-admin/internal/app/bll"
 	"github.com/wanhello/iris-admin/internal/app/config"
@@ -12,12 +13,16 @@ import (
 
 )
 
+// casbin权限策略数据是否加载完成(供就绪检查使用)
+var casbinPolicyLoaded int32
+
 // InitData 初始化应用数据
 func InitData(ctx context.Context, container *dig.Container) error {
 	err := loadCasbinPolicyData(ctx, container)
 	if err != nil {
 		return err
 	}
+	atomic.StoreInt32(&casbinPolicyLoaded, 1)
 
 	if config.GetGlobalConfig().AllowInitMenu {
 		return initMenuData(ctx, container)
@@ -76,13 +81,19 @@ func initMenuData(ctx context.Context, container *dig.Container) error {
 			return nil
 		}
 
-		var data schema.MenuTrees
-		err = util.JSONUnmarshal([]byte(menuData), &data)
-		if err != nil {
-			return err
-		}
+		for _, md := range append([]string{menuData}, extraMenuData...) {
+			var data schema.MenuTrees
+			err = util.JSONUnmarshal([]byte(md), &data)
+			if err != nil {
+				return err
+			}
 
-		return createMenus(ctx, trans, menu, "", data)
+			err = createMenus(ctx, trans, menu, "", data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
@@ -117,6 +128,9 @@ func createMenus(ctx context.Context, trans bll.ITrans, menu bll.IMenu, parentID
 }
 
 
+// 扩展的菜单初始化数据(由代码生成器在各自模块的文件中追加)
+var extraMenuData []string
+
 // 初始化菜单数据
 const menuData = `
 [
@@ -217,6 +231,12 @@ const menuData = `
             "method": "DELETE",
             "path": "/api/v1/roles/:id"
           },
+          {
+            "code": "batchDelete",
+            "name": "批量删除角色数据",
+            "method": "POST",
+            "path": "/api/v1/roles/batch/delete"
+          },
           {
             "code": "queryMenu",
             "name": "查询菜单数据",
@@ -279,6 +299,30 @@ const menuData = `
             "method": "PATCH",
             "path": "/api/v1/users/:id/enable"
           },
+          {
+            "code": "batchDelete",
+            "name": "批量删除用户数据",
+            "method": "POST",
+            "path": "/api/v1/users/batch/delete"
+          },
+          {
+            "code": "batchDisable",
+            "name": "批量禁用用户数据",
+            "method": "POST",
+            "path": "/api/v1/users/batch/disable"
+          },
+          {
+            "code": "batchEnable",
+            "name": "批量启用用户数据",
+            "method": "POST",
+            "path": "/api/v1/users/batch/enable"
+          },
+          {
+            "code": "batchAssignRoles",
+            "name": "批量分配用户角色",
+            "method": "POST",
+            "path": "/api/v1/users/batch/roles"
+          },
           {
             "code": "queryRole",
             "name": "查询角色数据",
@@ -286,6 +330,37 @@ const menuData = `
             "path": "/api/v1/roles"
           }
         ]
+      },
+      {
+        "name": "回收站",
+        "icon": "delete",
+        "router": "/system/recycle",
+        "sequence": 1160000,
+        "actions": [
+          { "code": "restore", "name": "恢复" },
+          { "code": "purge", "name": "彻底删除" },
+          { "code": "query", "name": "查询" }
+        ],
+        "resources": [
+          {
+            "code": "query",
+            "name": "查询已删除数据",
+            "method": "GET",
+            "path": "/api/v1/recycle/:type"
+          },
+          {
+            "code": "restore",
+            "name": "恢复已删除数据",
+            "method": "PATCH",
+            "path": "/api/v1/recycle/:type/:id/restore"
+          },
+          {
+            "code": "purge",
+            "name": "彻底删除数据",
+            "method": "DELETE",
+            "path": "/api/v1/recycle/:type/:id"
+          }
+        ]
       }
     ]
   }
@@ -25,10 +25,21 @@ var (
 	ErrNotAllowDeleteWithChild = New("含有子级，不能删除")
 	ErrResourceExists          = New("资源已经存在")
 	ErrResourceNotAllowDelete  = New("资源不允许删除")
+	ErrResourceConflict        = New("资源已被修改，请刷新后重试")
+	ErrPreconditionFailed      = New("资源版本不匹配")
+	ErrInvalidQueryField       = New("无效的查询字段")
+	ErrInvalidCursor           = New("无效的分页游标")
+	ErrInvalidCIDR             = New("无效的IP地址范围")
+	ErrServiceUnavailable      = New("服务暂时不可用，请稍后重试")
 
 	// 权限错误
 	ErrNoPerm         = New("无访问权限")
 	ErrNoResourcePerm = New("无资源的访问权限")
+	ErrIPNotAllowed   = New("当前IP地址不允许访问")
+	ErrInvalidCSRF    = New("无效的CSRF令牌")
+
+	// 角色错误
+	ErrInvalidRole = New("无效的角色")
 
 	// 用户错误
 	ErrInvalidUserName = New("无效的用户名")
@@ -55,10 +66,21 @@ func init() {
 	newBadRequestError(ErrNotAllowDeleteWithChild)
 	newBadRequestError(ErrResourceExists)
 	newBadRequestError(ErrResourceNotAllowDelete)
+	newErrorCode(ErrResourceConflict, 409, ErrResourceConflict.Error(), 409)
+	newErrorCode(ErrPreconditionFailed, 412, ErrPreconditionFailed.Error(), 412)
+	newBadRequestError(ErrInvalidQueryField)
+	newBadRequestError(ErrInvalidCursor)
+	newBadRequestError(ErrInvalidCIDR)
+	newErrorCode(ErrServiceUnavailable, 503, ErrServiceUnavailable.Error(), 503)
 
 	// 权限错误
 	newErrorCode(ErrNoPerm, 9999, ErrNoPerm.Error(), 401)
 	newErrorCode(ErrNoResourcePerm, 401, ErrNoResourcePerm.Error(), 401)
+	newErrorCode(ErrIPNotAllowed, 403, ErrIPNotAllowed.Error(), 403)
+	newErrorCode(ErrInvalidCSRF, 403, ErrInvalidCSRF.Error(), 403)
+
+	// 角色错误
+	newBadRequestError(ErrInvalidRole)
 
 	// 用户错误
 	newBadRequestError(ErrInvalidUserName)
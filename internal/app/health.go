@@ -0,0 +1,102 @@
+package app
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/health"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"github.com/casbin/casbin"
+	"github.com/go-redis/redis"
+	"github.com/jinzhu/gorm"
+	"go.uber.org/dig"
+)
+
+// InitHealth 初始化健康检查(注册各项依赖的就绪检查，服务启动后设定为就绪)
+func InitHealth(container *dig.Container) (func(), error) {
+	cfg := config.GetGlobalConfig()
+	checker := health.NewChecker(time.Duration(cfg.Health.Timeout) * time.Second)
+
+	var err error
+	switch cfg.Store {
+	case "gorm":
+		// 从库不可用时查询会回退到主库，因此只作为非必需检查项
+		err = container.Invoke(func(db *gormplus.DB) {
+			db.Each(func(name string, db *gorm.DB) {
+				if name == "primary" {
+					checker.Register("gorm", health.GormCheck(db))
+					return
+				}
+				checker.RegisterOptional("gorm:"+name, health.GormCheck(db))
+			})
+		})
+	case "mongo":
+		err = container.Invoke(func(db *mongoplus.DB) {
+			checker.Register("mongo", health.MongoCheck(db))
+		})
+	case "bolt":
+		err = container.Invoke(func(db *boltplus.DB) {
+			checker.Register("bolt", health.BoltCheck(db))
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = container.Invoke(func(e *casbin.Enforcer) {
+		checker.Register("casbin", health.CasbinCheck(e, func() bool {
+			return atomic.LoadInt32(&casbinPolicyLoaded) == 1
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c := cfg.JWTAuth; c.Store == "file" && c.FilePath != ":memory:" {
+		checker.Register("buntdb", health.FileCheck(c.FilePath))
+	}
+
+	// 使用redis的存储(相同的库共用连接)
+	redisDBs := make(map[string]int)
+	if cfg.JWTAuth.Store == "redis" {
+		redisDBs["redis:jwt"] = cfg.JWTAuth.RedisDB
+	}
+	if cfg.Captcha.Store == "redis" {
+		redisDBs["redis:captcha"] = cfg.Captcha.RedisDB
+	}
+	if useSharedRedis(cfg) {
+		redisDBs["redis:shared"] = cfg.Redis.DB
+	}
+
+	clients := make(map[int]*redis.Client)
+	for name, db := range redisDBs {
+		cli, ok := clients[db]
+		if !ok {
+			cli = redis.NewClient(&redis.Options{
+				Addr:     cfg.Redis.Addr,
+				Password: cfg.Redis.Password,
+				DB:       db,
+				PoolSize: 1,
+			})
+			clients[db] = cli
+		}
+		checker.Register(name, health.RedisCheck(cli))
+	}
+
+	err = container.Provide(func() *health.Checker {
+		return checker
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		for _, cli := range clients {
+			cli.Close()
+		}
+	}, nil
+}
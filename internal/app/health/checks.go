@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"github.com/casbin/casbin"
+	"github.com/go-redis/redis"
+	"github.com/jinzhu/gorm"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// GormCheck 检查数据库连接(主库及每个从库分别注册检查项)
+func GormCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.DB().PingContext(ctx)
+	}
+}
+
+// MongoCheck 检查mongo主节点是否可访问
+func MongoCheck(db *mongoplus.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.Client().Ping(ctx, readpref.Primary())
+	}
+}
+
+// BoltCheck 检查bolt数据库是否处于打开状态
+func BoltCheck(db *boltplus.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.View(func(*bbolt.Tx) error {
+			return nil
+		})
+	}
+}
+
+// RedisCheck 检查redis服务是否可访问
+func RedisCheck(cli *redis.Client) CheckFunc {
+	return func(ctx context.Context) error {
+		return cli.WithContext(ctx).Ping().Err()
+	}
+}
+
+// FileCheck 检查文件是否可读写(如buntdb存储文件)
+func FileCheck(path string) CheckFunc {
+	return func(ctx context.Context) error {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+}
+
+// CasbinCheck 检查casbin权限策略是否已加载(loaded返回策略数据是否加载完成)
+func CasbinCheck(e *casbin.Enforcer, loaded func() bool) CheckFunc {
+	return func(ctx context.Context) error {
+		if e == nil || !loaded() {
+			return errors.New("casbin policy not loaded")
+		}
+		if _, ok := e.GetModel()["p"]; !ok {
+			return errors.New("casbin model has no policy definition")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 定义检查状态
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc 定义依赖检查函数(返回nil表示依赖可用)
+type CheckFunc func(ctx context.Context) error
+
+// Result 单项检查结果
+type Result struct {
+	Name     string  `json:"name"`               // 检查名称
+	Status   string  `json:"status"`             // 检查状态(up/down)
+	Latency  float64 `json:"latency_ms"`         // 检查耗时(单位毫秒)
+	Error    string  `json:"error,omitempty"`    // 错误信息
+	Optional bool    `json:"optional,omitempty"` // 是否为非必需检查项(失败时不影响整体状态)
+}
+
+// Report 检查报告
+type Report struct {
+	Status string    `json:"status"`           // 整体状态(up/down)
+	Checks []*Result `json:"checks,omitempty"` // 各项检查结果
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	optional bool
+}
+
+// NewChecker 创建健康检查(timeout为单项检查的超时时长)
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+// Checker 健康检查
+type Checker struct {
+	timeout time.Duration
+	ready   int32
+	lock    sync.RWMutex
+	checks  []check
+}
+
+// Register 注册就绪检查项(同名的检查项将被替换)
+func (a *Checker) Register(name string, fn CheckFunc) {
+	a.register(check{name: name, fn: fn})
+}
+
+// RegisterOptional 注册非必需的检查项(如数据库从库)，检查结果只用于展示，失败时服务仍然就绪
+func (a *Checker) RegisterOptional(name string, fn CheckFunc) {
+	a.register(check{name: name, fn: fn, optional: true})
+}
+
+func (a *Checker) register(item check) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for i, c := range a.checks {
+		if c.name == item.name {
+			a.checks[i] = item
+			return
+		}
+	}
+	a.checks = append(a.checks, item)
+}
+
+// SetReady 设定服务是否就绪(服务关闭时设定为未就绪，使负载均衡停止转发请求)
+func (a *Checker) SetReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&a.ready, v)
+}
+
+// Ready 服务是否就绪
+func (a *Checker) Ready() bool {
+	return atomic.LoadInt32(&a.ready) == 1
+}
+
+// Check 并发执行所有检查项(服务未就绪时不执行检查，非必需检查项失败时不影响整体状态)
+func (a *Checker) Check(ctx context.Context) *Report {
+	if !a.Ready() {
+		return &Report{Status: StatusDown}
+	}
+
+	a.lock.RLock()
+	checks := make([]check, len(a.checks))
+	copy(checks, a.checks)
+	a.lock.RUnlock()
+
+	report := &Report{
+		Status: StatusUp,
+		Checks: make([]*Result, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			report.Checks[i] = a.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, r := range report.Checks {
+		if r.Status != StatusUp && !r.Optional {
+			report.Status = StatusDown
+			break
+		}
+	}
+	return report
+}
+
+func (a *Checker) run(ctx context.Context, c check) *Result {
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := c.fn(ctx)
+	result := &Result{
+		Name:     c.name,
+		Status:   StatusUp,
+		Latency:  float64(time.Since(start).Microseconds()) / 1000,
+		Optional: c.optional,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LivenessHandler 存活检查(进程能够响应请求即为存活)
+func (a *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, &Report{Status: StatusUp})
+	})
+}
+
+// ReadinessHandler 就绪检查(任一必需检查项失败时响应503)
+func (a *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, a.Check(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report *Report) {
+	status := http.StatusOK
+	if report.Status != StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
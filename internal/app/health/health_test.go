@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Register("ok", func(ctx context.Context) error { return nil })
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if r := checker.Check(context.Background()); r.Status != StatusDown || len(r.Checks) != 0 {
+		t.Fatalf("expected not ready before SetReady, got %+v", r)
+	}
+
+	checker.SetReady(true)
+	r := checker.Check(context.Background())
+	if r.Status != StatusDown || len(r.Checks) != 2 {
+		t.Fatalf("unexpected report %+v", r)
+	}
+	if r.Checks[0].Status != StatusUp || r.Checks[1].Status != StatusDown || r.Checks[1].Error == "" {
+		t.Fatalf("unexpected results %+v %+v", r.Checks[0], r.Checks[1])
+	}
+
+	// 同名检查项替换原检查
+	checker.Register("slow", func(ctx context.Context) error { return nil })
+	w := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// 非必需检查项失败时仍然就绪
+	checker.RegisterOptional("replica", func(ctx context.Context) error { return errors.New("unavailable") })
+	r = checker.Check(context.Background())
+	if r.Status != StatusUp || len(r.Checks) != 3 || !r.Checks[2].Optional || r.Checks[2].Status != StatusDown {
+		t.Fatalf("unexpected report with optional check %+v", r)
+	}
+
+	checker.Register("fail", func(ctx context.Context) error { return errors.New("unavailable") })
+	w = httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+
+	checker.SetReady(false)
+	w = httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+}
+
+func TestBoltCheck(t *testing.T) {
+	db, err := boltplus.New(&boltplus.Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := BoltCheck(db)
+	if err := check(context.Background()); err != nil {
+		t.Fatalf("expected bolt up, got %v", err)
+	}
+	db.Close()
+	if err := check(context.Background()); err == nil {
+		t.Fatal("expected bolt down after close")
+	}
+}
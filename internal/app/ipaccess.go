@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/pkg/ipfilter"
+	"github.com/wanhello/iris-admin/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+// InitCountryLookup 初始化IP访问控制的国家/地区查询(未启用IP访问控制时注入nil)
+// 配置重新加载后重新读取地理位置数据库(文件未变更时不重新读取，读取失败时继续使用原有的数据)
+func InitCountryLookup(container *dig.Container) error {
+	cfg := config.GetGlobalConfig().IPAccess
+	if !cfg.Enable {
+		return container.Provide(func() ipfilter.CountryLookup {
+			return nil
+		})
+	}
+
+	lookup := new(countryLookup)
+	err := lookup.load(cfg.GeoDatabase)
+	if err != nil {
+		return err
+	}
+
+	config.OnReload(func(c *config.Config) {
+		err := lookup.load(c.IPAccess.GeoDatabase)
+		if err != nil {
+			logger.Errorf(context.Background(), "重新加载IP地理位置数据库发生错误，继续使用原有的数据：%s", err.Error())
+		}
+	})
+
+	return container.Provide(func() ipfilter.CountryLookup {
+		return lookup
+	})
+}
+
+// 可重新加载的国家/地区查询
+type countryLookup struct {
+	lock    sync.RWMutex
+	name    string
+	modTime time.Time
+	table   *ipfilter.CountryTable
+}
+
+// 读取地理位置数据库(name为空时清除数据)
+func (a *countryLookup) load(name string) error {
+	if name == "" {
+		a.lock.Lock()
+		a.name, a.modTime, a.table = "", time.Time{}, nil
+		a.lock.Unlock()
+		return nil
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	a.lock.RLock()
+	unchanged := a.name == name && a.modTime.Equal(info.ModTime())
+	a.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	table, err := ipfilter.LoadCountryFile(name)
+	if err != nil {
+		return err
+	}
+	logger.Printf(context.Background(), "加载IP地理位置数据库：%s，共%d个地址范围", name, table.Len())
+
+	a.lock.Lock()
+	a.name, a.modTime, a.table = name, info.ModTime(), table
+	a.lock.Unlock()
+	return nil
+}
+
+func (a *countryLookup) Country(ip net.IP) string {
+	a.lock.RLock()
+	table := a.table
+	a.lock.RUnlock()
+
+	if table == nil {
+		return ""
+	}
+	return table.Country(ip)
+}
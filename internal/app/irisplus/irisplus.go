@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/wanhello/iris-admin/internal/app/config"
 	icontext "github.com/wanhello/iris-admin/internal/app/context"
 
 	"github.com/wanhello/iris-admin/internal/app/errors"
@@ -14,6 +16,8 @@ import (
 	"github.com/wanhello/iris-admin/pkg/util"
 
 	"github.com/kataras/iris"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
 
 )
 
@@ -26,8 +30,16 @@ const (
 	UserIDKey = prefix + "/user_id"
 	// TraceIDKey 存储上下文中的键(跟踪ID)
 	TraceIDKey = prefix + "/trace_id"
-	// ResBodyKey 存储上下文中的键(响应Body数据)
+	// ResBodyKey 存储上下文中的键(响应Body数据，仅在启用记录响应内容时存储)
 	ResBodyKey = prefix + "/res_body"
+	// ResBodyCaptureKey 存储上下文中的键(是否记录响应Body数据)
+	ResBodyCaptureKey = prefix + "/res_body_capture"
+	// SpanKey 存储上下文中的键(链路追踪的跟踪单元)
+	SpanKey = prefix + "/span"
+	// BaggageKey 存储上下文中的键(上游传递的W3C baggage)
+	BaggageKey = prefix + "/baggage"
+	// ClientIPKey 存储上下文中的键(客户端IP)
+	ClientIPKey = prefix + "/client_ip"
 )
 
 // NewContext 封装上线文入口
@@ -44,11 +56,31 @@ func NewContext(c iris.Context) context.Context {
 		parent = logger.NewUserIDContext(parent, v)
 	}
 
+	if span := GetSpan(c); span != nil {
+		parent = trace.ContextWithSpan(parent, span)
+	}
+
+	if bag := GetBaggage(c); bag.Len() > 0 {
+		parent = baggage.ContextWithBaggage(parent, bag)
+	}
+
+	// 非查询请求强制使用主库(保证写入后能读取到最新数据)
+	if m := c.Method(); m != http.MethodGet && m != http.MethodHead {
+		parent = icontext.NewPrimary(parent)
+	}
+
 	return parent
 }
 
-// GetToken 获取用户令牌
+// GetToken 获取用户令牌(优先使用Authorization请求头，启用Cookie令牌时从Cookie中获取)
 func GetToken(c iris.Context) string {
+	if token := getBearerToken(c); token != "" {
+		return token
+	}
+	return GetCookieToken(c)
+}
+
+func getBearerToken(c iris.Context) string {
 	var token string
 	auth := c.GetHeader("Authorization")
 	prefix := "Bearer "
@@ -58,6 +90,65 @@ func GetToken(c iris.Context) string {
 	return token
 }
 
+// GetCookieToken 获取Cookie中的用户令牌(未启用Cookie令牌或请求使用Authorization请求头时返回空)
+func GetCookieToken(c iris.Context) string {
+	cfg := config.GetGlobalConfig().AuthCookie
+	if !cfg.Enable || getBearerToken(c) != "" {
+		return ""
+	}
+	return c.GetCookie(cfg.Name)
+}
+
+// SetTokenCookie 设定令牌Cookie(HttpOnly)及CSRF令牌Cookie(前端可读取)，未启用Cookie令牌时不设定并返回false
+func SetTokenCookie(c iris.Context, token string, expiresAt int64) bool {
+	cfg := config.GetGlobalConfig().AuthCookie
+	if !cfg.Enable {
+		return false
+	}
+
+	expires := time.Unix(expiresAt, 0)
+	c.SetCookie(newCookie(cfg, cfg.Name, token, expires, true))
+	c.SetCookie(newCookie(cfg, cfg.CSRFCookieName, util.MustRandomToken(32), expires, false))
+	return true
+}
+
+// ClearTokenCookie 清除令牌Cookie及CSRF令牌Cookie
+func ClearTokenCookie(c iris.Context) {
+	cfg := config.GetGlobalConfig().AuthCookie
+	if !cfg.Enable {
+		return
+	}
+
+	expires := time.Unix(0, 0)
+	c.SetCookie(newCookie(cfg, cfg.Name, "", expires, true))
+	c.SetCookie(newCookie(cfg, cfg.CSRFCookieName, "", expires, false))
+}
+
+func newCookie(cfg config.AuthCookie, name, value string, expires time.Time, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   cfg.Domain,
+		Path:     cfg.Path,
+		Expires:  expires,
+		Secure:   cfg.Secure,
+		HttpOnly: httpOnly,
+	}
+	if value == "" {
+		cookie.MaxAge = -1
+	}
+
+	switch cfg.SameSite {
+	case "lax":
+		cookie.SameSite = http.SameSiteLaxMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	default:
+		cookie.SameSite = http.SameSiteStrictMode
+	}
+	return cookie
+}
+
 // GetPageIndex 获取分页的页索引
 func GetPageIndex(c iris.Context) int {
 	defaultVal := 1
@@ -73,7 +164,7 @@ func GetPageIndex(c iris.Context) int {
 // GetPageSize 获取分页的页大小(最大50)
 func GetPageSize(c iris.Context) int {
 	defaultVal := 10
-	if v := c.Params().Get("pageSize"); v != "" {
+	if v := c.URLParam("pageSize"); v != "" {
 		if iv := util.S(v).DefaultInt(defaultVal); iv > 0 {
 			if iv > 50 {
 				iv = 50
@@ -93,34 +184,211 @@ func GetPaginationParam(c iris.Context) *schema.PaginationParam {
 	}
 }
 
+// GetQuerySpec 获取通用查询规格
+// 排序：sort=-created_at,name(字段前加"-"表示降序)
+// 字段选择：fields=record_id,name
+// 过滤条件：filter=字段:操作符:值(可指定多个，in及between的多个值以英文逗号分隔)，
+// 例如：filter=status:eq:1&filter=created_at:between:2019-01-01,2019-02-01
+// 字段是否允许使用由存储层的白名单决定
+func GetQuerySpec(c iris.Context) (*schema.QuerySpec, error) {
+	spec := new(schema.QuerySpec)
+
+	for _, field := range splitQueryValue(c.URLParam("sort")) {
+		item := &schema.QuerySort{Field: field}
+		if strings.HasPrefix(field, "-") {
+			item.Field = field[1:]
+			item.Desc = true
+		} else if strings.HasPrefix(field, "+") {
+			item.Field = field[1:]
+		}
+
+		if item.Field == "" {
+			return nil, errors.ErrInvalidRequestParameter
+		}
+		spec.Sorts = append(spec.Sorts, item)
+	}
+
+	spec.Fields = splitQueryValue(c.URLParam("fields"))
+
+	for _, v := range c.Request().URL.Query()["filter"] {
+		vs := strings.SplitN(v, ":", 3)
+		if len(vs) != 3 || vs[0] == "" || !schema.IsValidQueryOperator(vs[1]) {
+			return nil, errors.ErrInvalidRequestParameter
+		}
+
+		item := &schema.QueryFilter{
+			Field:    vs[0],
+			Operator: vs[1],
+			Values:   []string{vs[2]},
+		}
+		if item.Operator == schema.QueryOpIN || item.Operator == schema.QueryOpBetween {
+			item.Values = splitQueryValue(vs[2])
+		}
+
+		if len(item.Values) == 0 ||
+			(item.Operator == schema.QueryOpBetween && len(item.Values) != 2) {
+			return nil, errors.ErrInvalidRequestParameter
+		}
+		spec.Filters = append(spec.Filters, item)
+	}
+
+	return spec, nil
+}
+
+// 拆分以英文逗号分隔的查询参数值(忽略空值)
+func splitQueryValue(v string) []string {
+	var values []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// GetCursorParam 获取游标分页参数(未指定cursor参数时返回nil，使用分页查询)
+// 参数：cursor(游标，首次查询为空)，limit(查询条数，最大1000)，total(总数查询方式：none/exact/estimate)
+func GetCursorParam(c iris.Context) *schema.CursorParam {
+	if _, ok := c.Request().URL.Query()["cursor"]; !ok {
+		return nil
+	}
+
+	cp := &schema.CursorParam{
+		Cursor: c.URLParam("cursor"),
+		Limit:  util.S(c.URLParam("limit")).DefaultInt(10),
+		Total:  c.URLParam("total"),
+	}
+	if cp.Limit <= 0 {
+		cp.Limit = 10
+	} else if cp.Limit > 1000 {
+		cp.Limit = 1000
+	}
+
+	switch cp.Total {
+	case schema.CursorTotalExact, schema.CursorTotalEstimate:
+	default:
+		cp.Total = schema.CursorTotalNone
+	}
+	return cp
+}
+
 // GetTraceID 获取追踪ID
 func GetTraceID(c iris.Context) string {
-	//return c.GetString(TraceIDKey)
-	return c.URLParam(TraceIDKey)
+	return c.Values().GetString(TraceIDKey)
 }
 
 // GetUserID 获取用户ID
 func GetUserID(c iris.Context) string {
-	//return c.GetString(UserIDKey)
-	return c.URLParam(UserIDKey)
+	return c.Values().GetString(UserIDKey)
+}
+
+// GetSpan 获取链路追踪的跟踪单元(未启用链路追踪时返回nil)
+func GetSpan(c iris.Context) trace.Span {
+	if span, ok := c.Values().Get(SpanKey).(trace.Span); ok {
+		return span
+	}
+	return nil
+}
+
+// SetSpan 设定链路追踪的跟踪单元
+func SetSpan(c iris.Context, span trace.Span) {
+	c.Values().Set(SpanKey, span)
+}
+
+// CaptureResBody 设定记录响应内容(如访问日志需要记录响应内容时，ResJSON将响应内容存储在上下文中)
+func CaptureResBody(c iris.Context) {
+	c.Values().Set(ResBodyCaptureKey, true)
+}
+
+// GetResBody 获取记录的响应内容(未设定记录响应内容时返回nil)
+func GetResBody(c iris.Context) []byte {
+	if buf, ok := c.Values().Get(ResBodyKey).([]byte); ok {
+		return buf
+	}
+	return nil
+}
+
+// GetBaggage 获取上游传递的W3C baggage(未传递时返回空的baggage)
+func GetBaggage(c iris.Context) baggage.Baggage {
+	if bag, ok := c.Values().Get(BaggageKey).(baggage.Baggage); ok {
+		return bag
+	}
+	return baggage.Baggage{}
+}
+
+// SetBaggage 设定上游传递的W3C baggage
+func SetBaggage(c iris.Context, bag baggage.Baggage) {
+	c.Values().Set(BaggageKey, bag)
+}
+
+// GetClientIP 获取客户端IP(未经过可信代理解析时使用对端地址)
+func GetClientIP(c iris.Context) string {
+	if ip := c.Values().GetString(ClientIPKey); ip != "" {
+		return ip
+	}
+	return c.RemoteAddr()
+}
+
+// SetClientIP 设定客户端IP
+func SetClientIP(c iris.Context, ip string) {
+	c.Values().Set(ClientIPKey, ip)
 }
 
 // SetUserID 设定用户ID
 func SetUserID(c iris.Context, userID string) {
-	c.Params().Set(UserIDKey, userID)
+	c.Values().Set(UserIDKey, userID)
 }
 
 // ParseJSON 解析请求JSON
 func ParseJSON(c iris.Context, obj interface{}) error {
-	if err := c.ShouldBindJSON(obj); err != nil {
+	if err := c.ReadJSON(obj); err != nil {
 		logger.Warnf(NewContext(c), err.Error())
 		return errors.ErrInvalidRequestParameter
 	}
 	return nil
 }
 
-// ResPage 响应分页数据
+// GetIfMatchVersion 获取请求头If-Match中指定的数据版本号(未指定时返回0)
+func GetIfMatchVersion(c iris.Context) (int, error) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	version := util.S(v).DefaultInt(0)
+	if version <= 0 {
+		return 0, errors.ErrPreconditionFailed
+	}
+	return version, nil
+}
+
+// SetETag 设定响应头ETag(数据版本号)
+func SetETag(c iris.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ResPage 响应分页数据(游标分页时响应游标数据)
 func ResPage(c iris.Context, v interface{}, pr *schema.PaginationResult) {
+	if pr != nil && pr.Cursor != nil {
+		list := schema.HTTPList{
+			List: v,
+			Cursor: &schema.HTTPCursor{
+				Next:      pr.Cursor.Next,
+				Prev:      pr.Cursor.Prev,
+				Limit:     pr.Cursor.Limit,
+				Estimated: pr.Cursor.Estimated,
+			},
+		}
+		if pr.Cursor.Counted {
+			total := pr.Total
+			list.Cursor.Total = &total
+		}
+
+		ResSuccess(c, list)
+		return
+	}
+
 	list := schema.HTTPList{
 		List: v,
 		Pagination: &schema.HTTPPagination{
@@ -135,6 +403,49 @@ func ResPage(c iris.Context, v interface{}, pr *schema.PaginationResult) {
 	ResSuccess(c, list)
 }
 
+// ResPageFields 响应分页数据(仅包含指定的字段，未指定时响应全部字段)
+// 字段选择在响应时进行，不减少数据库查询的列(实体转换及关联数据依赖完整的列数据)
+func ResPageFields(c iris.Context, v interface{}, pr *schema.PaginationResult, fields []string) {
+	if len(fields) == 0 {
+		ResPage(c, v, pr)
+		return
+	}
+
+	list, err := selectFields(v, fields)
+	if err != nil {
+		ResError(c, err)
+		return
+	}
+	ResPage(c, list, pr)
+}
+
+// 选择列表数据中的字段(记录ID始终保留，v必须是结构体列表)
+func selectFields(v interface{}, fields []string) ([]map[string]interface{}, error) {
+	buf, err := util.JSONMarshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var list []map[string]interface{}
+	if err := util.JSONUnmarshal(buf, &list); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	keys := map[string]bool{"record_id": true}
+	for _, field := range fields {
+		keys[field] = true
+	}
+
+	for _, item := range list {
+		for k := range item {
+			if !keys[k] {
+				delete(item, k)
+			}
+		}
+	}
+	return list, nil
+}
+
 // ResList 响应列表数据
 func ResList(c iris.Context, v interface{}) {
 	ResSuccess(c, schema.HTTPList{List: v})
@@ -156,9 +467,13 @@ func ResJSON(c iris.Context, status int, v interface{}) {
 	if err != nil {
 		panic(err)
 	}
-	c.Params().Set(ResBodyKey, string(buf) )
-	c.Data(status, "application/json; charset=utf-8", buf)
-	c.Abort()
+	if c.Values().GetBoolDefault(ResBodyCaptureKey, false) {
+		c.Values().Set(ResBodyKey, buf)
+	}
+	c.ContentType("application/json; charset=utf-8")
+	c.StatusCode(status)
+	c.Write(buf)
+	c.StopExecution()
 }
 
 // ResError 响应错误
@@ -0,0 +1,176 @@
+package irisplus
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+
+	"github.com/kataras/iris"
+	"github.com/kataras/iris/context"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func newTestContext(method, target string, header map[string]string) (iris.Context, *httptest.ResponseRecorder) {
+	r := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	c := context.NewContext(iris.New())
+	c.BeginRequest(w, r)
+	return c, w
+}
+
+func TestGetIfMatchVersion(t *testing.T) {
+	for _, item := range []struct {
+		header   string
+		expected int
+		err      error
+	}{
+		{"", 0, nil},
+		{"*", 0, nil},
+		{`"3"`, 3, nil},
+		{`W/"4"`, 4, nil},
+		{"5", 5, nil},
+		{`"abc"`, 0, errors.ErrPreconditionFailed},
+		{`"0"`, 0, errors.ErrPreconditionFailed},
+		{`"-1"`, 0, errors.ErrPreconditionFailed},
+		{`"1", "2"`, 0, errors.ErrPreconditionFailed},
+	} {
+		c, _ := newTestContext("PUT", "/", map[string]string{"If-Match": item.header})
+		version, err := GetIfMatchVersion(c)
+		if version != item.expected || err != item.err {
+			t.Errorf("%q: expected %d, %v, got %d, %v", item.header, item.expected, item.err, version, err)
+		}
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	c, w := newTestContext("GET", "/", nil)
+	SetETag(c, 7)
+	if v := w.Header().Get("ETag"); v != `"7"` {
+		t.Fatalf("unexpected etag %q", v)
+	}
+}
+
+func TestGetCursorParam(t *testing.T) {
+	c, _ := newTestContext("GET", "/?limit=5", nil)
+	if cp := GetCursorParam(c); cp != nil {
+		t.Fatalf("expected nil without cursor, got %+v", cp)
+	}
+
+	for _, item := range []struct {
+		query    string
+		expected schema.CursorParam
+	}{
+		{"cursor=", schema.CursorParam{Limit: 10, Total: schema.CursorTotalNone}},
+		{"cursor=abc&limit=5&total=exact", schema.CursorParam{Cursor: "abc", Limit: 5, Total: schema.CursorTotalExact}},
+		{"cursor=&limit=0&total=estimate", schema.CursorParam{Limit: 10, Total: schema.CursorTotalEstimate}},
+		{"cursor=&limit=-1", schema.CursorParam{Limit: 10, Total: schema.CursorTotalNone}},
+		{"cursor=&limit=5000&total=unknown", schema.CursorParam{Limit: 1000, Total: schema.CursorTotalNone}},
+		{"cursor=&limit=x", schema.CursorParam{Limit: 10, Total: schema.CursorTotalNone}},
+	} {
+		c, _ := newTestContext("GET", "/?"+item.query, nil)
+		cp := GetCursorParam(c)
+		if cp == nil || *cp != item.expected {
+			t.Errorf("%s: expected %+v, got %+v", item.query, item.expected, cp)
+		}
+	}
+}
+
+func TestGetQuerySpec(t *testing.T) {
+	c, _ := newTestContext("GET", "/?sort=-created_at,%2Bcode,name&fields=code,,name&filter=status:in:1,2&filter=code:between:A,B&filter=name:like:a:b", nil)
+	spec, err := GetQuerySpec(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorts := []schema.QuerySort{{Field: "created_at", Desc: true}, {Field: "code"}, {Field: "name"}}
+	if len(spec.Sorts) != len(sorts) {
+		t.Fatalf("unexpected sorts: %+v", spec.Sorts)
+	}
+	for i, item := range spec.Sorts {
+		if *item != sorts[i] {
+			t.Errorf("sort %d: expected %+v, got %+v", i, sorts[i], *item)
+		}
+	}
+	if strings.Join(spec.Fields, ",") != "code,name" {
+		t.Errorf("unexpected fields: %v", spec.Fields)
+	}
+	if len(spec.Filters) != 3 ||
+		strings.Join(spec.Filters[0].Values, ",") != "1,2" ||
+		strings.Join(spec.Filters[1].Values, ",") != "A,B" ||
+		strings.Join(spec.Filters[2].Values, ",") != "a:b" {
+		t.Errorf("unexpected filters: %+v", spec.Filters)
+	}
+
+	// 字段是否允许使用由存储层校验，此处仅校验格式
+	for _, query := range []string{
+		"sort=-",
+		"sort=code,%2B",
+		"filter=status",
+		"filter=status:eq",
+		"filter=:eq:1",
+		"filter=status:unknown:1",
+		"filter=status:in:,",
+		"filter=code:between:A",
+		"filter=code:between:A,B,C",
+	} {
+		c, _ := newTestContext("GET", "/?"+query, nil)
+		if _, err := GetQuerySpec(c); err != errors.ErrInvalidRequestParameter {
+			t.Errorf("%s: expected ErrInvalidRequestParameter, got %v", query, err)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	list, err := selectFields([]*schema.Demo{{RecordID: "d1", Code: "A001", Name: "Alpha"}}, []string{"code"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || len(list[0]) != 2 || list[0]["record_id"] != "d1" || list[0]["code"] != "A001" {
+		t.Fatalf("unexpected list: %v", list)
+	}
+
+	if _, err := selectFields(&schema.Demo{RecordID: "d1"}, []string{"code"}); err == nil {
+		t.Fatal("expected error for non-list value")
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	c, _ := newTestContext("GET", "/", nil)
+	if bag := baggage.FromContext(NewContext(c)); bag.Len() != 0 {
+		t.Fatalf("expected empty baggage, got %v", bag)
+	}
+
+	member, err := baggage.NewMember("tenant", "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bag, err := baggage.New(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetBaggage(c, bag)
+	if v := baggage.FromContext(NewContext(c)).Member("tenant").Value(); v != "t1" {
+		t.Fatalf("expected baggage to be propagated, got %q", v)
+	}
+}
+
+func TestCaptureResBody(t *testing.T) {
+	c, w := newTestContext("GET", "/", nil)
+	ResSuccess(c, schema.HTTPStatus{Status: "OK"})
+	if v := GetResBody(c); v != nil {
+		t.Fatalf("expected no captured body, got %q", v)
+	}
+
+	c, w = newTestContext("GET", "/", nil)
+	CaptureResBody(c)
+	ResSuccess(c, schema.HTTPStatus{Status: "OK"})
+	if v := GetResBody(c); string(v) != w.Body.String() {
+		t.Fatalf("expected captured body %q, got %q", w.Body.String(), v)
+	}
+}
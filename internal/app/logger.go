@@ -2,13 +2,18 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
 	"github.com/wanhello/iris-admin/pkg/logger"
 	loggerhook "github.com/wanhello/iris-admin/pkg/logger/hook"
 	loggergormhook "github.com/wanhello/iris-admin/pkg/logger/hook/gorm"
+	loggerhttphook "github.com/wanhello/iris-admin/pkg/logger/hook/http"
+	loggersysloghook "github.com/wanhello/iris-admin/pkg/logger/hook/syslog"
 	"github.com/wanhello/iris-admin/pkg/util"
 
 )
@@ -21,8 +26,14 @@ func InitLogger() (func(), error) {
 	logger.SetLevel(c.Level)
 	logger.SetFormatter(c.Format)
 
+	// 配置重新加载后更新日志级别及格式(输出方式及钩子需要重启服务)
+	config.OnReload(func(cfg *config.Config) {
+		logger.SetLevel(cfg.Log.Level)
+		logger.SetFormatter(cfg.Log.Format)
+	})
+
 	// 设定日志输出
-	var file *os.File
+	var file *logger.RotateFile
 	if c.Output != "" {
 		switch c.Output {
 		case "stdout":
@@ -33,58 +44,105 @@ func InitLogger() (func(), error) {
 			if name := c.OutputFile; name != "" {
 				os.MkdirAll(filepath.Dir(name), 0777)
 
-				f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0666)
-				if err != nil {
-					return nil, err
-				}
-				logger.SetOutput(f)
-				file = f
+				fc := config.GetGlobalConfig().LogFile
+				file = logger.NewRotateFile(&logger.RotateConfig{
+					Filename:   name,
+					MaxSize:    fc.MaxSize,
+					MaxBackups: fc.MaxBackups,
+					MaxAge:     fc.MaxAge,
+					Compress:   fc.Compress,
+					LocalTime:  fc.LocalTime,
+					RotateTime: fc.RotateTime,
+				})
+				logger.SetOutput(file)
 			}
 		}
 	}
 
 	var hook *loggerhook.Hook
 	if c.EnableHook {
-		switch c.Hook {
-		case "gorm":
-			hc := config.GetGlobalConfig().LogGormHook
-
-			var dsn string
-			switch hc.DBType {
-			case "mysql":
-				dsn = config.GetGlobalConfig().MySQL.DSN()
-			case "sqlite3":
-				dsn = config.GetGlobalConfig().Sqlite3.DSN()
-			case "postgres":
-				dsn = config.GetGlobalConfig().Postgres.DSN()
-			default:
-				return nil, errors.New("unknown db")
+		exec, err := newLoggerHook(c.Hook)
+		if err != nil {
+			if file != nil {
+				file.Close()
 			}
-
-			h := loggerhook.New(loggergormhook.New(&loggergormhook.Config{
-				DBType:       hc.DBType,
-				DSN:          dsn,
-				MaxLifetime:  hc.MaxLifetime,
-				MaxOpenConns: hc.MaxOpenConns,
-				MaxIdleConns: hc.MaxIdleConns,
-				TableName:    hc.Table,
-			}),
-				loggerhook.SetMaxWorkers(c.HookMaxThread),
-				loggerhook.SetMaxQueues(c.HookMaxBuffer),
-			)
-			logger.AddHook(h)
-			hook = h
+			return nil, err
 		}
+
+		h := loggerhook.New(exec,
+			loggerhook.SetMaxWorkers(c.HookMaxThread),
+			loggerhook.SetMaxQueues(c.HookMaxBuffer),
+		)
+		logger.AddHook(h)
+		hook = h
 	}
 
 	return func() {
-		if file != nil {
-			file.Close()
-		}
-
 		if hook != nil {
 			hook.Flush()
 		}
+
+		if file != nil {
+			file.Close()
+		}
 	}, nil
 }
 
+// 创建日志钩子(gorm/syslog/http)
+func newLoggerHook(name string) (loggerhook.ExecCloser, error) {
+	cfg := config.GetGlobalConfig()
+
+	switch name {
+	case "gorm":
+		hc := cfg.LogGormHook
+
+		var dsn string
+		switch hc.DBType {
+		case "mysql":
+			dsn = cfg.MySQL.DSN()
+		case "sqlite3":
+			dsn = cfg.Sqlite3.DSN()
+		case "postgres":
+			dsn = cfg.Postgres.DSN()
+		default:
+			return nil, errors.New("unknown db")
+		}
+
+		return loggergormhook.New(&loggergormhook.Config{
+			DBType:       hc.DBType,
+			DSN:          dsn,
+			MaxLifetime:  hc.MaxLifetime,
+			MaxOpenConns: hc.MaxOpenConns,
+			MaxIdleConns: hc.MaxIdleConns,
+			TableName:    hc.Table,
+		}), nil
+	case "syslog":
+		hc := cfg.LogSyslogHook
+		return loggersysloghook.New(&loggersysloghook.Config{
+			Network:  hc.Network,
+			Addr:     hc.Addr,
+			Tag:      hc.Tag,
+			Facility: hc.Facility,
+		})
+	case "http":
+		hc := cfg.LogHTTPHook
+		return loggerhttphook.New(&loggerhttphook.Config{
+			URL:           hc.URL,
+			Format:        hc.Format,
+			Index:         hc.Index,
+			Labels:        hc.LabelMap(),
+			Username:      hc.Username,
+			Password:      hc.Password,
+			BatchSize:     hc.BatchSize,
+			FlushInterval: time.Duration(hc.FlushInterval) * time.Second,
+			MaxBuffer:     hc.MaxBuffer,
+			MaxRetries:    hc.MaxRetries,
+			RetryWait:     time.Duration(hc.RetryWait) * time.Second,
+			Timeout:       time.Duration(hc.Timeout) * time.Second,
+			OnDrop: func(n int) {
+				metrics.LogHookDropped.WithLabelValues("http").Add(float64(n))
+			},
+		})
+	}
+	return nil, fmt.Errorf("unknown log hook: %s", name)
+}
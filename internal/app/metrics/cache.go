@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/wanhello/iris-admin/pkg/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cacheHitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hits_total"),
+		"Total number of query cache hits by cache namespace.",
+		[]string{"namespace"}, nil,
+	)
+	cacheMissesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "misses_total"),
+		"Total number of query cache misses by cache namespace.",
+		[]string{"namespace"}, nil,
+	)
+	cacheInvalidationsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "invalidations_total"),
+		"Total number of query cache invalidations issued by this instance by cache namespace.",
+		[]string{"namespace"}, nil,
+	)
+)
+
+// RegisterCache 注册查询缓存的命中、未命中及失效次数(按缓存的命名空间统计，采集时读取缓存的命中统计)
+func RegisterCache(c *cache.Cache) error {
+	return Registry.Register(&cacheCollector{c: c})
+}
+
+type cacheCollector struct {
+	c *cache.Cache
+}
+
+func (a *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheInvalidationsDesc
+}
+
+func (a *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range a.c.Stats() {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(s.Hits), s.Namespace)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(s.Misses), s.Namespace)
+		ch <- prometheus.MustNewConstMetric(cacheInvalidationsDesc, prometheus.CounterValue, float64(s.Invalidations), s.Namespace)
+	}
+}
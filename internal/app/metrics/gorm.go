@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/jinzhu/gorm"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const gormStartKey = "metrics:start_time"
+
+// RegisterGorm 注册数据库操作耗时统计及连接池状态(包括从库，连接名称为primary或replica-序号)
+func RegisterGorm(db *gormplus.DB) error {
+	var err error
+	db.Each(func(name string, gdb *gorm.DB) {
+		registerGormCallbacks(name, gdb)
+		if err == nil {
+			err = Registry.Register(collectors.NewDBStatsCollector(gdb.DB(), name))
+		}
+	})
+	return err
+}
+
+func registerGormCallbacks(name string, db *gorm.DB) {
+	cb := db.Callback()
+	cb.Create().Before("gorm:begin_transaction").Register("metrics:before_create", gormBefore)
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("metrics:after_create", gormAfter(name, "create"))
+	cb.Update().Before("gorm:begin_transaction").Register("metrics:before_update", gormBefore)
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("metrics:after_update", gormAfter(name, "update"))
+	cb.Delete().Before("gorm:begin_transaction").Register("metrics:before_delete", gormBefore)
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("metrics:after_delete", gormAfter(name, "delete"))
+	cb.Query().Before("gorm:query").Register("metrics:before_query", gormBefore)
+	cb.Query().After("gorm:after_query").Register("metrics:after_query", gormAfter(name, "query"))
+	cb.RowQuery().Before("gorm:row_query").Register("metrics:before_row_query", gormBefore)
+	cb.RowQuery().After("gorm:row_query").Register("metrics:after_row_query", gormAfter(name, "row_query"))
+}
+
+func gormBefore(scope *gorm.Scope) {
+	scope.InstanceSet(gormStartKey, time.Now())
+}
+
+func gormAfter(name, operation string) func(*gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		v, ok := scope.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		// 原生查询等无法确定数据表时不记录表名
+		table := ""
+		if scope.Value != nil {
+			table = scope.TableName()
+		}
+		DBQueryDuration.WithLabelValues(name, operation, table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "iris_admin"
+
+// 路由标签的取值(未匹配到路由时使用，避免以原始请求路径作为标签值)
+const (
+	RouteUnmatched = "unmatched"
+)
+
+// Registry 指标注册表(包含Go运行时及进程指标)
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests HTTP请求数量(按路由模板、请求方式及响应状态码统计)
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPDuration HTTP请求耗时
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// DBQueryDuration 数据库操作耗时(按连接、操作类型及数据表统计)
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Database query latency by connection, operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"db", "operation", "table"})
+
+	// LoginTotal 用户登录次数(result：success/failure)
+	LoginTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_total",
+		Help:      "Total number of login attempts by result.",
+	}, []string{"result"})
+
+	// TokenBlacklistHits 使用已注销令牌的请求次数
+	TokenBlacklistHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "token_blacklist_hits_total",
+		Help:      "Total number of requests using a revoked token.",
+	})
+
+	// CasbinEnforceDuration casbin权限校验耗时(result：allow/deny/error)
+	CasbinEnforceDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "casbin",
+		Name:      "enforce_duration_seconds",
+		Help:      "Casbin enforce latency by result.",
+		Buckets:   []float64{.00001, .000025, .00005, .0001, .00025, .0005, .001, .0025, .005, .01},
+	}, []string{"result"})
+
+	// RateLimiterRejections 请求频率限制拒绝的次数
+	RateLimiterRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limiter",
+		Name:      "rejections_total",
+		Help:      "Total number of requests rejected by the rate limiter.",
+	})
+
+	// RateLimiterErrors 请求频率限制发生错误的次数(action：allow为放行，deny为敏感策略拒绝请求)
+	RateLimiterErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limiter",
+		Name:      "errors_total",
+		Help:      "Total number of rate limiter errors by the action taken.",
+	}, []string{"action"})
+
+	// LogHookDropped 日志钩子丢弃的日志数量(缓冲区已满或发送失败)
+	LogHookDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "log",
+		Name:      "hook_dropped_total",
+		Help:      "Total number of log entries dropped by the log hook.",
+	}, []string{"hook"})
+
+	// IPAccessRejections IP访问控制拒绝的次数(reason为deny/allow/geo/user)
+	IPAccessRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ip_access",
+		Name:      "rejections_total",
+		Help:      "Total number of requests rejected by the ip access control.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		DBQueryDuration,
+		LoginTotal,
+		TokenBlacklistHits,
+		CasbinEnforceDuration,
+		RateLimiterRejections,
+		RateLimiterErrors,
+		LogHookDropped,
+		IPAccessRejections,
+	)
+}
+
+// Handler 指标响应处理
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveLogin 记录用户登录结果
+func ObserveLogin(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	LoginTotal.WithLabelValues(result).Inc()
+}
+
+// ObserveEnforce 记录casbin权限校验的耗时及结果
+func ObserveEnforce(start time.Time, allowed bool, err error) {
+	result := "allow"
+	if err != nil {
+		result = "error"
+	} else if !allowed {
+		result = "deny"
+	}
+	CasbinEnforceDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wanhello/iris-admin/pkg/cache"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type metricsItem struct {
+	ID   int
+	Name string
+}
+
+func TestRegisterGorm(t *testing.T) {
+	db, err := gormplus.New(&gormplus.Config{
+		DBType:       "sqlite3",
+		DSN:          ":memory:",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := RegisterGorm(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(new(metricsItem)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&metricsItem{Name: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var list []*metricsItem
+	if err := db.Where("name=?", "a").Find(&list).Error; err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.Raw("SELECT COUNT(*) FROM metrics_items").Row().Scan(&count); err != nil || count != 1 {
+		t.Fatalf("unexpected count %d: %v", count, err)
+	}
+
+	for _, op := range []string{"create", "query"} {
+		var m dto.Metric
+		h := DBQueryDuration.WithLabelValues("primary", op, "metrics_items").(prometheus.Histogram)
+		if err := h.Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		if n := m.GetHistogram().GetSampleCount(); n == 0 {
+			t.Fatalf("expected %s duration to be observed", op)
+		}
+	}
+
+	// 连接池状态按连接名称区分
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, f := range families {
+		if f.GetName() != "go_sql_open_connections" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "db_name" && l.GetValue() == "primary" {
+					found = true
+				}
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected db stats for primary connection")
+	}
+
+	// 重复注册连接池状态时返回错误
+	if err := RegisterGorm(db); err == nil {
+		t.Fatal("expected error for duplicate registration")
+	}
+}
+
+func TestObserveLogin(t *testing.T) {
+	before := testutil.ToFloat64(LoginTotal.WithLabelValues("failure"))
+	ObserveLogin(errors.New("invalid password"))
+	ObserveLogin(nil)
+	if v := testutil.ToFloat64(LoginTotal.WithLabelValues("failure")); v != before+1 {
+		t.Fatalf("unexpected failure count: %v", v)
+	}
+	if v := testutil.ToFloat64(LoginTotal.WithLabelValues("success")); v < 1 {
+		t.Fatalf("unexpected success count: %v", v)
+	}
+}
+
+func TestRegisterCache(t *testing.T) {
+	c := cache.New(cache.NewMemoryStore(10))
+	defer c.Close()
+
+	if err := RegisterCache(c); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		var v string
+		err := c.Load(ctx, "menu", "get:1", &v, func() error {
+			v = "menu"
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Invalidate(ctx, "menu"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, line := range []string{
+		`iris_admin_cache_hits_total{namespace="menu"} 2`,
+		`iris_admin_cache_misses_total{namespace="menu"} 1`,
+		`iris_admin_cache_invalidations_total{namespace="menu"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("expected %q in scrape output:\n%s", line, body)
+		}
+	}
+}
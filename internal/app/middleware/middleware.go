@@ -13,14 +13,14 @@ import (
 
 
 // NoMethodHandler 未找到请求方法的处理函数
-func NoMethodHandler() iris.HandlerFunc {
+func NoMethodHandler() iris.Handler {
 	return func(c iris.Context) {
 		irisplus.ResError(c, errors.ErrMethodNotAllow)
 	}
 }
 
 // NoRouteHandler 未找到请求路由的处理函数
-func NoRouteHandler() iris.HandlerFunc {
+func NoRouteHandler() iris.Handler {
 	return func(c iris.Context) {
 		irisplus.ResError(c, errors.ErrNotFound)
 	}
@@ -15,7 +15,7 @@ import (
 
 
 // UserAuthMiddleware 用户授权中间件
-func UserAuthMiddleware(a auth.Auther, skipper ...SkipperFunc) iris.HandlerFunc {
+func UserAuthMiddleware(a auth.Auther, skipper ...SkipperFunc) iris.Handler {
 	return func(c iris.Context) {
 		var userID string
 		if t := irisplus.GetToken(c); t != "" {
@@ -32,7 +32,7 @@ func UserAuthMiddleware(a auth.Auther, skipper ...SkipperFunc) iris.HandlerFunc
 		}
 
 		if userID != "" {
-			c.Params().Set(irisplus.UserIDKey, userID)
+			irisplus.SetUserID(c, userID)
 		}
 
 		if len(skipper) > 0 && skipper[0](c) {
@@ -42,12 +42,14 @@ func UserAuthMiddleware(a auth.Auther, skipper ...SkipperFunc) iris.HandlerFunc
 
 		if userID == "" {
 			if config.GetGlobalConfig().RunMode == "debug" {
-				c.Params().Set(irisplus.UserIDKey, config.GetGlobalConfig().Root.UserName)
+				irisplus.SetUserID(c, config.GetGlobalConfig().Root.UserName)
 				c.Next()
 				return
 			}
 			irisplus.ResError(c, errors.ErrNoPerm)
+			return
 		}
+		c.Next()
 	}
 }
 
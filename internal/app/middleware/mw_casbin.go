@@ -1,27 +1,32 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/wanhello/iris-admin/internal/app/config"
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
 
 	"github.com/casbin/casbin"
 	"github.com/kataras/iris"
 
 )
 
-// CasbinMiddleware casbin中间件
-func CasbinMiddleware(enforcer *casbin.Enforcer, skipper ...SkipperFunc) iris.HandlerFunc {
-	cfg := config.GetGlobalConfig()
+// CasbinMiddleware casbin中间件(每次请求读取是否启用，支持配置热加载)
+func CasbinMiddleware(enforcer *casbin.Enforcer, skipper ...SkipperFunc) iris.Handler {
 	return func(c iris.Context) {
-		if !cfg.EnableCasbin || len(skipper) > 0 && skipper[0](c) {
+		if !config.GetGlobalConfig().EnableCasbin || len(skipper) > 0 && skipper[0](c) {
 			c.Next()
 			return
 		}
 
 		p := c.Request().URL.Path
 		m := c.Request().Method
-		if b, err := enforcer.EnforceSafe(irisplus.GetUserID(c), p, m); err != nil {
+		start := time.Now()
+		b, err := enforcer.EnforceSafe(irisplus.GetUserID(c), p, m)
+		metrics.ObserveEnforce(start, b, err)
+		if err != nil {
 			irisplus.ResError(c, errors.WithStack(err))
 			return
 		} else if !b {
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"time"
+	"sync/atomic"
 
 	"github.com/wanhello/iris-admin/internal/app/config"
 
@@ -10,15 +10,33 @@ import (
 
 )
 
-// CORSMiddleware 跨域请求中间件
-func CORSMiddleware() iris.HandlerFunc {
-	cfg := config.GetGlobalConfig().CORS
-	return cors.New(cors.Config{
-		AllowOrigins:     cfg.AllowOrigins,
-		AllowMethods:     cfg.AllowMethods,
-		AllowHeaders:     cfg.AllowHeaders,
+// 根据配置创建的跨域处理
+type corsHandler struct {
+	cfg     *config.Config
+	handler iris.Handler
+}
+
+// CORSMiddleware 跨域请求中间件(全局配置重新加载后重新创建跨域处理)
+func CORSMiddleware() iris.Handler {
+	var current atomic.Value
+	return func(c iris.Context) {
+		cfg := config.GetGlobalConfig()
+		h, _ := current.Load().(*corsHandler)
+		if h == nil || h.cfg != cfg {
+			h = &corsHandler{cfg: cfg, handler: newCORS(cfg.CORS)}
+			current.Store(h)
+		}
+		h.handler(c)
+	}
+}
+
+func newCORS(cfg config.CORS) iris.Handler {
+	return cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowOrigins,
+		AllowedMethods:   cfg.AllowMethods,
+		AllowedHeaders:   cfg.AllowHeaders,
 		AllowCredentials: cfg.AllowCredentials,
-		MaxAge:           time.Second * time.Duration(cfg.MaxAge),
+		MaxAge:           cfg.MaxAge,
 	})
 }
 
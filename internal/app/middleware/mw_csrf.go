@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/irisplus"
+
+	"github.com/kataras/iris"
+)
+
+// CSRFMiddleware 跨站请求伪造校验中间件(启用Cookie令牌时使用)
+// 使用Cookie中的令牌发起的非安全请求(POST/PUT/PATCH/DELETE)需要双重提交CSRF令牌，
+// 即请求头中的CSRF令牌与CSRF令牌Cookie一致；使用Authorization请求头的请求不校验
+func CSRFMiddleware(skipper ...SkipperFunc) iris.Handler {
+	return func(c iris.Context) {
+		if len(skipper) > 0 && skipper[0](c) {
+			c.Next()
+			return
+		}
+
+		switch c.Method() {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			c.Next()
+			return
+		}
+
+		if irisplus.GetCookieToken(c) == "" {
+			c.Next()
+			return
+		}
+
+		cfg := config.GetGlobalConfig().AuthCookie
+		expected := c.GetCookie(cfg.CSRFCookieName)
+		actual := c.GetHeader(cfg.CSRFHeaderName)
+		if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) != 1 {
+			irisplus.ResError(c, errors.ErrInvalidCSRF)
+			return
+		}
+
+		c.Next()
+	}
+}
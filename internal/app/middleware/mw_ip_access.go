@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
+	"github.com/wanhello/iris-admin/pkg/ipfilter"
+	"github.com/wanhello/iris-admin/pkg/logger"
+
+	"github.com/kataras/iris"
+)
+
+// ClientIPMiddleware 客户端IP中间件(在其他中间件之前使用)
+// 仅当请求来自可信代理(http.trusted_proxies)时才从X-Forwarded-For请求头中获取客户端IP，
+// 后续中间件通过irisplus.GetClientIP获取
+func ClientIPMiddleware() iris.Handler {
+	// 可信代理在加载配置时已校验，且不支持在线更新
+	trusted, _ := ipfilter.ParseCIDRs(config.GetGlobalConfig().HTTP.TrustedProxies)
+	return func(c iris.Context) {
+		irisplus.SetClientIP(c, ipfilter.ClientIP(c.Request(), trusted))
+		c.Next()
+	}
+}
+
+// 根据配置解析的IP地址范围及国家/地区
+type ipAccessLists struct {
+	cfg      *config.Config
+	allow    ipfilter.List
+	deny     ipfilter.List
+	geoAllow map[string]bool
+	geoDeny  map[string]bool
+}
+
+func toSet(values []string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+// IPAccessMiddleware IP访问控制中间件(全局配置重新加载后重新解析IP地址范围)
+// 禁止访问的范围优先，允许访问的范围不为空时仅允许范围内的IP访问；
+// 之后按countries查询的国家/地区限制(禁止访问的国家/地区优先，内网及本机地址不限制)
+func IPAccessMiddleware(countries ipfilter.CountryLookup, skipper ...SkipperFunc) iris.Handler {
+	var current atomic.Value
+	return func(c iris.Context) {
+		if len(skipper) > 0 && skipper[0](c) {
+			c.Next()
+			return
+		}
+
+		cfg := config.GetGlobalConfig()
+		lists, _ := current.Load().(*ipAccessLists)
+		if lists == nil || lists.cfg != cfg {
+			lists = &ipAccessLists{cfg: cfg}
+			lists.allow, _ = ipfilter.ParseCIDRs(cfg.IPAccess.Allow)
+			lists.deny, _ = ipfilter.ParseCIDRs(cfg.IPAccess.Deny)
+			lists.geoAllow = toSet(cfg.IPAccess.GeoAllow)
+			lists.geoDeny = toSet(cfg.IPAccess.GeoDeny)
+			current.Store(lists)
+		}
+
+		ip := irisplus.GetClientIP(c)
+		if lists.deny.ContainsString(ip) {
+			rejectIPAccess(c, ip, "deny")
+			return
+		}
+		if len(lists.allow) > 0 && !lists.allow.ContainsString(ip) {
+			rejectIPAccess(c, ip, "allow")
+			return
+		}
+		if countries != nil && !allowCountry(lists, countries, net.ParseIP(ip)) {
+			rejectIPAccess(c, ip, "geo")
+			return
+		}
+		c.Next()
+	}
+}
+
+// 检查IP地址所属的国家/地区是否允许访问(内网及本机地址不限制)
+func allowCountry(lists *ipAccessLists, countries ipfilter.CountryLookup, ip net.IP) bool {
+	if len(lists.geoAllow) == 0 && len(lists.geoDeny) == 0 {
+		return true
+	} else if ip == nil {
+		return false
+	} else if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() {
+		return true
+	}
+
+	country := countries.Country(ip)
+	if country != "" && lists.geoDeny[country] {
+		return false
+	}
+	if len(lists.geoAllow) == 0 {
+		return true
+	} else if country == "" {
+		return lists.cfg.IPAccess.GeoAllowUnknown
+	}
+	return lists.geoAllow[country]
+}
+
+// UserIPAccessMiddleware 用户IP访问控制中间件(在用户授权中间件之后使用)
+// 访问IP需要满足用户限定的IP地址范围，并满足其任一角色限定的IP地址范围(存在未限定范围的角色时不按角色限制)，未登录的请求及root用户不限制；
+// 用户及角色的IP地址范围按ip_access.cache_expiration缓存，修改后在缓存过期时生效
+func UserIPAccessMiddleware(bUser bll.IUser, skipper ...SkipperFunc) iris.Handler {
+	cache := &userCIDRsCache{items: make(map[string]*userCIDRs)}
+	return func(c iris.Context) {
+		userID := irisplus.GetUserID(c)
+		if userID == "" || (len(skipper) > 0 && skipper[0](c)) {
+			c.Next()
+			return
+		}
+
+		expiration := time.Duration(config.GetGlobalConfig().IPAccess.CacheExpiration) * time.Second
+		lists, err := cache.get(userID, expiration, func() ([]ipfilter.List, error) {
+			cidrs, err := bUser.GetAllowedCIDRs(irisplus.NewContext(c), userID)
+			if err != nil {
+				return nil, err
+			}
+			lists := make([]ipfilter.List, len(cidrs))
+			for i, item := range cidrs {
+				lists[i], err = ipfilter.ParseCIDRs(item)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+			}
+			return lists, nil
+		})
+		if err != nil {
+			irisplus.ResError(c, err)
+			return
+		}
+
+		ip := irisplus.GetClientIP(c)
+		for _, list := range lists {
+			if !list.ContainsString(ip) {
+				rejectIPAccess(c, ip, "user")
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// 拒绝访问并记录审计日志
+func rejectIPAccess(c iris.Context, ip, reason string) {
+	metrics.IPAccessRejections.WithLabelValues(reason).Inc()
+
+	method := c.Request().Method
+	p := c.Request().URL.Path
+	span := logger.StartSpan(irisplus.NewContext(c), logger.SetSpanTitle("访问控制"), logger.SetSpanFuncName(JoinRouter(method, p)))
+	span.WithFields(map[string]interface{}{
+		"ip":              ip,
+		"remote_addr":     c.RemoteAddr(),
+		"x_forwarded_for": c.GetHeader("X-Forwarded-For"),
+		"method":          method,
+		"url":             c.Request().URL.String(),
+		"reason":          reason,
+		logger.UserIDKey:  irisplus.GetUserID(c),
+	}).Warnf("[ip_access] 拒绝来自%s的访问(%s)", ip, reason)
+
+	irisplus.ResError(c, errors.ErrIPNotAllowed)
+}
+
+// 用户及角色限定的IP地址范围缓存
+type userCIDRsCache struct {
+	lock  sync.RWMutex
+	items map[string]*userCIDRs
+}
+
+type userCIDRs struct {
+	lists    []ipfilter.List
+	expireAt time.Time
+}
+
+// 获取缓存的IP地址范围，缓存不存在或已过期时重新加载(expiration为0时不缓存)
+func (a *userCIDRsCache) get(userID string, expiration time.Duration, load func() ([]ipfilter.List, error)) ([]ipfilter.List, error) {
+	now := time.Now()
+	if expiration > 0 {
+		a.lock.RLock()
+		item, ok := a.items[userID]
+		a.lock.RUnlock()
+		if ok && now.Before(item.expireAt) {
+			return item.lists, nil
+		}
+	}
+
+	lists, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if expiration > 0 {
+		a.lock.Lock()
+		for key, item := range a.items {
+			if !now.Before(item.expireAt) {
+				delete(a.items, key)
+			}
+		}
+		a.items[userID] = &userCIDRs{lists: lists, expireAt: now.Add(expiration)}
+		a.lock.Unlock()
+	}
+	return lists, nil
+}
@@ -2,67 +2,137 @@ package middleware
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
 	"mime"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/wanhello/iris-admin/internal/app/config"
 	"github.com/wanhello/iris-admin/internal/app/irisplus"
 	"github.com/wanhello/iris-admin/pkg/logger"
 
 	"github.com/kataras/iris"
-
 )
 
-// LoggerMiddleware 日志中间件
-func LoggerMiddleware(skipper ...SkipperFunc) iris.HandlerFunc {
+// LoggerMiddleware 日志中间件(隐藏请求头及JSON内容中的敏感信息，支持按路由采样)
+func LoggerMiddleware(skipper ...SkipperFunc) iris.Handler {
 	return func(c iris.Context) {
 		if len(skipper) > 0 && skipper[0](c) {
 			c.Next()
 			return
 		}
 
+		// 每次请求读取配置(支持配置热加载)
+		cfg := config.GetGlobalConfig().AccessLog
+
 		p := c.Request().URL.Path
 		method := c.Request().Method
+		sampled := sampleAccessLog(cfg.Sampling, method, p)
 		span := logger.StartSpan(irisplus.NewContext(c), logger.SetSpanTitle("访问日志"), logger.SetSpanFuncName(JoinRouter(method, p)))
 		start := time.Now()
 
 		fields := make(map[string]interface{})
-		fields["ip"] = c.RemoteAddr()
+		fields["ip"] = irisplus.GetClientIP(c)
 		fields["method"] = method
 		fields["url"] = c.Request().URL.String()
 		fields["proto"] = c.Request().Proto
-		fields["header"] = c.Request().Header
+		fields["header"] = logger.RedactHeader(c.Request().Header, cfg.RedactHeaders)
 		fields["user_agent"] = c.GetHeader("User-Agent")
 
-		// 如果是POST/PUT请求，并且内容类型为JSON，则读取内容体
-		if method == http.MethodPost || method == http.MethodPut {
+		// 如果是POST/PUT请求，并且内容类型为JSON，则读取内容体(未采样的请求不读取)
+		if sampled && (method == http.MethodPost || method == http.MethodPut) {
 			mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
 			if mediaType == "application/json" {
-				body, err := ioutil.ReadAll(c.Request().Body)
-				c.Request().Body.Close()
-				if err == nil {
-					buf := bytes.NewBuffer(body)
-					c.Request().Body = ioutil.NopCloser(buf)
-					fields["content_length"] = c.Request().ContentLength
-					fields["body"] = string(body)
+				fields["content_length"] = c.Request().ContentLength
+				if body, err := readAccessLogBody(c.Request(), cfg); err == nil {
+					fields["body"] = body
 				}
 			}
 		}
+		if cfg.ResponseBody {
+			irisplus.CaptureResBody(c)
+		}
 		c.Next()
 
+		status := c.GetStatusCode()
+		if !sampled && status < http.StatusInternalServerError {
+			return
+		}
+
 		timeConsuming := time.Since(start).Nanoseconds() / 1e6
-		fields["res_status"] = c.ResponseWriter().Status()
-		fields["res_length"] = c.ResponseWriter().Size()
-		if v, ok := c.Get(irisplus.ResBodyKey); ok {
-			if b, ok := v.([]byte); ok {
-				fields["res_body"] = string(b)
+		fields["res_status"] = status
+		fields["res_length"] = c.ResponseWriter().Written()
+		if cfg.ResponseBody {
+			if v := irisplus.GetResBody(c); len(v) > 0 {
+				fields["res_body"] = accessLogBody(v, cfg)
 			}
 		}
 		fields[logger.UserIDKey] = irisplus.GetUserID(c)
 		span.WithFields(fields).Infof("[http] %s-%s-%s-%d(%dms)",
-			p, c.Request().Method, c.RemoteAddr(), c.ResponseWriter().Status(), timeConsuming)
+			p, c.Request().Method, irisplus.GetClientIP(c), status, timeConsuming)
 	}
 }
 
+// 按采样规则确定是否记录访问日志(匹配第一个请求方法及路径前缀相符的规则，没有匹配的规则时记录)
+func sampleAccessLog(rules []config.AccessLogSampling, method, path string) bool {
+	for _, rule := range rules {
+		if rule.Method != "" && !strings.EqualFold(rule.Method, method) {
+			continue
+		}
+		if !strings.HasPrefix(path, rule.Path) {
+			continue
+		}
+		return rand.Float64() < rule.Rate
+	}
+	return true
+}
+
+// 请求体的读取器(先读取已缓存的部分，再读取剩余的部分)
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
 
+// 读取记录到访问日志的请求内容(最多读取max_body_size+1个字节用于记录，剩余的内容不缓存，继续交给后续的处理读取)
+// 超过最大记录长度的内容无法完整解析以隐藏敏感字段，因此不记录
+func readAccessLogBody(r *http.Request, cfg config.AccessLog) (string, error) {
+	max := cfg.MaxBodySize
+	var (
+		head []byte
+		err  error
+	)
+	if max > 0 {
+		head, err = ioutil.ReadAll(io.LimitReader(r.Body, int64(max)+1))
+	} else {
+		head, err = ioutil.ReadAll(r.Body)
+	}
+	r.Body = multiReadCloser{
+		Reader: io.MultiReader(bytes.NewReader(head), r.Body),
+		Closer: r.Body,
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if max > 0 && len(head) > max {
+		return fmt.Sprintf("[json body larger than %d bytes omitted]", max), nil
+	}
+	return accessLogBody(head, cfg), nil
+}
+
+// 获取记录到访问日志的内容(隐藏敏感字段并截断超长的内容，无法解析的JSON内容不记录)
+func accessLogBody(body []byte, cfg config.AccessLog) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	redacted, err := logger.RedactJSON(body, cfg.RedactFields)
+	if err != nil {
+		return "[invalid json body omitted]"
+	}
+	return logger.Truncate(string(redacted), cfg.MaxBodySize)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+)
+
+func TestReadAccessLogBody(t *testing.T) {
+	cfg := config.AccessLog{
+		RedactFields: []string{"password"},
+		MaxBodySize:  64,
+	}
+
+	for _, item := range []struct {
+		body     string
+		expected string
+	}{
+		{`{"user_name":"root","password":"abc"}`, `{"password":"******","user_name":"root"}`},
+		{`{"memo":"` + strings.Repeat("x", 100) + `","password":"abc"}`, "[json body larger than 64 bytes omitted]"},
+	} {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(item.body))
+		logged, err := readAccessLogBody(r, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if logged != item.expected {
+			t.Errorf("expected logged body %q, got %q", item.expected, logged)
+		}
+
+		// 后续的处理仍然能够读取完整的请求内容
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != item.body {
+			t.Errorf("expected request body %q, got %q", item.body, body)
+		}
+		if err := r.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
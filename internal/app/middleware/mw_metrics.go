@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/metrics"
+
+	"github.com/kataras/iris"
+)
+
+// MetricsMiddleware 请求指标中间件(以路由模板作为标签，避免路径参数导致标签值无限增长)
+func MetricsMiddleware(skipper ...SkipperFunc) iris.Handler {
+	return func(c iris.Context) {
+		if len(skipper) > 0 && skipper[0](c) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		route := metrics.RouteUnmatched
+		if r := c.GetCurrentRoute(); r != nil {
+			route = r.Path()
+		}
+		method := c.Method()
+		status := strconv.Itoa(c.GetStatusCode())
+
+		metrics.HTTPRequests.WithLabelValues(route, method, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -1,62 +1,73 @@
 package middleware
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/go-redis/redis"
 	"github.com/wanhello/iris-admin/internal/app/config"
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
+	"github.com/wanhello/iris-admin/pkg/logger"
+	"github.com/wanhello/iris-admin/pkg/ratelimit"
 
-
+	"github.com/casbin/casbin"
+	"github.com/casbin/casbin/util"
 	"github.com/kataras/iris"
-	"github.com/go-redis/redis_rate"
-	"golang.org/x/time/rate"
-
 )
 
-// RateLimiterMiddleware 请求频率限制中间件
-func RateLimiterMiddleware(skipper ...SkipperFunc) iris.HandlerFunc {
-	cfg := config.GetGlobalConfig().RateLimiter
-	if !cfg.Enable {
-		return func(c iris.Context) {
-			c.Next()
-		}
-	}
-
-	rc := config.GetGlobalConfig().Redis
-	ring := redis.NewRing(&redis.RingOptions{
-		Addrs: map[string]string{
-			"server1": rc.Addr,
-		},
-		Password: rc.Password,
-		DB:       cfg.RedisDB,
-	})
-
-	limiter := redis_rate.NewLimiter(ring)
-	limiter.Fallback = rate.NewLimiter(rate.Inf, 0)
+// 未匹配策略时使用的默认策略名称
+const defaultRateLimitPolicy = "default"
 
+// RateLimiterMiddleware 请求频率限制中间件(每次请求读取限流策略，支持配置热加载)
+// 按顺序匹配第一个请求方法、路由及角色相符的策略，未匹配时已登录用户按用户ID、匿名请求按客户端IP限制；
+// 所有响应都返回RateLimit-*响应头，限流发生错误时记录日志，敏感策略拒绝请求，其他策略不限制请求
+func RateLimiterMiddleware(limiter *ratelimit.Limiter, enforcer *casbin.Enforcer, skipper ...SkipperFunc) iris.Handler {
 	return func(c iris.Context) {
-		if (len(skipper) > 0 && skipper[0](c)) || limiter == nil {
+		if limiter == nil || (len(skipper) > 0 && skipper[0](c)) {
 			c.Next()
 			return
 		}
 
+		cfg := config.GetGlobalConfig().RateLimiter
 		userID := irisplus.GetUserID(c)
-		if userID == "" {
-			c.Next()
+		policy := matchRateLimitPolicy(cfg, c.Request().Method, c.Request().URL.Path, func() []string {
+			if userID == "" || enforcer == nil {
+				return nil
+			}
+			roles, _ := enforcer.GetRolesForUser(userID)
+			return roles
+		})
+
+		key := fmt.Sprintf("%s:user:%s", policy.Name, userID)
+		if policy.Key == "ip" || userID == "" {
+			key = fmt.Sprintf("%s:ip:%s", policy.Name, irisplus.GetClientIP(c))
+		}
+
+		limit := ratelimit.Limit{
+			Algorithm: cfg.Algorithm,
+			Rate:      policy.Count,
+			Period:    time.Duration(policy.Period) * time.Second,
+			Burst:     policy.Burst,
+			Strict:    policy.Sensitive,
+		}
+		result, err := limiter.Allow(irisplus.NewContext(c), key, limit)
+		if err != nil {
+			handleRateLimitError(c, policy, err)
 			return
 		}
 
-		limit := cfg.Count
-		rate, delay, allowed := limiter.AllowMinute(userID, limit)
-		if !allowed {
-			h := c.ResponseWriter().Header()
-			h.Set("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
-			h.Set("X-RateLimit-Remaining", strconv.FormatInt(limit-rate, 10))
-			delaySec := int64(delay / time.Second)
-			h.Set("X-RateLimit-Delay", strconv.FormatInt(delaySec, 10))
+		h := c.ResponseWriter().Header()
+		h.Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		h.Set("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		h.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+		h.Set("RateLimit-Policy", rateLimitPolicyHeader(limit))
+
+		if !result.Allowed {
+			metrics.RateLimiterRejections.Inc()
+			h.Set("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
 			irisplus.ResError(c, errors.ErrTooManyRequests)
 			return
 		}
@@ -64,3 +75,87 @@ func RateLimiterMiddleware(skipper ...SkipperFunc) iris.HandlerFunc {
 		c.Next()
 	}
 }
+
+// 限流发生错误时记录日志，敏感策略拒绝请求(503)，其他策略放行
+func handleRateLimitError(c iris.Context, policy config.RateLimiterPolicy, err error) {
+	action := "allow"
+	if policy.Sensitive {
+		action = "deny"
+	}
+	metrics.RateLimiterErrors.WithLabelValues(action).Inc()
+
+	method := c.Request().Method
+	p := c.Request().URL.Path
+	span := logger.StartSpan(irisplus.NewContext(c), logger.SetSpanTitle("请求频率限制"), logger.SetSpanFuncName(JoinRouter(method, p)))
+	span.WithFields(map[string]interface{}{
+		"policy": policy.Name,
+		"action": action,
+	}).Warnf("[rate_limiter] 策略[%s]发生错误(%s)：%s", policy.Name, action, err.Error())
+
+	if policy.Sensitive {
+		irisplus.ResError(c, errors.ErrServiceUnavailable)
+		return
+	}
+	c.Next()
+}
+
+// 匹配第一个请求方法、路由及角色相符的限流策略(roles在策略限定角色时才调用)，未匹配时使用默认策略
+func matchRateLimitPolicy(cfg config.RateLimiter, method, path string, roles func() []string) config.RateLimiterPolicy {
+	var userRoles []string
+	var rolesLoaded bool
+
+	for _, p := range cfg.Policies {
+		if p.Method != "" && !strings.EqualFold(p.Method, method) {
+			continue
+		}
+		if p.Path != "" && !util.KeyMatch2(path, p.Path) {
+			continue
+		}
+		if len(p.Roles) > 0 {
+			if !rolesLoaded {
+				userRoles = roles()
+				rolesLoaded = true
+			}
+			if !containsAny(userRoles, p.Roles) {
+				continue
+			}
+		}
+
+		if p.Period == 0 {
+			p.Period = cfg.Period
+		}
+		return p
+	}
+
+	return config.RateLimiterPolicy{
+		Name:   defaultRateLimitPolicy,
+		Count:  cfg.Count,
+		Period: cfg.Period,
+		Burst:  cfg.Burst,
+	}
+}
+
+func containsAny(values, targets []string) bool {
+	for _, v := range values {
+		for _, t := range targets {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RateLimit-Policy响应头(如：300;w=60，令牌桶算法附加容量)
+func rateLimitPolicyHeader(limit ratelimit.Limit) string {
+	s := fmt.Sprintf("%d;w=%d", limit.Rate, int64(limit.Period/time.Second))
+	if limit.Algorithm == ratelimit.TokenBucket && limit.Burst > 0 {
+		s += fmt.Sprintf(";burst=%d", limit.Burst)
+	}
+	return s
+}
+
+// 向上取整的秒数
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
@@ -21,7 +21,7 @@ var (
 )
 
 // RecoveryMiddleware 崩溃恢复中间件
-func RecoveryMiddleware() iris.HandlerFunc {
+func RecoveryMiddleware() iris.Handler {
 	return func(c iris.Context) {
 		defer func() {
 			if err := recover(); err != nil {
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/pkg/ipfilter"
+
+	"github.com/kataras/iris"
+)
+
+type headerValue struct {
+	key   string
+	value string
+}
+
+// 根据配置生成的安全响应头
+type securityHeaders struct {
+	cfg     *config.Config
+	headers []headerValue
+	hsts    string
+}
+
+// SecurityHeadersMiddleware 安全响应头中间件(全局配置重新加载后重新生成响应头)
+// Strict-Transport-Security仅对https请求(包括可信代理转发的https请求)设定
+func SecurityHeadersMiddleware(skipper ...SkipperFunc) iris.Handler {
+	// 可信代理在加载配置时已校验，且不支持在线更新
+	trusted, _ := ipfilter.ParseCIDRs(config.GetGlobalConfig().HTTP.TrustedProxies)
+
+	var current atomic.Value
+	return func(c iris.Context) {
+		if len(skipper) > 0 && skipper[0](c) {
+			c.Next()
+			return
+		}
+
+		cfg := config.GetGlobalConfig()
+		h, _ := current.Load().(*securityHeaders)
+		if h == nil || h.cfg != cfg {
+			h = newSecurityHeaders(cfg)
+			current.Store(h)
+		}
+
+		header := c.ResponseWriter().Header()
+		for _, item := range h.headers {
+			header.Set(item.key, item.value)
+		}
+
+		if h.hsts != "" {
+			r := c.Request()
+			if r.TLS != nil || (r.Header.Get("X-Forwarded-Proto") == "https" && trusted.ContainsString(ipfilter.RemoteIP(r))) {
+				header.Set("Strict-Transport-Security", h.hsts)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func newSecurityHeaders(cfg *config.Config) *securityHeaders {
+	c := cfg.SecurityHeaders
+	h := &securityHeaders{cfg: cfg}
+	add := func(key, value string) {
+		if value != "" {
+			h.headers = append(h.headers, headerValue{key: key, value: value})
+		}
+	}
+
+	add("Content-Security-Policy", c.ContentSecurityPolicy)
+	add("X-Frame-Options", c.FrameOptions)
+	if c.ContentTypeNosniff {
+		add("X-Content-Type-Options", "nosniff")
+	}
+	add("Referrer-Policy", c.ReferrerPolicy)
+	add("Permissions-Policy", c.PermissionsPolicy)
+	add("Cross-Origin-Opener-Policy", c.CrossOriginOpenerPolicy)
+
+	if c.HSTSMaxAge > 0 {
+		h.hsts = fmt.Sprintf("max-age=%d", c.HSTSMaxAge)
+		if c.HSTSIncludeSubdomains {
+			h.hsts += "; includeSubDomains"
+		}
+		if c.HSTSPreload {
+			h.hsts += "; preload"
+		}
+	}
+	return h
+}
@@ -8,19 +8,23 @@ import (
 )
 
 // TraceMiddleware 跟踪ID中间件
-func TraceMiddleware(skipper ...SkipperFunc) iris.HandlerFunc {
+func TraceMiddleware(skipper ...SkipperFunc) iris.Handler {
 	return func(c iris.Context) {
 		if len(skipper) > 0 && skipper[0](c) {
 			c.Next()
 			return
 		}
 
-		// 优先从请求头中获取请求ID，如果没有则使用UUID
+		// 优先从请求头中获取请求ID，如果没有则使用链路追踪的跟踪ID或UUID
 		traceID := c.GetHeader("X-Request-Id")
 		if traceID == "" {
-			traceID = util.MustUUID()
+			if span := irisplus.GetSpan(c); span != nil && span.SpanContext().IsValid() {
+				traceID = span.SpanContext().TraceID().String()
+			} else {
+				traceID = util.MustUUID()
+			}
 		}
-		c.Params().Set(irisplus.TraceIDKey, traceID)
+		c.Values().Set(irisplus.TraceIDKey, traceID)
 		c.Next()
 	}
 }
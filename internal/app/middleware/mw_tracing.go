@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
+
+	"github.com/kataras/iris"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware 链路追踪中间件(从W3C traceparent请求头中继续上游的跟踪，并以路由模板作为跟踪单元名称)
+// 上游通过baggage请求头传递的数据保留在请求上下文中，随后续的调用继续传递
+func TracingMiddleware(skipper ...SkipperFunc) iris.Handler {
+	return func(c iris.Context) {
+		if len(skipper) > 0 && skipper[0](c) {
+			c.Next()
+			return
+		}
+
+		r := c.Request()
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		if bag := baggage.FromContext(ctx); bag.Len() > 0 {
+			irisplus.SetBaggage(c, bag)
+		}
+
+		route := r.URL.Path
+		if cr := c.GetCurrentRoute(); cr != nil {
+			route = cr.Path()
+		}
+
+		_, span := tracing.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(r.URL.Path),
+				semconv.ClientAddress(irisplus.GetClientIP(c)),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		irisplus.SetSpan(c, span)
+		c.Next()
+
+		status := c.GetStatusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
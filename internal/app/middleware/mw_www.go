@@ -9,7 +9,7 @@ import (
 )
 
 // WWWMiddleware 静态站点中间件
-func WWWMiddleware(root string, skipper ...SkipperFunc) iris.HandlerFunc {
+func WWWMiddleware(root string, skipper ...SkipperFunc) iris.Handler {
 	return func(c iris.Context) {
 		if len(skipper) > 0 && skipper[0](c) {
 			c.Next()
@@ -24,6 +24,6 @@ func WWWMiddleware(root string, skipper ...SkipperFunc) iris.HandlerFunc {
 		}
 
 		http.ServeFile(c.ResponseWriter(), c.Request(), fpath)
-		c.Abort()
+		c.StopExecution()
 	}
 }
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm"
+	"github.com/wanhello/iris-admin/pkg/logger"
+)
+
+// Migrate 执行数据库版本迁移命令
+// 支持的命令：
+//
+//	up [n]        执行未执行的迁移(不指定n时执行全部)
+//	down [n|all]  回滚已执行的迁移(不指定n时回滚最近的一个)
+//	status        查询迁移状态
+func Migrate(ctx context.Context, args []string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	err := config.LoadGlobalConfig(o.configFiles()...)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.GetGlobalConfig()
+	logger.SetSecrets(cfg.Secrets()...)
+	if cfg.Store != "gorm" {
+		return errors.New("仅gorm存储支持数据库迁移")
+	}
+
+	db, err := initGorm()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	gorm.SetTablePrefix(cfg.Gorm.TablePrefix)
+	m, err := gorm.NewMigrator(db, cfg.Gorm.DBType)
+	if err != nil {
+		return err
+	}
+
+	cmd := "status"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	steps := 0
+	if cmd == "down" {
+		steps = 1
+	}
+	if len(args) > 1 {
+		if args[1] == "all" {
+			steps = 0
+		} else if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+			return fmt.Errorf("无效的迁移数量：%s", args[1])
+		}
+	}
+
+	switch cmd {
+	case "up":
+		result, err := m.Up(ctx, steps)
+		for _, item := range result {
+			fmt.Printf("已执行迁移：%d_%s\n", item.Version, item.Name)
+		}
+		if err == nil && len(result) == 0 {
+			fmt.Println("没有需要执行的迁移")
+		}
+		return err
+	case "down":
+		result, err := m.Down(ctx, steps)
+		for _, item := range result {
+			fmt.Printf("已回滚迁移：%d_%s\n", item.Version, item.Name)
+		}
+		if err == nil && len(result) == 0 {
+			fmt.Println("没有需要回滚的迁移")
+		}
+		return err
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED_AT")
+		for _, s := range status {
+			state, appliedAt := "pending", "-"
+			if s.Missing {
+				state = "missing"
+			} else if s.Applied {
+				state = "applied"
+			}
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("未知的迁移命令：%s(支持：up/down/status)", cmd)
+}
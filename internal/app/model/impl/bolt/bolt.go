@@ -0,0 +1,47 @@
+package bolt
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	imodel "github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/model"
+	icache "github.com/wanhello/iris-admin/internal/app/model/impl/cache"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+	"github.com/wanhello/iris-admin/pkg/cache"
+
+	"go.uber.org/dig"
+)
+
+// SetBucketPrefix 设定存储桶名前缀
+func SetBucketPrefix(prefix string) {
+	entity.SetBucketPrefix(prefix)
+}
+
+// CreateBuckets 创建存储桶
+func CreateBuckets(db *boltplus.DB) error {
+	return imodel.CreateBuckets(db)
+}
+
+// Inject 注入bolt实现(用户、角色及菜单存储使用查询缓存装饰，需要先注入*cache.Cache)
+// 嵌入式存储(纯Go实现，不依赖外部数据库服务)，查询在内存中完成过滤、排序及分页，适用于数据量较小的单机部署；
+// 关联数据(用户角色、角色菜单、菜单动作及资源)内嵌在主数据中；
+// 代码生成器目前仅生成gorm实现，新增模块使用bolt存储时需要在此处手动注入
+// 使用方式：
+//
+//	container := dig.New()
+//	Inject(container)
+//	container.Invoke(func(foo IDemo) {
+//	})
+func Inject(container *dig.Container) error {
+	container.Provide(imodel.NewTrans, dig.As(new(model.ITrans)))
+	container.Provide(imodel.NewDemo, dig.As(new(model.IDemo)))
+	container.Provide(func(db *boltplus.DB, c *cache.Cache) model.IMenu {
+		return icache.WrapMenu(imodel.NewMenu(db), c)
+	})
+	container.Provide(func(db *boltplus.DB, c *cache.Cache) model.IRole {
+		return icache.WrapRole(imodel.NewRole(db), c)
+	})
+	container.Provide(func(db *boltplus.DB, c *cache.Cache) model.IUser {
+		return icache.WrapUser(imodel.NewUser(db), c)
+	})
+	return nil
+}
@@ -0,0 +1,35 @@
+package bolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	imodel "github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/model"
+	"github.com/wanhello/iris-admin/internal/app/model/modeltest"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+// 每个测试使用独立的临时数据库文件
+func newTestStore(t *testing.T) *modeltest.Store {
+	db, err := boltplus.New(&boltplus.Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := CreateBuckets(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return &modeltest.Store{
+		Trans: imodel.NewTrans(db),
+		Demo:  imodel.NewDemo(db),
+		User:  imodel.NewUser(db),
+		Role:  imodel.NewRole(db),
+		Menu:  imodel.NewMenu(db),
+	}
+}
+
+func TestConformance(t *testing.T) {
+	modeltest.Run(t, newTestStore)
+}
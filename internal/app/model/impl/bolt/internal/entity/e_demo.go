@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// DemoBucket 获取demo存储桶名称
+func DemoBucket() []byte {
+	return bucketName("demo")
+}
+
+// SchemaDemo demo对象
+type SchemaDemo schema.Demo
+
+// ToDemo 转换为demo实体
+func (a SchemaDemo) ToDemo() *Demo {
+	item := &Demo{
+		RecordID: a.RecordID,
+		Code:     a.Code,
+		Name:     a.Name,
+		Memo:     a.Memo,
+		Status:   a.Status,
+		Creator:  a.Creator,
+		Version:  a.Version,
+	}
+	return item
+}
+
+// Demo demo实体
+type Demo struct {
+	Model
+	RecordID string `json:"record_id"` // 记录内码
+	Code     string `json:"code"`      // 编号
+	Name     string `json:"name"`      // 名称
+	Memo     string `json:"memo"`      // 备注
+	Status   int    `json:"status"`    // 状态(1:启用 2:停用)
+	Creator  string `json:"creator"`   // 创建者
+	Version  int    `json:"version"`   // 版本号(每次更新递增)
+}
+
+func (a Demo) String() string {
+	return toString(a)
+}
+
+// BucketName 存储桶名称
+func (a Demo) BucketName() string {
+	return a.Model.BucketName("demo")
+}
+
+// ToSchemaDemo 转换为demo对象
+func (a Demo) ToSchemaDemo() *schema.Demo {
+	item := &schema.Demo{
+		RecordID:  a.RecordID,
+		Code:      a.Code,
+		Name:      a.Name,
+		Memo:      a.Memo,
+		Status:    a.Status,
+		Creator:   a.Creator,
+		Version:   a.Version,
+		CreatedAt: a.CreatedAt,
+		DeletedAt: a.DeletedAt,
+	}
+	return item
+}
+
+// Demos demo列表
+type Demos []*Demo
+
+// ToSchemaDemos 转换为demo对象列表
+func (a Demos) ToSchemaDemos() []*schema.Demo {
+	list := make([]*schema.Demo, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaDemo()
+	}
+	return list
+}
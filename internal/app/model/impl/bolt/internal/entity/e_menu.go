@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// MenuBucket 获取菜单存储桶名称(菜单动作及资源内嵌在菜单数据中)
+func MenuBucket() []byte {
+	return bucketName("menu")
+}
+
+// SchemaMenu 菜单对象
+type SchemaMenu schema.Menu
+
+// ToMenu 转换为菜单实体
+func (a SchemaMenu) ToMenu() *Menu {
+	item := &Menu{
+		RecordID:   a.RecordID,
+		Name:       a.Name,
+		Sequence:   a.Sequence,
+		Icon:       a.Icon,
+		Router:     a.Router,
+		Hidden:     a.Hidden,
+		ParentID:   a.ParentID,
+		ParentPath: a.ParentPath,
+		Creator:    a.Creator,
+		Version:    a.Version,
+		Actions:    a.ToMenuActions(),
+		Resources:  a.ToMenuResources(),
+	}
+	return item
+}
+
+// ToMenuActions 转换为菜单动作列表
+func (a SchemaMenu) ToMenuActions() []*MenuAction {
+	list := make([]*MenuAction, len(a.Actions))
+	for i, item := range a.Actions {
+		list[i] = &MenuAction{
+			Code: item.Code,
+			Name: item.Name,
+		}
+	}
+	return list
+}
+
+// ToMenuResources 转换为菜单资源列表
+func (a SchemaMenu) ToMenuResources() []*MenuResource {
+	list := make([]*MenuResource, len(a.Resources))
+	for i, item := range a.Resources {
+		list[i] = &MenuResource{
+			Code:   item.Code,
+			Name:   item.Name,
+			Method: item.Method,
+			Path:   item.Path,
+		}
+	}
+	return list
+}
+
+// Menu 菜单实体
+type Menu struct {
+	Model
+	RecordID   string          `json:"record_id"`   // 记录内码
+	Name       string          `json:"name"`        // 菜单名称
+	Sequence   int             `json:"sequence"`    // 排序值
+	Icon       string          `json:"icon"`        // 菜单图标
+	Router     string          `json:"router"`      // 访问路由
+	Hidden     int             `json:"hidden"`      // 隐藏菜单(0:不隐藏 1:隐藏)
+	ParentID   string          `json:"parent_id"`   // 父级内码
+	ParentPath string          `json:"parent_path"` // 父级路径
+	Creator    string          `json:"creator"`     // 创建人
+	Version    int             `json:"version"`     // 版本号(每次更新递增)
+	Actions    []*MenuAction   `json:"actions"`     // 动作列表
+	Resources  []*MenuResource `json:"resources"`   // 资源列表
+}
+
+func (a Menu) String() string {
+	return toString(a)
+}
+
+// BucketName 存储桶名称
+func (a Menu) BucketName() string {
+	return a.Model.BucketName("menu")
+}
+
+// ToSchemaMenu 转换为菜单对象
+func (a Menu) ToSchemaMenu() *schema.Menu {
+	item := &schema.Menu{
+		RecordID:   a.RecordID,
+		Name:       a.Name,
+		Sequence:   a.Sequence,
+		Icon:       a.Icon,
+		Router:     a.Router,
+		Hidden:     a.Hidden,
+		ParentID:   a.ParentID,
+		ParentPath: a.ParentPath,
+		Creator:    a.Creator,
+		Version:    a.Version,
+		CreatedAt:  a.CreatedAt,
+		DeletedAt:  a.DeletedAt,
+	}
+	return item
+}
+
+// ToSchemaMenuActions 转换为菜单动作对象列表
+func (a Menu) ToSchemaMenuActions() []*schema.MenuAction {
+	list := make([]*schema.MenuAction, len(a.Actions))
+	for i, item := range a.Actions {
+		list[i] = &schema.MenuAction{
+			Code: item.Code,
+			Name: item.Name,
+		}
+	}
+	return list
+}
+
+// ToSchemaMenuResources 转换为菜单资源对象列表
+func (a Menu) ToSchemaMenuResources() []*schema.MenuResource {
+	list := make([]*schema.MenuResource, len(a.Resources))
+	for i, item := range a.Resources {
+		list[i] = &schema.MenuResource{
+			Code:   item.Code,
+			Name:   item.Name,
+			Method: item.Method,
+			Path:   item.Path,
+		}
+	}
+	return list
+}
+
+// Menus 菜单实体列表
+type Menus []*Menu
+
+// ToSchemaMenus 转换为菜单对象列表
+func (a Menus) ToSchemaMenus() []*schema.Menu {
+	list := make([]*schema.Menu, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaMenu()
+	}
+	return list
+}
+
+// MenuAction 菜单动作实体
+type MenuAction struct {
+	Code string `json:"code"` // 动作编号
+	Name string `json:"name"` // 动作名称
+}
+
+// MenuResource 菜单资源实体
+type MenuResource struct {
+	Code   string `json:"code"`   // 资源编号
+	Name   string `json:"name"`   // 资源名称
+	Method string `json:"method"` // 请求方式
+	Path   string `json:"path"`   // 请求路径
+}
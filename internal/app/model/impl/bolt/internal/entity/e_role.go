@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// RoleBucket 获取角色存储桶名称(角色菜单关联内嵌在角色数据中)
+func RoleBucket() []byte {
+	return bucketName("role")
+}
+
+// SchemaRole 角色对象
+type SchemaRole schema.Role
+
+// ToRole 转换为角色实体
+func (a SchemaRole) ToRole() *Role {
+	item := &Role{
+		RecordID: a.RecordID,
+		Name:     a.Name,
+		Sequence: a.Sequence,
+		Memo:     a.Memo,
+		Creator:  a.Creator,
+		Version:  a.Version,
+		Menus:    a.ToRoleMenus(),
+
+		AllowedCIDRs: a.AllowedCIDRs,
+	}
+	return item
+}
+
+// ToRoleMenus 转换为角色菜单实体列表
+func (a SchemaRole) ToRoleMenus() []*RoleMenu {
+	list := make([]*RoleMenu, len(a.Menus))
+	for i, item := range a.Menus {
+		list[i] = &RoleMenu{
+			MenuID:    item.MenuID,
+			Actions:   item.Actions,
+			Resources: item.Resources,
+		}
+	}
+	return list
+}
+
+// Role 角色实体
+type Role struct {
+	Model
+	RecordID string      `json:"record_id"` // 记录内码
+	Name     string      `json:"name"`      // 角色名称
+	Sequence int         `json:"sequence"`  // 排序值
+	Memo     string      `json:"memo"`      // 备注
+	Creator  string      `json:"creator"`   // 创建者
+	Version  int         `json:"version"`   // 版本号(每次更新递增)
+	Menus    []*RoleMenu `json:"menus"`     // 菜单权限
+
+	AllowedCIDRs []string `json:"allowed_cidrs"` // 允许访问的IP地址范围
+}
+
+func (a Role) String() string {
+	return toString(a)
+}
+
+// BucketName 存储桶名称
+func (a Role) BucketName() string {
+	return a.Model.BucketName("role")
+}
+
+// ToSchemaRole 转换为角色对象
+func (a Role) ToSchemaRole() *schema.Role {
+	item := &schema.Role{
+		RecordID:     a.RecordID,
+		Name:         a.Name,
+		Sequence:     a.Sequence,
+		Memo:         a.Memo,
+		AllowedCIDRs: a.AllowedCIDRs,
+		Creator:      a.Creator,
+		Version:      a.Version,
+		CreatedAt:    a.CreatedAt,
+		DeletedAt:    a.DeletedAt,
+	}
+	return item
+}
+
+// ToSchemaRoleMenus 转换为角色菜单对象列表
+func (a Role) ToSchemaRoleMenus() []*schema.RoleMenu {
+	list := make([]*schema.RoleMenu, len(a.Menus))
+	for i, item := range a.Menus {
+		list[i] = &schema.RoleMenu{
+			MenuID:    item.MenuID,
+			Actions:   item.Actions,
+			Resources: item.Resources,
+		}
+	}
+	return list
+}
+
+// Roles 角色实体列表
+type Roles []*Role
+
+// ToSchemaRoles 转换为角色对象列表
+func (a Roles) ToSchemaRoles() []*schema.Role {
+	list := make([]*schema.Role, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaRole()
+	}
+	return list
+}
+
+// RoleMenu 角色菜单关联实体
+type RoleMenu struct {
+	MenuID    string   `json:"menu_id"`   // 菜单内码
+	Actions   []string `json:"actions"`   // 动作权限列表
+	Resources []string `json:"resources"` // 资源权限列表
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// UserBucket 获取用户存储桶名称(用户角色关联内嵌在用户数据中)
+func UserBucket() []byte {
+	return bucketName("user")
+}
+
+// SchemaUser 用户对象
+type SchemaUser schema.User
+
+// ToUser 转换为用户实体
+func (a SchemaUser) ToUser() *User {
+	item := &User{
+		RecordID: a.RecordID,
+		UserName: a.UserName,
+		RealName: a.RealName,
+		Password: a.Password,
+		Status:   a.Status,
+		Creator:  a.Creator,
+		Version:  a.Version,
+		Email:    a.Email,
+		Phone:    a.Phone,
+		Roles:    a.ToUserRoles(),
+
+		AllowedCIDRs: a.AllowedCIDRs,
+	}
+	return item
+}
+
+// ToUserRoles 转换为用户角色关联列表
+func (a SchemaUser) ToUserRoles() []*UserRole {
+	list := make([]*UserRole, len(a.Roles))
+	for i, item := range a.Roles {
+		list[i] = &UserRole{
+			RoleID: item.RoleID,
+		}
+	}
+	return list
+}
+
+// User 用户实体
+type User struct {
+	Model
+	RecordID string      `json:"record_id"` // 记录内码
+	UserName string      `json:"user_name"` // 用户名
+	RealName string      `json:"real_name"` // 真实姓名
+	Password string      `json:"password"`  // 密码(sha1(md5(明文))加密)
+	Email    string      `json:"email"`     // 邮箱
+	Phone    string      `json:"phone"`     // 手机号
+	Status   int         `json:"status"`    // 状态(1:启用 2:停用)
+	Creator  string      `json:"creator"`   // 创建者
+	Version  int         `json:"version"`   // 版本号(每次更新递增)
+	Roles    []*UserRole `json:"roles"`     // 角色授权
+
+	AllowedCIDRs []string `json:"allowed_cidrs"` // 允许访问的IP地址范围
+}
+
+func (a User) String() string {
+	return toString(a)
+}
+
+// BucketName 存储桶名称
+func (a User) BucketName() string {
+	return a.Model.BucketName("user")
+}
+
+// ToSchemaUser 转换为用户对象
+func (a User) ToSchemaUser() *schema.User {
+	item := &schema.User{
+		RecordID:     a.RecordID,
+		UserName:     a.UserName,
+		RealName:     a.RealName,
+		Password:     a.Password,
+		Status:       a.Status,
+		Creator:      a.Creator,
+		Version:      a.Version,
+		Email:        a.Email,
+		Phone:        a.Phone,
+		AllowedCIDRs: a.AllowedCIDRs,
+		CreatedAt:    a.CreatedAt,
+		DeletedAt:    a.DeletedAt,
+	}
+	return item
+}
+
+// ToSchemaUserRoles 转换为用户角色对象列表
+func (a User) ToSchemaUserRoles() []*schema.UserRole {
+	list := make([]*schema.UserRole, len(a.Roles))
+	for i, item := range a.Roles {
+		list[i] = &schema.UserRole{
+			RoleID: item.RoleID,
+		}
+	}
+	return list
+}
+
+// Users 用户实体列表
+type Users []*User
+
+// ToSchemaUsers 转换为用户对象列表
+func (a Users) ToSchemaUsers() []*schema.User {
+	list := make([]*schema.User, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaUser()
+	}
+	return list
+}
+
+// UserRole 用户角色关联实体
+type UserRole struct {
+	RoleID string `json:"role_id"` // 角色内码
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"go.etcd.io/bbolt"
+)
+
+// 存储桶名前缀
+var bucketPrefix string
+
+// SetBucketPrefix 设定存储桶名前缀
+func SetBucketPrefix(prefix string) {
+	bucketPrefix = prefix
+}
+
+// GetBucketPrefix 获取存储桶名前缀
+func GetBucketPrefix() string {
+	return bucketPrefix
+}
+
+// Model base model
+type Model struct {
+	ID        uint64     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// BucketName bucket name
+func (Model) BucketName(name string) string {
+	return GetBucketPrefix() + name
+}
+
+// GetModel 获取base model(用于通用的存储操作)
+func (a *Model) GetModel() *Model {
+	return a
+}
+
+// Document 存储的数据(以记录内码作为键，JSON编码后作为值)
+type Document interface {
+	GetModel() *Model
+}
+
+// NewModel 创建新增数据的base model(ID在写入时由存储桶的序列生成)
+func NewModel() Model {
+	now := time.Now()
+	return Model{
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func toString(v interface{}) string {
+	return util.JSONMarshalToString(v)
+}
+
+func bucketName(name string) []byte {
+	return []byte(Model{}.BucketName(name))
+}
+
+// GetTx 获取上下文中的事务(不在事务中时返回nil)
+func GetTx(ctx context.Context) *bbolt.Tx {
+	trans, ok := icontext.FromTrans(ctx)
+	if ok {
+		tx, ok := trans.(*bbolt.Tx)
+		if ok {
+			return tx
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+// CreateBuckets 创建存储桶(存储桶已存在时忽略)
+func CreateBuckets(db *boltplus.DB) error {
+	err := db.CreateBuckets(
+		entity.DemoBucket(),
+		entity.UserBucket(),
+		entity.RoleBucket(),
+		entity.MenuBucket(),
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
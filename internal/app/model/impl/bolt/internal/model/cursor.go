@@ -0,0 +1,228 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"go.etcd.io/bbolt"
+)
+
+// CursorKey 游标分页的排序键(排序值相同时按ID排序)
+type CursorKey struct {
+	Field string // 排序字段(为空时仅按ID排序)
+	Desc  bool   // 是否降序
+}
+
+// 游标数据(编码后对调用方不透明)
+type cursor struct {
+	Field    string      `json:"c,omitempty"` // 排序字段
+	Desc     bool        `json:"d,omitempty"` // 是否降序
+	Value    interface{} `json:"v,omitempty"` // 排序值
+	Time     bool        `json:"t,omitempty"` // 排序值是否是时间
+	ID       uint64      `json:"i"`           // 数据ID
+	Backward bool        `json:"b,omitempty"` // 是否查询上一页
+}
+
+func encodeCursor(c *cursor) (string, error) {
+	buf, err := util.JSONMarshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeCursor(s string, key CursorKey) (*cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	var c cursor
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	// 排序条件变化后游标失效
+	if c.Field != key.Field || c.Desc != key.Desc || c.ID == 0 {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	switch v := c.Value.(type) {
+	case json.Number:
+		if iv, err := v.Int64(); err == nil {
+			c.Value = iv
+		} else if fv, err := v.Float64(); err == nil {
+			c.Value = fv
+		}
+	case string:
+		if c.Time {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, errors.ErrInvalidCursor
+			}
+			c.Value = t
+		}
+	}
+
+	return &c, nil
+}
+
+// 获取游标分页的排序键(游标分页仅支持指定一个排序字段)
+func getCursorKey(spec *schema.QuerySpec, fields QueryFields, defaultKey CursorKey) (CursorKey, error) {
+	if spec == nil || len(spec.Sorts) == 0 {
+		return defaultKey, nil
+	} else if len(spec.Sorts) > 1 {
+		return defaultKey, errors.ErrInvalidCursor
+	}
+
+	field := spec.Sorts[0].Field
+	if _, ok := fields[field]; !ok {
+		return defaultKey, errors.ErrInvalidQueryField
+	}
+	return CursorKey{Field: field, Desc: spec.Sorts[0].Desc}, nil
+}
+
+// 根据数据项创建游标
+func newCursor(key CursorKey, item interface{}, backward bool) (string, error) {
+	c := &cursor{
+		Field:    key.Field,
+		Desc:     key.Desc,
+		ID:       item.(entity.Document).GetModel().ID,
+		Backward: backward,
+	}
+
+	if key.Field != "" {
+		switch v := fieldValue(item, key.Field).(type) {
+		case time.Time:
+			c.Value = v.Format(time.RFC3339Nano)
+			c.Time = true
+		default:
+			c.Value = v
+		}
+	}
+
+	return encodeCursor(c)
+}
+
+// 检查数据是否在游标之后(按排序方向)
+func afterCursor(key CursorKey, c *cursor, desc bool, item interface{}) bool {
+	var n int
+	if key.Field != "" {
+		n = compareValue(fieldValue(item, key.Field), c.Value)
+	}
+	if n == 0 {
+		n = compareValue(item.(entity.Document).GetModel().ID, c.ID)
+	}
+
+	if desc {
+		return n < 0
+	}
+	return n > 0
+}
+
+// WrapCursorQuery 包装游标分页查询(按排序键进行键集分页，在内存中完成过滤及排序)
+// 查询规格中指定排序字段时使用该字段作为排序键，否则使用defaultKey
+func WrapCursorQuery(ctx context.Context, db *boltplus.DB, name []byte, filter Filter, cp *schema.CursorParam, spec *schema.QuerySpec, fields QueryFields, defaultKey CursorKey, out interface{}) (*schema.PaginationResult, error) {
+	key, err := getCursorKey(spec, fields, defaultKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *cursor
+	if cp.Cursor != "" {
+		c, err = decodeCursor(cp.Cursor, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pr := &schema.PaginationResult{
+		Cursor: &schema.CursorResult{
+			Limit: cp.Limit,
+		},
+	}
+
+	err = view(ctx, db, func(tx *bbolt.Tx) error {
+		if cp.Total == schema.CursorTotalEstimate {
+			pr.Total = boltplus.Count(tx, name)
+			pr.Cursor.Counted = true
+			pr.Cursor.Estimated = true
+		}
+		return find(tx, name, filter, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := reflect.ValueOf(out).Elem()
+	if cp.Total == schema.CursorTotalExact {
+		pr.Total = list.Len()
+		pr.Cursor.Counted = true
+	}
+
+	backward := c != nil && c.Backward
+	desc := key.Desc != backward
+
+	if c != nil {
+		result := reflect.MakeSlice(list.Type(), 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			if afterCursor(key, c, desc, list.Index(i).Interface()) {
+				result = reflect.Append(result, list.Index(i))
+			}
+		}
+		list.Set(result)
+	}
+
+	s := Sort{{Field: "id", Desc: desc}}
+	if key.Field != "" {
+		s = append(Sort{{Field: key.Field, Desc: desc}}, s...)
+	}
+	sortList(list, s)
+
+	hasMore := list.Len() > cp.Limit
+	if hasMore {
+		list.Set(list.Slice(0, cp.Limit))
+	}
+
+	n := list.Len()
+	if n == 0 {
+		return pr, nil
+	}
+
+	// 查询上一页时按相反的顺序查询，需要还原顺序
+	if backward {
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			vi, vj := list.Index(i).Interface(), list.Index(j).Interface()
+			list.Index(i).Set(reflect.ValueOf(vj))
+			list.Index(j).Set(reflect.ValueOf(vi))
+		}
+	}
+
+	if (!backward && hasMore) || backward {
+		pr.Cursor.Next, err = newCursor(key, list.Index(n-1).Interface(), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if (backward && hasMore) || (!backward && c != nil) {
+		pr.Cursor.Prev, err = newCursor(key, list.Index(0).Interface(), true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pr, nil
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+// NewDemo 创建demo存储实例
+func NewDemo(db *boltplus.DB) *Demo {
+	return &Demo{db}
+}
+
+// Demo demo存储
+type Demo struct {
+	db *boltplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的demo字段
+var demoQueryFields = QueryFields{
+	"record_id":  FieldString,
+	"code":       FieldString,
+	"name":       FieldString,
+	"memo":       FieldString,
+	"status":     FieldInt,
+	"creator":    FieldString,
+	"version":    FieldInt,
+	"created_at": FieldTime,
+}
+
+func (a *Demo) getQueryOption(opts ...schema.DemoQueryOptions) schema.DemoQueryOptions {
+	var opt schema.DemoQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *Demo) Query(ctx context.Context, params schema.DemoQueryParam, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
+	filter := Filter{notDeleted()}
+	if v := params.Code; v != "" {
+		filter = append(filter, eqValue("code", v))
+	}
+	if v := params.LikeCode; v != "" {
+		filter = append(filter, likeValue("code", v))
+	}
+	if v := params.LikeName; v != "" {
+		filter = append(filter, likeValue("name", v))
+	}
+	if v := params.Status; v > 0 {
+		filter = append(filter, eqValue("status", v))
+	}
+	opt := a.getQueryOption(opts...)
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, demoQueryFields, Sort{{Field: "id", Desc: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	var list entity.Demos
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, entity.DemoBucket(), filter, cp, opt.QuerySpec, demoQueryFields, CursorKey{Desc: true}, &list)
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, entity.DemoBucket(), filter, sort, opt.PageParam, &list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	qr := &schema.DemoQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaDemos(),
+	}
+	return qr, nil
+}
+
+// Get 查询指定数据
+func (a *Demo) Get(ctx context.Context, recordID string, opts ...schema.DemoQueryOptions) (*schema.Demo, error) {
+	var item entity.Demo
+	ok, err := findOne(ctx, a.db, entity.DemoBucket(), recordID, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaDemo(), nil
+}
+
+// Create 创建数据
+func (a *Demo) Create(ctx context.Context, item schema.Demo) error {
+	sitem := entity.SchemaDemo(item)
+	sitem.Version = 1
+	eitem := sitem.ToDemo()
+	eitem.Model = entity.NewModel()
+	return insert(ctx, a.db, entity.DemoBucket(), item.RecordID, eitem)
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) error {
+	var eitem entity.Demo
+	return updateVersion(ctx, a.db, entity.DemoBucket(), recordID, item.Version, &eitem, func() {
+		eitem.Code = item.Code
+		eitem.Name = item.Name
+		eitem.Memo = item.Memo
+		eitem.Status = item.Status
+	})
+}
+
+// Delete 删除数据
+func (a *Demo) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, a.db, entity.DemoBucket(), recordID, new(entity.Demo))
+}
+
+// UpdateStatus 更新状态
+func (a *Demo) UpdateStatus(ctx context.Context, recordID string, status int) error {
+	var eitem entity.Demo
+	return updateFields(ctx, a.db, entity.DemoBucket(), recordID, &eitem, func() {
+		eitem.Status = status
+	})
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Demo) QueryDeleted(ctx context.Context, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Demos
+	pr, err := WrapPageQuery(ctx, a.db, entity.DemoBucket(), Filter{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, err
+	}
+	qr := &schema.DemoQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaDemos(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Demo) GetDeleted(ctx context.Context, recordID string) (*schema.Demo, error) {
+	var item entity.Demo
+	ok, err := findByDeleted(ctx, a.db, entity.DemoBucket(), recordID, true, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaDemo(), nil
+}
+
+// Restore 恢复已删除的数据
+func (a *Demo) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, a.db, entity.DemoBucket(), recordID, new(entity.Demo))
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Demo) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, a.db, entity.DemoBucket(), recordID, new(entity.Demo))
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Demo) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, a.db, entity.DemoBucket(), deletedAt)
+}
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+// NewMenu 创建菜单存储实例
+func NewMenu(db *boltplus.DB) *Menu {
+	return &Menu{db}
+}
+
+// Menu 菜单存储
+type Menu struct {
+	db *boltplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的菜单字段
+var menuQueryFields = QueryFields{
+	"record_id":   FieldString,
+	"name":        FieldString,
+	"sequence":    FieldInt,
+	"icon":        FieldString,
+	"router":      FieldString,
+	"hidden":      FieldInt,
+	"parent_id":   FieldString,
+	"parent_path": FieldString,
+	"creator":     FieldString,
+	"version":     FieldInt,
+	"created_at":  FieldTime,
+}
+
+func (a *Menu) getQueryOption(opts ...schema.MenuQueryOptions) schema.MenuQueryOptions {
+	var opt schema.MenuQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+	filter := Filter{notDeleted()}
+	if v := params.RecordIDs; len(v) > 0 {
+		filter = append(filter, inStrings("record_id", v))
+	}
+	if v := params.LikeName; v != "" {
+		filter = append(filter, likeValue("name", v))
+	}
+	if v := params.ParentID; v != nil {
+		filter = append(filter, eqValue("parent_id", *v))
+	}
+	if v := params.PrefixParentPath; v != "" {
+		filter = append(filter, prefixValue("parent_path", v))
+	}
+	if v := params.Hidden; v != nil {
+		filter = append(filter, eqValue("hidden", *v))
+	}
+	opt := a.getQueryOption(opts...)
+	defaultSort := Sort{{Field: "sequence", Desc: true}, {Field: "id", Desc: true}}
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, menuQueryFields, defaultSort)
+	if err != nil {
+		return nil, err
+	}
+
+	var list entity.Menus
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, entity.MenuBucket(), filter, cp, opt.QuerySpec, menuQueryFields, CursorKey{Field: "sequence", Desc: true}, &list)
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, entity.MenuBucket(), filter, sort, opt.PageParam, &list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	qr := &schema.MenuQueryResult{
+		PageResult: pr,
+		Data:       a.toSchemaMenus(list, opts...),
+	}
+	return qr, nil
+}
+
+// 转换为菜单对象列表(动作及资源内嵌在菜单数据中，按需填充)
+func (a *Menu) toSchemaMenus(list entity.Menus, opts ...schema.MenuQueryOptions) []*schema.Menu {
+	opt := a.getQueryOption(opts...)
+
+	items := list.ToSchemaMenus()
+	for i, item := range list {
+		if opt.IncludeActions && len(item.Actions) > 0 {
+			items[i].Actions = item.ToSchemaMenuActions()
+		}
+		if opt.IncludeResources && len(item.Resources) > 0 {
+			items[i].Resources = item.ToSchemaMenuResources()
+		}
+	}
+	return items
+}
+
+// Get 查询指定数据
+func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQueryOptions) (*schema.Menu, error) {
+	var item entity.Menu
+	ok, err := findOne(ctx, a.db, entity.MenuBucket(), recordID, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return a.toSchemaMenus(entity.Menus{&item}, opts...)[0], nil
+}
+
+// Create 创建数据(动作及资源内嵌在菜单数据中)
+func (a *Menu) Create(ctx context.Context, item schema.Menu) error {
+	sitem := entity.SchemaMenu(item)
+	sitem.Version = 1
+	eitem := sitem.ToMenu()
+	eitem.Model = entity.NewModel()
+	return insert(ctx, a.db, entity.MenuBucket(), item.RecordID, eitem)
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) error {
+	sitem := entity.SchemaMenu(item)
+	var eitem entity.Menu
+	return updateVersion(ctx, a.db, entity.MenuBucket(), recordID, item.Version, &eitem, func() {
+		eitem.Name = item.Name
+		eitem.Sequence = item.Sequence
+		eitem.Icon = item.Icon
+		eitem.Router = item.Router
+		eitem.Hidden = item.Hidden
+		eitem.ParentID = item.ParentID
+		eitem.ParentPath = item.ParentPath
+		eitem.Actions = sitem.ToMenuActions()
+		eitem.Resources = sitem.ToMenuResources()
+	})
+}
+
+// UpdateParentPath 更新父级路径
+func (a *Menu) UpdateParentPath(ctx context.Context, recordID, parentPath string) error {
+	var eitem entity.Menu
+	return updateFields(ctx, a.db, entity.MenuBucket(), recordID, &eitem, func() {
+		eitem.ParentPath = parentPath
+	})
+}
+
+// Delete 删除数据
+func (a *Menu) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, a.db, entity.MenuBucket(), recordID, new(entity.Menu))
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Menu) QueryDeleted(ctx context.Context, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Menus
+	pr, err := WrapPageQuery(ctx, a.db, entity.MenuBucket(), Filter{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, err
+	}
+	qr := &schema.MenuQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaMenus(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Menu) GetDeleted(ctx context.Context, recordID string) (*schema.Menu, error) {
+	var item entity.Menu
+	ok, err := findByDeleted(ctx, a.db, entity.MenuBucket(), recordID, true, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaMenu(), nil
+}
+
+// Restore 恢复已删除的数据(包括内嵌的菜单动作及资源数据)
+func (a *Menu) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, a.db, entity.MenuBucket(), recordID, new(entity.Menu))
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Menu) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, a.db, entity.MenuBucket(), recordID, new(entity.Menu))
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Menu) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, a.db, entity.MenuBucket(), deletedAt)
+}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+// NewRole 创建角色存储实例
+func NewRole(db *boltplus.DB) *Role {
+	return &Role{db}
+}
+
+// Role 角色存储
+type Role struct {
+	db *boltplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的角色字段
+var roleQueryFields = QueryFields{
+	"record_id":  FieldString,
+	"name":       FieldString,
+	"sequence":   FieldInt,
+	"memo":       FieldString,
+	"creator":    FieldString,
+	"version":    FieldInt,
+	"created_at": FieldTime,
+}
+
+func (a *Role) getQueryOption(opts ...schema.RoleQueryOptions) schema.RoleQueryOptions {
+	var opt schema.RoleQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+	recordIDs := params.RecordIDs
+	if v := params.UserID; v != "" {
+		roleIDs, err := a.queryUserRoleIDs(ctx, v, recordIDs)
+		if err != nil {
+			return nil, err
+		}
+		recordIDs = roleIDs
+	}
+
+	filter := Filter{notDeleted()}
+	if v := recordIDs; len(v) > 0 || params.UserID != "" {
+		filter = append(filter, inStrings("record_id", v))
+	}
+	if v := params.Name; v != "" {
+		filter = append(filter, eqValue("name", v))
+	}
+	if v := params.LikeName; v != "" {
+		filter = append(filter, likeValue("name", v))
+	}
+	opt := a.getQueryOption(opts...)
+	defaultSort := Sort{{Field: "sequence", Desc: true}, {Field: "id", Desc: true}}
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, roleQueryFields, defaultSort)
+	if err != nil {
+		return nil, err
+	}
+
+	var list entity.Roles
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, entity.RoleBucket(), filter, cp, opt.QuerySpec, roleQueryFields, CursorKey{Field: "sequence", Desc: true}, &list)
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, entity.RoleBucket(), filter, sort, opt.PageParam, &list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	qr := &schema.RoleQueryResult{
+		PageResult: pr,
+		Data:       a.toSchemaRoles(list, opts...),
+	}
+	return qr, nil
+}
+
+// 查询用户授权的角色ID列表(用户角色关联内嵌在用户数据中，指定recordIDs时取交集)
+func (a *Role) queryUserRoleIDs(ctx context.Context, userID string, recordIDs []string) ([]string, error) {
+	var user entity.User
+	ok, err := findOne(ctx, a.db, entity.UserBucket(), userID, &user)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	roleIDs := make([]string, 0, len(user.Roles))
+	for _, item := range user.Roles {
+		if len(recordIDs) > 0 && !containsString(recordIDs, item.RoleID) {
+			continue
+		}
+		roleIDs = append(roleIDs, item.RoleID)
+	}
+	return roleIDs, nil
+}
+
+// 转换为角色对象列表(菜单权限内嵌在角色数据中，按需填充)
+func (a *Role) toSchemaRoles(list entity.Roles, opts ...schema.RoleQueryOptions) []*schema.Role {
+	opt := a.getQueryOption(opts...)
+
+	items := list.ToSchemaRoles()
+	if opt.IncludeMenus {
+		for i, item := range list {
+			if len(item.Menus) > 0 {
+				items[i].Menus = item.ToSchemaRoleMenus()
+			}
+		}
+	}
+	return items
+}
+
+// Get 查询指定数据
+func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQueryOptions) (*schema.Role, error) {
+	var item entity.Role
+	ok, err := findOne(ctx, a.db, entity.RoleBucket(), recordID, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return a.toSchemaRoles(entity.Roles{&item}, opts...)[0], nil
+}
+
+// Create 创建数据(角色菜单关联内嵌在角色数据中)
+func (a *Role) Create(ctx context.Context, item schema.Role) error {
+	sitem := entity.SchemaRole(item)
+	sitem.Version = 1
+	eitem := sitem.ToRole()
+	eitem.Model = entity.NewModel()
+	return insert(ctx, a.db, entity.RoleBucket(), item.RecordID, eitem)
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *Role) Update(ctx context.Context, recordID string, item schema.Role) error {
+	sitem := entity.SchemaRole(item)
+	var eitem entity.Role
+	return updateVersion(ctx, a.db, entity.RoleBucket(), recordID, item.Version, &eitem, func() {
+		eitem.Name = item.Name
+		eitem.Sequence = item.Sequence
+		eitem.Memo = item.Memo
+		eitem.AllowedCIDRs = item.AllowedCIDRs
+		eitem.Menus = sitem.ToRoleMenus()
+	})
+}
+
+// Delete 删除数据
+func (a *Role) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, a.db, entity.RoleBucket(), recordID, new(entity.Role))
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Role) QueryDeleted(ctx context.Context, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Roles
+	pr, err := WrapPageQuery(ctx, a.db, entity.RoleBucket(), Filter{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, err
+	}
+	qr := &schema.RoleQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaRoles(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Role) GetDeleted(ctx context.Context, recordID string) (*schema.Role, error) {
+	var item entity.Role
+	ok, err := findByDeleted(ctx, a.db, entity.RoleBucket(), recordID, true, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaRole(), nil
+}
+
+// Restore 恢复已删除的数据(包括内嵌的角色菜单关联数据)
+func (a *Role) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, a.db, entity.RoleBucket(), recordID, new(entity.Role))
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Role) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, a.db, entity.RoleBucket(), recordID, new(entity.Role))
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Role) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, a.db, entity.RoleBucket(), deletedAt)
+}
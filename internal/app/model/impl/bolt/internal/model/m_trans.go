@@ -0,0 +1,57 @@
+package model
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+
+	"go.etcd.io/bbolt"
+)
+
+// NewTrans 创建事务管理实例
+func NewTrans(db *boltplus.DB) *Trans {
+	return &Trans{db}
+}
+
+// Trans 事务管理(bolt同时只允许一个读写事务，事务提交或回滚前其他写操作将会等待)
+type Trans struct {
+	db *boltplus.DB
+}
+
+// Begin 开启事务
+func (a *Trans) Begin(ctx context.Context) (interface{}, error) {
+	tx, err := a.db.Begin(true)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tx, nil
+}
+
+// Commit 提交事务
+func (a *Trans) Commit(ctx context.Context, trans interface{}) error {
+	tx, ok := trans.(*bbolt.Tx)
+	if !ok {
+		return errors.New("unknow trans")
+	}
+
+	err := tx.Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Rollback 回滚事务
+func (a *Trans) Rollback(ctx context.Context, trans interface{}) error {
+	tx, ok := trans.(*bbolt.Tx)
+	if !ok {
+		return errors.New("unknow trans")
+	}
+
+	err := tx.Rollback()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
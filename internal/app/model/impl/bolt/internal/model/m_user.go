@@ -0,0 +1,218 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+// NewUser 创建用户存储实例
+func NewUser(db *boltplus.DB) *User {
+	return &User{db}
+}
+
+// User 用户存储
+type User struct {
+	db *boltplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的用户字段(不允许包含敏感字段)
+var userQueryFields = QueryFields{
+	"record_id":  FieldString,
+	"user_name":  FieldString,
+	"real_name":  FieldString,
+	"phone":      FieldString,
+	"email":      FieldString,
+	"status":     FieldInt,
+	"creator":    FieldString,
+	"version":    FieldInt,
+	"created_at": FieldTime,
+}
+
+func (a *User) getQueryOption(opts ...schema.UserQueryOptions) schema.UserQueryOptions {
+	var opt schema.UserQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// 用户授权了任一指定角色
+func hasAnyRole(roleIDs []string) Cond {
+	return func(item interface{}) bool {
+		for _, role := range item.(*entity.User).Roles {
+			if containsString(roleIDs, role.RoleID) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Query 查询数据
+func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	filter := Filter{notDeleted()}
+	if v := params.RecordIDs; len(v) > 0 {
+		filter = append(filter, inStrings("record_id", v))
+	}
+	if v := params.UserName; v != "" {
+		filter = append(filter, eqValue("user_name", v))
+	}
+	if v := params.LikeUserName; v != "" {
+		filter = append(filter, likeValue("user_name", v))
+	}
+	if v := params.LikeRealName; v != "" {
+		filter = append(filter, likeValue("real_name", v))
+	}
+	if v := params.Status; v > 0 {
+		filter = append(filter, eqValue("status", v))
+	}
+	if v := params.RoleIDs; len(v) > 0 {
+		filter = append(filter, hasAnyRole(v))
+	}
+	opt := a.getQueryOption(opts...)
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, userQueryFields, Sort{{Field: "id", Desc: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	var list entity.Users
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, entity.UserBucket(), filter, cp, opt.QuerySpec, userQueryFields, CursorKey{Desc: true}, &list)
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, entity.UserBucket(), filter, sort, opt.PageParam, &list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	qr := &schema.UserQueryResult{
+		PageResult: pr,
+		Data:       a.toSchemaUsers(list, opts...),
+	}
+	return qr, nil
+}
+
+// 转换为用户对象列表(角色授权内嵌在用户数据中，按需填充)
+func (a *User) toSchemaUsers(list entity.Users, opts ...schema.UserQueryOptions) []*schema.User {
+	opt := a.getQueryOption(opts...)
+
+	items := list.ToSchemaUsers()
+	if opt.IncludeRoles {
+		for i, item := range list {
+			if len(item.Roles) > 0 {
+				items[i].Roles = item.ToSchemaUserRoles()
+			}
+		}
+	}
+	return items
+}
+
+// Get 查询指定数据
+func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQueryOptions) (*schema.User, error) {
+	var item entity.User
+	ok, err := findOne(ctx, a.db, entity.UserBucket(), recordID, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return a.toSchemaUsers(entity.Users{&item}, opts...)[0], nil
+}
+
+// Create 创建数据(用户角色关联内嵌在用户数据中)
+func (a *User) Create(ctx context.Context, item schema.User) error {
+	sitem := entity.SchemaUser(item)
+	sitem.Version = 1
+	eitem := sitem.ToUser()
+	eitem.Model = entity.NewModel()
+	return insert(ctx, a.db, entity.UserBucket(), item.RecordID, eitem)
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *User) Update(ctx context.Context, recordID string, item schema.User) error {
+	sitem := entity.SchemaUser(item)
+	var eitem entity.User
+	return updateVersion(ctx, a.db, entity.UserBucket(), recordID, item.Version, &eitem, func() {
+		eitem.UserName = item.UserName
+		eitem.RealName = item.RealName
+		eitem.Email = item.Email
+		eitem.Phone = item.Phone
+		eitem.Status = item.Status
+		eitem.AllowedCIDRs = item.AllowedCIDRs
+		eitem.Roles = sitem.ToUserRoles()
+		if item.Password != "" {
+			eitem.Password = item.Password
+		}
+	})
+}
+
+// Delete 删除数据
+func (a *User) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, a.db, entity.UserBucket(), recordID, new(entity.User))
+}
+
+// UpdateStatus 更新状态
+func (a *User) UpdateStatus(ctx context.Context, recordID string, status int) error {
+	var eitem entity.User
+	return updateFields(ctx, a.db, entity.UserBucket(), recordID, &eitem, func() {
+		eitem.Status = status
+	})
+}
+
+// UpdatePassword 更新密码
+func (a *User) UpdatePassword(ctx context.Context, recordID, password string) error {
+	var eitem entity.User
+	return updateFields(ctx, a.db, entity.UserBucket(), recordID, &eitem, func() {
+		eitem.Password = password
+	})
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *User) QueryDeleted(ctx context.Context, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Users
+	pr, err := WrapPageQuery(ctx, a.db, entity.UserBucket(), Filter{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, err
+	}
+	qr := &schema.UserQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaUsers(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *User) GetDeleted(ctx context.Context, recordID string) (*schema.User, error) {
+	var item entity.User
+	ok, err := findByDeleted(ctx, a.db, entity.UserBucket(), recordID, true, &item)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaUser(), nil
+}
+
+// Restore 恢复已删除的数据(包括内嵌的用户角色关联数据)
+func (a *User) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, a.db, entity.UserBucket(), recordID, new(entity.User))
+}
+
+// Purge 彻底删除已删除的数据
+func (a *User) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, a.db, entity.UserBucket(), recordID, new(entity.User))
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *User) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, a.db, entity.UserBucket(), deletedAt)
+}
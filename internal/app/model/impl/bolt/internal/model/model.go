@@ -0,0 +1,656 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"go.etcd.io/bbolt"
+)
+
+// ExecTrans 执行事务
+func ExecTrans(ctx context.Context, db *boltplus.DB, fn func(context.Context) error) error {
+	if _, ok := icontext.FromTrans(ctx); ok {
+		return fn(ctx)
+	}
+
+	transModel := NewTrans(db)
+	trans, err := transModel.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(icontext.NewTrans(ctx, trans))
+	if err != nil {
+		_ = transModel.Rollback(ctx, trans)
+		return err
+	}
+	return transModel.Commit(ctx, trans)
+}
+
+// 执行只读操作(在事务中时使用当前事务)
+func view(ctx context.Context, db *boltplus.DB, fn func(*bbolt.Tx) error) error {
+	return db.Exec(entity.GetTx(ctx), false, fn)
+}
+
+// 执行读写操作(在事务中时使用当前事务)
+func update(ctx context.Context, db *boltplus.DB, fn func(*bbolt.Tx) error) error {
+	return db.Exec(entity.GetTx(ctx), true, fn)
+}
+
+// Cond 过滤条件(item为实体指针)
+type Cond func(item interface{}) bool
+
+// Filter 过滤条件列表(满足全部条件时匹配)
+type Filter []Cond
+
+// Match 检查数据是否匹配
+func (a Filter) Match(item interface{}) bool {
+	for _, cond := range a {
+		if !cond(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// SortField 排序字段
+type SortField struct {
+	Field string // 字段名
+	Desc  bool   // 是否降序
+}
+
+// Sort 排序字段列表
+type Sort []SortField
+
+// 未删除数据的过滤条件
+func notDeleted() Cond {
+	return func(item interface{}) bool {
+		return item.(entity.Document).GetModel().DeletedAt == nil
+	}
+}
+
+// 已删除数据的过滤条件
+func isDeleted() Cond {
+	return func(item interface{}) bool {
+		return item.(entity.Document).GetModel().DeletedAt != nil
+	}
+}
+
+// 已删除数据的排序
+func deletedSort() Sort {
+	return Sort{{Field: "deleted_at", Desc: true}, {Field: "id", Desc: true}}
+}
+
+// 字段值等于value
+func eqValue(field string, value interface{}) Cond {
+	return func(item interface{}) bool {
+		return compareValue(fieldValue(item, field), value) == 0
+	}
+}
+
+// 字段值在values中
+func inValues(field string, values []interface{}) Cond {
+	return func(item interface{}) bool {
+		v := fieldValue(item, field)
+		for _, value := range values {
+			if compareValue(v, value) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// 字段值在字符串列表中
+func inStrings(field string, values []string) Cond {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return inValues(field, list)
+}
+
+// 模糊匹配(与数据库的LIKE查询一致，不区分大小写)
+func likeValue(field, value string) Cond {
+	value = strings.ToLower(value)
+	return func(item interface{}) bool {
+		s := fmt.Sprint(fieldValue(item, field))
+		return strings.Contains(strings.ToLower(s), value)
+	}
+}
+
+// 前缀匹配
+func prefixValue(field, value string) Cond {
+	return func(item interface{}) bool {
+		s, _ := fieldValue(item, field).(string)
+		return strings.HasPrefix(s, value)
+	}
+}
+
+// 字段值与value比较的结果满足fn
+func compareCond(field string, value interface{}, fn func(int) bool) Cond {
+	return func(item interface{}) bool {
+		return fn(compareValue(fieldValue(item, field), value))
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 实体类型的JSON字段名与字段索引的映射(包括嵌入的base model字段)
+var fieldIndexes sync.Map
+
+func getFieldIndexes(typ reflect.Type) map[string][]int {
+	if v, ok := fieldIndexes.Load(typ); ok {
+		return v.(map[string][]int)
+	}
+
+	indexes := make(map[string][]int)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for name, index := range getFieldIndexes(field.Type) {
+				indexes[name] = append([]int{i}, index...)
+			}
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		indexes[name] = []int{i}
+	}
+
+	fieldIndexes.Store(typ, indexes)
+	return indexes
+}
+
+// 获取实体指定JSON字段的值(字段不存在时返回nil)
+func fieldValue(item interface{}, name string) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	index, ok := getFieldIndexes(v.Type())[name]
+	if !ok {
+		return nil
+	}
+
+	fv := v.FieldByIndex(index)
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	return fv.Interface()
+}
+
+// 递增实体的版本号
+func incVersion(item entity.Document) {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	fv := v.FieldByIndex(getFieldIndexes(v.Type())["version"])
+	fv.SetInt(fv.Int() + 1)
+}
+
+// 将值转换为可比较的类型(整数、浮点数、字符串及时间)
+func normalizeValue(v interface{}) interface{} {
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return v
+}
+
+// 比较两个值的大小(nil小于任何值，类型不一致时按字符串比较)
+func compareValue(a, b interface{}) int {
+	a, b = normalizeValue(a), normalizeValue(b)
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		}
+		return 1
+	}
+
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1
+			case av > bv:
+				return 1
+			}
+			return 0
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv)
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			switch {
+			case av.Before(bv):
+				return -1
+			case av.After(bv):
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// 按排序字段对实体列表排序(list为实体指针列表)
+func sortList(list reflect.Value, s Sort) {
+	sort.SliceStable(list.Interface(), func(i, j int) bool {
+		vi, vj := list.Index(i).Interface(), list.Index(j).Interface()
+		for _, item := range s {
+			c := compareValue(fieldValue(vi, item.Field), fieldValue(vj, item.Field))
+			if c == 0 {
+				continue
+			}
+			if item.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// 查询存储桶中满足过滤条件的数据(out为实体指针列表的指针)
+func find(tx *bbolt.Tx, name []byte, filter Filter, out interface{}) error {
+	list := reflect.ValueOf(out).Elem()
+	typ := list.Type().Elem().Elem()
+	err := boltplus.Scan(tx, name, func(k, v []byte) error {
+		item := reflect.New(typ)
+		err := util.JSONUnmarshal(v, item.Interface())
+		if err != nil {
+			return err
+		}
+		if filter.Match(item.Interface()) {
+			list.Set(reflect.Append(list, item))
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 查询指定记录内码的数据
+func getOne(tx *bbolt.Tx, name []byte, recordID string, out entity.Document) (bool, error) {
+	b := tx.Bucket(name)
+	if b == nil {
+		return false, nil
+	}
+
+	v := b.Get([]byte(recordID))
+	if v == nil {
+		return false, nil
+	}
+
+	err := util.JSONUnmarshal(v, out)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// 写入指定记录内码的数据
+func putOne(tx *bbolt.Tx, name []byte, recordID string, item entity.Document) error {
+	b, err := tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	buf, err := util.JSONMarshal(item)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = b.Put([]byte(recordID), buf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 查询未删除的指定数据(在事务中时使用当前事务)
+func findOne(ctx context.Context, db *boltplus.DB, name []byte, recordID string, out entity.Document) (bool, error) {
+	return findByDeleted(ctx, db, name, recordID, false, out)
+}
+
+// 查询指定数据(deleted指定查询已删除或者未删除的数据)
+func findByDeleted(ctx context.Context, db *boltplus.DB, name []byte, recordID string, deleted bool, out entity.Document) (bool, error) {
+	var ok bool
+	err := view(ctx, db, func(tx *bbolt.Tx) error {
+		var err error
+		ok, err = getOne(tx, name, recordID, out)
+		return err
+	})
+	if err != nil || !ok {
+		return false, err
+	}
+	return (out.GetModel().DeletedAt != nil) == deleted, nil
+}
+
+// 新增数据(记录内码已存在时返回ErrResourceExists，ID使用存储桶的序列生成)
+func insert(ctx context.Context, db *boltplus.DB, name []byte, recordID string, item entity.Document) error {
+	return update(ctx, db, func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(name)
+		if err != nil {
+			return errors.WithStack(err)
+		} else if b.Get([]byte(recordID)) != nil {
+			return errors.ErrResourceExists
+		}
+
+		id, err := b.NextSequence()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		item.GetModel().ID = id
+		return putOne(tx, name, recordID, item)
+	})
+}
+
+// 修改指定数据(deleted指定修改已删除或者未删除的数据，数据不存在时返回false)
+// fn返回false时不写入数据
+func modify(ctx context.Context, db *boltplus.DB, name []byte, recordID string, deleted bool, item entity.Document, fn func() bool) (bool, error) {
+	var ok bool
+	err := update(ctx, db, func(tx *bbolt.Tx) error {
+		found, err := getOne(tx, name, recordID, item)
+		if err != nil {
+			return err
+		} else if !found || (item.GetModel().DeletedAt != nil) != deleted {
+			return nil
+		}
+
+		ok = fn()
+		if !ok {
+			return nil
+		}
+		return putOne(tx, name, recordID, item)
+	})
+	return ok, err
+}
+
+// 更新数据(仅当版本号与version一致时调用fn修改数据并递增版本号，否则返回ErrResourceConflict)
+func updateVersion(ctx context.Context, db *boltplus.DB, name []byte, recordID string, version int, item entity.Document, fn func()) error {
+	ok, err := modify(ctx, db, name, recordID, false, item, func() bool {
+		if v, _ := fieldValue(item, "version").(int); v != version {
+			return false
+		}
+		fn()
+		incVersion(item)
+		item.GetModel().UpdatedAt = time.Now()
+		return true
+	})
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.ErrResourceConflict
+	}
+	return nil
+}
+
+// 更新未删除数据的指定字段(由fn修改，同时递增版本号)
+func updateFields(ctx context.Context, db *boltplus.DB, name []byte, recordID string, item entity.Document, fn func()) error {
+	_, err := modify(ctx, db, name, recordID, false, item, func() bool {
+		fn()
+		incVersion(item)
+		item.GetModel().UpdatedAt = time.Now()
+		return true
+	})
+	return err
+}
+
+// 删除数据(标记删除时间，关联数据内嵌在数据中一同删除)
+func softDelete(ctx context.Context, db *boltplus.DB, name []byte, recordID string, item entity.Document) error {
+	_, err := modify(ctx, db, name, recordID, false, item, func() bool {
+		now := time.Now()
+		item.GetModel().DeletedAt = &now
+		return true
+	})
+	return err
+}
+
+// 恢复已删除的数据(关联数据内嵌在数据中一同恢复)
+func restore(ctx context.Context, db *boltplus.DB, name []byte, recordID string, item entity.Document) error {
+	_, err := modify(ctx, db, name, recordID, true, item, func() bool {
+		item.GetModel().DeletedAt = nil
+		item.GetModel().UpdatedAt = time.Now()
+		incVersion(item)
+		return true
+	})
+	return err
+}
+
+// 彻底删除已删除的数据
+func purge(ctx context.Context, db *boltplus.DB, name []byte, recordID string, item entity.Document) error {
+	return update(ctx, db, func(tx *bbolt.Tx) error {
+		ok, err := getOne(tx, name, recordID, item)
+		if err != nil || !ok || item.GetModel().DeletedAt == nil {
+			return err
+		}
+		return errors.WithStack(tx.Bucket(name).Delete([]byte(recordID)))
+	})
+}
+
+// 彻底删除指定时间之前删除的数据
+func purgeBefore(ctx context.Context, db *boltplus.DB, name []byte, deletedAt time.Time) error {
+	return update(ctx, db, func(tx *bbolt.Tx) error {
+		// 遍历时不能删除数据，先收集需要删除的键
+		var keys [][]byte
+		err := boltplus.Scan(tx, name, func(k, v []byte) error {
+			var item entity.Model
+			err := util.JSONUnmarshal(v, &item)
+			if err != nil {
+				return err
+			}
+			if item.DeletedAt != nil && item.DeletedAt.Before(deletedAt) {
+				keys = append(keys, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		for _, k := range keys {
+			err := tx.Bucket(name).Delete(k)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	})
+}
+
+// WrapPageQuery 包装带有分页的查询(在内存中过滤、排序及分页，适用于嵌入式部署的数据规模)
+func WrapPageQuery(ctx context.Context, db *boltplus.DB, name []byte, filter Filter, s Sort, pp *schema.PaginationParam, out interface{}) (*schema.PaginationResult, error) {
+	err := view(ctx, db, func(tx *bbolt.Tx) error {
+		return find(tx, name, filter, out)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := reflect.ValueOf(out).Elem()
+	sortList(list, s)
+	if pp == nil {
+		return nil, nil
+	}
+
+	total := list.Len()
+	// 如果分页大小小于0或者分页索引小于0，则不返回数据
+	if pp.PageSize < 0 || pp.PageIndex < 0 {
+		list.Set(list.Slice(0, 0))
+		return &schema.PaginationResult{Total: total}, nil
+	}
+
+	start, end := 0, total
+	if pp.PageIndex > 0 {
+		start = (pp.PageIndex - 1) * pp.PageSize
+	}
+	if pp.PageSize > 0 {
+		end = start + pp.PageSize
+	}
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	list.Set(list.Slice(start, end))
+
+	return &schema.PaginationResult{
+		Total: total,
+	}, nil
+}
+
+// 定义查询字段的值类型(过滤条件的值需要转换为字段对应的类型)
+const (
+	FieldString = iota // 字符串
+	FieldInt           // 整数
+	FieldTime          // 时间
+)
+
+// QueryFields 定义允许查询(排序、字段选择及过滤)的字段与值类型的映射
+type QueryFields map[string]int
+
+// 支持的时间格式(使用本地时区解析)
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// 将过滤条件的值转换为字段对应的类型
+func parseFieldValue(typ int, s string) (interface{}, error) {
+	switch typ {
+	case FieldInt:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.ErrInvalidQueryField
+		}
+		return v, nil
+	case FieldTime:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return nil, errors.ErrInvalidQueryField
+	}
+	return s, nil
+}
+
+func parseFieldValues(typ int, values []string) ([]interface{}, error) {
+	list := make([]interface{}, len(values))
+	for i, s := range values {
+		v, err := parseFieldValue(typ, s)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// WrapQuerySpec 包装通用查询规格(仅允许使用fields中定义的字段，否则返回ErrInvalidQueryField)
+// 返回合并后的过滤条件及排序，未指定排序字段时使用defaultSort排序
+func WrapQuerySpec(filter Filter, spec *schema.QuerySpec, fields QueryFields, defaultSort Sort) (Filter, Sort, error) {
+	if spec == nil {
+		return filter, defaultSort, nil
+	}
+
+	for _, item := range spec.Filters {
+		typ, ok := fields[item.Field]
+		if !ok || len(item.Values) == 0 {
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+
+		if item.Operator == schema.QueryOpLike {
+			filter = append(filter, likeValue(item.Field, item.Values[0]))
+			continue
+		}
+
+		values, err := parseFieldValues(typ, item.Values)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch item.Operator {
+		case schema.QueryOpEQ:
+			filter = append(filter, eqValue(item.Field, values[0]))
+		case schema.QueryOpNE:
+			filter = append(filter, compareCond(item.Field, values[0], func(c int) bool { return c != 0 }))
+		case schema.QueryOpIN:
+			filter = append(filter, inValues(item.Field, values))
+		case schema.QueryOpGTE:
+			filter = append(filter, compareCond(item.Field, values[0], func(c int) bool { return c >= 0 }))
+		case schema.QueryOpLTE:
+			filter = append(filter, compareCond(item.Field, values[0], func(c int) bool { return c <= 0 }))
+		case schema.QueryOpBetween:
+			if len(values) != 2 {
+				return nil, nil, errors.ErrInvalidQueryField
+			}
+			filter = append(filter,
+				compareCond(item.Field, values[0], func(c int) bool { return c >= 0 }),
+				compareCond(item.Field, values[1], func(c int) bool { return c <= 0 }),
+			)
+		default:
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+	}
+
+	// 选择字段仅做校验，由响应时过滤(实体转换依赖完整的数据)
+	for _, field := range spec.Fields {
+		if _, ok := fields[field]; !ok {
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+	}
+
+	if len(spec.Sorts) == 0 {
+		return filter, defaultSort, nil
+	}
+
+	var s Sort
+	for _, item := range spec.Sorts {
+		if _, ok := fields[item.Field]; !ok {
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+		s = append(s, SortField{Field: item.Field, Desc: item.Desc})
+	}
+	// 保证排序结果稳定
+	s = append(s, SortField{Field: "id", Desc: true})
+	return filter, s, nil
+}
@@ -0,0 +1,258 @@
+package model
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
+)
+
+var (
+	_ model.ITrans = (*Trans)(nil)
+	_ model.IDemo  = (*Demo)(nil)
+	_ model.IUser  = (*User)(nil)
+	_ model.IRole  = (*Role)(nil)
+	_ model.IMenu  = (*Menu)(nil)
+)
+
+func newTestDB(t *testing.T) *boltplus.DB {
+	db, err := boltplus.New(&boltplus.Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := CreateBuckets(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestWrapQuerySpec(t *testing.T) {
+	ctx := context.Background()
+	m := NewDemo(newTestDB(t))
+	for i, code := range []string{"a.b", "A.B.c", "x"} {
+		err := m.Create(ctx, schema.Demo{RecordID: code, Code: code, Name: code, Status: i + 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := m.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		QuerySpec: &schema.QuerySpec{
+			Sorts: []*schema.QuerySort{{Field: "status", Desc: true}},
+			Filters: []*schema.QueryFilter{
+				{Field: "status", Operator: schema.QueryOpIN, Values: []string{"1", "2"}},
+				{Field: "name", Operator: schema.QueryOpLike, Values: []string{"a.b"}},
+				{Field: "created_at", Operator: schema.QueryOpGTE, Values: []string{"2019-01-02"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Data) != 2 || result.Data[0].Code != "A.B.c" || result.Data[1].Code != "a.b" {
+		t.Fatalf("unexpected result: %v", result.Data)
+	}
+
+	for _, item := range []*schema.QueryFilter{
+		{Field: "password", Operator: schema.QueryOpEQ, Values: []string{"x"}},
+		{Field: "status", Operator: schema.QueryOpEQ, Values: []string{"x"}},
+		{Field: "status", Operator: schema.QueryOpBetween, Values: []string{"1"}},
+	} {
+		_, _, err := WrapQuerySpec(nil, &schema.QuerySpec{Filters: []*schema.QueryFilter{item}}, demoQueryFields, nil)
+		if err != errors.ErrInvalidQueryField {
+			t.Errorf("filter %v: expected ErrInvalidQueryField, got %v", item, err)
+		}
+	}
+}
+
+func TestUserQuery(t *testing.T) {
+	ctx := context.Background()
+	m := NewUser(newTestDB(t))
+	for _, item := range []schema.User{
+		{RecordID: "u1", UserName: "admin", Status: 1, Roles: schema.UserRoles{{RoleID: "r1"}, {RoleID: "r2"}}},
+		{RecordID: "u2", UserName: "test", Status: 1, Roles: schema.UserRoles{{RoleID: "r1"}}},
+		{RecordID: "u3", UserName: "guest", Status: 2, Roles: schema.UserRoles{{RoleID: "r1"}}},
+		{RecordID: "u4", UserName: "other", Status: 1},
+	} {
+		if err := m.Create(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.Create(ctx, schema.User{RecordID: "u1"}); err != errors.ErrResourceExists {
+		t.Errorf("expected ErrResourceExists, got %v", err)
+	}
+
+	result, err := m.Query(ctx, schema.UserQueryParam{
+		RoleIDs: []string{"r1"},
+	}, schema.UserQueryOptions{
+		PageParam:    &schema.PaginationParam{PageIndex: 2, PageSize: 2},
+		IncludeRoles: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PageResult.Total != 3 || len(result.Data) != 1 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if item := result.Data[0]; item.UserName != "admin" || len(item.Roles) != 2 || item.Roles[1].RoleID != "r2" {
+		t.Errorf("unexpected user: %v", item)
+	}
+
+	roles, err := NewRole(m.db).Query(ctx, schema.RoleQueryParam{UserID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(roles.Data) != 0 {
+		t.Errorf("unexpected roles: %v", roles.Data)
+	}
+}
+
+func TestMenuUpdate(t *testing.T) {
+	ctx := context.Background()
+	m := NewMenu(newTestDB(t))
+	err := m.Create(ctx, schema.Menu{RecordID: "m1", Name: "menu", ParentPath: "a.b/c", Actions: schema.MenuActions{{Code: "add"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Update(ctx, "m1", schema.Menu{Name: "menu", Version: 3})
+	if err != errors.ErrResourceConflict {
+		t.Fatalf("expected ErrResourceConflict, got %v", err)
+	}
+
+	err = m.Update(ctx, "m1", schema.Menu{Name: "new menu", ParentPath: "a.b/c/d", Version: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := m.Query(ctx, schema.MenuQueryParam{PrefixParentPath: "a.b/c"}, schema.MenuQueryOptions{IncludeActions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Name != "new menu" || result.Data[0].Version != 2 || len(result.Data[0].Actions) != 0 {
+		t.Fatalf("unexpected result: %v", result.Data)
+	}
+}
+
+func TestDemoCursorQuery(t *testing.T) {
+	ctx := context.Background()
+	m := NewDemo(newTestDB(t))
+	for _, code := range []string{"a", "b", "c"} {
+		if err := m.Create(ctx, schema.Demo{RecordID: code, Code: code}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := m.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		CursorParam: &schema.CursorParam{Limit: 1, Total: schema.CursorTotalExact},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Code != "c" || result.PageResult.Total != 3 || result.PageResult.Cursor.Next == "" || result.PageResult.Cursor.Prev != "" {
+		t.Fatalf("unexpected first page: %v", result.PageResult.Cursor)
+	}
+
+	result, err = m.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		CursorParam: &schema.CursorParam{Limit: 1, Cursor: result.PageResult.Cursor.Next},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Code != "b" || result.PageResult.Cursor.Next == "" || result.PageResult.Cursor.Prev == "" {
+		t.Fatalf("unexpected second page: %v", result.PageResult.Cursor)
+	}
+
+	prev, err := m.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		CursorParam: &schema.CursorParam{Limit: 1, Cursor: result.PageResult.Cursor.Prev},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prev.Data) != 1 || prev.Data[0].Code != "c" || prev.PageResult.Cursor.Prev != "" {
+		t.Fatalf("unexpected previous page: %v", prev.PageResult.Cursor)
+	}
+
+	_, err = m.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		CursorParam: &schema.CursorParam{Limit: 1, Cursor: result.PageResult.Cursor.Prev},
+		QuerySpec:   &schema.QuerySpec{Sorts: []*schema.QuerySort{{Field: "code"}}},
+	})
+	if err != errors.ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestExecTransRollback(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	m := NewMenu(db)
+
+	errRollback := errors.New("rollback")
+	err := ExecTrans(ctx, db, func(ctx context.Context) error {
+		err := m.Create(ctx, schema.Menu{RecordID: "m1", Name: "menu"})
+		if err != nil {
+			return err
+		}
+		if item, err := m.Get(ctx, "m1"); err != nil || item == nil {
+			t.Errorf("created menu not visible in transaction: %v", err)
+		}
+		return errRollback
+	})
+	if err != errRollback {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	item, err := m.Get(ctx, "m1")
+	if err != nil {
+		t.Fatal(err)
+	} else if item != nil {
+		t.Errorf("menu not rolled back: %v", item)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	m := NewRole(newTestDB(t))
+	for _, id := range []string{"r1", "r2"} {
+		if err := m.Create(ctx, schema.Role{RecordID: id, Name: id}); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Delete(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if item, err := m.Get(ctx, "r1"); err != nil || item != nil {
+		t.Fatalf("deleted role returned: %v, %v", item, err)
+	}
+
+	err := m.Restore(ctx, "r1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err := m.Get(ctx, "r1")
+	if err != nil || item == nil || item.Version != 2 {
+		t.Fatalf("unexpected restored role: %v, %v", item, err)
+	}
+
+	err = m.PurgeBefore(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := m.QueryDeleted(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(result.Data) != 0 {
+		t.Errorf("deleted roles not purged: %v", result.Data)
+	}
+	if item, err := m.Get(ctx, "r1"); err != nil || item == nil {
+		t.Errorf("restored role purged: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/pkg/cache"
+
+	"go.uber.org/dig"
+)
+
+// NewCache 创建查询缓存管理实例(c为nil时表示未启用缓存)
+func NewCache(c *cache.Cache) *Cache {
+	return &Cache{
+		cache: c,
+	}
+}
+
+// Cache 查询缓存管理
+type Cache struct {
+	cache *cache.Cache
+}
+
+// Invalidate 使指定命名空间的缓存失效
+func (a *Cache) Invalidate(ctx context.Context, namespaces ...string) error {
+	if a.cache == nil {
+		return nil
+	}
+	return a.cache.Invalidate(ctx, namespaces...)
+}
+
+// Inject 注入查询缓存管理(c为nil时表示未启用缓存)
+// 存储实现通过WrapUser/WrapRole/WrapMenu装饰用户、角色及菜单存储
+func Inject(container *dig.Container, c *cache.Cache) error {
+	err := container.Provide(func() *cache.Cache {
+		return c
+	})
+	if err != nil {
+		return err
+	}
+	return container.Provide(NewCache, dig.As(new(model.ICache)))
+}
+
+// 加载缓存数据(事务中的查询不使用缓存；缓存未命中时从主库查询，避免从库延迟的数据写入缓存)
+func load(ctx context.Context, c *cache.Cache, namespace, key string, v interface{}, fn func(ctx context.Context) error) error {
+	if _, ok := icontext.FromTrans(ctx); ok {
+		return fn(ctx)
+	}
+
+	return c.Load(ctx, namespace, key, v, func() error {
+		return fn(icontext.NewPrimary(ctx))
+	})
+}
+
+// 根据查询方法及参数生成缓存键
+func cacheKey(method string, args ...interface{}) string {
+	buf, _ := json.Marshal(args)
+	h := sha1.Sum(buf)
+	return method + ":" + hex.EncodeToString(h[:])
+}
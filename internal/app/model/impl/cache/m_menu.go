@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/cache"
+)
+
+// WrapMenu 使用查询缓存装饰菜单存储(c为nil时不装饰)
+func WrapMenu(m model.IMenu, c *cache.Cache) model.IMenu {
+	if c == nil {
+		return m
+	}
+	return &Menu{
+		IMenu: m,
+		cache: c,
+	}
+}
+
+// Menu 菜单存储的缓存装饰(仅缓存Query及Get，缓存失效由业务逻辑层在数据变更后执行)
+type Menu struct {
+	model.IMenu
+	cache *cache.Cache
+}
+
+// Query 查询数据
+func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+	var result *schema.MenuQueryResult
+	err := load(ctx, a.cache, model.CacheMenu, cacheKey("query", params, opts), &result, func(ctx context.Context) error {
+		var err error
+		result, err = a.IMenu.Query(ctx, params, opts...)
+		return err
+	})
+	return result, err
+}
+
+// Get 查询指定数据
+func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQueryOptions) (*schema.Menu, error) {
+	var item *schema.Menu
+	err := load(ctx, a.cache, model.CacheMenu, cacheKey("get", recordID, opts), &item, func(ctx context.Context) error {
+		var err error
+		item, err = a.IMenu.Get(ctx, recordID, opts...)
+		return err
+	})
+	return item, err
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/cache"
+)
+
+// WrapRole 使用查询缓存装饰角色存储(c为nil时不装饰)
+func WrapRole(m model.IRole, c *cache.Cache) model.IRole {
+	if c == nil {
+		return m
+	}
+	return &Role{
+		IRole: m,
+		cache: c,
+	}
+}
+
+// Role 角色存储的缓存装饰(仅缓存Query及Get，缓存失效由业务逻辑层在数据变更后执行)
+type Role struct {
+	model.IRole
+	cache *cache.Cache
+}
+
+// Query 查询数据
+func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+	var result *schema.RoleQueryResult
+	err := load(ctx, a.cache, model.CacheRole, cacheKey("query", params, opts), &result, func(ctx context.Context) error {
+		var err error
+		result, err = a.IRole.Query(ctx, params, opts...)
+		return err
+	})
+	return result, err
+}
+
+// Get 查询指定数据
+func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQueryOptions) (*schema.Role, error) {
+	var item *schema.Role
+	err := load(ctx, a.cache, model.CacheRole, cacheKey("get", recordID, opts), &item, func(ctx context.Context) error {
+		var err error
+		item, err = a.IRole.Get(ctx, recordID, opts...)
+		return err
+	})
+	return item, err
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/cache"
+)
+
+// WrapUser 使用查询缓存装饰用户存储(c为nil时不装饰)
+func WrapUser(m model.IUser, c *cache.Cache) model.IUser {
+	if c == nil {
+		return m
+	}
+	return &User{
+		IUser: m,
+		cache: c,
+	}
+}
+
+// User 用户存储的缓存装饰(仅缓存Query及Get，缓存失效由业务逻辑层在数据变更后执行)
+type User struct {
+	model.IUser
+	cache *cache.Cache
+}
+
+// Query 查询数据
+func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	var result *schema.UserQueryResult
+	err := load(ctx, a.cache, model.CacheUser, cacheKey("query", params, opts), &result, func(ctx context.Context) error {
+		var err error
+		result, err = a.IUser.Query(ctx, params, opts...)
+		return err
+	})
+	return result, err
+}
+
+// Get 查询指定数据
+func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQueryOptions) (*schema.User, error) {
+	var item *schema.User
+	err := load(ctx, a.cache, model.CacheUser, cacheKey("get", recordID, opts), &item, func(ctx context.Context) error {
+		var err error
+		item, err = a.IUser.Get(ctx, recordID, opts...)
+		return err
+	})
+	return item, err
+}
@@ -0,0 +1,57 @@
+package fulltext
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/model"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/fulltext"
+
+	"go.uber.org/dig"
+)
+
+// NewSearch 创建基于内嵌全文索引的检索实例
+func NewSearch() *Search {
+	return &Search{
+		index: fulltext.NewIndex(),
+	}
+}
+
+// Search 基于内嵌全文索引的检索(索引保存在内存中，启动时需要重建索引)
+type Search struct {
+	index *fulltext.Index
+}
+
+// Index 索引文档
+func (a *Search) Index(ctx context.Context, docs ...*schema.SearchDocument) error {
+	for _, doc := range docs {
+		a.index.Add(doc.Type, doc.RecordID, doc.Title, doc.Contents...)
+	}
+	return nil
+}
+
+// Delete 删除文档
+func (a *Search) Delete(ctx context.Context, typ string, recordIDs ...string) error {
+	a.index.Remove(typ, recordIDs...)
+	return nil
+}
+
+// Search 检索数据
+func (a *Search) Search(ctx context.Context, params schema.SearchParam) (schema.SearchResult, error) {
+	result := make(schema.SearchResult)
+	for typ, hits := range a.index.Search(params.Query, params.Types, params.Limit) {
+		for _, hit := range hits {
+			result[typ] = append(result[typ], &schema.SearchItem{
+				RecordID: hit.ID,
+				Title:    hit.Title,
+				Score:    hit.Score,
+			})
+		}
+	}
+	return result, nil
+}
+
+// Inject 注入内嵌全文索引的检索实现
+func Inject(container *dig.Container) error {
+	return container.Provide(NewSearch, dig.As(new(model.ISearch)))
+}
@@ -2,10 +2,14 @@ package gorm
 
 import (
 	"github.com/wanhello/iris-admin/internal/app/model"
+	icache "github.com/wanhello/iris-admin/internal/app/model/impl/cache"
 	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/migration"
 	imodel "github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/model"
 
+	"github.com/wanhello/iris-admin/pkg/cache"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/migrate"
 	"go.uber.org/dig"
 
 )
@@ -26,10 +30,21 @@ func AutoMigrate(db *gormplus.DB) error {
 		new(entity.Menu),
 		new(entity.MenuAction),
 		new(entity.MenuResource),
+		// generator:migrate
 	).Error
 }
 
-// Inject 注入gorm实现
+// NewMigrator 创建数据库版本迁移(迁移版本记录在schema_migrations表中，表名包含表名前缀)
+func NewMigrator(db *gormplus.DB, dbType string) (*migrate.Migrator, error) {
+	prefix := entity.GetTablePrefix()
+	migrations, err := migration.Migrations(dbType, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db.DB.DB(), dbType, migrations, migrate.SetTable(prefix+"schema_migrations"))
+}
+
+// Inject 注入gorm实现(用户、角色及菜单存储使用查询缓存装饰，需要先注入*cache.Cache)
 // 使用方式：
 //   container := dig.New()
 //   Inject(container)
@@ -38,9 +53,25 @@ func AutoMigrate(db *gormplus.DB) error {
 func Inject(container *dig.Container) error {
 	container.Provide(imodel.NewTrans, dig.As(new(model.ITrans)))
 	container.Provide(imodel.NewDemo, dig.As(new(model.IDemo)))
-	container.Provide(imodel.NewMenu, dig.As(new(model.IMenu)))
-	container.Provide(imodel.NewRole, dig.As(new(model.IRole)))
-	container.Provide(imodel.NewUser, dig.As(new(model.IUser)))
+	container.Provide(func(db *gormplus.DB, c *cache.Cache) model.IMenu {
+		return icache.WrapMenu(imodel.NewMenu(db), c)
+	})
+	container.Provide(func(db *gormplus.DB, c *cache.Cache) model.IRole {
+		return icache.WrapRole(imodel.NewRole(db), c)
+	})
+	container.Provide(func(db *gormplus.DB, c *cache.Cache) model.IUser {
+		return icache.WrapUser(imodel.NewUser(db), c)
+	})
+	// generator:inject
 	return nil
 }
 
+// InjectSearch 注入数据库原生全文检索实现(mysql及postgres会自动创建全文索引)
+func InjectSearch(container *dig.Container, db *gormplus.DB) error {
+	err := imodel.CreateSearchIndexes(db)
+	if err != nil {
+		return err
+	}
+	return container.Provide(imodel.NewSearch, dig.As(new(model.ISearch)))
+}
+
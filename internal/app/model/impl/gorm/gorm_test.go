@@ -0,0 +1,143 @@
+package gorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
+	imodel "github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/model"
+	"github.com/wanhello/iris-admin/internal/app/model/modeltest"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+)
+
+// 使用内存sqlite数据库(只允许一个连接，否则每个连接会打开不同的数据库)
+func newTestDB(t *testing.T) *gormplus.DB {
+	db, err := gormplus.New(&gormplus.Config{
+		DBType:       "sqlite3",
+		DSN:          ":memory:",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newModelStore(db *gormplus.DB) *modeltest.Store {
+	return &modeltest.Store{
+		Trans: imodel.NewTrans(db),
+		Demo:  imodel.NewDemo(db),
+		User:  imodel.NewUser(db),
+		Role:  imodel.NewRole(db),
+		Menu:  imodel.NewMenu(db),
+	}
+}
+
+func newTestStore(t *testing.T) *modeltest.Store {
+	db := newTestDB(t)
+	if err := AutoMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return newModelStore(db)
+}
+
+func TestConformance(t *testing.T) {
+	modeltest.Run(t, newTestStore)
+}
+
+// 版本迁移创建的数据表与实体定义一致(回滚最近一次迁移后重新执行)
+func TestMigrationConformance(t *testing.T) {
+	modeltest.Run(t, func(t *testing.T) *modeltest.Store {
+		db := newTestDB(t)
+		m, err := NewMigrator(db, "sqlite3")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		if _, err := m.Up(ctx, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Down(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Up(ctx, 0); err != nil {
+			t.Fatal(err)
+		}
+		return newModelStore(db)
+	})
+}
+
+// 排序值为NULL的数据(如迁移新增的列或者外部导入的数据)不中断游标分页
+func TestCursorNullValues(t *testing.T) {
+	db := newTestDB(t)
+	if err := AutoMigrate(db); err != nil {
+		t.Fatal(err)
+	}
+	s := newModelStore(db)
+
+	ctx := context.Background()
+	for _, item := range []schema.Demo{
+		{RecordID: "d1", Code: "1", Name: "demo", Memo: "b", Status: 1},
+		{RecordID: "d2", Code: "2", Name: "demo", Status: 1},
+		{RecordID: "d3", Code: "3", Name: "demo", Memo: "a", Status: 1},
+		{RecordID: "d4", Code: "4", Name: "demo", Status: 1},
+		{RecordID: "d5", Code: "5", Name: "demo", Memo: "a", Status: 1},
+	} {
+		if err := s.Demo.Create(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := db.Exec("UPDATE "+entity.Demo{}.TableName()+" SET memo=NULL WHERE record_id IN (?)", []string{"d2", "d4"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := func(cursor string, desc bool) *schema.DemoQueryResult {
+		t.Helper()
+		result, err := s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+			CursorParam: &schema.CursorParam{Cursor: cursor, Limit: 2},
+			QuerySpec:   &schema.QuerySpec{Sorts: []*schema.QuerySort{{Field: "memo", Desc: desc}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	for _, item := range []struct {
+		desc     bool
+		expected string
+	}{
+		{false, "d3,d5,d1,d2,d4"},
+		{true, "d4,d2,d1,d5,d3"},
+	} {
+		var ids []string
+		var prev []string
+		cursor := ""
+		for i := 0; i < 5; i++ {
+			result := query(cursor, item.desc)
+			for _, demo := range result.Data {
+				ids = append(ids, demo.RecordID)
+			}
+			if result.PageResult.Cursor.Next == "" {
+				// 从最后一页向前翻页
+				for _, demo := range query(result.PageResult.Cursor.Prev, item.desc).Data {
+					prev = append(prev, demo.RecordID)
+				}
+				break
+			}
+			cursor = result.PageResult.Cursor.Next
+		}
+		if v := strings.Join(ids, ","); v != item.expected {
+			t.Errorf("desc=%v: expected %s, got %s", item.desc, item.expected, v)
+		}
+		if v := strings.Join(prev, ","); v != strings.Join(ids[2:4], ",") {
+			t.Errorf("desc=%v: unexpected prev page %s", item.desc, v)
+		}
+	}
+}
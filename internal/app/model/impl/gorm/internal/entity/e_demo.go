@@ -13,6 +13,11 @@ func GetDemoDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, Demo{})
 }
 
+// GetDemoReadDB 获取demo查询存储(读写分离时使用从库)
+func GetDemoReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, Demo{})
+}
+
 // SchemaDemo demo对象
 type SchemaDemo schema.Demo
 
@@ -25,6 +30,7 @@ func (a SchemaDemo) ToDemo() *Demo {
 		Memo:     &a.Memo,
 		Status:   &a.Status,
 		Creator:  &a.Creator,
+		Version:  &a.Version,
 	}
 	return item
 }
@@ -38,6 +44,7 @@ type Demo struct {
 	Memo     *string `gorm:"column:memo;size:200;"`           // 备注
 	Status   *int    `gorm:"column:status;index;"`            // 状态(1:启用 2:停用)
 	Creator  *string `gorm:"column:creator;size:36;"`         // 创建者
+	Version  *int    `gorm:"column:version;default:1;"`       // 版本号(每次更新递增)
 }
 
 func (a Demo) String() string {
@@ -55,10 +62,12 @@ func (a Demo) ToSchemaDemo() *schema.Demo {
 		RecordID:  a.RecordID,
 		Code:      *a.Code,
 		Name:      *a.Name,
-		Memo:      *a.Memo,
+		Memo:      stringValue(a.Memo),
 		Status:    *a.Status,
 		Creator:   *a.Creator,
+		Version:   *a.Version,
 		CreatedAt: a.CreatedAt,
+		DeletedAt: a.DeletedAt,
 	}
 	return item
 }
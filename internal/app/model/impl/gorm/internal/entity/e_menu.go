@@ -12,16 +12,31 @@ func GetMenuDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, Menu{})
 }
 
+// GetMenuReadDB 获取菜单查询存储(读写分离时使用从库)
+func GetMenuReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, Menu{})
+}
+
 // GetMenuActionDB 获取菜单动作存储
 func GetMenuActionDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, MenuAction{})
 }
 
+// GetMenuActionReadDB 获取菜单动作查询存储(读写分离时使用从库)
+func GetMenuActionReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, MenuAction{})
+}
+
 // GetMenuResourceDB 获取菜单资源存储
 func GetMenuResourceDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, MenuResource{})
 }
 
+// GetMenuResourceReadDB 获取菜单资源查询存储(读写分离时使用从库)
+func GetMenuResourceReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, MenuResource{})
+}
+
 // SchemaMenu 菜单对象
 type SchemaMenu schema.Menu
 
@@ -37,6 +52,7 @@ func (a SchemaMenu) ToMenu() *Menu {
 		ParentID:   &a.ParentID,
 		ParentPath: &a.ParentPath,
 		Creator:    &a.Creator,
+		Version:    &a.Version,
 	}
 	return item
 }
@@ -71,6 +87,7 @@ type Menu struct {
 	ParentID   *string `gorm:"column:parent_id;size:36;index;"`    // 父级内码
 	ParentPath *string `gorm:"column:parent_path;size:518;index;"` // 父级路径
 	Creator    *string `gorm:"column:creator;size:36;"`            // 创建人
+	Version    *int    `gorm:"column:version;default:1;"`          // 版本号(每次更新递增)
 }
 
 func (a Menu) String() string {
@@ -93,7 +110,9 @@ func (a Menu) ToSchemaMenu() *schema.Menu {
 		ParentID:   *a.ParentID,
 		ParentPath: *a.ParentPath,
 		Creator:    *a.Creator,
+		Version:    *a.Version,
 		CreatedAt:  a.CreatedAt,
+		DeletedAt:  a.DeletedAt,
 	}
 	if a.Hidden != nil {
 		item.Hidden = *a.Hidden
@@ -14,22 +14,36 @@ func GetRoleDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, Role{})
 }
 
+// GetRoleReadDB 获取角色查询存储(读写分离时使用从库)
+func GetRoleReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, Role{})
+}
+
 // GetRoleMenuDB 获取角色菜单关联存储
 func GetRoleMenuDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, RoleMenu{})
 }
 
+// GetRoleMenuReadDB 获取角色菜单关联查询存储(读写分离时使用从库)
+func GetRoleMenuReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, RoleMenu{})
+}
+
 // SchemaRole 角色对象
 type SchemaRole schema.Role
 
 // ToRole 转换为角色实体
 func (a SchemaRole) ToRole() *Role {
+	allowedCIDRs := strings.Join(a.AllowedCIDRs, ",")
 	item := &Role{
 		RecordID: a.RecordID,
 		Name:     &a.Name,
 		Sequence: &a.Sequence,
 		Memo:     &a.Memo,
 		Creator:  &a.Creator,
+		Version:  &a.Version,
+
+		AllowedCIDRs: &allowedCIDRs,
 	}
 	return item
 }
@@ -51,6 +65,9 @@ type Role struct {
 	Sequence *int    `gorm:"column:sequence;index;"`          // 排序值
 	Memo     *string `gorm:"column:memo;size:200;"`           // 备注
 	Creator  *string `gorm:"column:creator;size:36;"`         // 创建者
+	Version  *int    `gorm:"column:version;default:1;"`       // 版本号(每次更新递增)
+
+	AllowedCIDRs *string `gorm:"column:allowed_cidrs;size:1024;"` // 允许访问的IP地址范围(多个以英文逗号分隔)
 }
 
 func (a Role) String() string {
@@ -68,9 +85,14 @@ func (a Role) ToSchemaRole() *schema.Role {
 		RecordID:  a.RecordID,
 		Name:      *a.Name,
 		Sequence:  *a.Sequence,
-		Memo:      *a.Memo,
+		Memo:      stringValue(a.Memo),
 		Creator:   *a.Creator,
+		Version:   *a.Version,
 		CreatedAt: a.CreatedAt,
+		DeletedAt: a.DeletedAt,
+	}
+	if v := a.AllowedCIDRs; v != nil && *v != "" {
+		item.AllowedCIDRs = strings.Split(*v, ",")
 	}
 	return item
 }
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"context"
+	"strings"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
@@ -13,16 +14,27 @@ func GetUserDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, User{})
 }
 
+// GetUserReadDB 获取用户查询存储(读写分离时使用从库)
+func GetUserReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, User{})
+}
+
 // GetUserRoleDB 获取用户角色关联存储
 func GetUserRoleDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	return getDBWithModel(ctx, defDB, UserRole{})
 }
 
+// GetUserRoleReadDB 获取用户角色关联查询存储(读写分离时使用从库)
+func GetUserRoleReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	return getReadDBWithModel(ctx, defDB, UserRole{})
+}
+
 // SchemaUser 用户对象
 type SchemaUser schema.User
 
 // ToUser 转换为用户实体
 func (a SchemaUser) ToUser() *User {
+	allowedCIDRs := strings.Join(a.AllowedCIDRs, ",")
 	item := &User{
 		RecordID: a.RecordID,
 		UserName: &a.UserName,
@@ -30,8 +42,11 @@ func (a SchemaUser) ToUser() *User {
 		Password: &a.Password,
 		Status:   &a.Status,
 		Creator:  &a.Creator,
+		Version:  &a.Version,
 		Email:    &a.Email,
 		Phone:    &a.Phone,
+
+		AllowedCIDRs: &allowedCIDRs,
 	}
 	return item
 }
@@ -59,6 +74,9 @@ type User struct {
 	Phone    *string `gorm:"column:phone;size:20;index;"`     // 手机号
 	Status   *int    `gorm:"column:status;index;"`            // 状态(1:启用 2:停用)
 	Creator  *string `gorm:"column:creator;size:36;"`         // 创建者
+	Version  *int    `gorm:"column:version;default:1;"`       // 版本号(每次更新递增)
+
+	AllowedCIDRs *string `gorm:"column:allowed_cidrs;size:1024;"` // 允许访问的IP地址范围(多个以英文逗号分隔)
 }
 
 func (a User) String() string {
@@ -79,9 +97,14 @@ func (a User) ToSchemaUser() *schema.User {
 		Password:  *a.Password,
 		Status:    *a.Status,
 		Creator:   *a.Creator,
-		Email:     *a.Email,
-		Phone:     *a.Phone,
+		Version:   *a.Version,
+		Email:     stringValue(a.Email),
+		Phone:     stringValue(a.Phone),
 		CreatedAt: a.CreatedAt,
+		DeletedAt: a.DeletedAt,
+	}
+	if v := a.AllowedCIDRs; v != nil && *v != "" {
+		item.AllowedCIDRs = strings.Split(*v, ",")
 	}
 	return item
 }
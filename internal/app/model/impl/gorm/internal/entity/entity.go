@@ -3,10 +3,11 @@ package entity
 import (
 	"context"
 	"fmt"
+	"time"
+
 	icontext "github.com/wanhello/iris-admin/internal/app/context"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
 	"github.com/wanhello/iris-admin/pkg/util"
-	"time"
 )
 
 // 表名前缀
@@ -39,6 +40,14 @@ func toString(v interface{}) string {
 	return util.JSONMarshalToString(v)
 }
 
+// 获取可选的字符串列的值(NULL时返回空字符串，如迁移新增的列或者外部导入的数据)
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
 func getDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 	trans, ok := icontext.FromTrans(ctx)
 	if ok {
@@ -51,5 +60,17 @@ func getDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
 }
 
 func getDBWithModel(ctx context.Context, defDB *gormplus.DB, m interface{}) *gormplus.DB {
-	return gormplus.Wrap(getDB(ctx, defDB).Model(m))
+	return gormplus.Wrap(getDB(ctx, defDB).WithContext(ctx).Model(m))
+}
+
+// 获取查询使用的存储(事务中使用事务，强制使用主库时使用主库，否则使用可用的从库)
+func getReadDB(ctx context.Context, defDB *gormplus.DB) *gormplus.DB {
+	if _, ok := icontext.FromTrans(ctx); ok || icontext.FromPrimary(ctx) {
+		return getDB(ctx, defDB)
+	}
+	return defDB.Replica()
+}
+
+func getReadDBWithModel(ctx context.Context, defDB *gormplus.DB, m interface{}) *gormplus.DB {
+	return gormplus.Wrap(getReadDB(ctx, defDB).WithContext(ctx).Model(m))
 }
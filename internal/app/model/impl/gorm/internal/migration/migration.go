@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/wanhello/iris-admin/pkg/migrate"
+)
+
+// 各数据库的迁移文件(编译时嵌入)
+// 迁移文件位于"sql/数据库类型"目录，以"版本号_名称.up.sql"及"版本号_名称.down.sql"命名，
+// 脚本中的{prefix}会被替换为表名前缀
+//
+//go:embed sql
+var files embed.FS
+
+// Migrations 获取数据库的迁移定义
+func Migrations(dialect, tablePrefix string) ([]*migrate.Migration, error) {
+	dir, err := fs.Sub(files, "sql/"+dialect)
+	if err != nil {
+		return nil, err
+	} else if _, err := fs.Stat(dir, "."); err != nil {
+		return nil, fmt.Errorf("不支持的数据库类型：%s", dialect)
+	}
+
+	list, err := migrate.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range list {
+		item.Up = strings.Replace(item.Up, "{prefix}", tablePrefix, -1)
+		item.Down = strings.Replace(item.Down, "{prefix}", tablePrefix, -1)
+	}
+	return list, nil
+}
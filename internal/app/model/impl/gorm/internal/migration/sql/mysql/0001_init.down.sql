@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS {prefix}menu_resource;
+DROP TABLE IF EXISTS {prefix}menu_action;
+DROP TABLE IF EXISTS {prefix}menu;
+DROP TABLE IF EXISTS {prefix}role_menu;
+DROP TABLE IF EXISTS {prefix}role;
+DROP TABLE IF EXISTS {prefix}user_role;
+DROP TABLE IF EXISTS {prefix}user;
+DROP TABLE IF EXISTS {prefix}demo;
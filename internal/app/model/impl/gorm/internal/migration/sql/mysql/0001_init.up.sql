@@ -0,0 +1,144 @@
+CREATE TABLE IF NOT EXISTS {prefix}demo (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  record_id varchar(36),
+  code varchar(50),
+  name varchar(100),
+  memo varchar(200),
+  status int,
+  creator varchar(36),
+  version int DEFAULT 1,
+  PRIMARY KEY (id),
+  KEY idx_{prefix}demo_deleted_at (deleted_at),
+  KEY idx_{prefix}demo_record_id (record_id),
+  KEY idx_{prefix}demo_code (code),
+  KEY idx_{prefix}demo_name (name),
+  KEY idx_{prefix}demo_status (status)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}user (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  record_id varchar(36),
+  user_name varchar(64),
+  real_name varchar(64),
+  password varchar(40),
+  email varchar(255),
+  phone varchar(20),
+  status int,
+  creator varchar(36),
+  version int DEFAULT 1,
+  PRIMARY KEY (id),
+  KEY idx_{prefix}user_deleted_at (deleted_at),
+  KEY idx_{prefix}user_record_id (record_id),
+  KEY idx_{prefix}user_user_name (user_name),
+  KEY idx_{prefix}user_real_name (real_name),
+  KEY idx_{prefix}user_email (email),
+  KEY idx_{prefix}user_phone (phone),
+  KEY idx_{prefix}user_status (status)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}user_role (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  user_id varchar(36),
+  role_id varchar(36),
+  PRIMARY KEY (id),
+  KEY idx_{prefix}user_role_deleted_at (deleted_at),
+  KEY idx_{prefix}user_role_user_id (user_id),
+  KEY idx_{prefix}user_role_role_id (role_id)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}role (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  record_id varchar(36),
+  name varchar(100),
+  sequence int,
+  memo varchar(200),
+  creator varchar(36),
+  version int DEFAULT 1,
+  PRIMARY KEY (id),
+  KEY idx_{prefix}role_deleted_at (deleted_at),
+  KEY idx_{prefix}role_record_id (record_id),
+  KEY idx_{prefix}role_name (name),
+  KEY idx_{prefix}role_sequence (sequence)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}role_menu (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  role_id varchar(36),
+  menu_id varchar(36),
+  action varchar(2048),
+  resource varchar(2048),
+  PRIMARY KEY (id),
+  KEY idx_{prefix}role_menu_deleted_at (deleted_at),
+  KEY idx_{prefix}role_menu_role_id (role_id),
+  KEY idx_{prefix}role_menu_menu_id (menu_id)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}menu (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  record_id varchar(36),
+  name varchar(50),
+  sequence int,
+  icon varchar(255),
+  router varchar(255),
+  hidden int,
+  parent_id varchar(36),
+  parent_path varchar(518),
+  creator varchar(36),
+  version int DEFAULT 1,
+  PRIMARY KEY (id),
+  KEY idx_{prefix}menu_deleted_at (deleted_at),
+  KEY idx_{prefix}menu_record_id (record_id),
+  KEY idx_{prefix}menu_name (name),
+  KEY idx_{prefix}menu_sequence (sequence),
+  KEY idx_{prefix}menu_hidden (hidden),
+  KEY idx_{prefix}menu_parent_id (parent_id),
+  KEY idx_{prefix}menu_parent_path (parent_path)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}menu_action (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  menu_id varchar(36),
+  code varchar(50),
+  name varchar(50),
+  PRIMARY KEY (id),
+  KEY idx_{prefix}menu_action_deleted_at (deleted_at),
+  KEY idx_{prefix}menu_action_menu_id (menu_id),
+  KEY idx_{prefix}menu_action_code (code)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS {prefix}menu_resource (
+  id int unsigned NOT NULL AUTO_INCREMENT,
+  created_at timestamp NULL,
+  updated_at timestamp NULL,
+  deleted_at timestamp NULL,
+  menu_id varchar(36),
+  code varchar(50),
+  name varchar(50),
+  method varchar(50),
+  path varchar(255),
+  PRIMARY KEY (id),
+  KEY idx_{prefix}menu_resource_deleted_at (deleted_at),
+  KEY idx_{prefix}menu_resource_menu_id (menu_id),
+  KEY idx_{prefix}menu_resource_code (code)
+) ENGINE=InnoDB;
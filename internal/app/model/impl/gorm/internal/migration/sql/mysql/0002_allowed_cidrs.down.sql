@@ -0,0 +1,2 @@
+ALTER TABLE {prefix}role DROP COLUMN allowed_cidrs;
+ALTER TABLE {prefix}user DROP COLUMN allowed_cidrs;
@@ -0,0 +1,2 @@
+ALTER TABLE {prefix}user ADD COLUMN allowed_cidrs varchar(1024);
+ALTER TABLE {prefix}role ADD COLUMN allowed_cidrs varchar(1024);
@@ -0,0 +1,136 @@
+CREATE TABLE IF NOT EXISTS "{prefix}demo" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "record_id" varchar(36),
+  "code" varchar(50),
+  "name" varchar(100),
+  "memo" varchar(200),
+  "status" integer,
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}demo_deleted_at ON "{prefix}demo" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}demo_record_id ON "{prefix}demo" ("record_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}demo_code ON "{prefix}demo" ("code");
+CREATE INDEX IF NOT EXISTS idx_{prefix}demo_name ON "{prefix}demo" ("name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}demo_status ON "{prefix}demo" ("status");
+
+CREATE TABLE IF NOT EXISTS "{prefix}user" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "record_id" varchar(36),
+  "user_name" varchar(64),
+  "real_name" varchar(64),
+  "password" varchar(40),
+  "email" varchar(255),
+  "phone" varchar(20),
+  "status" integer,
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_deleted_at ON "{prefix}user" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_record_id ON "{prefix}user" ("record_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_user_name ON "{prefix}user" ("user_name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_real_name ON "{prefix}user" ("real_name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_email ON "{prefix}user" ("email");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_phone ON "{prefix}user" ("phone");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_status ON "{prefix}user" ("status");
+
+CREATE TABLE IF NOT EXISTS "{prefix}user_role" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "user_id" varchar(36),
+  "role_id" varchar(36)
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_role_deleted_at ON "{prefix}user_role" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_role_user_id ON "{prefix}user_role" ("user_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_role_role_id ON "{prefix}user_role" ("role_id");
+
+CREATE TABLE IF NOT EXISTS "{prefix}role" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "record_id" varchar(36),
+  "name" varchar(100),
+  "sequence" integer,
+  "memo" varchar(200),
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_deleted_at ON "{prefix}role" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_record_id ON "{prefix}role" ("record_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_name ON "{prefix}role" ("name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_sequence ON "{prefix}role" ("sequence");
+
+CREATE TABLE IF NOT EXISTS "{prefix}role_menu" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "role_id" varchar(36),
+  "menu_id" varchar(36),
+  "action" varchar(2048),
+  "resource" varchar(2048)
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_menu_deleted_at ON "{prefix}role_menu" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_menu_role_id ON "{prefix}role_menu" ("role_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_menu_menu_id ON "{prefix}role_menu" ("menu_id");
+
+CREATE TABLE IF NOT EXISTS "{prefix}menu" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "record_id" varchar(36),
+  "name" varchar(50),
+  "sequence" integer,
+  "icon" varchar(255),
+  "router" varchar(255),
+  "hidden" integer,
+  "parent_id" varchar(36),
+  "parent_path" varchar(518),
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_deleted_at ON "{prefix}menu" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_record_id ON "{prefix}menu" ("record_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_name ON "{prefix}menu" ("name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_sequence ON "{prefix}menu" ("sequence");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_hidden ON "{prefix}menu" ("hidden");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_parent_id ON "{prefix}menu" ("parent_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_parent_path ON "{prefix}menu" ("parent_path");
+
+CREATE TABLE IF NOT EXISTS "{prefix}menu_action" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "menu_id" varchar(36),
+  "code" varchar(50),
+  "name" varchar(50)
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_action_deleted_at ON "{prefix}menu_action" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_action_menu_id ON "{prefix}menu_action" ("menu_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_action_code ON "{prefix}menu_action" ("code");
+
+CREATE TABLE IF NOT EXISTS "{prefix}menu_resource" (
+  "id" serial NOT NULL PRIMARY KEY,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "deleted_at" timestamp with time zone,
+  "menu_id" varchar(36),
+  "code" varchar(50),
+  "name" varchar(50),
+  "method" varchar(50),
+  "path" varchar(255)
+);
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_resource_deleted_at ON "{prefix}menu_resource" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_resource_menu_id ON "{prefix}menu_resource" ("menu_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}menu_resource_code ON "{prefix}menu_resource" ("code");
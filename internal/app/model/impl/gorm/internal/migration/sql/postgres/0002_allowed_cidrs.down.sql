@@ -0,0 +1,2 @@
+ALTER TABLE "{prefix}role" DROP COLUMN IF EXISTS "allowed_cidrs";
+ALTER TABLE "{prefix}user" DROP COLUMN IF EXISTS "allowed_cidrs";
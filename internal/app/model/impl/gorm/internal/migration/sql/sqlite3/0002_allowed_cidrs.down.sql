@@ -0,0 +1,46 @@
+-- sqlite3不支持删除列，回滚时重建数据表
+CREATE TABLE "{prefix}user_bak" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "record_id" varchar(36),
+  "user_name" varchar(64),
+  "real_name" varchar(64),
+  "password" varchar(40),
+  "email" varchar(255),
+  "phone" varchar(20),
+  "status" integer,
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+INSERT INTO "{prefix}user_bak" SELECT "id", "created_at", "updated_at", "deleted_at", "record_id", "user_name", "real_name", "password", "email", "phone", "status", "creator", "version" FROM "{prefix}user";
+DROP TABLE "{prefix}user";
+ALTER TABLE "{prefix}user_bak" RENAME TO "{prefix}user";
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_deleted_at ON "{prefix}user" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_record_id ON "{prefix}user" ("record_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_user_name ON "{prefix}user" ("user_name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_real_name ON "{prefix}user" ("real_name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_email ON "{prefix}user" ("email");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_phone ON "{prefix}user" ("phone");
+CREATE INDEX IF NOT EXISTS idx_{prefix}user_status ON "{prefix}user" ("status");
+
+CREATE TABLE "{prefix}role_bak" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime,
+  "updated_at" datetime,
+  "deleted_at" datetime,
+  "record_id" varchar(36),
+  "name" varchar(100),
+  "sequence" integer,
+  "memo" varchar(200),
+  "creator" varchar(36),
+  "version" integer DEFAULT 1
+);
+INSERT INTO "{prefix}role_bak" SELECT "id", "created_at", "updated_at", "deleted_at", "record_id", "name", "sequence", "memo", "creator", "version" FROM "{prefix}role";
+DROP TABLE "{prefix}role";
+ALTER TABLE "{prefix}role_bak" RENAME TO "{prefix}role";
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_deleted_at ON "{prefix}role" ("deleted_at");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_record_id ON "{prefix}role" ("record_id");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_name ON "{prefix}role" ("name");
+CREATE INDEX IF NOT EXISTS idx_{prefix}role_sequence ON "{prefix}role" ("sequence");
@@ -0,0 +1,239 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"github.com/jinzhu/gorm"
+)
+
+// CursorKey 游标分页的排序键(排序值相同时按id排序)
+type CursorKey struct {
+	Column string // 排序列(为空时仅按id排序)
+	Desc   bool   // 是否降序
+}
+
+// 游标数据(编码后对调用方不透明)
+type cursor struct {
+	Column   string      `json:"c,omitempty"` // 排序列
+	Desc     bool        `json:"d,omitempty"` // 是否降序
+	Value    interface{} `json:"v,omitempty"` // 排序值
+	Null     bool        `json:"n,omitempty"` // 排序值是否为NULL
+	Time     bool        `json:"t,omitempty"` // 排序值是否是时间
+	ID       uint        `json:"i"`           // 数据ID
+	Backward bool        `json:"b,omitempty"` // 是否查询上一页
+}
+
+func encodeCursor(c *cursor) (string, error) {
+	buf, err := util.JSONMarshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeCursor(s string, key CursorKey) (*cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	var c cursor
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	// 排序条件变化后游标失效
+	if c.Column != key.Column || c.Desc != key.Desc {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	switch v := c.Value.(type) {
+	case json.Number:
+		if iv, err := v.Int64(); err == nil {
+			c.Value = iv
+		} else if fv, err := v.Float64(); err == nil {
+			c.Value = fv
+		}
+	case string:
+		if c.Time {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, errors.ErrInvalidCursor
+			}
+			c.Value = t
+		}
+	}
+
+	return &c, nil
+}
+
+// 获取游标分页的排序键(游标分页仅支持指定一个排序字段)
+func getCursorKey(spec *schema.QuerySpec, columns QueryColumns, defaultKey CursorKey) (CursorKey, error) {
+	if spec == nil || len(spec.Sorts) == 0 {
+		return defaultKey, nil
+	} else if len(spec.Sorts) > 1 {
+		return defaultKey, errors.ErrInvalidCursor
+	}
+
+	column, ok := columns[spec.Sorts[0].Field]
+	if !ok {
+		return defaultKey, errors.ErrInvalidQueryField
+	}
+	return CursorKey{Column: column, Desc: spec.Sorts[0].Desc}, nil
+}
+
+// 根据数据项创建游标
+func newCursor(db *gorm.DB, key CursorKey, item reflect.Value, backward bool) (string, error) {
+	c := &cursor{
+		Column:   key.Column,
+		Desc:     key.Desc,
+		Backward: backward,
+	}
+
+	scope := db.NewScope(item.Interface())
+	if field, ok := scope.FieldByName("id"); ok {
+		c.ID = uint(reflect.Indirect(field.Field).Uint())
+	}
+
+	if key.Column != "" {
+		if field, ok := scope.FieldByName(key.Column); ok {
+			if v := reflect.Indirect(field.Field); v.IsValid() {
+				c.Value = v.Interface()
+			}
+		}
+		c.Null = c.Value == nil
+
+		if t, ok := c.Value.(time.Time); ok {
+			c.Value = t.Format(time.RFC3339Nano)
+			c.Time = true
+		}
+	}
+
+	return encodeCursor(c)
+}
+
+// 游标之后的数据的查询条件(NULL视为最大值)
+func cursorCondition(column string, c *cursor, desc bool) (string, []interface{}) {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+
+	switch {
+	case column == "":
+		return "id" + op + "?", []interface{}{c.ID}
+	case c.Null && desc:
+		return "(" + column + " IS NULL AND id" + op + "?) OR " + column + " IS NOT NULL", []interface{}{c.ID}
+	case c.Null:
+		return column + " IS NULL AND id" + op + "?", []interface{}{c.ID}
+	case desc:
+		return "(" + column + op + "?) OR (" + column + "=? AND id" + op + "?)", []interface{}{c.Value, c.Value, c.ID}
+	}
+	return "(" + column + op + "?) OR (" + column + "=? AND id" + op + "?) OR " + column + " IS NULL", []interface{}{c.Value, c.Value, c.ID}
+}
+
+// WrapCursorQuery 包装游标分页查询(按排序键进行键集分页，不使用OFFSET)
+// 查询规格中指定排序字段时使用该字段作为排序键，否则使用defaultKey；
+// 排序值为NULL的数据视为最大值(升序时排在最后，降序时排在最前)，不依赖数据库对NULL的排序规则
+func WrapCursorQuery(db *gorm.DB, cp *schema.CursorParam, spec *schema.QuerySpec, columns QueryColumns, defaultKey CursorKey, out interface{}) (*schema.PaginationResult, error) {
+	key, err := getCursorKey(spec, columns, defaultKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *cursor
+	if cp.Cursor != "" {
+		c, err = decodeCursor(cp.Cursor, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pr := &schema.PaginationResult{
+		Cursor: &schema.CursorResult{
+			Limit: cp.Limit,
+		},
+	}
+
+	switch cp.Total {
+	case schema.CursorTotalExact:
+		err = db.Count(&pr.Total).Error
+		pr.Cursor.Counted = true
+	case schema.CursorTotalEstimate:
+		pr.Total, pr.Cursor.Estimated, err = gormplus.Wrap(db).EstimateCount(db, db.NewScope(out).TableName())
+		pr.Cursor.Counted = true
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	backward := c != nil && c.Backward
+	desc := key.Desc != backward
+	dir := " ASC"
+	if desc {
+		dir = " DESC"
+	}
+
+	if c != nil {
+		cond, args := cursorCondition(key.Column, c, desc)
+		db = db.Where(cond, args...)
+	}
+
+	order := "id" + dir
+	if key.Column != "" {
+		order = key.Column + " IS NULL" + dir + "," + key.Column + dir + "," + order
+	}
+
+	// 多查询一条数据，用于判断是否还有数据
+	result := db.Order(order, true).Limit(cp.Limit + 1).Find(out)
+	if err := result.Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	list := reflect.ValueOf(out).Elem()
+	hasMore := list.Len() > cp.Limit
+	if hasMore {
+		list.Set(list.Slice(0, cp.Limit))
+	}
+
+	n := list.Len()
+	if n == 0 {
+		return pr, nil
+	}
+
+	// 查询上一页时按相反的顺序查询，需要还原顺序
+	if backward {
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			vi, vj := list.Index(i).Interface(), list.Index(j).Interface()
+			list.Index(i).Set(reflect.ValueOf(vj))
+			list.Index(j).Set(reflect.ValueOf(vi))
+		}
+	}
+
+	if (!backward && hasMore) || backward {
+		pr.Cursor.Next, err = newCursor(db, key, list.Index(n-1), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if (backward && hasMore) || (!backward && c != nil) {
+		pr.Cursor.Prev, err = newCursor(db, key, list.Index(0), true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pr, nil
+}
@@ -2,10 +2,14 @@ package model
 
 import (
 	"context"
+	"time"
+
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/jinzhu/gorm"
 )
 
 
@@ -19,6 +23,18 @@ type Demo struct {
 	db *gormplus.DB
 }
 
+// 允许通过通用查询规格进行排序、字段选择及过滤的demo字段
+var demoQueryColumns = QueryColumns{
+	"record_id":  "record_id",
+	"code":       "code",
+	"name":       "name",
+	"memo":       "memo",
+	"status":     "status",
+	"creator":    "creator",
+	"version":    "version",
+	"created_at": "created_at",
+}
+
 func (a *Demo) getQueryOption(opts ...schema.DemoQueryOptions) schema.DemoQueryOptions {
 	var opt schema.DemoQueryOptions
 	if len(opts) > 0 {
@@ -29,26 +45,37 @@ func (a *Demo) getQueryOption(opts ...schema.DemoQueryOptions) schema.DemoQueryO
 
 // Query 查询数据
 func (a *Demo) Query(ctx context.Context, params schema.DemoQueryParam, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
-	db := entity.GetDemoDB(ctx, a.db).DB
+	db := entity.GetDemoReadDB(ctx, a.db).DB
 	if v := params.Code; v != "" {
 		db = db.Where("code=?", v)
 	}
 	if v := params.LikeCode; v != "" {
-		db = db.Where("code LIKE ?", "%"+v+"%")
+		db = db.Where(likeQuery("code"), likeContains(v))
 	}
 	if v := params.LikeName; v != "" {
-		db = db.Where("name LIKE ?", "%"+v+"%")
+		db = db.Where(likeQuery("name"), likeContains(v))
 	}
 	if v := params.Status; v > 0 {
 		db = db.Where("status=?", v)
 	}
-	db = db.Order("id DESC")
-
 	opt := a.getQueryOption(opts...)
-	var list entity.Demos
-	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	db, err := WrapQuerySpec(db, opt.QuerySpec, demoQueryColumns, "id DESC")
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
+	}
+
+	var list entity.Demos
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(db, cp, opt.QuerySpec, demoQueryColumns, CursorKey{Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(db, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 	}
 	qr := &schema.DemoQueryResult{
 		PageResult: pr,
@@ -60,7 +87,7 @@ func (a *Demo) Query(ctx context.Context, params schema.DemoQueryParam, opts ...
 
 // Get 查询指定数据
 func (a *Demo) Get(ctx context.Context, recordID string, opts ...schema.DemoQueryOptions) (*schema.Demo, error) {
-	db := entity.GetDemoDB(ctx, a.db).Where("record_id=?", recordID)
+	db := entity.GetDemoReadDB(ctx, a.db).Where("record_id=?", recordID)
 	var item entity.Demo
 	ok, err := a.db.FindOne(db, &item)
 	if err != nil {
@@ -74,6 +101,7 @@ func (a *Demo) Get(ctx context.Context, recordID string, opts ...schema.DemoQuer
 
 // Create 创建数据
 func (a *Demo) Create(ctx context.Context, item schema.Demo) error {
+	item.Version = 1
 	demo := entity.SchemaDemo(item).ToDemo()
 	result := entity.GetDemoDB(ctx, a.db).Create(demo)
 	if err := result.Error; err != nil {
@@ -82,12 +110,15 @@ func (a *Demo) Create(ctx context.Context, item schema.Demo) error {
 	return nil
 }
 
-// Update 更新数据
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
 func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) error {
-	demo := entity.SchemaDemo(item).ToDemo()
-	result := entity.GetDemoDB(ctx, a.db).Where("record_id=?", recordID).Omit("record_id", "creator").Updates(demo)
+	sitem := entity.SchemaDemo(item)
+	sitem.Version = item.Version + 1
+	result := entity.GetDemoDB(ctx, a.db).Where("record_id=? AND version=?", recordID, item.Version).Omit("record_id", "creator").Updates(sitem.ToDemo())
 	if err := result.Error; err != nil {
 		return errors.WithStack(err)
+	} else if result.RowsAffected == 0 {
+		return errors.ErrResourceConflict
 	}
 	return nil
 }
@@ -103,11 +134,75 @@ func (a *Demo) Delete(ctx context.Context, recordID string) error {
 
 // UpdateStatus 更新状态
 func (a *Demo) UpdateStatus(ctx context.Context, recordID string, status int) error {
-	result := entity.GetDemoDB(ctx, a.db).Where("record_id=?", recordID).Update("status", status)
+	result := entity.GetDemoDB(ctx, a.db).Where("record_id=?", recordID).Updates(map[string]interface{}{
+		"status":  status,
+		"version": gorm.Expr("version+1"),
+	})
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Demo) QueryDeleted(ctx context.Context, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
+	db := entity.GetDemoReadDB(ctx, a.db).Unscoped().Where("deleted_at IS NOT NULL")
+	db = db.Order("deleted_at DESC,id DESC")
+
+	opt := a.getQueryOption(opts...)
+	var list entity.Demos
+	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.DemoQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaDemos(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Demo) GetDeleted(ctx context.Context, recordID string) (*schema.Demo, error) {
+	db := entity.GetDemoReadDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID)
+	var item entity.Demo
+	ok, err := a.db.FindOne(db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaDemo(), nil
+}
+
+// Restore 恢复已删除的数据
+func (a *Demo) Restore(ctx context.Context, recordID string) error {
+	result := entity.GetDemoDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    gorm.Expr("version+1"),
+	})
 	if err := result.Error; err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
+// Purge 彻底删除已删除的数据
+func (a *Demo) Purge(ctx context.Context, recordID string) error {
+	result := entity.GetDemoDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID).Delete(entity.Demo{})
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
 
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Demo) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	result := entity.GetDemoDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.Demo{})
+	if err := result.Error; err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -2,12 +2,15 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/errors"
 
 	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/jinzhu/gorm"
 )
 
 // NewMenu 创建菜单存储实例
@@ -20,6 +23,21 @@ type Menu struct {
 	db *gormplus.DB
 }
 
+// 允许通过通用查询规格进行排序、字段选择及过滤的菜单字段
+var menuQueryColumns = QueryColumns{
+	"record_id":   "record_id",
+	"name":        "name",
+	"sequence":    "sequence",
+	"icon":        "icon",
+	"router":      "router",
+	"hidden":      "hidden",
+	"parent_id":   "parent_id",
+	"parent_path": "parent_path",
+	"creator":     "creator",
+	"version":     "version",
+	"created_at":  "created_at",
+}
+
 func (a *Menu) getQueryOption(opts ...schema.MenuQueryOptions) schema.MenuQueryOptions {
 	var opt schema.MenuQueryOptions
 	if len(opts) > 0 {
@@ -30,29 +48,40 @@ func (a *Menu) getQueryOption(opts ...schema.MenuQueryOptions) schema.MenuQueryO
 
 // Query 查询数据
 func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
-	db := entity.GetMenuDB(ctx, a.db).DB
+	db := entity.GetMenuReadDB(ctx, a.db).DB
 	if v := params.RecordIDs; len(v) > 0 {
 		db = db.Where("record_id IN(?)", v)
 	}
 	if v := params.LikeName; v != "" {
-		db = db.Where("name LIKE ?", "%"+v+"%")
+		db = db.Where(likeQuery("name"), likeContains(v))
 	}
 	if v := params.ParentID; v != nil {
 		db = db.Where("parent_id=?", *v)
 	}
 	if v := params.PrefixParentPath; v != "" {
-		db = db.Where("parent_path LIKE ?", v+"%")
+		db = db.Where(likeQuery("parent_path"), likePrefix(v))
 	}
 	if v := params.Hidden; v != nil {
 		db = db.Where("hidden=?", *v)
 	}
-	db = db.Order("sequence DESC,id DESC")
-
 	opt := a.getQueryOption(opts...)
-	var list entity.Menus
-	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	db, err := WrapQuerySpec(db, opt.QuerySpec, menuQueryColumns, "sequence DESC,id DESC")
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
+	}
+
+	var list entity.Menus
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(db, cp, opt.QuerySpec, menuQueryColumns, CursorKey{Column: "sequence", Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(db, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 	}
 	qr := &schema.MenuQueryResult{
 		PageResult: pr,
@@ -112,7 +141,7 @@ func (a *Menu) fillSchemaMenus(ctx context.Context, items []*schema.Menu, opts .
 // Get 查询指定数据
 func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQueryOptions) (*schema.Menu, error) {
 	var item entity.Menu
-	ok, err := a.db.FindOne(entity.GetMenuDB(ctx, a.db).Where("record_id=?", recordID), &item)
+	ok, err := a.db.FindOne(entity.GetMenuReadDB(ctx, a.db).Where("record_id=?", recordID), &item)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	} else if !ok {
@@ -132,6 +161,7 @@ func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQuer
 func (a *Menu) Create(ctx context.Context, item schema.Menu) error {
 	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
 		sitem := entity.SchemaMenu(item)
+		sitem.Version = 1
 		result := entity.GetMenuDB(ctx, a.db).Create(sitem.ToMenu())
 		if err := result.Error; err != nil {
 			return errors.WithStack(err)
@@ -257,13 +287,17 @@ func (a *Menu) updateResources(ctx context.Context, menuID string, items entity.
 	return nil
 }
 
-// Update 更新数据
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
 func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) error {
 	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		item.RecordID = recordID
 		sitem := entity.SchemaMenu(item)
-		result := entity.GetMenuDB(ctx, a.db).Where("record_id=?", recordID).Omit("record_id", "creator").Updates(sitem.ToMenu())
+		sitem.Version = item.Version + 1
+		result := entity.GetMenuDB(ctx, a.db).Where("record_id=? AND version=?", recordID, item.Version).Omit("record_id", "creator").Updates(sitem.ToMenu())
 		if err := result.Error; err != nil {
 			return errors.WithStack(err)
+		} else if result.RowsAffected == 0 {
+			return errors.ErrResourceConflict
 		}
 
 		err := a.updateActions(ctx, recordID, sitem.ToMenuActions())
@@ -282,7 +316,10 @@ func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) er
 
 // UpdateParentPath 更新父级路径
 func (a *Menu) UpdateParentPath(ctx context.Context, recordID, parentPath string) error {
-	result := entity.GetMenuDB(ctx, a.db).Where("record_id=?", recordID).Update("parent_path", parentPath)
+	result := entity.GetMenuDB(ctx, a.db).Where("record_id=?", recordID).Updates(map[string]interface{}{
+		"parent_path": parentPath,
+		"version":     gorm.Expr("version+1"),
+	})
 	if err := result.Error; err != nil {
 		return errors.WithStack(err)
 	}
@@ -312,7 +349,7 @@ func (a *Menu) Delete(ctx context.Context, recordID string) error {
 
 func (a *Menu) queryActions(ctx context.Context, menuIDs ...string) (entity.MenuActions, error) {
 	var list entity.MenuActions
-	result := entity.GetMenuActionDB(ctx, a.db).Where("menu_id IN(?)", menuIDs).Find(&list)
+	result := entity.GetMenuActionReadDB(ctx, a.db).Where("menu_id IN(?)", menuIDs).Find(&list)
 	if err := result.Error; err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -322,10 +359,121 @@ func (a *Menu) queryActions(ctx context.Context, menuIDs ...string) (entity.Menu
 
 func (a *Menu) queryResources(ctx context.Context, menuIDs ...string) (entity.MenuResources, error) {
 	var list entity.MenuResources
-	result := entity.GetMenuResourceDB(ctx, a.db).Where("menu_id IN(?)", menuIDs).Find(&list)
+	result := entity.GetMenuResourceReadDB(ctx, a.db).Where("menu_id IN(?)", menuIDs).Find(&list)
 	if err := result.Error; err != nil {
 		return nil, errors.WithStack(err)
 	}
 
 	return list, nil
 }
+
+// QueryDeleted 查询已删除的数据
+func (a *Menu) QueryDeleted(ctx context.Context, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+	db := entity.GetMenuReadDB(ctx, a.db).Unscoped().Where("deleted_at IS NOT NULL")
+	db = db.Order("deleted_at DESC,id DESC")
+
+	opt := a.getQueryOption(opts...)
+	var list entity.Menus
+	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.MenuQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaMenus(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Menu) GetDeleted(ctx context.Context, recordID string) (*schema.Menu, error) {
+	db := entity.GetMenuReadDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID)
+	var item entity.Menu
+	ok, err := a.db.FindOne(db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaMenu(), nil
+}
+
+// Restore 恢复已删除的数据(包括一同删除的菜单动作及资源关联数据)
+func (a *Menu) Restore(ctx context.Context, recordID string) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		item, err := a.GetDeleted(ctx, recordID)
+		if err != nil {
+			return err
+		} else if item == nil {
+			return nil
+		}
+
+		result := entity.GetMenuActionDB(ctx, a.db).Unscoped().Where("menu_id=? AND deleted_at>=?", recordID, item.DeletedAt).Update("deleted_at", nil)
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetMenuResourceDB(ctx, a.db).Unscoped().Where("menu_id=? AND deleted_at>=?", recordID, item.DeletedAt).Update("deleted_at", nil)
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetMenuDB(ctx, a.db).Unscoped().Where("record_id=?", recordID).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version+1"),
+		})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Menu) Purge(ctx context.Context, recordID string) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		result := entity.GetMenuDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID).Delete(entity.Menu{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = entity.GetMenuActionDB(ctx, a.db).Unscoped().Where("menu_id=?", recordID).Delete(entity.MenuAction{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetMenuResourceDB(ctx, a.db).Unscoped().Where("menu_id=?", recordID).Delete(entity.MenuResource{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Menu) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		result := entity.GetMenuDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.Menu{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetMenuActionDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.MenuAction{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetMenuResourceDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.MenuResource{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
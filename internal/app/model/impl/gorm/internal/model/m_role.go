@@ -2,12 +2,15 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
 
+	"github.com/jinzhu/gorm"
+
 )
 
 // NewRole 创建角色存储实例
@@ -20,6 +23,17 @@ type Role struct {
 	db *gormplus.DB
 }
 
+// 允许通过通用查询规格进行排序、字段选择及过滤的角色字段
+var roleQueryColumns = QueryColumns{
+	"record_id":  "record_id",
+	"name":       "name",
+	"sequence":   "sequence",
+	"memo":       "memo",
+	"creator":    "creator",
+	"version":    "version",
+	"created_at": "created_at",
+}
+
 func (a *Role) getQueryOption(opts ...schema.RoleQueryOptions) schema.RoleQueryOptions {
 	var opt schema.RoleQueryOptions
 	if len(opts) > 0 {
@@ -30,7 +44,7 @@ func (a *Role) getQueryOption(opts ...schema.RoleQueryOptions) schema.RoleQueryO
 
 // Query 查询数据
 func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
-	db := entity.GetRoleDB(ctx, a.db).DB
+	db := entity.GetRoleReadDB(ctx, a.db).DB
 	if v := params.RecordIDs; len(v) > 0 {
 		db = db.Where("record_id IN(?)", v)
 	}
@@ -38,19 +52,30 @@ func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...
 		db = db.Where("name=?", v)
 	}
 	if v := params.LikeName; v != "" {
-		db = db.Where("name LIKE ?", "%"+v+"%")
+		db = db.Where(likeQuery("name"), likeContains(v))
 	}
 	if v := params.UserID; v != "" {
-		subQuery := entity.GetUserRoleDB(ctx, a.db).Where("user_id=?", v).Select("role_id").SubQuery()
-		db = db.Where("record_id IN(?)", subQuery)
+		subQuery := entity.GetUserRoleReadDB(ctx, a.db).Where("user_id=?", v).Select("role_id").SubQuery()
+		db = db.Where("record_id IN ?", subQuery)
 	}
-	db = db.Order("sequence DESC,id DESC")
-
 	opt := a.getQueryOption(opts...)
-	var list entity.Roles
-	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	db, err := WrapQuerySpec(db, opt.QuerySpec, roleQueryColumns, "sequence DESC,id DESC")
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
+	}
+
+	var list entity.Roles
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(db, cp, opt.QuerySpec, roleQueryColumns, CursorKey{Column: "sequence", Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(db, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 	}
 	qr := &schema.RoleQueryResult{
 		PageResult: pr,
@@ -97,7 +122,7 @@ func (a *Role) fillSchameRoles(ctx context.Context, items []*schema.Role, opts .
 // Get 查询指定数据
 func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQueryOptions) (*schema.Role, error) {
 	var role entity.Role
-	ok, err := a.db.FindOne(entity.GetRoleDB(ctx, a.db).Where("record_id=?", recordID), &role)
+	ok, err := a.db.FindOne(entity.GetRoleReadDB(ctx, a.db).Where("record_id=?", recordID), &role)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	} else if !ok {
@@ -117,6 +142,7 @@ func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQuer
 func (a *Role) Create(ctx context.Context, item schema.Role) error {
 	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
 		sitem := entity.SchemaRole(item)
+		sitem.Version = 1
 		result := entity.GetRoleDB(ctx, a.db).Create(sitem.ToRole())
 		if err := result.Error; err != nil {
 			return errors.WithStack(err)
@@ -184,13 +210,17 @@ func (a *Role) updateMenus(ctx context.Context, roleID string, items entity.Role
 	return nil
 }
 
-// Update 更新数据
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
 func (a *Role) Update(ctx context.Context, recordID string, item schema.Role) error {
 	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		item.RecordID = recordID
 		sitem := entity.SchemaRole(item)
-		result := entity.GetRoleDB(ctx, a.db).Where("record_id=?", recordID).Omit("record_id", "creator").Updates(sitem.ToRole())
+		sitem.Version = item.Version + 1
+		result := entity.GetRoleDB(ctx, a.db).Where("record_id=? AND version=?", recordID, item.Version).Omit("record_id", "creator").Updates(sitem.ToRole())
 		if err := result.Error; err != nil {
 			return errors.WithStack(err)
+		} else if result.RowsAffected == 0 {
+			return errors.ErrResourceConflict
 		}
 
 		err := a.updateMenus(ctx, recordID, sitem.ToRoleMenus())
@@ -221,10 +251,106 @@ func (a *Role) Delete(ctx context.Context, recordID string) error {
 
 func (a *Role) queryMenus(ctx context.Context, roleIDs ...string) (entity.RoleMenus, error) {
 	var list entity.RoleMenus
-	result := entity.GetRoleMenuDB(ctx, a.db).Where("role_id IN(?)", roleIDs).Find(&list)
+	result := entity.GetRoleMenuReadDB(ctx, a.db).Where("role_id IN(?)", roleIDs).Find(&list)
 	if err := result.Error; err != nil {
 		return nil, errors.WithStack(err)
 	}
 
 	return list, nil
 }
+
+// QueryDeleted 查询已删除的数据
+func (a *Role) QueryDeleted(ctx context.Context, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+	db := entity.GetRoleReadDB(ctx, a.db).Unscoped().Where("deleted_at IS NOT NULL")
+	db = db.Order("deleted_at DESC,id DESC")
+
+	opt := a.getQueryOption(opts...)
+	var list entity.Roles
+	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.RoleQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaRoles(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Role) GetDeleted(ctx context.Context, recordID string) (*schema.Role, error) {
+	db := entity.GetRoleReadDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID)
+	var item entity.Role
+	ok, err := a.db.FindOne(db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaRole(), nil
+}
+
+// Restore 恢复已删除的数据(包括一同删除的角色菜单关联数据)
+func (a *Role) Restore(ctx context.Context, recordID string) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		item, err := a.GetDeleted(ctx, recordID)
+		if err != nil {
+			return err
+		} else if item == nil {
+			return nil
+		}
+
+		result := entity.GetRoleMenuDB(ctx, a.db).Unscoped().Where("role_id=? AND deleted_at>=?", recordID, item.DeletedAt).Update("deleted_at", nil)
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetRoleDB(ctx, a.db).Unscoped().Where("record_id=?", recordID).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version+1"),
+		})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Role) Purge(ctx context.Context, recordID string) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		result := entity.GetRoleDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID).Delete(entity.Role{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = entity.GetRoleMenuDB(ctx, a.db).Unscoped().Where("role_id=?", recordID).Delete(entity.RoleMenu{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Role) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		result := entity.GetRoleDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.Role{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetRoleMenuDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.RoleMenu{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
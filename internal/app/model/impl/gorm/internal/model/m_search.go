@@ -0,0 +1,164 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+)
+
+// 检索的数据表定义
+type searchTable struct {
+	Type    string                                                  // 数据类型
+	Table   func() string                                           // 表名
+	GetDB   func(ctx context.Context, db *gormplus.DB) *gormplus.DB // 获取存储
+	Title   string                                                  // 标题列
+	Columns []string                                                // 检索列
+}
+
+var searchTables = []*searchTable{
+	{
+		Type:    schema.SearchTypeUser,
+		Table:   func() string { return entity.User{}.TableName() },
+		GetDB:   entity.GetUserReadDB,
+		Title:   "user_name",
+		Columns: []string{"user_name", "real_name", "email", "phone"},
+	},
+	{
+		Type:    schema.SearchTypeRole,
+		Table:   func() string { return entity.Role{}.TableName() },
+		GetDB:   entity.GetRoleReadDB,
+		Title:   "name",
+		Columns: []string{"name", "memo"},
+	},
+	{
+		Type:    schema.SearchTypeMenu,
+		Table:   func() string { return entity.Menu{}.TableName() },
+		GetDB:   entity.GetMenuReadDB,
+		Title:   "name",
+		Columns: []string{"name", "router"},
+	},
+	{
+		Type:    schema.SearchTypeDemo,
+		Table:   func() string { return entity.Demo{}.TableName() },
+		GetDB:   entity.GetDemoReadDB,
+		Title:   "name",
+		Columns: []string{"code", "name", "memo"},
+	},
+}
+
+// postgres全文检索的文档向量表达式
+func (t *searchTable) tsvector() string {
+	var cols []string
+	for _, col := range t.Columns {
+		cols = append(cols, fmt.Sprintf("coalesce(%s,'')", col))
+	}
+	return fmt.Sprintf("to_tsvector('simple', %s)", strings.Join(cols, " || ' ' || "))
+}
+
+func (t *searchTable) indexName() string {
+	return fmt.Sprintf("idx_%s_fulltext", t.Table())
+}
+
+// CreateSearchIndexes 创建数据库原生的全文索引(仅mysql及postgres)
+func CreateSearchIndexes(db *gormplus.DB) error {
+	dialect := db.Dialect()
+	for _, t := range searchTables {
+		var sql string
+		switch dialect.GetName() {
+		case "mysql":
+			if dialect.HasIndex(t.Table(), t.indexName()) {
+				continue
+			}
+			// 使用ngram解析器以支持中文检索
+			sql = fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (%s) WITH PARSER ngram",
+				t.indexName(), t.Table(), strings.Join(t.Columns, ","))
+		case "postgres":
+			sql = fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s)",
+				t.indexName(), t.Table(), t.tsvector())
+		default:
+			return nil
+		}
+
+		err := db.Exec(sql).Error
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// NewSearch 创建数据库原生全文检索实例
+func NewSearch(db *gormplus.DB) *Search {
+	return &Search{db}
+}
+
+// Search 数据库原生全文检索(mysql使用FULLTEXT索引，postgres使用tsvector，其它数据库使用模糊查询)
+// 检索直接查询业务数据表，不需要维护索引
+type Search struct {
+	db *gormplus.DB
+}
+
+// Index 索引文档(数据表即索引，无需处理)
+func (a *Search) Index(ctx context.Context, docs ...*schema.SearchDocument) error {
+	return nil
+}
+
+// Delete 删除文档(数据表即索引，无需处理)
+func (a *Search) Delete(ctx context.Context, typ string, recordIDs ...string) error {
+	return nil
+}
+
+// Search 检索数据
+func (a *Search) Search(ctx context.Context, params schema.SearchParam) (schema.SearchResult, error) {
+	types := make(map[string]bool)
+	for _, typ := range params.Types {
+		types[typ] = true
+	}
+
+	result := make(schema.SearchResult)
+	for _, t := range searchTables {
+		if len(types) > 0 && !types[t.Type] {
+			continue
+		}
+
+		db := t.GetDB(ctx, a.db).DB
+		switch a.db.Dialect().GetName() {
+		case "mysql":
+			match := fmt.Sprintf("MATCH(%s) AGAINST(? IN NATURAL LANGUAGE MODE)", strings.Join(t.Columns, ","))
+			db = db.Select(fmt.Sprintf("record_id,%s AS title,%s AS score", t.Title, match), params.Query).
+				Where(match, params.Query).Order("score DESC")
+		case "postgres":
+			query := "plainto_tsquery('simple', ?)"
+			db = db.Select(fmt.Sprintf("record_id,%s AS title,ts_rank(%s,%s) AS score", t.Title, t.tsvector(), query), params.Query).
+				Where(fmt.Sprintf("%s @@ %s", t.tsvector(), query), params.Query).Order("score DESC")
+		default:
+			var conds []string
+			var args []interface{}
+			for _, col := range t.Columns {
+				conds = append(conds, likeQuery(col))
+				args = append(args, likeContains(params.Query))
+			}
+			db = db.Select(fmt.Sprintf("record_id,%s AS title,1 AS score", t.Title)).
+				Where(strings.Join(conds, " OR "), args...).Order("id DESC")
+		}
+
+		if params.Limit > 0 {
+			db = db.Limit(params.Limit)
+		}
+
+		var items []*schema.SearchItem
+		err := db.Scan(&items).Error
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if len(items) > 0 {
+			result[t.Type] = items
+		}
+	}
+	return result, nil
+}
@@ -2,12 +2,15 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/errors"
 
 	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm/internal/entity"
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/jinzhu/gorm"
 )
 
 // NewUser 创建用户存储实例
@@ -20,6 +23,19 @@ type User struct {
 	db *gormplus.DB
 }
 
+// 允许通过通用查询规格进行排序、字段选择及过滤的用户字段(不允许包含敏感字段)
+var userQueryColumns = QueryColumns{
+	"record_id":  "record_id",
+	"user_name":  "user_name",
+	"real_name":  "real_name",
+	"phone":      "phone",
+	"email":      "email",
+	"status":     "status",
+	"creator":    "creator",
+	"version":    "version",
+	"created_at": "created_at",
+}
+
 func (a *User) getQueryOption(opts ...schema.UserQueryOptions) schema.UserQueryOptions {
 	var opt schema.UserQueryOptions
 	if len(opts) > 0 {
@@ -30,30 +46,44 @@ func (a *User) getQueryOption(opts ...schema.UserQueryOptions) schema.UserQueryO
 
 // Query 查询数据
 func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
-	db := entity.GetUserDB(ctx, a.db).DB
+	db := entity.GetUserReadDB(ctx, a.db).DB
+	if v := params.RecordIDs; len(v) > 0 {
+		db = db.Where("record_id IN(?)", v)
+	}
 	if v := params.UserName; v != "" {
 		db = db.Where("user_name=?", v)
 	}
 	if v := params.LikeUserName; v != "" {
-		db = db.Where("user_name LIKE ?", "%"+v+"%")
+		db = db.Where(likeQuery("user_name"), likeContains(v))
 	}
 	if v := params.LikeRealName; v != "" {
-		db = db.Where("real_name LIKE ?", "%"+v+"%")
+		db = db.Where(likeQuery("real_name"), likeContains(v))
 	}
 	if v := params.Status; v > 0 {
 		db = db.Where("status=?", v)
 	}
 	if v := params.RoleIDs; len(v) > 0 {
-		subQuery := entity.GetUserRoleDB(ctx, a.db).Select("user_id").Where("role_id IN(?)", v).SubQuery()
-		db = db.Where("record_id IN(?)", subQuery)
+		subQuery := entity.GetUserRoleReadDB(ctx, a.db).Select("user_id").Where("role_id IN(?)", v).SubQuery()
+		db = db.Where("record_id IN ?", subQuery)
 	}
-	db = db.Order("id DESC")
-
 	opt := a.getQueryOption(opts...)
-	var list entity.Users
-	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	db, err := WrapQuerySpec(db, opt.QuerySpec, userQueryColumns, "id DESC")
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
+	}
+
+	var list entity.Users
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(db, cp, opt.QuerySpec, userQueryColumns, CursorKey{Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(db, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 	}
 
 	qr := &schema.UserQueryResult{
@@ -100,7 +130,7 @@ func (a *User) fillSchemaUsers(ctx context.Context, items []*schema.User, opts .
 // Get 查询指定数据
 func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQueryOptions) (*schema.User, error) {
 	var item entity.User
-	ok, err := a.db.FindOne(entity.GetUserDB(ctx, a.db).Where("record_id=?", recordID), &item)
+	ok, err := a.db.FindOne(entity.GetUserReadDB(ctx, a.db).Where("record_id=?", recordID), &item)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	} else if !ok {
@@ -120,6 +150,7 @@ func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQuer
 func (a *User) Create(ctx context.Context, item schema.User) error {
 	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
 		sitem := entity.SchemaUser(item)
+		sitem.Version = 1
 		result := entity.GetUserDB(ctx, a.db).Create(sitem.ToUser())
 		if err := result.Error; err != nil {
 			return errors.WithStack(err)
@@ -167,18 +198,22 @@ func (a *User) compareUpdateRole(oldList, newList []*entity.UserRole) (clist, dl
 	return
 }
 
-// Update 更新数据
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
 func (a *User) Update(ctx context.Context, recordID string, item schema.User) error {
 	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		item.RecordID = recordID
 		sitem := entity.SchemaUser(item)
 		omits := []string{"record_id", "creator"}
 		if sitem.Password == "" {
 			omits = append(omits, "password")
 		}
 
-		result := entity.GetUserDB(ctx, a.db).Where("record_id=?", recordID).Omit(omits...).Updates(sitem.ToUser())
+		sitem.Version = item.Version + 1
+		result := entity.GetUserDB(ctx, a.db).Where("record_id=? AND version=?", recordID, item.Version).Omit(omits...).Updates(sitem.ToUser())
 		if err := result.Error; err != nil {
 			return errors.WithStack(err)
+		} else if result.RowsAffected == 0 {
+			return errors.ErrResourceConflict
 		}
 
 		roles, err := a.queryRoles(ctx, recordID)
@@ -230,7 +265,10 @@ func (a *User) Delete(ctx context.Context, recordID string) error {
 
 // UpdateStatus 更新状态
 func (a *User) UpdateStatus(ctx context.Context, recordID string, status int) error {
-	result := entity.GetUserDB(ctx, a.db).Where("record_id=?", recordID).Update("status", status)
+	result := entity.GetUserDB(ctx, a.db).Where("record_id=?", recordID).Updates(map[string]interface{}{
+		"status":  status,
+		"version": gorm.Expr("version+1"),
+	})
 	if err := result.Error; err != nil {
 		return errors.WithStack(err)
 	}
@@ -239,7 +277,10 @@ func (a *User) UpdateStatus(ctx context.Context, recordID string, status int) er
 
 // UpdatePassword 更新密码
 func (a *User) UpdatePassword(ctx context.Context, recordID, password string) error {
-	result := entity.GetUserDB(ctx, a.db).Where("record_id=?", recordID).Update("password", password)
+	result := entity.GetUserDB(ctx, a.db).Where("record_id=?", recordID).Updates(map[string]interface{}{
+		"password": password,
+		"version":  gorm.Expr("version+1"),
+	})
 	if err := result.Error; err != nil {
 		return errors.WithStack(err)
 	}
@@ -248,9 +289,105 @@ func (a *User) UpdatePassword(ctx context.Context, recordID, password string) er
 
 func (a *User) queryRoles(ctx context.Context, userIDs ...string) (entity.UserRoles, error) {
 	var list entity.UserRoles
-	result := entity.GetUserRoleDB(ctx, a.db).Where("user_id IN(?)", userIDs).Find(&list)
+	result := entity.GetUserRoleReadDB(ctx, a.db).Where("user_id IN(?)", userIDs).Find(&list)
 	if err := result.Error; err != nil {
 		return nil, errors.WithStack(err)
 	}
 	return list, nil
 }
+
+// QueryDeleted 查询已删除的数据
+func (a *User) QueryDeleted(ctx context.Context, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	db := entity.GetUserReadDB(ctx, a.db).Unscoped().Where("deleted_at IS NOT NULL")
+	db = db.Order("deleted_at DESC,id DESC")
+
+	opt := a.getQueryOption(opts...)
+	var list entity.Users
+	pr, err := WrapPageQuery(db, opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.UserQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaUsers(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *User) GetDeleted(ctx context.Context, recordID string) (*schema.User, error) {
+	db := entity.GetUserReadDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID)
+	var item entity.User
+	ok, err := a.db.FindOne(db, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaUser(), nil
+}
+
+// Restore 恢复已删除的数据(包括一同删除的用户角色关联数据)
+func (a *User) Restore(ctx context.Context, recordID string) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		item, err := a.GetDeleted(ctx, recordID)
+		if err != nil {
+			return err
+		} else if item == nil {
+			return nil
+		}
+
+		result := entity.GetUserRoleDB(ctx, a.db).Unscoped().Where("user_id=? AND deleted_at>=?", recordID, item.DeletedAt).Update("deleted_at", nil)
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetUserDB(ctx, a.db).Unscoped().Where("record_id=?", recordID).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version+1"),
+		})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
+
+// Purge 彻底删除已删除的数据
+func (a *User) Purge(ctx context.Context, recordID string) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		result := entity.GetUserDB(ctx, a.db).Unscoped().Where("record_id=? AND deleted_at IS NOT NULL", recordID).Delete(entity.User{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = entity.GetUserRoleDB(ctx, a.db).Unscoped().Where("user_id=?", recordID).Delete(entity.UserRole{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *User) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return ExecTrans(ctx, a.db, func(ctx context.Context) error {
+		result := entity.GetUserDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.User{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		result = entity.GetUserRoleDB(ctx, a.db).Unscoped().Where("deleted_at<?", deletedAt).Delete(entity.UserRole{})
+		if err := result.Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+}
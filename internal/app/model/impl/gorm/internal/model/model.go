@@ -2,7 +2,10 @@ package model
 
 import (
 	"context"
+	"strings"
+
 	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/errors"
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
 
@@ -46,4 +49,86 @@ func WrapPageQuery(db *gorm.DB, pp *schema.PaginationParam, out interface{}) (*s
 	return nil, result.Error
 }
 
+// 模糊查询值的转义(反斜杠在mysql的字符串常量中本身需要转义，使用!作为转义字符保证各数据库一致)
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// 模糊查询条件(查询值需要通过likeContains或likePrefix转义)
+func likeQuery(column string) string {
+	return column + " LIKE ? ESCAPE '!'"
+}
+
+// 包含匹配的模糊查询值(查询值中的通配符按普通字符匹配)
+func likeContains(v string) string {
+	return "%" + likeEscaper.Replace(v) + "%"
+}
+
+// 前缀匹配的模糊查询值
+func likePrefix(v string) string {
+	return likeEscaper.Replace(v) + "%"
+}
+
+// QueryColumns 定义允许查询(排序、字段选择及过滤)的字段与数据库列的映射
+type QueryColumns map[string]string
+
+// WrapQuerySpec 包装通用查询规格(仅允许使用columns中定义的字段，否则返回ErrInvalidQueryField)
+// 未指定排序字段时使用defaultOrder排序
+func WrapQuerySpec(db *gorm.DB, spec *schema.QuerySpec, columns QueryColumns, defaultOrder string) (*gorm.DB, error) {
+	if spec == nil {
+		return db.Order(defaultOrder), nil
+	}
+
+	for _, item := range spec.Filters {
+		column, ok := columns[item.Field]
+		if !ok || len(item.Values) == 0 {
+			return nil, errors.ErrInvalidQueryField
+		}
+
+		switch item.Operator {
+		case schema.QueryOpEQ:
+			db = db.Where(column+"=?", item.Values[0])
+		case schema.QueryOpNE:
+			db = db.Where(column+"<>?", item.Values[0])
+		case schema.QueryOpIN:
+			db = db.Where(column+" IN(?)", item.Values)
+		case schema.QueryOpLike:
+			db = db.Where(likeQuery(column), likeContains(item.Values[0]))
+		case schema.QueryOpGTE:
+			db = db.Where(column+">=?", item.Values[0])
+		case schema.QueryOpLTE:
+			db = db.Where(column+"<=?", item.Values[0])
+		case schema.QueryOpBetween:
+			if len(item.Values) != 2 {
+				return nil, errors.ErrInvalidQueryField
+			}
+			db = db.Where(column+" BETWEEN ? AND ?", item.Values[0], item.Values[1])
+		default:
+			return nil, errors.ErrInvalidQueryField
+		}
+	}
+
+	// 选择字段仅做校验，由响应时过滤(实体转换依赖完整的列数据)
+	for _, field := range spec.Fields {
+		if _, ok := columns[field]; !ok {
+			return nil, errors.ErrInvalidQueryField
+		}
+	}
+
+	if len(spec.Sorts) == 0 {
+		return db.Order(defaultOrder), nil
+	}
 
+	var orders []string
+	for _, item := range spec.Sorts {
+		column, ok := columns[item.Field]
+		if !ok {
+			return nil, errors.ErrInvalidQueryField
+		}
+		if item.Desc {
+			column += " DESC"
+		}
+		orders = append(orders, column)
+	}
+	// 保证排序结果稳定
+	orders = append(orders, "id DESC")
+	return db.Order(strings.Join(orders, ",")), nil
+}
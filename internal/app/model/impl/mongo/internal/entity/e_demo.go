@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetDemoCollection 获取demo集合
+func GetDemoCollection(db *mongoplus.DB) *mongo.Collection {
+	return getCollection(db, "demo")
+}
+
+// SchemaDemo demo对象
+type SchemaDemo schema.Demo
+
+// ToDemo 转换为demo实体
+func (a SchemaDemo) ToDemo() *Demo {
+	item := &Demo{
+		RecordID: a.RecordID,
+		Code:     a.Code,
+		Name:     a.Name,
+		Memo:     a.Memo,
+		Status:   a.Status,
+		Creator:  a.Creator,
+		Version:  a.Version,
+	}
+	return item
+}
+
+// Demo demo实体
+type Demo struct {
+	Model    `bson:",inline"`
+	RecordID string `bson:"record_id"` // 记录内码
+	Code     string `bson:"code"`      // 编号
+	Name     string `bson:"name"`      // 名称
+	Memo     string `bson:"memo"`      // 备注
+	Status   int    `bson:"status"`    // 状态(1:启用 2:停用)
+	Creator  string `bson:"creator"`   // 创建者
+	Version  int    `bson:"version"`   // 版本号(每次更新递增)
+}
+
+func (a Demo) String() string {
+	return toString(a)
+}
+
+// CollectionName 集合名
+func (a Demo) CollectionName() string {
+	return a.Model.CollectionName("demo")
+}
+
+// ToSchemaDemo 转换为demo对象
+func (a Demo) ToSchemaDemo() *schema.Demo {
+	item := &schema.Demo{
+		RecordID:  a.RecordID,
+		Code:      a.Code,
+		Name:      a.Name,
+		Memo:      a.Memo,
+		Status:    a.Status,
+		Creator:   a.Creator,
+		Version:   a.Version,
+		CreatedAt: a.CreatedAt,
+		DeletedAt: a.DeletedAt,
+	}
+	return item
+}
+
+// Demos demo列表
+type Demos []*Demo
+
+// ToSchemaDemos 转换为demo对象列表
+func (a Demos) ToSchemaDemos() []*schema.Demo {
+	list := make([]*schema.Demo, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaDemo()
+	}
+	return list
+}
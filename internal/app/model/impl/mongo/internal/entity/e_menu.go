@@ -0,0 +1,158 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetMenuCollection 获取菜单集合(菜单动作及资源内嵌在菜单文档中)
+func GetMenuCollection(db *mongoplus.DB) *mongo.Collection {
+	return getCollection(db, "menu")
+}
+
+// SchemaMenu 菜单对象
+type SchemaMenu schema.Menu
+
+// ToMenu 转换为菜单实体
+func (a SchemaMenu) ToMenu() *Menu {
+	item := &Menu{
+		RecordID:   a.RecordID,
+		Name:       a.Name,
+		Sequence:   a.Sequence,
+		Icon:       a.Icon,
+		Router:     a.Router,
+		Hidden:     a.Hidden,
+		ParentID:   a.ParentID,
+		ParentPath: a.ParentPath,
+		Creator:    a.Creator,
+		Version:    a.Version,
+		Actions:    a.ToMenuActions(),
+		Resources:  a.ToMenuResources(),
+	}
+	return item
+}
+
+// ToMenuActions 转换为菜单动作列表
+func (a SchemaMenu) ToMenuActions() []*MenuAction {
+	list := make([]*MenuAction, len(a.Actions))
+	for i, item := range a.Actions {
+		list[i] = &MenuAction{
+			Code: item.Code,
+			Name: item.Name,
+		}
+	}
+	return list
+}
+
+// ToMenuResources 转换为菜单资源列表
+func (a SchemaMenu) ToMenuResources() []*MenuResource {
+	list := make([]*MenuResource, len(a.Resources))
+	for i, item := range a.Resources {
+		list[i] = &MenuResource{
+			Code:   item.Code,
+			Name:   item.Name,
+			Method: item.Method,
+			Path:   item.Path,
+		}
+	}
+	return list
+}
+
+// Menu 菜单实体
+type Menu struct {
+	Model      `bson:",inline"`
+	RecordID   string          `bson:"record_id"`   // 记录内码
+	Name       string          `bson:"name"`        // 菜单名称
+	Sequence   int             `bson:"sequence"`    // 排序值
+	Icon       string          `bson:"icon"`        // 菜单图标
+	Router     string          `bson:"router"`      // 访问路由
+	Hidden     int             `bson:"hidden"`      // 隐藏菜单(0:不隐藏 1:隐藏)
+	ParentID   string          `bson:"parent_id"`   // 父级内码
+	ParentPath string          `bson:"parent_path"` // 父级路径
+	Creator    string          `bson:"creator"`     // 创建人
+	Version    int             `bson:"version"`     // 版本号(每次更新递增)
+	Actions    []*MenuAction   `bson:"actions"`     // 动作列表
+	Resources  []*MenuResource `bson:"resources"`   // 资源列表
+}
+
+func (a Menu) String() string {
+	return toString(a)
+}
+
+// CollectionName 集合名
+func (a Menu) CollectionName() string {
+	return a.Model.CollectionName("menu")
+}
+
+// ToSchemaMenu 转换为菜单对象
+func (a Menu) ToSchemaMenu() *schema.Menu {
+	item := &schema.Menu{
+		RecordID:   a.RecordID,
+		Name:       a.Name,
+		Sequence:   a.Sequence,
+		Icon:       a.Icon,
+		Router:     a.Router,
+		Hidden:     a.Hidden,
+		ParentID:   a.ParentID,
+		ParentPath: a.ParentPath,
+		Creator:    a.Creator,
+		Version:    a.Version,
+		CreatedAt:  a.CreatedAt,
+		DeletedAt:  a.DeletedAt,
+	}
+	return item
+}
+
+// ToSchemaMenuActions 转换为菜单动作对象列表
+func (a Menu) ToSchemaMenuActions() []*schema.MenuAction {
+	list := make([]*schema.MenuAction, len(a.Actions))
+	for i, item := range a.Actions {
+		list[i] = &schema.MenuAction{
+			Code: item.Code,
+			Name: item.Name,
+		}
+	}
+	return list
+}
+
+// ToSchemaMenuResources 转换为菜单资源对象列表
+func (a Menu) ToSchemaMenuResources() []*schema.MenuResource {
+	list := make([]*schema.MenuResource, len(a.Resources))
+	for i, item := range a.Resources {
+		list[i] = &schema.MenuResource{
+			Code:   item.Code,
+			Name:   item.Name,
+			Method: item.Method,
+			Path:   item.Path,
+		}
+	}
+	return list
+}
+
+// Menus 菜单实体列表
+type Menus []*Menu
+
+// ToSchemaMenus 转换为菜单对象列表
+func (a Menus) ToSchemaMenus() []*schema.Menu {
+	list := make([]*schema.Menu, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaMenu()
+	}
+	return list
+}
+
+// MenuAction 菜单动作实体
+type MenuAction struct {
+	Code string `bson:"code"` // 动作编号
+	Name string `bson:"name"` // 动作名称
+}
+
+// MenuResource 菜单资源实体
+type MenuResource struct {
+	Code   string `bson:"code"`   // 资源编号
+	Name   string `bson:"name"`   // 资源名称
+	Method string `bson:"method"` // 请求方式
+	Path   string `bson:"path"`   // 请求路径
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetRoleCollection 获取角色集合(角色菜单关联内嵌在角色文档中)
+func GetRoleCollection(db *mongoplus.DB) *mongo.Collection {
+	return getCollection(db, "role")
+}
+
+// SchemaRole 角色对象
+type SchemaRole schema.Role
+
+// ToRole 转换为角色实体
+func (a SchemaRole) ToRole() *Role {
+	item := &Role{
+		RecordID: a.RecordID,
+		Name:     a.Name,
+		Sequence: a.Sequence,
+		Memo:     a.Memo,
+		Creator:  a.Creator,
+		Version:  a.Version,
+		Menus:    a.ToRoleMenus(),
+
+		AllowedCIDRs: a.AllowedCIDRs,
+	}
+	return item
+}
+
+// ToRoleMenus 转换为角色菜单实体列表
+func (a SchemaRole) ToRoleMenus() []*RoleMenu {
+	list := make([]*RoleMenu, len(a.Menus))
+	for i, item := range a.Menus {
+		list[i] = &RoleMenu{
+			MenuID:    item.MenuID,
+			Actions:   item.Actions,
+			Resources: item.Resources,
+		}
+	}
+	return list
+}
+
+// Role 角色实体
+type Role struct {
+	Model    `bson:",inline"`
+	RecordID string      `bson:"record_id"` // 记录内码
+	Name     string      `bson:"name"`      // 角色名称
+	Sequence int         `bson:"sequence"`  // 排序值
+	Memo     string      `bson:"memo"`      // 备注
+	Creator  string      `bson:"creator"`   // 创建者
+	Version  int         `bson:"version"`   // 版本号(每次更新递增)
+	Menus    []*RoleMenu `bson:"menus"`     // 菜单权限
+
+	AllowedCIDRs []string `bson:"allowed_cidrs"` // 允许访问的IP地址范围
+}
+
+func (a Role) String() string {
+	return toString(a)
+}
+
+// CollectionName 集合名
+func (a Role) CollectionName() string {
+	return a.Model.CollectionName("role")
+}
+
+// ToSchemaRole 转换为角色对象
+func (a Role) ToSchemaRole() *schema.Role {
+	item := &schema.Role{
+		RecordID:     a.RecordID,
+		Name:         a.Name,
+		Sequence:     a.Sequence,
+		Memo:         a.Memo,
+		AllowedCIDRs: a.AllowedCIDRs,
+		Creator:      a.Creator,
+		Version:      a.Version,
+		CreatedAt:    a.CreatedAt,
+		DeletedAt:    a.DeletedAt,
+	}
+	return item
+}
+
+// ToSchemaRoleMenus 转换为角色菜单对象列表
+func (a Role) ToSchemaRoleMenus() []*schema.RoleMenu {
+	list := make([]*schema.RoleMenu, len(a.Menus))
+	for i, item := range a.Menus {
+		list[i] = &schema.RoleMenu{
+			MenuID:    item.MenuID,
+			Actions:   item.Actions,
+			Resources: item.Resources,
+		}
+	}
+	return list
+}
+
+// Roles 角色实体列表
+type Roles []*Role
+
+// ToSchemaRoles 转换为角色对象列表
+func (a Roles) ToSchemaRoles() []*schema.Role {
+	list := make([]*schema.Role, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaRole()
+	}
+	return list
+}
+
+// RoleMenu 角色菜单关联实体
+type RoleMenu struct {
+	MenuID    string   `bson:"menu_id"`   // 菜单内码
+	Actions   []string `bson:"actions"`   // 动作权限列表
+	Resources []string `bson:"resources"` // 资源权限列表
+}
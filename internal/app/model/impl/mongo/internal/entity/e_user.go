@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetUserCollection 获取用户集合(用户角色关联内嵌在用户文档中)
+func GetUserCollection(db *mongoplus.DB) *mongo.Collection {
+	return getCollection(db, "user")
+}
+
+// SchemaUser 用户对象
+type SchemaUser schema.User
+
+// ToUser 转换为用户实体
+func (a SchemaUser) ToUser() *User {
+	item := &User{
+		RecordID: a.RecordID,
+		UserName: a.UserName,
+		RealName: a.RealName,
+		Password: a.Password,
+		Status:   a.Status,
+		Creator:  a.Creator,
+		Version:  a.Version,
+		Email:    a.Email,
+		Phone:    a.Phone,
+		Roles:    a.ToUserRoles(),
+
+		AllowedCIDRs: a.AllowedCIDRs,
+	}
+	return item
+}
+
+// ToUserRoles 转换为用户角色关联列表
+func (a SchemaUser) ToUserRoles() []*UserRole {
+	list := make([]*UserRole, len(a.Roles))
+	for i, item := range a.Roles {
+		list[i] = &UserRole{
+			RoleID: item.RoleID,
+		}
+	}
+	return list
+}
+
+// User 用户实体
+type User struct {
+	Model    `bson:",inline"`
+	RecordID string      `bson:"record_id"` // 记录内码
+	UserName string      `bson:"user_name"` // 用户名
+	RealName string      `bson:"real_name"` // 真实姓名
+	Password string      `bson:"password"`  // 密码(sha1(md5(明文))加密)
+	Email    string      `bson:"email"`     // 邮箱
+	Phone    string      `bson:"phone"`     // 手机号
+	Status   int         `bson:"status"`    // 状态(1:启用 2:停用)
+	Creator  string      `bson:"creator"`   // 创建者
+	Version  int         `bson:"version"`   // 版本号(每次更新递增)
+	Roles    []*UserRole `bson:"roles"`     // 角色授权
+
+	AllowedCIDRs []string `bson:"allowed_cidrs"` // 允许访问的IP地址范围
+}
+
+func (a User) String() string {
+	return toString(a)
+}
+
+// CollectionName 集合名
+func (a User) CollectionName() string {
+	return a.Model.CollectionName("user")
+}
+
+// ToSchemaUser 转换为用户对象
+func (a User) ToSchemaUser() *schema.User {
+	item := &schema.User{
+		RecordID:     a.RecordID,
+		UserName:     a.UserName,
+		RealName:     a.RealName,
+		Password:     a.Password,
+		Status:       a.Status,
+		Creator:      a.Creator,
+		Version:      a.Version,
+		Email:        a.Email,
+		Phone:        a.Phone,
+		AllowedCIDRs: a.AllowedCIDRs,
+		CreatedAt:    a.CreatedAt,
+		DeletedAt:    a.DeletedAt,
+	}
+	return item
+}
+
+// ToSchemaUserRoles 转换为用户角色对象列表
+func (a User) ToSchemaUserRoles() []*schema.UserRole {
+	list := make([]*schema.UserRole, len(a.Roles))
+	for i, item := range a.Roles {
+		list[i] = &schema.UserRole{
+			RoleID: item.RoleID,
+		}
+	}
+	return list
+}
+
+// Users 用户实体列表
+type Users []*User
+
+// ToSchemaUsers 转换为用户对象列表
+func (a Users) ToSchemaUsers() []*schema.User {
+	list := make([]*schema.User, len(a))
+	for i, item := range a {
+		list[i] = item.ToSchemaUser()
+	}
+	return list
+}
+
+// UserRole 用户角色关联实体
+type UserRole struct {
+	RoleID string `bson:"role_id"` // 角色内码
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// 集合名前缀
+var collectionPrefix string
+
+// SetCollectionPrefix 设定集合名前缀
+func SetCollectionPrefix(prefix string) {
+	collectionPrefix = prefix
+}
+
+// GetCollectionPrefix 获取集合名前缀
+func GetCollectionPrefix() string {
+	return collectionPrefix
+}
+
+// Model base model
+type Model struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	DeletedAt *time.Time         `bson:"deleted_at"`
+}
+
+// CollectionName collection name
+func (Model) CollectionName(name string) string {
+	return GetCollectionPrefix() + name
+}
+
+// NewModel 创建新增数据的base model
+func NewModel() Model {
+	now := time.Now()
+	return Model{
+		ID:        primitive.NewObjectID(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func toString(v interface{}) string {
+	return util.JSONMarshalToString(v)
+}
+
+func getCollection(db *mongoplus.DB, name string) *mongo.Collection {
+	return db.Collection(Model{}.CollectionName(name))
+}
+
+// GetContext 获取存储操作使用的上下文(事务中使用事务会话)
+func GetContext(ctx context.Context) context.Context {
+	trans, ok := icontext.FromTrans(ctx)
+	if ok {
+		sess, ok := trans.(mongo.Session)
+		if ok {
+			return mongo.NewSessionContext(ctx, sess)
+		}
+	}
+	return ctx
+}
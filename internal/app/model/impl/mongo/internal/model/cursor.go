@@ -0,0 +1,242 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CursorKey 游标分页的排序键(排序值相同时按_id排序)
+type CursorKey struct {
+	Field string // 排序字段(为空时仅按_id排序)
+	Desc  bool   // 是否降序
+}
+
+// 游标数据(编码后对调用方不透明)
+type cursor struct {
+	Field    string             `json:"c,omitempty"` // 排序字段
+	Desc     bool               `json:"d,omitempty"` // 是否降序
+	Value    interface{}        `json:"v,omitempty"` // 排序值
+	Time     bool               `json:"t,omitempty"` // 排序值是否是时间
+	ID       primitive.ObjectID `json:"i"`           // 文档ID
+	Backward bool               `json:"b,omitempty"` // 是否查询上一页
+}
+
+func encodeCursor(c *cursor) (string, error) {
+	buf, err := util.JSONMarshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeCursor(s string, key CursorKey) (*cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	var c cursor
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	// 排序条件变化后游标失效
+	if c.Field != key.Field || c.Desc != key.Desc || c.ID.IsZero() {
+		return nil, errors.ErrInvalidCursor
+	}
+
+	switch v := c.Value.(type) {
+	case json.Number:
+		if iv, err := v.Int64(); err == nil {
+			c.Value = iv
+		} else if fv, err := v.Float64(); err == nil {
+			c.Value = fv
+		}
+	case string:
+		if c.Time {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, errors.ErrInvalidCursor
+			}
+			c.Value = t
+		}
+	}
+
+	return &c, nil
+}
+
+// 获取游标分页的排序键(游标分页仅支持指定一个排序字段)
+func getCursorKey(spec *schema.QuerySpec, fields QueryFields, defaultKey CursorKey) (CursorKey, error) {
+	if spec == nil || len(spec.Sorts) == 0 {
+		return defaultKey, nil
+	} else if len(spec.Sorts) > 1 {
+		return defaultKey, errors.ErrInvalidCursor
+	}
+
+	field := spec.Sorts[0].Field
+	if _, ok := fields[field]; !ok {
+		return defaultKey, errors.ErrInvalidQueryField
+	}
+	return CursorKey{Field: field, Desc: spec.Sorts[0].Desc}, nil
+}
+
+// 根据数据项创建游标(从实体编码后的文档中读取排序值)
+func newCursor(key CursorKey, item reflect.Value, backward bool) (string, error) {
+	c := &cursor{
+		Field:    key.Field,
+		Desc:     key.Desc,
+		Backward: backward,
+	}
+
+	doc, err := bson.Marshal(item.Interface())
+	if err != nil {
+		return "", err
+	}
+	raw := bson.Raw(doc)
+
+	if v, ok := raw.Lookup("_id").ObjectIDOK(); ok {
+		c.ID = v
+	}
+
+	if key.Field != "" {
+		v := raw.Lookup(key.Field)
+		switch v.Type {
+		case bsontype.Int32:
+			c.Value = int64(v.Int32())
+		case bsontype.Int64:
+			c.Value = v.Int64()
+		case bsontype.Double:
+			c.Value = v.Double()
+		case bsontype.String:
+			c.Value = v.StringValue()
+		case bsontype.DateTime:
+			c.Value = v.Time().Format(time.RFC3339Nano)
+			c.Time = true
+		}
+	}
+
+	return encodeCursor(c)
+}
+
+// WrapCursorQuery 包装游标分页查询(按排序键进行键集分页，不使用skip)
+// 查询规格中指定排序字段时使用该字段作为排序键，否则使用defaultKey
+func WrapCursorQuery(ctx context.Context, db *mongoplus.DB, coll *mongo.Collection, filter bson.D, cp *schema.CursorParam, spec *schema.QuerySpec, fields QueryFields, defaultKey CursorKey, out interface{}) (*schema.PaginationResult, error) {
+	key, err := getCursorKey(spec, fields, defaultKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *cursor
+	if cp.Cursor != "" {
+		c, err = decodeCursor(cp.Cursor, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pr := &schema.PaginationResult{
+		Cursor: &schema.CursorResult{
+			Limit: cp.Limit,
+		},
+	}
+
+	switch cp.Total {
+	case schema.CursorTotalExact:
+		var count int64
+		count, err = coll.CountDocuments(ctx, filter)
+		pr.Total = int(count)
+		pr.Cursor.Counted = true
+	case schema.CursorTotalEstimate:
+		pr.Total, err = db.EstimateCount(ctx, coll)
+		pr.Cursor.Counted = true
+		pr.Cursor.Estimated = true
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	backward := c != nil && c.Backward
+	desc := key.Desc != backward
+	op, dir := "$gt", 1
+	if desc {
+		op, dir = "$lt", -1
+	}
+
+	if c != nil {
+		var cond bson.D
+		if key.Field == "" {
+			cond = bson.D{{Key: "_id", Value: bson.M{op: c.ID}}}
+		} else {
+			cond = bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: key.Field, Value: bson.M{op: c.Value}}},
+				bson.D{{Key: key.Field, Value: c.Value}, {Key: "_id", Value: bson.M{op: c.ID}}},
+			}}}
+		}
+		filter = bson.D{{Key: "$and", Value: bson.A{filter, cond}}}
+	}
+
+	sort := bson.D{{Key: "_id", Value: dir}}
+	if key.Field != "" {
+		sort = append(bson.D{{Key: key.Field, Value: dir}}, sort...)
+	}
+
+	// 多查询一条数据，用于判断是否还有数据
+	opts := options.Find().SetSort(sort).SetLimit(int64(cp.Limit + 1))
+	err = db.Find(ctx, coll, filter, opts, out)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	list := reflect.ValueOf(out).Elem()
+	hasMore := list.Len() > cp.Limit
+	if hasMore {
+		list.Set(list.Slice(0, cp.Limit))
+	}
+
+	n := list.Len()
+	if n == 0 {
+		return pr, nil
+	}
+
+	// 查询上一页时按相反的顺序查询，需要还原顺序
+	if backward {
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			vi, vj := list.Index(i).Interface(), list.Index(j).Interface()
+			list.Index(i).Set(reflect.ValueOf(vj))
+			list.Index(j).Set(reflect.ValueOf(vi))
+		}
+	}
+
+	if (!backward && hasMore) || backward {
+		pr.Cursor.Next, err = newCursor(key, list.Index(n-1), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if (backward && hasMore) || (!backward && c != nil) {
+		pr.Cursor.Prev, err = newCursor(key, list.Index(0), true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pr, nil
+}
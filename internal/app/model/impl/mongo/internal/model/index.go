@@ -0,0 +1,63 @@
+package model
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func newIndex(unique bool, keys ...string) mongo.IndexModel {
+	var d bson.D
+	for _, key := range keys {
+		d = append(d, bson.E{Key: key, Value: 1})
+	}
+
+	m := mongo.IndexModel{Keys: d}
+	if unique {
+		m.Options = options.Index().SetUnique(true)
+	}
+	return m
+}
+
+// CreateIndexes 创建集合索引(索引已存在时忽略，同时会创建不存在的集合)
+func CreateIndexes(ctx context.Context, db *mongoplus.DB) error {
+	indexes := map[*mongo.Collection][]mongo.IndexModel{
+		entity.GetDemoCollection(db): {
+			newIndex(true, "record_id"),
+			newIndex(false, "code"),
+			newIndex(false, "deleted_at"),
+		},
+		entity.GetUserCollection(db): {
+			newIndex(true, "record_id"),
+			newIndex(false, "user_name"),
+			newIndex(false, "roles.role_id"),
+			newIndex(false, "deleted_at"),
+		},
+		entity.GetRoleCollection(db): {
+			newIndex(true, "record_id"),
+			newIndex(false, "sequence"),
+			newIndex(false, "deleted_at"),
+		},
+		entity.GetMenuCollection(db): {
+			newIndex(true, "record_id"),
+			newIndex(false, "sequence"),
+			newIndex(false, "parent_id"),
+			newIndex(false, "parent_path"),
+			newIndex(false, "deleted_at"),
+		},
+	}
+
+	for coll, models := range indexes {
+		_, err := coll.Indexes().CreateMany(ctx, models)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
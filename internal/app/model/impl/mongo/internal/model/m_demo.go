@@ -0,0 +1,183 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewDemo 创建demo存储实例
+func NewDemo(db *mongoplus.DB) *Demo {
+	return &Demo{db}
+}
+
+// Demo demo存储
+type Demo struct {
+	db *mongoplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的demo字段
+var demoQueryFields = QueryFields{
+	"record_id":  FieldString,
+	"code":       FieldString,
+	"name":       FieldString,
+	"memo":       FieldString,
+	"status":     FieldInt,
+	"creator":    FieldString,
+	"version":    FieldInt,
+	"created_at": FieldTime,
+}
+
+func (a *Demo) getQueryOption(opts ...schema.DemoQueryOptions) schema.DemoQueryOptions {
+	var opt schema.DemoQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *Demo) Query(ctx context.Context, params schema.DemoQueryParam, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
+	ctx = entity.GetContext(ctx)
+	filter := bson.D{notDeleted()}
+	if v := params.Code; v != "" {
+		filter = append(filter, bson.E{Key: "code", Value: v})
+	}
+	if v := params.LikeCode; v != "" {
+		filter = append(filter, bson.E{Key: "code", Value: likeValue(v)})
+	}
+	if v := params.LikeName; v != "" {
+		filter = append(filter, bson.E{Key: "name", Value: likeValue(v)})
+	}
+	if v := params.Status; v > 0 {
+		filter = append(filter, bson.E{Key: "status", Value: v})
+	}
+	opt := a.getQueryOption(opts...)
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, demoQueryFields, bson.D{{Key: "_id", Value: -1}})
+	if err != nil {
+		return nil, err
+	}
+
+	coll := entity.GetDemoCollection(a.db)
+	var list entity.Demos
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, coll, filter, cp, opt.QuerySpec, demoQueryFields, CursorKey{Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, coll, filter, sort, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	qr := &schema.DemoQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaDemos(),
+	}
+	return qr, nil
+}
+
+// Get 查询指定数据
+func (a *Demo) Get(ctx context.Context, recordID string, opts ...schema.DemoQueryOptions) (*schema.Demo, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, notDeleted()}
+	var item entity.Demo
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetDemoCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaDemo(), nil
+}
+
+// Create 创建数据
+func (a *Demo) Create(ctx context.Context, item schema.Demo) error {
+	sitem := entity.SchemaDemo(item)
+	sitem.Version = 1
+	eitem := sitem.ToDemo()
+	eitem.Model = entity.NewModel()
+	_, err := entity.GetDemoCollection(a.db).InsertOne(entity.GetContext(ctx), eitem)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *Demo) Update(ctx context.Context, recordID string, item schema.Demo) error {
+	filter := bson.D{{Key: "record_id", Value: recordID}, {Key: "version", Value: item.Version}, notDeleted()}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "code", Value: item.Code},
+		{Key: "name", Value: item.Name},
+		{Key: "memo", Value: item.Memo},
+		{Key: "status", Value: item.Status},
+		{Key: "version", Value: item.Version + 1},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+	return checkConflict(entity.GetDemoCollection(a.db).UpdateOne(entity.GetContext(ctx), filter, update))
+}
+
+// Delete 删除数据
+func (a *Demo) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, entity.GetDemoCollection(a.db), recordID)
+}
+
+// UpdateStatus 更新状态
+func (a *Demo) UpdateStatus(ctx context.Context, recordID string, status int) error {
+	return updateFields(ctx, entity.GetDemoCollection(a.db), recordID, bson.D{{Key: "status", Value: status}})
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Demo) QueryDeleted(ctx context.Context, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Demos
+	pr, err := WrapPageQuery(entity.GetContext(ctx), a.db, entity.GetDemoCollection(a.db), bson.D{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.DemoQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaDemos(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Demo) GetDeleted(ctx context.Context, recordID string) (*schema.Demo, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, isDeleted()}
+	var item entity.Demo
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetDemoCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaDemo(), nil
+}
+
+// Restore 恢复已删除的数据
+func (a *Demo) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, entity.GetDemoCollection(a.db), recordID)
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Demo) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, entity.GetDemoCollection(a.db), recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Demo) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, entity.GetDemoCollection(a.db), deletedAt)
+}
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewMenu 创建菜单存储实例
+func NewMenu(db *mongoplus.DB) *Menu {
+	return &Menu{db}
+}
+
+// Menu 菜单存储
+type Menu struct {
+	db *mongoplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的菜单字段
+var menuQueryFields = QueryFields{
+	"record_id":   FieldString,
+	"name":        FieldString,
+	"sequence":    FieldInt,
+	"icon":        FieldString,
+	"router":      FieldString,
+	"hidden":      FieldInt,
+	"parent_id":   FieldString,
+	"parent_path": FieldString,
+	"creator":     FieldString,
+	"version":     FieldInt,
+	"created_at":  FieldTime,
+}
+
+func (a *Menu) getQueryOption(opts ...schema.MenuQueryOptions) schema.MenuQueryOptions {
+	var opt schema.MenuQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *Menu) Query(ctx context.Context, params schema.MenuQueryParam, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+	ctx = entity.GetContext(ctx)
+	filter := bson.D{notDeleted()}
+	if v := params.RecordIDs; len(v) > 0 {
+		filter = append(filter, bson.E{Key: "record_id", Value: bson.M{"$in": v}})
+	}
+	if v := params.LikeName; v != "" {
+		filter = append(filter, bson.E{Key: "name", Value: likeValue(v)})
+	}
+	if v := params.ParentID; v != nil {
+		filter = append(filter, bson.E{Key: "parent_id", Value: *v})
+	}
+	if v := params.PrefixParentPath; v != "" {
+		filter = append(filter, bson.E{Key: "parent_path", Value: prefixValue(v)})
+	}
+	if v := params.Hidden; v != nil {
+		filter = append(filter, bson.E{Key: "hidden", Value: *v})
+	}
+	opt := a.getQueryOption(opts...)
+	defaultSort := bson.D{{Key: "sequence", Value: -1}, {Key: "_id", Value: -1}}
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, menuQueryFields, defaultSort)
+	if err != nil {
+		return nil, err
+	}
+
+	coll := entity.GetMenuCollection(a.db)
+	var list entity.Menus
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, coll, filter, cp, opt.QuerySpec, menuQueryFields, CursorKey{Field: "sequence", Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, coll, filter, sort, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	qr := &schema.MenuQueryResult{
+		PageResult: pr,
+		Data:       a.toSchemaMenus(list, opts...),
+	}
+	return qr, nil
+}
+
+// 转换为菜单对象列表(动作及资源内嵌在菜单文档中，按需填充)
+func (a *Menu) toSchemaMenus(list entity.Menus, opts ...schema.MenuQueryOptions) []*schema.Menu {
+	opt := a.getQueryOption(opts...)
+
+	items := list.ToSchemaMenus()
+	for i, item := range list {
+		if opt.IncludeActions && len(item.Actions) > 0 {
+			items[i].Actions = item.ToSchemaMenuActions()
+		}
+		if opt.IncludeResources && len(item.Resources) > 0 {
+			items[i].Resources = item.ToSchemaMenuResources()
+		}
+	}
+	return items
+}
+
+// Get 查询指定数据
+func (a *Menu) Get(ctx context.Context, recordID string, opts ...schema.MenuQueryOptions) (*schema.Menu, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, notDeleted()}
+	var item entity.Menu
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetMenuCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return a.toSchemaMenus(entity.Menus{&item}, opts...)[0], nil
+}
+
+// Create 创建数据(动作及资源内嵌在菜单文档中，单文档写入不需要事务)
+func (a *Menu) Create(ctx context.Context, item schema.Menu) error {
+	sitem := entity.SchemaMenu(item)
+	sitem.Version = 1
+	eitem := sitem.ToMenu()
+	eitem.Model = entity.NewModel()
+	_, err := entity.GetMenuCollection(a.db).InsertOne(entity.GetContext(ctx), eitem)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *Menu) Update(ctx context.Context, recordID string, item schema.Menu) error {
+	sitem := entity.SchemaMenu(item)
+	filter := bson.D{{Key: "record_id", Value: recordID}, {Key: "version", Value: item.Version}, notDeleted()}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: item.Name},
+		{Key: "sequence", Value: item.Sequence},
+		{Key: "icon", Value: item.Icon},
+		{Key: "router", Value: item.Router},
+		{Key: "hidden", Value: item.Hidden},
+		{Key: "parent_id", Value: item.ParentID},
+		{Key: "parent_path", Value: item.ParentPath},
+		{Key: "actions", Value: sitem.ToMenuActions()},
+		{Key: "resources", Value: sitem.ToMenuResources()},
+		{Key: "version", Value: item.Version + 1},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+	return checkConflict(entity.GetMenuCollection(a.db).UpdateOne(entity.GetContext(ctx), filter, update))
+}
+
+// UpdateParentPath 更新父级路径
+func (a *Menu) UpdateParentPath(ctx context.Context, recordID, parentPath string) error {
+	return updateFields(ctx, entity.GetMenuCollection(a.db), recordID, bson.D{{Key: "parent_path", Value: parentPath}})
+}
+
+// Delete 删除数据
+func (a *Menu) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, entity.GetMenuCollection(a.db), recordID)
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Menu) QueryDeleted(ctx context.Context, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Menus
+	pr, err := WrapPageQuery(entity.GetContext(ctx), a.db, entity.GetMenuCollection(a.db), bson.D{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.MenuQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaMenus(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Menu) GetDeleted(ctx context.Context, recordID string) (*schema.Menu, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, isDeleted()}
+	var item entity.Menu
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetMenuCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaMenu(), nil
+}
+
+// Restore 恢复已删除的数据(包括内嵌的菜单动作及资源数据)
+func (a *Menu) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, entity.GetMenuCollection(a.db), recordID)
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Menu) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, entity.GetMenuCollection(a.db), recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Menu) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, entity.GetMenuCollection(a.db), deletedAt)
+}
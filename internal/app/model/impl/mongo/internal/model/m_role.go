@@ -0,0 +1,220 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewRole 创建角色存储实例
+func NewRole(db *mongoplus.DB) *Role {
+	return &Role{db}
+}
+
+// Role 角色存储
+type Role struct {
+	db *mongoplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的角色字段
+var roleQueryFields = QueryFields{
+	"record_id":  FieldString,
+	"name":       FieldString,
+	"sequence":   FieldInt,
+	"memo":       FieldString,
+	"creator":    FieldString,
+	"version":    FieldInt,
+	"created_at": FieldTime,
+}
+
+func (a *Role) getQueryOption(opts ...schema.RoleQueryOptions) schema.RoleQueryOptions {
+	var opt schema.RoleQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *Role) Query(ctx context.Context, params schema.RoleQueryParam, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+	ctx = entity.GetContext(ctx)
+	recordIDs := params.RecordIDs
+	if v := params.UserID; v != "" {
+		roleIDs, err := a.queryUserRoleIDs(ctx, v, recordIDs)
+		if err != nil {
+			return nil, err
+		}
+		recordIDs = roleIDs
+	}
+
+	filter := bson.D{notDeleted()}
+	if v := recordIDs; len(v) > 0 || params.UserID != "" {
+		filter = append(filter, bson.E{Key: "record_id", Value: bson.M{"$in": v}})
+	}
+	if v := params.Name; v != "" {
+		filter = append(filter, bson.E{Key: "name", Value: v})
+	}
+	if v := params.LikeName; v != "" {
+		filter = append(filter, bson.E{Key: "name", Value: likeValue(v)})
+	}
+	opt := a.getQueryOption(opts...)
+	defaultSort := bson.D{{Key: "sequence", Value: -1}, {Key: "_id", Value: -1}}
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, roleQueryFields, defaultSort)
+	if err != nil {
+		return nil, err
+	}
+
+	coll := entity.GetRoleCollection(a.db)
+	var list entity.Roles
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, coll, filter, cp, opt.QuerySpec, roleQueryFields, CursorKey{Field: "sequence", Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, coll, filter, sort, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	qr := &schema.RoleQueryResult{
+		PageResult: pr,
+		Data:       a.toSchemaRoles(list, opts...),
+	}
+	return qr, nil
+}
+
+// 查询用户授权的角色ID列表(用户角色关联内嵌在用户文档中，指定recordIDs时取交集)
+func (a *Role) queryUserRoleIDs(ctx context.Context, userID string, recordIDs []string) ([]string, error) {
+	filter := bson.D{{Key: "record_id", Value: userID}, notDeleted()}
+	var user entity.User
+	_, err := a.db.FindOne(ctx, entity.GetUserCollection(a.db), filter, &user)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	roleIDs := make([]string, 0, len(user.Roles))
+	for _, item := range user.Roles {
+		if len(recordIDs) > 0 && !inStrings(recordIDs, item.RoleID) {
+			continue
+		}
+		roleIDs = append(roleIDs, item.RoleID)
+	}
+	return roleIDs, nil
+}
+
+// 转换为角色对象列表(菜单权限内嵌在角色文档中，按需填充)
+func (a *Role) toSchemaRoles(list entity.Roles, opts ...schema.RoleQueryOptions) []*schema.Role {
+	opt := a.getQueryOption(opts...)
+
+	items := list.ToSchemaRoles()
+	if opt.IncludeMenus {
+		for i, item := range list {
+			if len(item.Menus) > 0 {
+				items[i].Menus = item.ToSchemaRoleMenus()
+			}
+		}
+	}
+	return items
+}
+
+// Get 查询指定数据
+func (a *Role) Get(ctx context.Context, recordID string, opts ...schema.RoleQueryOptions) (*schema.Role, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, notDeleted()}
+	var item entity.Role
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetRoleCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return a.toSchemaRoles(entity.Roles{&item}, opts...)[0], nil
+}
+
+// Create 创建数据(角色菜单关联内嵌在角色文档中，单文档写入不需要事务)
+func (a *Role) Create(ctx context.Context, item schema.Role) error {
+	sitem := entity.SchemaRole(item)
+	sitem.Version = 1
+	eitem := sitem.ToRole()
+	eitem.Model = entity.NewModel()
+	_, err := entity.GetRoleCollection(a.db).InsertOne(entity.GetContext(ctx), eitem)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *Role) Update(ctx context.Context, recordID string, item schema.Role) error {
+	sitem := entity.SchemaRole(item)
+	filter := bson.D{{Key: "record_id", Value: recordID}, {Key: "version", Value: item.Version}, notDeleted()}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: item.Name},
+		{Key: "sequence", Value: item.Sequence},
+		{Key: "memo", Value: item.Memo},
+		{Key: "allowed_cidrs", Value: item.AllowedCIDRs},
+		{Key: "menus", Value: sitem.ToRoleMenus()},
+		{Key: "version", Value: item.Version + 1},
+		{Key: "updated_at", Value: time.Now()},
+	}}}
+	return checkConflict(entity.GetRoleCollection(a.db).UpdateOne(entity.GetContext(ctx), filter, update))
+}
+
+// Delete 删除数据
+func (a *Role) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, entity.GetRoleCollection(a.db), recordID)
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *Role) QueryDeleted(ctx context.Context, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Roles
+	pr, err := WrapPageQuery(entity.GetContext(ctx), a.db, entity.GetRoleCollection(a.db), bson.D{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.RoleQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaRoles(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *Role) GetDeleted(ctx context.Context, recordID string) (*schema.Role, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, isDeleted()}
+	var item entity.Role
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetRoleCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaRole(), nil
+}
+
+// Restore 恢复已删除的数据(包括内嵌的角色菜单关联数据)
+func (a *Role) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, entity.GetRoleCollection(a.db), recordID)
+}
+
+// Purge 彻底删除已删除的数据
+func (a *Role) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, entity.GetRoleCollection(a.db), recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *Role) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, entity.GetRoleCollection(a.db), deletedAt)
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewTrans 创建事务管理实例
+func NewTrans(db *mongoplus.DB) *Trans {
+	return &Trans{db}
+}
+
+// Trans 事务管理(基于会话，数据库不支持事务时会话中的操作直接执行)
+type Trans struct {
+	db *mongoplus.DB
+}
+
+// Begin 开启事务
+func (a *Trans) Begin(ctx context.Context) (interface{}, error) {
+	sess, err := a.db.Client().StartSession()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if a.db.Transaction() {
+		err = sess.StartTransaction()
+		if err != nil {
+			sess.EndSession(ctx)
+			return nil, errors.WithStack(err)
+		}
+	}
+	return sess, nil
+}
+
+// Commit 提交事务
+func (a *Trans) Commit(ctx context.Context, trans interface{}) error {
+	sess, ok := trans.(mongo.Session)
+	if !ok {
+		return errors.New("unknow trans")
+	}
+	defer sess.EndSession(ctx)
+
+	if a.db.Transaction() {
+		err := sess.CommitTransaction(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// Rollback 回滚事务
+func (a *Trans) Rollback(ctx context.Context, trans interface{}) error {
+	sess, ok := trans.(mongo.Session)
+	if !ok {
+		return errors.New("unknow trans")
+	}
+	defer sess.EndSession(ctx)
+
+	if a.db.Transaction() {
+		err := sess.AbortTransaction(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
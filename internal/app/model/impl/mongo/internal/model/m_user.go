@@ -0,0 +1,219 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewUser 创建用户存储实例
+func NewUser(db *mongoplus.DB) *User {
+	return &User{db}
+}
+
+// User 用户存储
+type User struct {
+	db *mongoplus.DB
+}
+
+// 允许通过通用查询规格进行排序、字段选择及过滤的用户字段(不允许包含敏感字段)
+var userQueryFields = QueryFields{
+	"record_id":  FieldString,
+	"user_name":  FieldString,
+	"real_name":  FieldString,
+	"phone":      FieldString,
+	"email":      FieldString,
+	"status":     FieldInt,
+	"creator":    FieldString,
+	"version":    FieldInt,
+	"created_at": FieldTime,
+}
+
+func (a *User) getQueryOption(opts ...schema.UserQueryOptions) schema.UserQueryOptions {
+	var opt schema.UserQueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return opt
+}
+
+// Query 查询数据
+func (a *User) Query(ctx context.Context, params schema.UserQueryParam, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	ctx = entity.GetContext(ctx)
+	filter := bson.D{notDeleted()}
+	if v := params.RecordIDs; len(v) > 0 {
+		filter = append(filter, bson.E{Key: "record_id", Value: bson.M{"$in": v}})
+	}
+	if v := params.UserName; v != "" {
+		filter = append(filter, bson.E{Key: "user_name", Value: v})
+	}
+	if v := params.LikeUserName; v != "" {
+		filter = append(filter, bson.E{Key: "user_name", Value: likeValue(v)})
+	}
+	if v := params.LikeRealName; v != "" {
+		filter = append(filter, bson.E{Key: "real_name", Value: likeValue(v)})
+	}
+	if v := params.Status; v > 0 {
+		filter = append(filter, bson.E{Key: "status", Value: v})
+	}
+	if v := params.RoleIDs; len(v) > 0 {
+		filter = append(filter, bson.E{Key: "roles.role_id", Value: bson.M{"$in": v}})
+	}
+	opt := a.getQueryOption(opts...)
+	filter, sort, err := WrapQuerySpec(filter, opt.QuerySpec, userQueryFields, bson.D{{Key: "_id", Value: -1}})
+	if err != nil {
+		return nil, err
+	}
+
+	coll := entity.GetUserCollection(a.db)
+	var list entity.Users
+	var pr *schema.PaginationResult
+	if cp := opt.CursorParam; cp != nil {
+		pr, err = WrapCursorQuery(ctx, a.db, coll, filter, cp, opt.QuerySpec, userQueryFields, CursorKey{Desc: true}, &list)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pr, err = WrapPageQuery(ctx, a.db, coll, filter, sort, opt.PageParam, &list)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	qr := &schema.UserQueryResult{
+		PageResult: pr,
+		Data:       a.toSchemaUsers(list, opts...),
+	}
+	return qr, nil
+}
+
+// 转换为用户对象列表(角色授权内嵌在用户文档中，按需填充)
+func (a *User) toSchemaUsers(list entity.Users, opts ...schema.UserQueryOptions) []*schema.User {
+	opt := a.getQueryOption(opts...)
+
+	items := list.ToSchemaUsers()
+	if opt.IncludeRoles {
+		for i, item := range list {
+			if len(item.Roles) > 0 {
+				items[i].Roles = item.ToSchemaUserRoles()
+			}
+		}
+	}
+	return items
+}
+
+// Get 查询指定数据
+func (a *User) Get(ctx context.Context, recordID string, opts ...schema.UserQueryOptions) (*schema.User, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, notDeleted()}
+	var item entity.User
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetUserCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return a.toSchemaUsers(entity.Users{&item}, opts...)[0], nil
+}
+
+// Create 创建数据(用户角色关联内嵌在用户文档中，单文档写入不需要事务)
+func (a *User) Create(ctx context.Context, item schema.User) error {
+	sitem := entity.SchemaUser(item)
+	sitem.Version = 1
+	eitem := sitem.ToUser()
+	eitem.Model = entity.NewModel()
+	_, err := entity.GetUserCollection(a.db).InsertOne(entity.GetContext(ctx), eitem)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Update 更新数据(仅当版本号与item.Version一致时更新，否则返回ErrResourceConflict)
+func (a *User) Update(ctx context.Context, recordID string, item schema.User) error {
+	sitem := entity.SchemaUser(item)
+	fields := bson.D{
+		{Key: "user_name", Value: item.UserName},
+		{Key: "real_name", Value: item.RealName},
+		{Key: "email", Value: item.Email},
+		{Key: "phone", Value: item.Phone},
+		{Key: "status", Value: item.Status},
+		{Key: "allowed_cidrs", Value: item.AllowedCIDRs},
+		{Key: "roles", Value: sitem.ToUserRoles()},
+		{Key: "version", Value: item.Version + 1},
+		{Key: "updated_at", Value: time.Now()},
+	}
+	if item.Password != "" {
+		fields = append(fields, bson.E{Key: "password", Value: item.Password})
+	}
+
+	filter := bson.D{{Key: "record_id", Value: recordID}, {Key: "version", Value: item.Version}, notDeleted()}
+	update := bson.D{{Key: "$set", Value: fields}}
+	return checkConflict(entity.GetUserCollection(a.db).UpdateOne(entity.GetContext(ctx), filter, update))
+}
+
+// Delete 删除数据
+func (a *User) Delete(ctx context.Context, recordID string) error {
+	return softDelete(ctx, entity.GetUserCollection(a.db), recordID)
+}
+
+// UpdateStatus 更新状态
+func (a *User) UpdateStatus(ctx context.Context, recordID string, status int) error {
+	return updateFields(ctx, entity.GetUserCollection(a.db), recordID, bson.D{{Key: "status", Value: status}})
+}
+
+// UpdatePassword 更新密码
+func (a *User) UpdatePassword(ctx context.Context, recordID, password string) error {
+	return updateFields(ctx, entity.GetUserCollection(a.db), recordID, bson.D{{Key: "password", Value: password}})
+}
+
+// QueryDeleted 查询已删除的数据
+func (a *User) QueryDeleted(ctx context.Context, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error) {
+	opt := a.getQueryOption(opts...)
+	var list entity.Users
+	pr, err := WrapPageQuery(entity.GetContext(ctx), a.db, entity.GetUserCollection(a.db), bson.D{isDeleted()}, deletedSort(), opt.PageParam, &list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qr := &schema.UserQueryResult{
+		PageResult: pr,
+		Data:       list.ToSchemaUsers(),
+	}
+
+	return qr, nil
+}
+
+// GetDeleted 查询指定的已删除数据
+func (a *User) GetDeleted(ctx context.Context, recordID string) (*schema.User, error) {
+	filter := bson.D{{Key: "record_id", Value: recordID}, isDeleted()}
+	var item entity.User
+	ok, err := a.db.FindOne(entity.GetContext(ctx), entity.GetUserCollection(a.db), filter, &item)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !ok {
+		return nil, nil
+	}
+
+	return item.ToSchemaUser(), nil
+}
+
+// Restore 恢复已删除的数据(包括内嵌的用户角色关联数据)
+func (a *User) Restore(ctx context.Context, recordID string) error {
+	return restore(ctx, entity.GetUserCollection(a.db), recordID)
+}
+
+// Purge 彻底删除已删除的数据
+func (a *User) Purge(ctx context.Context, recordID string) error {
+	return purge(ctx, entity.GetUserCollection(a.db), recordID)
+}
+
+// PurgeBefore 彻底删除指定时间之前删除的数据
+func (a *User) PurgeBefore(ctx context.Context, deletedAt time.Time) error {
+	return purgeBefore(ctx, entity.GetUserCollection(a.db), deletedAt)
+}
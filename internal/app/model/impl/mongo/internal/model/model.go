@@ -0,0 +1,285 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExecTrans 执行事务
+func ExecTrans(ctx context.Context, db *mongoplus.DB, fn func(context.Context) error) error {
+	if _, ok := icontext.FromTrans(ctx); ok {
+		return fn(ctx)
+	}
+
+	transModel := NewTrans(db)
+	trans, err := transModel.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(icontext.NewTrans(ctx, trans))
+	if err != nil {
+		_ = transModel.Rollback(ctx, trans)
+		return err
+	}
+	return transModel.Commit(ctx, trans)
+}
+
+// 未删除数据的过滤条件
+func notDeleted() bson.E {
+	return bson.E{Key: "deleted_at", Value: nil}
+}
+
+// 已删除数据的过滤条件
+func isDeleted() bson.E {
+	return bson.E{Key: "deleted_at", Value: bson.M{"$ne": nil}}
+}
+
+// 已删除数据的排序
+func deletedSort() bson.D {
+	return bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}
+}
+
+// 模糊匹配(与数据库的LIKE查询一致，不区分大小写)
+func likeValue(v string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(v), Options: "i"}
+}
+
+// 前缀匹配(可以使用索引)
+func prefixValue(v string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(v)}
+}
+
+func inStrings(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 更新结果未匹配到数据时返回ErrResourceConflict(版本号不一致或者数据不存在)
+func checkConflict(result *mongo.UpdateResult, err error) error {
+	if err != nil {
+		return errors.WithStack(err)
+	} else if result.MatchedCount == 0 {
+		return errors.ErrResourceConflict
+	}
+	return nil
+}
+
+// WrapPageQuery 包装带有分页的查询
+func WrapPageQuery(ctx context.Context, db *mongoplus.DB, coll *mongo.Collection, filter, sort bson.D, pp *schema.PaginationParam, out interface{}) (*schema.PaginationResult, error) {
+	if pp != nil {
+		total, err := db.FindPage(ctx, coll, filter, sort, pp.PageIndex, pp.PageSize, out)
+		if err != nil {
+			return nil, err
+		}
+		return &schema.PaginationResult{
+			Total: total,
+		}, nil
+	}
+
+	err := db.Find(ctx, coll, filter, options.Find().SetSort(sort), out)
+	return nil, err
+}
+
+// 定义查询字段的值类型(过滤条件的值需要转换为字段对应的类型)
+const (
+	FieldString = iota // 字符串
+	FieldInt           // 整数
+	FieldTime          // 时间
+)
+
+// QueryFields 定义允许查询(排序、字段选择及过滤)的字段与值类型的映射
+type QueryFields map[string]int
+
+// 支持的时间格式(使用本地时区解析)
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// 将过滤条件的值转换为字段对应的类型
+func parseFieldValue(typ int, s string) (interface{}, error) {
+	switch typ {
+	case FieldInt:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.ErrInvalidQueryField
+		}
+		return v, nil
+	case FieldTime:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return nil, errors.ErrInvalidQueryField
+	}
+	return s, nil
+}
+
+func parseFieldValues(typ int, values []string) ([]interface{}, error) {
+	list := make([]interface{}, len(values))
+	for i, s := range values {
+		v, err := parseFieldValue(typ, s)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// WrapQuerySpec 包装通用查询规格(仅允许使用fields中定义的字段，否则返回ErrInvalidQueryField)
+// 返回合并后的过滤条件及排序，未指定排序字段时使用defaultSort排序
+func WrapQuerySpec(filter bson.D, spec *schema.QuerySpec, fields QueryFields, defaultSort bson.D) (bson.D, bson.D, error) {
+	if spec == nil {
+		return filter, defaultSort, nil
+	}
+
+	// 同一字段可能有多个过滤条件，使用$and合并
+	var conds bson.A
+	for _, item := range spec.Filters {
+		typ, ok := fields[item.Field]
+		if !ok || len(item.Values) == 0 {
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+
+		if item.Operator == schema.QueryOpLike {
+			conds = append(conds, bson.D{{Key: item.Field, Value: likeValue(item.Values[0])}})
+			continue
+		}
+
+		values, err := parseFieldValues(typ, item.Values)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var cond interface{}
+		switch item.Operator {
+		case schema.QueryOpEQ:
+			cond = values[0]
+		case schema.QueryOpNE:
+			cond = bson.M{"$ne": values[0]}
+		case schema.QueryOpIN:
+			cond = bson.M{"$in": values}
+		case schema.QueryOpGTE:
+			cond = bson.M{"$gte": values[0]}
+		case schema.QueryOpLTE:
+			cond = bson.M{"$lte": values[0]}
+		case schema.QueryOpBetween:
+			if len(values) != 2 {
+				return nil, nil, errors.ErrInvalidQueryField
+			}
+			cond = bson.D{{Key: "$gte", Value: values[0]}, {Key: "$lte", Value: values[1]}}
+		default:
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+		conds = append(conds, bson.D{{Key: item.Field, Value: cond}})
+	}
+	if len(conds) > 0 {
+		filter = append(filter, bson.E{Key: "$and", Value: conds})
+	}
+
+	// 选择字段仅做校验，由响应时过滤(实体转换依赖完整的文档数据)
+	for _, field := range spec.Fields {
+		if _, ok := fields[field]; !ok {
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+	}
+
+	if len(spec.Sorts) == 0 {
+		return filter, defaultSort, nil
+	}
+
+	var sort bson.D
+	for _, item := range spec.Sorts {
+		if _, ok := fields[item.Field]; !ok {
+			return nil, nil, errors.ErrInvalidQueryField
+		}
+		dir := 1
+		if item.Desc {
+			dir = -1
+		}
+		sort = append(sort, bson.E{Key: item.Field, Value: dir})
+	}
+	// 保证排序结果稳定
+	sort = append(sort, bson.E{Key: "_id", Value: -1})
+	return filter, sort, nil
+}
+
+// 更新未删除数据的指定字段(同时递增版本号)
+func updateFields(ctx context.Context, coll *mongo.Collection, recordID string, fields bson.D) error {
+	filter := bson.D{{Key: "record_id", Value: recordID}, notDeleted()}
+	update := bson.D{
+		{Key: "$set", Value: append(fields, bson.E{Key: "updated_at", Value: time.Now()})},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+	_, err := coll.UpdateOne(entity.GetContext(ctx), filter, update)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 删除数据(标记删除时间，关联数据内嵌在文档中一同删除)
+func softDelete(ctx context.Context, coll *mongo.Collection, recordID string) error {
+	filter := bson.D{{Key: "record_id", Value: recordID}, notDeleted()}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: time.Now()}}}}
+	_, err := coll.UpdateOne(entity.GetContext(ctx), filter, update)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 恢复已删除的数据(关联数据内嵌在文档中一同恢复)
+func restore(ctx context.Context, coll *mongo.Collection, recordID string) error {
+	filter := bson.D{{Key: "record_id", Value: recordID}, isDeleted()}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: nil}, {Key: "updated_at", Value: time.Now()}}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+	_, err := coll.UpdateOne(entity.GetContext(ctx), filter, update)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 彻底删除已删除的数据
+func purge(ctx context.Context, coll *mongo.Collection, recordID string) error {
+	filter := bson.D{{Key: "record_id", Value: recordID}, isDeleted()}
+	_, err := coll.DeleteOne(entity.GetContext(ctx), filter)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// 彻底删除指定时间之前删除的数据
+func purgeBefore(ctx context.Context, coll *mongo.Collection, deletedAt time.Time) error {
+	filter := bson.D{{Key: "deleted_at", Value: bson.M{"$lt": deletedAt}}}
+	_, err := coll.DeleteMany(entity.GetContext(ctx), filter)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
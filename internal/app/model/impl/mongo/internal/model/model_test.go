@@ -0,0 +1,270 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// 使用mock部署(不需要启动mongo服务)，按顺序返回预设的响应并记录发送的命令
+func newMockTest(t *testing.T) *mtest.T {
+	return mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+}
+
+func toDoc(t *testing.T, v interface{}) bson.D {
+	buf, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(buf, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func lookup(t *testing.T, raw bson.Raw, key ...string) bson.RawValue {
+	v, err := raw.LookupErr(key...)
+	if err != nil {
+		t.Fatalf("lookup %v: %s", key, err.Error())
+	}
+	return v
+}
+
+func TestWrapQuerySpec(t *testing.T) {
+	spec := &schema.QuerySpec{
+		Sorts: []*schema.QuerySort{{Field: "status", Desc: true}},
+		Filters: []*schema.QueryFilter{
+			{Field: "status", Operator: schema.QueryOpIN, Values: []string{"1", "2"}},
+			{Field: "name", Operator: schema.QueryOpLike, Values: []string{"a.b"}},
+			{Field: "created_at", Operator: schema.QueryOpGTE, Values: []string{"2019-01-02"}},
+		},
+	}
+
+	filter, sort, err := WrapQuerySpec(bson.D{notDeleted()}, spec, demoQueryFields, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conds := filter[1].Value.(bson.A)
+	if filter[1].Key != "$and" || len(conds) != 3 {
+		t.Fatalf("unexpected filter: %v", filter)
+	}
+	if v := conds[0].(bson.D)[0].Value.(bson.M)["$in"].([]interface{}); v[0] != 1 || v[1] != 2 {
+		t.Errorf("status values not converted: %v", v)
+	}
+	if v := conds[1].(bson.D)[0].Value.(primitive.Regex); v.Pattern != `a\.b` {
+		t.Errorf("like pattern not escaped: %v", v)
+	}
+	if v := conds[2].(bson.D)[0].Value.(bson.M)["$gte"].(time.Time); !v.Equal(time.Date(2019, 1, 2, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("time value not parsed: %v", v)
+	}
+	if len(sort) != 2 || sort[0].Key != "status" || sort[0].Value != -1 || sort[1].Key != "_id" {
+		t.Errorf("unexpected sort: %v", sort)
+	}
+
+	for _, item := range []*schema.QueryFilter{
+		{Field: "password", Operator: schema.QueryOpEQ, Values: []string{"x"}},
+		{Field: "status", Operator: schema.QueryOpEQ, Values: []string{"x"}},
+		{Field: "status", Operator: schema.QueryOpBetween, Values: []string{"1"}},
+	} {
+		_, _, err := WrapQuerySpec(nil, &schema.QuerySpec{Filters: []*schema.QueryFilter{item}}, demoQueryFields, nil)
+		if err != errors.ErrInvalidQueryField {
+			t.Errorf("filter %v: expected ErrInvalidQueryField, got %v", item, err)
+		}
+	}
+}
+
+func TestUserQuery(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("page", func(mt *mtest.T) {
+		user := entity.SchemaUser(schema.User{
+			RecordID: "u1",
+			UserName: "admin",
+			Status:   1,
+			Roles:    schema.UserRoles{{RoleID: "r1"}, {RoleID: "r2"}},
+		}).ToUser()
+		user.Model = entity.NewModel()
+
+		ns := mt.DB.Name() + ".user"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, toDoc(mt.T, user)),
+		)
+
+		result, err := NewUser(mongoplus.Wrap(mt.DB)).Query(context.Background(), schema.UserQueryParam{
+			RoleIDs: []string{"r1"},
+		}, schema.UserQueryOptions{
+			PageParam:    &schema.PaginationParam{PageIndex: 2, PageSize: 2},
+			IncludeRoles: true,
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		if result.PageResult.Total != 3 || len(result.Data) != 1 {
+			mt.Fatalf("unexpected result: %v", result)
+		}
+		if item := result.Data[0]; item.UserName != "admin" || len(item.Roles) != 2 || item.Roles[1].RoleID != "r2" {
+			mt.Errorf("unexpected user: %v", item)
+		}
+
+		mt.GetStartedEvent() // count
+		cmd := mt.GetStartedEvent().Command
+		if v := lookup(mt.T, cmd, "filter", "roles.role_id", "$in").Array().Index(0).Value().StringValue(); v != "r1" {
+			mt.Errorf("unexpected role filter: %v", v)
+		}
+		if v := lookup(mt.T, cmd, "filter", "deleted_at"); v.Type != bson.TypeNull {
+			mt.Errorf("deleted data not excluded: %v", v)
+		}
+		if lookup(mt.T, cmd, "skip").AsInt64() != 2 || lookup(mt.T, cmd, "limit").AsInt64() != 2 {
+			mt.Errorf("unexpected pagination: %v", cmd)
+		}
+	})
+}
+
+func TestMenuQueryPrefixParentPath(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("prefix", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".menu", mtest.FirstBatch))
+
+		_, err := NewMenu(mongoplus.Wrap(mt.DB)).Query(context.Background(), schema.MenuQueryParam{
+			PrefixParentPath: "a.b/c",
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		pattern, _ := lookup(mt.T, cmd, "filter", "parent_path").Regex()
+		if pattern != `^a\.b/c` {
+			mt.Errorf("unexpected parent path pattern: %s", pattern)
+		}
+		if lookup(mt.T, cmd, "sort", "sequence").AsInt64() != -1 {
+			mt.Errorf("unexpected sort: %v", cmd)
+		}
+	})
+}
+
+func TestMenuUpdateConflict(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("conflict", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+
+		err := NewMenu(mongoplus.Wrap(mt.DB)).Update(context.Background(), "m1", schema.Menu{Name: "menu", Version: 3})
+		if err != errors.ErrResourceConflict {
+			mt.Fatalf("expected ErrResourceConflict, got %v", err)
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		update := lookup(mt.T, cmd, "updates").Array().Index(0).Value().Document()
+		if lookup(mt.T, update, "q", "version").AsInt64() != 3 || lookup(mt.T, update, "u", "$set", "version").AsInt64() != 4 {
+			mt.Errorf("unexpected update: %v", update)
+		}
+	})
+}
+
+func TestDemoCursorQuery(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("cursor", func(mt *mtest.T) {
+		var docs []bson.D
+		for _, code := range []string{"c", "b", "a"} {
+			item := entity.SchemaDemo(schema.Demo{RecordID: code, Code: code}).ToDemo()
+			item.Model = entity.NewModel()
+			docs = append(docs, toDoc(mt.T, item))
+		}
+
+		ns := mt.DB.Name() + ".demo"
+		m := NewDemo(mongoplus.Wrap(mt.DB))
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, docs[:2]...))
+		result, err := m.Query(context.Background(), schema.DemoQueryParam{}, schema.DemoQueryOptions{
+			CursorParam: &schema.CursorParam{Limit: 1},
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(result.Data) != 1 || result.PageResult.Cursor.Next == "" || result.PageResult.Cursor.Prev != "" {
+			mt.Fatalf("unexpected first page: %v", result.PageResult.Cursor)
+		}
+		if lookup(mt.T, mt.GetStartedEvent().Command, "limit").AsInt64() != 2 {
+			mt.Error("expected one more row to be queried")
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, docs[1]))
+		result, err = m.Query(context.Background(), schema.DemoQueryParam{}, schema.DemoQueryOptions{
+			CursorParam: &schema.CursorParam{Limit: 1, Cursor: result.PageResult.Cursor.Next},
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(result.Data) != 1 || result.Data[0].Code != "b" || result.PageResult.Cursor.Next != "" || result.PageResult.Cursor.Prev == "" {
+			mt.Fatalf("unexpected second page: %v", result.PageResult.Cursor)
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		first := docs[0].Map()["_id"].(primitive.ObjectID)
+		if v := lookup(mt.T, cmd, "filter", "$and", "1", "_id", "$lt").ObjectID(); v != first {
+			mt.Errorf("unexpected keyset filter: %v", cmd)
+		}
+
+		_, err = m.Query(context.Background(), schema.DemoQueryParam{}, schema.DemoQueryOptions{
+			CursorParam: &schema.CursorParam{Limit: 1, Cursor: result.PageResult.Cursor.Prev},
+			QuerySpec:   &schema.QuerySpec{Sorts: []*schema.QuerySort{{Field: "code"}}},
+		})
+		if err != errors.ErrInvalidCursor {
+			mt.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestExecTrans(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("commit", func(mt *mtest.T) {
+		db := mongoplus.Wrap(mt.DB)
+		db.SetTransaction(true)
+
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		err := ExecTrans(context.Background(), db, func(ctx context.Context) error {
+			m := NewMenu(db)
+			err := m.Create(ctx, schema.Menu{RecordID: "m1", Name: "menu"})
+			if err != nil {
+				return err
+			}
+			return m.UpdateParentPath(ctx, "m2", "m1")
+		})
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		var txnNumbers []int64
+		for _, name := range []string{"insert", "update", "commitTransaction"} {
+			evt := mt.GetStartedEvent()
+			if evt == nil || evt.CommandName != name {
+				mt.Fatalf("expected %s command, got %v", name, evt)
+			}
+			txnNumbers = append(txnNumbers, lookup(mt.T, evt.Command, "txnNumber").Int64())
+		}
+		if txnNumbers[0] != txnNumbers[1] || txnNumbers[1] != txnNumbers[2] {
+			mt.Errorf("commands not executed in the same transaction: %v", txnNumbers)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/model"
+	icache "github.com/wanhello/iris-admin/internal/app/model/impl/cache"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/entity"
+	imodel "github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/model"
+	"github.com/wanhello/iris-admin/pkg/cache"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.uber.org/dig"
+)
+
+// SetCollectionPrefix 设定集合名前缀
+func SetCollectionPrefix(prefix string) {
+	entity.SetCollectionPrefix(prefix)
+}
+
+// CreateIndexes 创建集合索引
+func CreateIndexes(ctx context.Context, db *mongoplus.DB) error {
+	return imodel.CreateIndexes(ctx, db)
+}
+
+// Inject 注入mongo实现(用户、角色及菜单存储使用查询缓存装饰，需要先注入*cache.Cache)
+// 关联数据(用户角色、角色菜单、菜单动作及资源)内嵌在主文档中；
+// 代码生成器目前仅生成gorm实现，新增模块使用mongo存储时需要在此处手动注入
+// 使用方式：
+//
+//	container := dig.New()
+//	Inject(container)
+//	container.Invoke(func(foo IDemo) {
+//	})
+func Inject(container *dig.Container) error {
+	container.Provide(imodel.NewTrans, dig.As(new(model.ITrans)))
+	container.Provide(imodel.NewDemo, dig.As(new(model.IDemo)))
+	container.Provide(func(db *mongoplus.DB, c *cache.Cache) model.IMenu {
+		return icache.WrapMenu(imodel.NewMenu(db), c)
+	})
+	container.Provide(func(db *mongoplus.DB, c *cache.Cache) model.IRole {
+		return icache.WrapRole(imodel.NewRole(db), c)
+	})
+	container.Provide(func(db *mongoplus.DB, c *cache.Cache) model.IUser {
+		return icache.WrapUser(imodel.NewUser(db), c)
+	})
+	return nil
+}
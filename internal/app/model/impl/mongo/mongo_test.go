@@ -0,0 +1,179 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	imodel "github.com/wanhello/iris-admin/internal/app/model/impl/mongo/internal/model"
+	"github.com/wanhello/iris-admin/internal/app/model/modeltest"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 测试使用的mongo连接串
+// 优先使用环境变量MONGODB_TEST_URI指定的服务，否则使用MONGOD_BIN或PATH中的mongod启动临时的单节点副本集(支持事务)；
+// 本地找不到mongod时跳过测试，CI环境(设置了环境变量CI)中或mongod启动失败时测试失败
+var testURI string
+
+// 无法使用mongo的原因
+var testErr error
+
+var errMongodNotFound = errors.New("mongod not found (set MONGODB_TEST_URI or MONGOD_BIN)")
+
+// 每个测试使用独立的数据库
+var testDBSeq int64
+
+func TestMain(m *testing.M) {
+	var stop func()
+	testURI = os.Getenv("MONGODB_TEST_URI")
+	if testURI == "" {
+		testURI, stop, testErr = startMongod()
+	}
+
+	code := m.Run()
+	if stop != nil {
+		stop()
+	}
+	os.Exit(code)
+}
+
+// 启动临时的单节点副本集并初始化
+func startMongod() (string, func(), error) {
+	bin := os.Getenv("MONGOD_BIN")
+	if bin == "" {
+		p, err := exec.LookPath("mongod")
+		if err != nil {
+			return "", nil, errMongodNotFound
+		}
+		bin = p
+	}
+
+	dir, err := ioutil.TempDir("", "mongotest")
+	if err != nil {
+		return "", nil, err
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	cmd := exec.Command(bin, "--replSet", "rs0", "--bind_ip", "127.0.0.1",
+		"--port", strconv.Itoa(port), "--dbpath", dir, "--quiet")
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	stop := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		os.RemoveAll(dir)
+	}
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	if err := initiateReplicaSet(addr); err != nil {
+		stop()
+		return "", nil, err
+	}
+	return fmt.Sprintf("mongodb://%s/?replicaSet=rs0", addr), stop, nil
+}
+
+// 初始化副本集并等待成为主节点
+func initiateReplicaSet(addr string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://"+addr+"/?directConnection=true"))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+
+	admin := client.Database("admin")
+	for {
+		err = admin.RunCommand(ctx, bson.D{{Key: "replSetInitiate", Value: bson.D{
+			{Key: "_id", Value: "rs0"},
+			{Key: "members", Value: bson.A{bson.D{{Key: "_id", Value: 0}, {Key: "host", Value: addr}}}},
+		}}}).Err()
+		if err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	for {
+		var result struct {
+			IsMaster bool `bson:"ismaster"`
+		}
+		if err := admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result); err == nil && result.IsMaster {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("replica set not ready: %v", ctx.Err())
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func newTestStore(t *testing.T) *modeltest.Store {
+	db, err := mongoplus.New(&mongoplus.Config{
+		URI:      testURI,
+		Database: fmt.Sprintf("modeltest_%d_%d", os.Getpid(), atomic.AddInt64(&testDBSeq, 1)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Drop(context.Background())
+		db.Close()
+	})
+
+	if err := CreateIndexes(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	return &modeltest.Store{
+		Trans: imodel.NewTrans(db),
+		Demo:  imodel.NewDemo(db),
+		User:  imodel.NewUser(db),
+		Role:  imodel.NewRole(db),
+		Menu:  imodel.NewMenu(db),
+	}
+}
+
+func TestConformance(t *testing.T) {
+	if testURI == "" {
+		if testErr != errMongodNotFound || os.Getenv("CI") != "" {
+			t.Fatalf("mongo is not available: %s", testErr.Error())
+		}
+		t.Skipf("mongo is not available: %s", testErr.Error())
+	}
+	modeltest.Run(t, newTestStore)
+}
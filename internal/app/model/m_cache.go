@@ -0,0 +1,18 @@
+package model
+
+import (
+	"context"
+)
+
+// 定义查询缓存的命名空间
+const (
+	CacheUser = "user" // 用户(包含用户角色)
+	CacheRole = "role" // 角色(包含角色菜单，按用户查询角色依赖用户角色)
+	CacheMenu = "menu" // 菜单(包含菜单动作及资源)
+)
+
+// ICache 查询缓存管理接口
+type ICache interface {
+	// 使指定命名空间的缓存失效
+	Invalidate(ctx context.Context, namespaces ...string) error
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -20,4 +21,14 @@ type IDemo interface {
 	Delete(ctx context.Context, recordID string) error
 	// 更新状态
 	UpdateStatus(ctx context.Context, recordID string, status int) error
+	// 查询已删除的数据
+	QueryDeleted(ctx context.Context, opts ...schema.DemoQueryOptions) (*schema.DemoQueryResult, error)
+	// 查询指定的已删除数据
+	GetDeleted(ctx context.Context, recordID string) (*schema.Demo, error)
+	// 恢复已删除的数据
+	Restore(ctx context.Context, recordID string) error
+	// 彻底删除已删除的数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
 }
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -20,4 +21,14 @@ type IMenu interface {
 	UpdateParentPath(ctx context.Context, recordID, parentPath string) error
 	// 删除数据
 	Delete(ctx context.Context, recordID string) error
-}
\ No newline at end of file
+	// 查询已删除的数据
+	QueryDeleted(ctx context.Context, opts ...schema.MenuQueryOptions) (*schema.MenuQueryResult, error)
+	// 查询指定的已删除数据
+	GetDeleted(ctx context.Context, recordID string) (*schema.Menu, error)
+	// 恢复已删除的数据
+	Restore(ctx context.Context, recordID string) error
+	// 彻底删除已删除的数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -18,4 +19,14 @@ type IRole interface {
 	Update(ctx context.Context, recordID string, item schema.Role) error
 	// 删除数据
 	Delete(ctx context.Context, recordID string) error
+	// 查询已删除的数据
+	QueryDeleted(ctx context.Context, opts ...schema.RoleQueryOptions) (*schema.RoleQueryResult, error)
+	// 查询指定的已删除数据
+	GetDeleted(ctx context.Context, recordID string) (*schema.Role, error)
+	// 恢复已删除的数据
+	Restore(ctx context.Context, recordID string) error
+	// 彻底删除已删除的数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
 }
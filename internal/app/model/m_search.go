@@ -0,0 +1,17 @@
+package model
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// ISearch 全文检索接口
+type ISearch interface {
+	// 索引文档(文档已存在时更新)
+	Index(ctx context.Context, docs ...*schema.SearchDocument) error
+	// 删除文档
+	Delete(ctx context.Context, typ string, recordIDs ...string) error
+	// 检索数据
+	Search(ctx context.Context, params schema.SearchParam) (schema.SearchResult, error)
+}
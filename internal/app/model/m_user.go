@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/schema"
 )
@@ -22,5 +23,14 @@ type IUser interface {
 	UpdateStatus(ctx context.Context, recordID string, status int) error
 	// 更新密码
 	UpdatePassword(ctx context.Context, recordID, password string) error
+	// 查询已删除的数据
+	QueryDeleted(ctx context.Context, opts ...schema.UserQueryOptions) (*schema.UserQueryResult, error)
+	// 查询指定的已删除数据
+	GetDeleted(ctx context.Context, recordID string) (*schema.User, error)
+	// 恢复已删除的数据
+	Restore(ctx context.Context, recordID string) error
+	// 彻底删除已删除的数据
+	Purge(ctx context.Context, recordID string) error
+	// 彻底删除指定时间之前删除的数据
+	PurgeBefore(ctx context.Context, deletedAt time.Time) error
 }
-
@@ -0,0 +1,326 @@
+package modeltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+func demoIDs(list []*schema.Demo) []string {
+	ids := make([]string, len(list))
+	for i, item := range list {
+		ids[i] = item.RecordID
+	}
+	return ids
+}
+
+func createDemos(t *testing.T, s *Store, items ...schema.Demo) {
+	t.Helper()
+	for _, item := range items {
+		check(t, s.Demo.Create(context.Background(), item))
+	}
+}
+
+func testDemoCRUD(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createDemos(t, s, schema.Demo{RecordID: "d1", Code: "A001", Name: "Alpha", Memo: "memo", Status: 1, Creator: "root"})
+
+	item, err := s.Demo.Get(ctx, "d1")
+	check(t, err)
+	if item == nil || item.Code != "A001" || item.Name != "Alpha" || item.Memo != "memo" || item.Status != 1 || item.Creator != "root" || item.Version != 1 {
+		t.Fatalf("unexpected demo: %+v", item)
+	}
+	if item.CreatedAt.IsZero() || item.DeletedAt != nil {
+		t.Fatalf("unexpected demo times: %+v", item)
+	}
+
+	item, err = s.Demo.Get(ctx, "none")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for missing demo, got %+v", item)
+	}
+
+	update := schema.Demo{Code: "A002", Name: "Beta", Memo: "new memo", Status: 2, Version: 5}
+	expectError(t, errors.ErrResourceConflict, s.Demo.Update(ctx, "d1", update))
+	expectError(t, errors.ErrResourceConflict, s.Demo.Update(ctx, "none", update))
+
+	update.Version = 1
+	check(t, s.Demo.Update(ctx, "d1", update))
+	item, err = s.Demo.Get(ctx, "d1")
+	check(t, err)
+	if item.Code != "A002" || item.Name != "Beta" || item.Memo != "new memo" || item.Status != 2 || item.Creator != "root" || item.Version != 2 {
+		t.Fatalf("unexpected updated demo: %+v", item)
+	}
+
+	// 旧版本号的更新不再生效
+	expectError(t, errors.ErrResourceConflict, s.Demo.Update(ctx, "d1", update))
+
+	check(t, s.Demo.UpdateStatus(ctx, "d1", 1))
+	item, err = s.Demo.Get(ctx, "d1")
+	check(t, err)
+	if item.Status != 1 || item.Version != 3 {
+		t.Fatalf("unexpected demo status: %+v", item)
+	}
+
+	check(t, s.Demo.Delete(ctx, "d1"))
+	item, err = s.Demo.Get(ctx, "d1")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for deleted demo, got %+v", item)
+	}
+}
+
+func testDemoQuery(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createDemos(t, s,
+		schema.Demo{RecordID: "d1", Code: "A001", Name: "Alpha", Status: 1},
+		schema.Demo{RecordID: "d2", Code: "A002", Name: "Beta", Status: 1},
+		schema.Demo{RecordID: "d3", Code: "B001", Name: "Alphabet", Status: 2},
+	)
+
+	tests := []struct {
+		name     string
+		params   schema.DemoQueryParam
+		expected []string
+	}{
+		{"all", schema.DemoQueryParam{}, []string{"d3", "d2", "d1"}},
+		{"code", schema.DemoQueryParam{Code: "A002"}, []string{"d2"}},
+		{"like code", schema.DemoQueryParam{LikeCode: "a00"}, []string{"d2", "d1"}},
+		{"like name", schema.DemoQueryParam{LikeName: "alpha"}, []string{"d3", "d1"}},
+		{"status", schema.DemoQueryParam{Status: 1}, []string{"d2", "d1"}},
+		{"combined", schema.DemoQueryParam{LikeName: "alpha", Status: 2}, []string{"d3"}},
+		// 查询值中的通配符按普通字符匹配
+		{"like wildcard", schema.DemoQueryParam{LikeName: "%"}, nil},
+		{"like underscore", schema.DemoQueryParam{LikeCode: "A_0"}, nil},
+	}
+	for _, item := range tests {
+		result, err := s.Demo.Query(ctx, item.params)
+		check(t, err)
+		expectIDs(t, item.name, true, demoIDs(result.Data), item.expected...)
+	}
+
+	// 分页查询
+	result, err := s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		PageParam: &schema.PaginationParam{PageIndex: 1, PageSize: 2},
+	})
+	check(t, err)
+	if result.PageResult == nil || result.PageResult.Total != 3 {
+		t.Fatalf("unexpected page result: %+v", result.PageResult)
+	}
+	expectIDs(t, "page 1", true, demoIDs(result.Data), "d3", "d2")
+
+	result, err = s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		PageParam: &schema.PaginationParam{PageIndex: 2, PageSize: 2},
+	})
+	check(t, err)
+	expectIDs(t, "page 2", true, demoIDs(result.Data), "d1")
+
+	// 分页大小小于0时仅查询总数
+	result, err = s.Demo.Query(ctx, schema.DemoQueryParam{Status: 1}, schema.DemoQueryOptions{
+		PageParam: &schema.PaginationParam{PageIndex: 1, PageSize: -1},
+	})
+	check(t, err)
+	if result.PageResult.Total != 2 || len(result.Data) != 0 {
+		t.Fatalf("unexpected count result: %+v, %v", result.PageResult, demoIDs(result.Data))
+	}
+
+	// 通用查询规格
+	result, err = s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		QuerySpec: &schema.QuerySpec{
+			Sorts: []*schema.QuerySort{{Field: "code", Desc: false}},
+			Filters: []*schema.QueryFilter{
+				{Field: "status", Operator: schema.QueryOpEQ, Values: []string{"1"}},
+			},
+		},
+	})
+	check(t, err)
+	expectIDs(t, "spec eq", true, demoIDs(result.Data), "d1", "d2")
+
+	specTests := []struct {
+		name     string
+		filter   *schema.QueryFilter
+		expected []string
+	}{
+		{"spec ne", &schema.QueryFilter{Field: "status", Operator: schema.QueryOpNE, Values: []string{"1"}}, []string{"d3"}},
+		{"spec in", &schema.QueryFilter{Field: "code", Operator: schema.QueryOpIN, Values: []string{"A001", "B001"}}, []string{"d1", "d3"}},
+		{"spec like", &schema.QueryFilter{Field: "name", Operator: schema.QueryOpLike, Values: []string{"ALPHA"}}, []string{"d1", "d3"}},
+		{"spec like wildcard", &schema.QueryFilter{Field: "name", Operator: schema.QueryOpLike, Values: []string{"%"}}, nil},
+		{"spec like underscore", &schema.QueryFilter{Field: "name", Operator: schema.QueryOpLike, Values: []string{"Al_ha"}}, nil},
+		{"spec like escape", &schema.QueryFilter{Field: "name", Operator: schema.QueryOpLike, Values: []string{"a!"}}, nil},
+		{"spec gte", &schema.QueryFilter{Field: "code", Operator: schema.QueryOpGTE, Values: []string{"A002"}}, []string{"d2", "d3"}},
+		{"spec lte", &schema.QueryFilter{Field: "code", Operator: schema.QueryOpLTE, Values: []string{"A002"}}, []string{"d1", "d2"}},
+		{"spec between", &schema.QueryFilter{Field: "code", Operator: schema.QueryOpBetween, Values: []string{"A002", "B001"}}, []string{"d2", "d3"}},
+		{"spec time", &schema.QueryFilter{Field: "created_at", Operator: schema.QueryOpGTE, Values: []string{time.Now().Add(-time.Hour).Format("2006-01-02 15:04:05")}}, []string{"d1", "d2", "d3"}},
+	}
+	for _, item := range specTests {
+		result, err := s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+			QuerySpec: &schema.QuerySpec{
+				Sorts:   []*schema.QuerySort{{Field: "code"}},
+				Filters: []*schema.QueryFilter{item.filter},
+			},
+		})
+		check(t, err)
+		expectIDs(t, item.name, true, demoIDs(result.Data), item.expected...)
+	}
+
+	for _, spec := range []*schema.QuerySpec{
+		{Sorts: []*schema.QuerySort{{Field: "unknown"}}},
+		{Fields: []string{"unknown"}},
+		{Filters: []*schema.QueryFilter{{Field: "unknown", Operator: schema.QueryOpEQ, Values: []string{"1"}}}},
+	} {
+		_, err := s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{QuerySpec: spec})
+		expectError(t, errors.ErrInvalidQueryField, err)
+	}
+}
+
+func testDemoCursor(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createDemos(t, s,
+		schema.Demo{RecordID: "d1", Code: "C", Name: "demo", Status: 1},
+		schema.Demo{RecordID: "d2", Code: "A", Name: "demo", Status: 1},
+		schema.Demo{RecordID: "d3", Code: "B", Name: "demo", Status: 2},
+		schema.Demo{RecordID: "d4", Code: "B", Name: "demo", Status: 1},
+		schema.Demo{RecordID: "d5", Code: "E", Name: "demo", Status: 1},
+	)
+
+	query := func(cursor string, spec *schema.QuerySpec, total string) *schema.DemoQueryResult {
+		t.Helper()
+		result, err := s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+			CursorParam: &schema.CursorParam{Cursor: cursor, Limit: 2, Total: total},
+			QuerySpec:   spec,
+		})
+		check(t, err)
+		return result
+	}
+
+	// 默认按ID降序向后翻页
+	result := query("", nil, schema.CursorTotalExact)
+	expectIDs(t, "cursor page 1", true, demoIDs(result.Data), "d5", "d4")
+	if c := result.PageResult.Cursor; c.Next == "" || c.Prev != "" || !c.Counted || result.PageResult.Total != 5 {
+		t.Fatalf("unexpected first page cursor: %+v, total %d", c, result.PageResult.Total)
+	}
+
+	result = query(result.PageResult.Cursor.Next, nil, "")
+	expectIDs(t, "cursor page 2", true, demoIDs(result.Data), "d3", "d2")
+	page2 := result.PageResult.Cursor
+	if page2.Next == "" || page2.Prev == "" {
+		t.Fatalf("unexpected second page cursor: %+v", page2)
+	}
+
+	result = query(page2.Next, nil, "")
+	expectIDs(t, "cursor page 3", true, demoIDs(result.Data), "d1")
+	if c := result.PageResult.Cursor; c.Next != "" || c.Prev == "" {
+		t.Fatalf("unexpected last page cursor: %+v", c)
+	}
+
+	// 向前翻页
+	result = query(page2.Prev, nil, "")
+	expectIDs(t, "cursor prev page", true, demoIDs(result.Data), "d5", "d4")
+	if c := result.PageResult.Cursor; c.Prev != "" || c.Next == "" {
+		t.Fatalf("unexpected prev page cursor: %+v", c)
+	}
+
+	// 指定排序字段(排序值相同时按ID排序)
+	spec := &schema.QuerySpec{Sorts: []*schema.QuerySort{{Field: "code"}}}
+	var ids []string
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		result = query(cursor, spec, "")
+		ids = append(ids, demoIDs(result.Data)...)
+		cursor = result.PageResult.Cursor.Next
+		if cursor == "" {
+			break
+		}
+	}
+	expectIDs(t, "cursor by code", true, ids, "d2", "d3", "d4", "d1", "d5")
+
+	// 排序条件变化后游标失效
+	result = query("", nil, "")
+	_, err := s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		CursorParam: &schema.CursorParam{Cursor: result.PageResult.Cursor.Next, Limit: 2},
+		QuerySpec:   spec,
+	})
+	expectError(t, errors.ErrInvalidCursor, err)
+
+	_, err = s.Demo.Query(ctx, schema.DemoQueryParam{}, schema.DemoQueryOptions{
+		CursorParam: &schema.CursorParam{Cursor: "invalid", Limit: 2},
+	})
+	expectError(t, errors.ErrInvalidCursor, err)
+}
+
+func testDemoSoftDelete(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createDemos(t, s,
+		schema.Demo{RecordID: "d1", Code: "A001", Name: "demo", Status: 1},
+		schema.Demo{RecordID: "d2", Code: "A002", Name: "demo", Status: 1},
+		schema.Demo{RecordID: "d3", Code: "A003", Name: "demo", Status: 1},
+	)
+
+	check(t, s.Demo.Delete(ctx, "d1"))
+	tick()
+	check(t, s.Demo.Delete(ctx, "d2"))
+
+	result, err := s.Demo.Query(ctx, schema.DemoQueryParam{})
+	check(t, err)
+	expectIDs(t, "undeleted", true, demoIDs(result.Data), "d3")
+
+	result, err = s.Demo.QueryDeleted(ctx, schema.DemoQueryOptions{
+		PageParam: &schema.PaginationParam{PageIndex: 1, PageSize: 10},
+	})
+	check(t, err)
+	if result.PageResult.Total != 2 {
+		t.Fatalf("unexpected deleted total: %d", result.PageResult.Total)
+	}
+	// 按删除时间降序
+	expectIDs(t, "deleted", true, demoIDs(result.Data), "d2", "d1")
+
+	item, err := s.Demo.GetDeleted(ctx, "d1")
+	check(t, err)
+	if item == nil || item.DeletedAt == nil || item.Code != "A001" {
+		t.Fatalf("unexpected deleted demo: %+v", item)
+	}
+	item, err = s.Demo.GetDeleted(ctx, "d3")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for undeleted demo, got %+v", item)
+	}
+
+	// 已删除的数据不允许更新
+	expectError(t, errors.ErrResourceConflict, s.Demo.Update(ctx, "d2", schema.Demo{Code: "X", Name: "X", Status: 1, Version: 1}))
+
+	check(t, s.Demo.Restore(ctx, "d1"))
+	item, err = s.Demo.Get(ctx, "d1")
+	check(t, err)
+	if item == nil || item.DeletedAt != nil || item.Version != 2 {
+		t.Fatalf("unexpected restored demo: %+v", item)
+	}
+
+	// 未删除的数据不会被彻底删除
+	check(t, s.Demo.Purge(ctx, "d3"))
+	item, err = s.Demo.Get(ctx, "d3")
+	check(t, err)
+	if item == nil {
+		t.Fatal("undeleted demo purged")
+	}
+
+	check(t, s.Demo.Purge(ctx, "d2"))
+	item, err = s.Demo.GetDeleted(ctx, "d2")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("demo not purged: %+v", item)
+	}
+
+	check(t, s.Demo.Delete(ctx, "d1"))
+	tick()
+	before := time.Now()
+	tick()
+	check(t, s.Demo.Delete(ctx, "d3"))
+
+	check(t, s.Demo.PurgeBefore(ctx, before))
+	result, err = s.Demo.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "purge before", true, demoIDs(result.Data), "d3")
+}
@@ -0,0 +1,239 @@
+package modeltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+func menuIDs(list []*schema.Menu) []string {
+	ids := make([]string, len(list))
+	for i, item := range list {
+		ids[i] = item.RecordID
+	}
+	return ids
+}
+
+func menuActions(item *schema.Menu) []string {
+	list := make([]string, len(item.Actions))
+	for i, action := range item.Actions {
+		list[i] = action.Code + ":" + action.Name
+	}
+	return list
+}
+
+func menuResources(item *schema.Menu) []string {
+	list := make([]string, len(item.Resources))
+	for i, res := range item.Resources {
+		list[i] = res.Code + ":" + res.Name + ":" + res.Method + ":" + res.Path
+	}
+	return list
+}
+
+func newMenu(recordID, name string, sequence int, parentID, parentPath string) schema.Menu {
+	return schema.Menu{
+		RecordID:   recordID,
+		Name:       name,
+		Sequence:   sequence,
+		ParentID:   parentID,
+		ParentPath: parentPath,
+		Actions: schema.MenuActions{
+			{Code: "add", Name: "新增"},
+			{Code: "edit", Name: "编辑"},
+		},
+		Resources: schema.MenuResources{
+			{Code: "query", Name: "查询", Method: "GET", Path: "/api/v1/" + recordID},
+		},
+	}
+}
+
+func createMenus(t *testing.T, s *Store, items ...schema.Menu) {
+	t.Helper()
+	for _, item := range items {
+		check(t, s.Menu.Create(context.Background(), item))
+	}
+}
+
+func testMenuCRUD(t *testing.T, s *Store) {
+	ctx := context.Background()
+	menu := newMenu("m1", "system", 10, "m0", "m0")
+	menu.Icon = "setting"
+	menu.Router = "/system"
+	menu.Hidden = 1
+	menu.Creator = "root"
+	createMenus(t, s, menu)
+
+	item, err := s.Menu.Get(ctx, "m1", schema.MenuQueryOptions{IncludeActions: true, IncludeResources: true})
+	check(t, err)
+	if item == nil || item.Name != "system" || item.Sequence != 10 || item.Icon != "setting" || item.Router != "/system" ||
+		item.Hidden != 1 || item.ParentID != "m0" || item.ParentPath != "m0" || item.Creator != "root" || item.Version != 1 {
+		t.Fatalf("unexpected menu: %+v", item)
+	}
+	expectIDs(t, "menu actions", false, menuActions(item), "add:新增", "edit:编辑")
+	expectIDs(t, "menu resources", false, menuResources(item), "query:查询:GET:/api/v1/m1")
+
+	item, err = s.Menu.Get(ctx, "m1", schema.MenuQueryOptions{IncludeActions: true})
+	check(t, err)
+	if len(item.Actions) != 2 || len(item.Resources) != 0 {
+		t.Fatalf("unexpected menu associations: %+v", item)
+	}
+
+	item, err = s.Menu.Get(ctx, "none")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for missing menu, got %+v", item)
+	}
+
+	update := newMenu("", "settings", 20, "m2", "m2")
+	update.Icon = "gear"
+	update.Router = "/settings"
+	update.Hidden = 1
+	update.Actions = schema.MenuActions{{Code: "edit", Name: "修改"}, {Code: "delete", Name: "删除"}}
+	update.Resources = schema.MenuResources{{Code: "list", Name: "列表", Method: "GET", Path: "/api/v1/settings"}}
+	update.Version = 2
+	expectError(t, errors.ErrResourceConflict, s.Menu.Update(ctx, "m1", update))
+
+	update.Version = 1
+	check(t, s.Menu.Update(ctx, "m1", update))
+	item, err = s.Menu.Get(ctx, "m1", schema.MenuQueryOptions{IncludeActions: true, IncludeResources: true})
+	check(t, err)
+	if item.Name != "settings" || item.Sequence != 20 || item.Icon != "gear" || item.Router != "/settings" ||
+		item.ParentID != "m2" || item.ParentPath != "m2" || item.Creator != "root" || item.Version != 2 {
+		t.Fatalf("unexpected updated menu: %+v", item)
+	}
+	expectIDs(t, "updated actions", false, menuActions(item), "edit:修改", "delete:删除")
+	expectIDs(t, "updated resources", false, menuResources(item), "list:列表:GET:/api/v1/settings")
+
+	check(t, s.Menu.UpdateParentPath(ctx, "m1", "m2/m3"))
+	item, err = s.Menu.Get(ctx, "m1")
+	check(t, err)
+	if item.ParentPath != "m2/m3" || item.Version != 3 {
+		t.Fatalf("unexpected parent path: %+v", item)
+	}
+
+	check(t, s.Menu.Delete(ctx, "m1"))
+	item, err = s.Menu.Get(ctx, "m1")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for deleted menu, got %+v", item)
+	}
+}
+
+func testMenuQuery(t *testing.T, s *Store) {
+	ctx := context.Background()
+	hidden := newMenu("m4", "Hidden", 10, "m1", "m1")
+	hidden.Hidden = 1
+	createMenus(t, s,
+		newMenu("m1", "System", 30, "", ""),
+		newMenu("m2", "Users", 20, "m1", "m1"),
+		newMenu("m3", "Roles", 20, "m1", "m1"),
+		hidden,
+		newMenu("m5", "User Roles", 10, "m3", "m1/m3"),
+		newMenu("m6", "Other", 40, "", ""),
+	)
+
+	tests := []struct {
+		name     string
+		params   schema.MenuQueryParam
+		expected []string
+	}{
+		// 默认按排序值降序，排序值相同时按ID降序
+		{"all", schema.MenuQueryParam{}, []string{"m6", "m1", "m3", "m2", "m5", "m4"}},
+		{"record ids", schema.MenuQueryParam{RecordIDs: []string{"m2", "m5"}}, []string{"m2", "m5"}},
+		{"like name", schema.MenuQueryParam{LikeName: "user"}, []string{"m2", "m5"}},
+		{"root", schema.MenuQueryParam{ParentID: stringPtr("")}, []string{"m6", "m1"}},
+		{"parent id", schema.MenuQueryParam{ParentID: stringPtr("m1")}, []string{"m3", "m2", "m4"}},
+		{"prefix parent path", schema.MenuQueryParam{PrefixParentPath: "m1"}, []string{"m3", "m2", "m5", "m4"}},
+		{"prefix sub path", schema.MenuQueryParam{PrefixParentPath: "m1/"}, []string{"m5"}},
+		{"hidden", schema.MenuQueryParam{Hidden: intPtr(1)}, []string{"m4"}},
+		{"visible", schema.MenuQueryParam{Hidden: intPtr(0), ParentID: stringPtr("m1")}, []string{"m3", "m2"}},
+	}
+	for _, item := range tests {
+		result, err := s.Menu.Query(ctx, item.params)
+		check(t, err)
+		expectIDs(t, item.name, true, menuIDs(result.Data), item.expected...)
+	}
+
+	result, err := s.Menu.Query(ctx, schema.MenuQueryParam{ParentID: stringPtr("m1")}, schema.MenuQueryOptions{
+		PageParam:        &schema.PaginationParam{PageIndex: 1, PageSize: 2},
+		IncludeActions:   true,
+		IncludeResources: true,
+	})
+	check(t, err)
+	if result.PageResult.Total != 3 {
+		t.Fatalf("unexpected total: %d", result.PageResult.Total)
+	}
+	expectIDs(t, "page", true, menuIDs(result.Data), "m3", "m2")
+	for _, item := range result.Data {
+		if len(item.Actions) != 2 || len(item.Resources) != 1 {
+			t.Fatalf("unexpected menu associations: %+v", item)
+		}
+	}
+
+	result, err = s.Menu.Query(ctx, schema.MenuQueryParam{}, schema.MenuQueryOptions{
+		QuerySpec: &schema.QuerySpec{
+			Sorts:   []*schema.QuerySort{{Field: "name"}},
+			Filters: []*schema.QueryFilter{{Field: "sequence", Operator: schema.QueryOpBetween, Values: []string{"10", "20"}}},
+		},
+	})
+	check(t, err)
+	expectIDs(t, "spec", true, menuIDs(result.Data), "m4", "m3", "m5", "m2")
+}
+
+func testMenuSoftDelete(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createMenus(t, s,
+		newMenu("m1", "system", 1, "", ""),
+		newMenu("m2", "users", 2, "m1", "m1"),
+		newMenu("m3", "roles", 3, "m1", "m1"),
+	)
+
+	check(t, s.Menu.Delete(ctx, "m1"))
+	tick()
+	check(t, s.Menu.Delete(ctx, "m2"))
+
+	result, err := s.Menu.Query(ctx, schema.MenuQueryParam{})
+	check(t, err)
+	expectIDs(t, "undeleted", true, menuIDs(result.Data), "m3")
+
+	result, err = s.Menu.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "deleted", true, menuIDs(result.Data), "m2", "m1")
+
+	item, err := s.Menu.GetDeleted(ctx, "m1")
+	check(t, err)
+	if item == nil || item.DeletedAt == nil || item.Name != "system" {
+		t.Fatalf("unexpected deleted menu: %+v", item)
+	}
+
+	// 恢复时一同恢复动作及资源
+	check(t, s.Menu.Restore(ctx, "m1"))
+	item, err = s.Menu.Get(ctx, "m1", schema.MenuQueryOptions{IncludeActions: true, IncludeResources: true})
+	check(t, err)
+	if item == nil || item.Version != 2 {
+		t.Fatalf("unexpected restored menu: %+v", item)
+	}
+	expectIDs(t, "restored actions", false, menuActions(item), "add:新增", "edit:编辑")
+	expectIDs(t, "restored resources", false, menuResources(item), "query:查询:GET:/api/v1/m1")
+
+	check(t, s.Menu.Purge(ctx, "m2"))
+	item, err = s.Menu.GetDeleted(ctx, "m2")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("menu not purged: %+v", item)
+	}
+
+	check(t, s.Menu.Delete(ctx, "m1"))
+	tick()
+	before := time.Now()
+	tick()
+	check(t, s.Menu.Delete(ctx, "m3"))
+
+	check(t, s.Menu.PurgeBefore(ctx, before))
+	result, err = s.Menu.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "purge before", true, menuIDs(result.Data), "m3")
+}
@@ -0,0 +1,98 @@
+// Package modeltest 存储实现的一致性测试
+// 对IUser、IRole、IMenu、IDemo及ITrans的所有方法进行测试(分页、过滤、关联数据、事务回滚及软删除)，
+// 新增存储实现或者修改查询时，在实现包的测试中调用Run即可验证与其他实现的行为一致。
+package modeltest
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/model"
+)
+
+// Store 待测试的存储实现
+type Store struct {
+	Trans model.ITrans
+	Demo  model.IDemo
+	User  model.IUser
+	Role  model.IRole
+	Menu  model.IMenu
+}
+
+// Run 运行一致性测试(newStore为每个测试用例创建独立的空存储)
+func Run(t *testing.T, newStore func(t *testing.T) *Store) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s *Store)
+	}{
+		{"DemoCRUD", testDemoCRUD},
+		{"DemoQuery", testDemoQuery},
+		{"DemoCursor", testDemoCursor},
+		{"DemoSoftDelete", testDemoSoftDelete},
+		{"UserCRUD", testUserCRUD},
+		{"UserQuery", testUserQuery},
+		{"UserSoftDelete", testUserSoftDelete},
+		{"RoleCRUD", testRoleCRUD},
+		{"RoleQuery", testRoleQuery},
+		{"RoleSoftDelete", testRoleSoftDelete},
+		{"MenuCRUD", testMenuCRUD},
+		{"MenuQuery", testMenuQuery},
+		{"MenuSoftDelete", testMenuSoftDelete},
+		{"TransCommit", testTransCommit},
+		{"TransRollback", testTransRollback},
+	}
+
+	for _, item := range tests {
+		fn := item.fn
+		t.Run(item.name, func(t *testing.T) {
+			fn(t, newStore(t))
+		})
+	}
+}
+
+func check(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
+
+func expectError(t *testing.T, expected, err error) {
+	t.Helper()
+	if err != expected {
+		t.Fatalf("expected error %v, got %v", expected, err)
+	}
+}
+
+// 比较记录ID列表(ordered为false时忽略顺序)
+func expectIDs(t *testing.T, name string, ordered bool, actual []string, expected ...string) {
+	t.Helper()
+	if !ordered {
+		actual = sortedCopy(actual)
+		expected = sortedCopy(expected)
+	}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("%s: expected %v, got %v", name, expected, actual)
+	}
+}
+
+func sortedCopy(list []string) []string {
+	result := append([]string(nil), list...)
+	sort.Strings(result)
+	return result
+}
+
+// 等待一段时间，保证前后两次写入的时间不同
+func tick() {
+	time.Sleep(10 * time.Millisecond)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
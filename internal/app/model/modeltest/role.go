@@ -0,0 +1,220 @@
+package modeltest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+func roleIDs(list []*schema.Role) []string {
+	ids := make([]string, len(list))
+	for i, item := range list {
+		ids[i] = item.RecordID
+	}
+	return ids
+}
+
+// 角色菜单权限(格式：菜单ID:动作列表:资源列表)
+func roleMenus(item *schema.Role) []string {
+	list := make([]string, len(item.Menus))
+	for i, menu := range item.Menus {
+		list[i] = menu.MenuID + ":" + strings.Join(menu.Actions, ",") + ":" + strings.Join(menu.Resources, ",")
+	}
+	return list
+}
+
+func newRole(recordID, name string, sequence int, menuIDs ...string) schema.Role {
+	item := schema.Role{
+		RecordID: recordID,
+		Name:     name,
+		Sequence: sequence,
+	}
+	for _, menuID := range menuIDs {
+		item.Menus = append(item.Menus, &schema.RoleMenu{
+			MenuID:    menuID,
+			Actions:   []string{"add", "edit"},
+			Resources: []string{"query"},
+		})
+	}
+	return item
+}
+
+func createRoles(t *testing.T, s *Store, items ...schema.Role) {
+	t.Helper()
+	for _, item := range items {
+		check(t, s.Role.Create(context.Background(), item))
+	}
+}
+
+func testRoleCRUD(t *testing.T, s *Store) {
+	ctx := context.Background()
+	role := newRole("r1", "admin", 10, "m1", "m2")
+	role.Memo = "memo"
+	role.Creator = "root"
+	role.AllowedCIDRs = []string{"10.0.0.0/8"}
+	createRoles(t, s, role)
+
+	item, err := s.Role.Get(ctx, "r1", schema.RoleQueryOptions{IncludeMenus: true})
+	check(t, err)
+	if item == nil || item.Name != "admin" || item.Sequence != 10 || item.Memo != "memo" || item.Creator != "root" || item.Version != 1 {
+		t.Fatalf("unexpected role: %+v", item)
+	}
+	expectIDs(t, "role menus", false, roleMenus(item), "m1:add,edit:query", "m2:add,edit:query")
+	expectIDs(t, "role allowed cidrs", true, item.AllowedCIDRs, "10.0.0.0/8")
+
+	item, err = s.Role.Get(ctx, "r1")
+	check(t, err)
+	if len(item.Menus) != 0 {
+		t.Fatalf("menus returned without IncludeMenus: %v", roleMenus(item))
+	}
+
+	item, err = s.Role.Get(ctx, "none")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for missing role, got %+v", item)
+	}
+
+	update := newRole("", "manager", 20, "m2", "m3")
+	update.Menus[0].Actions = []string{"delete"}
+	update.Memo = "new memo"
+	update.AllowedCIDRs = []string{"172.16.0.0/12", "2001:db8::/32"}
+	update.Version = 3
+	expectError(t, errors.ErrResourceConflict, s.Role.Update(ctx, "r1", update))
+
+	update.Version = 1
+	check(t, s.Role.Update(ctx, "r1", update))
+	item, err = s.Role.Get(ctx, "r1", schema.RoleQueryOptions{IncludeMenus: true})
+	check(t, err)
+	if item.Name != "manager" || item.Sequence != 20 || item.Memo != "new memo" || item.Creator != "root" || item.Version != 2 {
+		t.Fatalf("unexpected updated role: %+v", item)
+	}
+	expectIDs(t, "updated role menus", false, roleMenus(item), "m2:delete:query", "m3:add,edit:query")
+	expectIDs(t, "updated role allowed cidrs", true, item.AllowedCIDRs, "172.16.0.0/12", "2001:db8::/32")
+
+	check(t, s.Role.Delete(ctx, "r1"))
+	item, err = s.Role.Get(ctx, "r1")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for deleted role, got %+v", item)
+	}
+}
+
+func testRoleQuery(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createRoles(t, s,
+		newRole("r1", "admin", 30, "m1"),
+		newRole("r2", "Administrator", 20, "m1", "m2"),
+		newRole("r3", "guest", 20),
+		newRole("r4", "tester", 40),
+	)
+	createUsers(t, s,
+		newUser("u1", "admin", "Admin", 1, "r1", "r3", "r9"),
+		newUser("u2", "guest", "Guest", 1),
+	)
+
+	tests := []struct {
+		name     string
+		params   schema.RoleQueryParam
+		expected []string
+	}{
+		// 默认按排序值降序，排序值相同时按ID降序
+		{"all", schema.RoleQueryParam{}, []string{"r4", "r1", "r3", "r2"}},
+		{"record ids", schema.RoleQueryParam{RecordIDs: []string{"r2", "r4"}}, []string{"r4", "r2"}},
+		{"name", schema.RoleQueryParam{Name: "admin"}, []string{"r1"}},
+		{"like name", schema.RoleQueryParam{LikeName: "ADMIN"}, []string{"r1", "r2"}},
+		{"user id", schema.RoleQueryParam{UserID: "u1"}, []string{"r1", "r3"}},
+		{"user id and record ids", schema.RoleQueryParam{UserID: "u1", RecordIDs: []string{"r3", "r4"}}, []string{"r3"}},
+		{"user without roles", schema.RoleQueryParam{UserID: "u2"}, nil},
+		{"missing user", schema.RoleQueryParam{UserID: "none"}, nil},
+	}
+	for _, item := range tests {
+		result, err := s.Role.Query(ctx, item.params)
+		check(t, err)
+		expectIDs(t, item.name, true, roleIDs(result.Data), item.expected...)
+	}
+
+	result, err := s.Role.Query(ctx, schema.RoleQueryParam{}, schema.RoleQueryOptions{
+		PageParam:    &schema.PaginationParam{PageIndex: 2, PageSize: 2},
+		IncludeMenus: true,
+	})
+	check(t, err)
+	if result.PageResult.Total != 4 {
+		t.Fatalf("unexpected total: %d", result.PageResult.Total)
+	}
+	expectIDs(t, "page", true, roleIDs(result.Data), "r3", "r2")
+	expectIDs(t, "page menus", false, roleMenus(result.Data[1]), "m1:add,edit:query", "m2:add,edit:query")
+
+	// 游标分页默认按排序值降序
+	var ids []string
+	cursor := ""
+	for i := 0; i < 4; i++ {
+		result, err = s.Role.Query(ctx, schema.RoleQueryParam{}, schema.RoleQueryOptions{
+			CursorParam: &schema.CursorParam{Cursor: cursor, Limit: 3},
+		})
+		check(t, err)
+		ids = append(ids, roleIDs(result.Data)...)
+		cursor = result.PageResult.Cursor.Next
+		if cursor == "" {
+			break
+		}
+	}
+	expectIDs(t, "cursor", true, ids, "r4", "r1", "r3", "r2")
+}
+
+func testRoleSoftDelete(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createRoles(t, s,
+		newRole("r1", "admin", 1, "m1", "m2"),
+		newRole("r2", "guest", 2, "m1"),
+		newRole("r3", "tester", 3),
+	)
+
+	check(t, s.Role.Delete(ctx, "r1"))
+	tick()
+	check(t, s.Role.Delete(ctx, "r2"))
+
+	result, err := s.Role.Query(ctx, schema.RoleQueryParam{})
+	check(t, err)
+	expectIDs(t, "undeleted", true, roleIDs(result.Data), "r3")
+
+	result, err = s.Role.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "deleted", true, roleIDs(result.Data), "r2", "r1")
+
+	item, err := s.Role.GetDeleted(ctx, "r1")
+	check(t, err)
+	if item == nil || item.DeletedAt == nil || item.Name != "admin" {
+		t.Fatalf("unexpected deleted role: %+v", item)
+	}
+
+	// 恢复时一同恢复菜单权限
+	check(t, s.Role.Restore(ctx, "r1"))
+	item, err = s.Role.Get(ctx, "r1", schema.RoleQueryOptions{IncludeMenus: true})
+	check(t, err)
+	if item == nil || item.Version != 2 {
+		t.Fatalf("unexpected restored role: %+v", item)
+	}
+	expectIDs(t, "restored menus", false, roleMenus(item), "m1:add,edit:query", "m2:add,edit:query")
+
+	check(t, s.Role.Purge(ctx, "r2"))
+	item, err = s.Role.GetDeleted(ctx, "r2")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("role not purged: %+v", item)
+	}
+
+	check(t, s.Role.Delete(ctx, "r1"))
+	tick()
+	before := time.Now()
+	tick()
+	check(t, s.Role.Delete(ctx, "r3"))
+
+	check(t, s.Role.PurgeBefore(ctx, before))
+	result, err = s.Role.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "purge before", true, roleIDs(result.Data), "r3")
+}
@@ -0,0 +1,97 @@
+package modeltest
+
+import (
+	"context"
+	"testing"
+
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+// 开启事务并在事务中执行写操作(事务中的查询需要能读取到未提交的数据)
+func writeInTrans(t *testing.T, s *Store) interface{} {
+	t.Helper()
+	trans, err := s.Trans.Begin(context.Background())
+	check(t, err)
+
+	ctx := icontext.NewTrans(context.Background(), trans)
+	check(t, s.Demo.Create(ctx, schema.Demo{RecordID: "d1", Code: "A001", Name: "demo", Status: 1}))
+	check(t, s.User.Create(ctx, newUser("u1", "admin", "Admin", 1, "r1")))
+	check(t, s.Role.Create(ctx, newRole("r1", "admin", 1, "m1")))
+	check(t, s.Menu.Create(ctx, newMenu("m1", "system", 1, "", "")))
+	check(t, s.User.UpdateStatus(ctx, "u0", 2))
+
+	item, err := s.User.Get(ctx, "u1", schema.UserQueryOptions{IncludeRoles: true})
+	check(t, err)
+	if item == nil || len(item.Roles) != 1 {
+		t.Fatalf("uncommitted user not visible in transaction: %+v", item)
+	}
+
+	result, err := s.Role.Query(ctx, schema.RoleQueryParam{UserID: "u1"}, schema.RoleQueryOptions{IncludeMenus: true})
+	check(t, err)
+	if len(result.Data) != 1 || len(result.Data[0].Menus) != 1 {
+		t.Fatalf("uncommitted role not visible in transaction: %v", roleIDs(result.Data))
+	}
+	return trans
+}
+
+func testTransCommit(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createUsers(t, s, newUser("u0", "root", "Root", 1))
+
+	trans := writeInTrans(t, s)
+	check(t, s.Trans.Commit(ctx, trans))
+
+	demo, err := s.Demo.Get(ctx, "d1")
+	check(t, err)
+	user, err := s.User.Get(ctx, "u1", schema.UserQueryOptions{IncludeRoles: true})
+	check(t, err)
+	role, err := s.Role.Get(ctx, "r1", schema.RoleQueryOptions{IncludeMenus: true})
+	check(t, err)
+	menu, err := s.Menu.Get(ctx, "m1", schema.MenuQueryOptions{IncludeActions: true, IncludeResources: true})
+	check(t, err)
+	if demo == nil || user == nil || len(user.Roles) != 1 || role == nil || len(role.Menus) != 1 ||
+		menu == nil || len(menu.Actions) != 2 || len(menu.Resources) != 1 {
+		t.Fatalf("committed data not found: %+v, %+v, %+v, %+v", demo, user, role, menu)
+	}
+
+	root, err := s.User.Get(ctx, "u0")
+	check(t, err)
+	if root.Status != 2 {
+		t.Fatalf("committed update not found: %+v", root)
+	}
+}
+
+func testTransRollback(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createUsers(t, s, newUser("u0", "root", "Root", 1))
+
+	trans := writeInTrans(t, s)
+	check(t, s.Trans.Rollback(ctx, trans))
+
+	demo, err := s.Demo.Get(ctx, "d1")
+	check(t, err)
+	user, err := s.User.Get(ctx, "u1")
+	check(t, err)
+	role, err := s.Role.Get(ctx, "r1")
+	check(t, err)
+	menu, err := s.Menu.Get(ctx, "m1")
+	check(t, err)
+	if demo != nil || user != nil || role != nil || menu != nil {
+		t.Fatalf("data not rolled back: %+v, %+v, %+v, %+v", demo, user, role, menu)
+	}
+
+	// 关联数据一同回滚
+	users, err := s.User.Query(ctx, schema.UserQueryParam{RoleIDs: []string{"r1"}})
+	check(t, err)
+	expectIDs(t, "rolled back user roles", true, userIDs(users.Data))
+
+	root, err := s.User.Get(ctx, "u0")
+	check(t, err)
+	if root.Status != 1 || root.Version != 1 {
+		t.Fatalf("update not rolled back: %+v", root)
+	}
+
+	// 回滚后存储可以继续使用
+	check(t, s.Demo.Create(ctx, schema.Demo{RecordID: "d1", Code: "A001", Name: "demo", Status: 1}))
+}
@@ -0,0 +1,234 @@
+package modeltest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+)
+
+func userIDs(list []*schema.User) []string {
+	ids := make([]string, len(list))
+	for i, item := range list {
+		ids[i] = item.RecordID
+	}
+	return ids
+}
+
+func userRoleIDs(item *schema.User) []string {
+	ids := make([]string, len(item.Roles))
+	for i, role := range item.Roles {
+		ids[i] = role.RoleID
+	}
+	return ids
+}
+
+func newUser(recordID, userName, realName string, status int, roleIDs ...string) schema.User {
+	item := schema.User{
+		RecordID: recordID,
+		UserName: userName,
+		RealName: realName,
+		Password: "pwd-" + recordID,
+		Status:   status,
+	}
+	for _, roleID := range roleIDs {
+		item.Roles = append(item.Roles, &schema.UserRole{RoleID: roleID})
+	}
+	return item
+}
+
+func createUsers(t *testing.T, s *Store, items ...schema.User) {
+	t.Helper()
+	for _, item := range items {
+		check(t, s.User.Create(context.Background(), item))
+	}
+}
+
+func testUserCRUD(t *testing.T, s *Store) {
+	ctx := context.Background()
+	user := newUser("u1", "admin", "Admin", 1, "r1", "r2")
+	user.Email = "admin@example.com"
+	user.Phone = "10086"
+	user.Creator = "root"
+	user.AllowedCIDRs = []string{"10.0.0.0/8", "192.168.1.10"}
+	createUsers(t, s, user)
+
+	item, err := s.User.Get(ctx, "u1", schema.UserQueryOptions{IncludeRoles: true})
+	check(t, err)
+	if item == nil || item.UserName != "admin" || item.RealName != "Admin" || item.Password != "pwd-u1" ||
+		item.Email != "admin@example.com" || item.Phone != "10086" || item.Status != 1 || item.Creator != "root" || item.Version != 1 {
+		t.Fatalf("unexpected user: %+v", item)
+	}
+	expectIDs(t, "user roles", false, userRoleIDs(item), "r1", "r2")
+	expectIDs(t, "user allowed cidrs", true, item.AllowedCIDRs, "10.0.0.0/8", "192.168.1.10")
+
+	item, err = s.User.Get(ctx, "u1")
+	check(t, err)
+	if len(item.Roles) != 0 {
+		t.Fatalf("roles returned without IncludeRoles: %v", userRoleIDs(item))
+	}
+
+	item, err = s.User.Get(ctx, "none")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for missing user, got %+v", item)
+	}
+
+	// 密码为空时不更新密码
+	update := newUser("", "admin2", "Admin2", 2, "r2", "r3")
+	update.Password = ""
+	update.Email = "admin2@example.com"
+	update.Phone = "10010"
+	update.Version = 2
+	expectError(t, errors.ErrResourceConflict, s.User.Update(ctx, "u1", update))
+
+	update.Version = 1
+	check(t, s.User.Update(ctx, "u1", update))
+	item, err = s.User.Get(ctx, "u1", schema.UserQueryOptions{IncludeRoles: true})
+	check(t, err)
+	if item.UserName != "admin2" || item.RealName != "Admin2" || item.Password != "pwd-u1" || item.Email != "admin2@example.com" ||
+		item.Phone != "10010" || item.Status != 2 || item.Creator != "root" || item.Version != 2 {
+		t.Fatalf("unexpected updated user: %+v", item)
+	}
+	expectIDs(t, "updated user roles", false, userRoleIDs(item), "r2", "r3")
+	if len(item.AllowedCIDRs) != 0 {
+		t.Fatalf("allowed cidrs not cleared: %v", item.AllowedCIDRs)
+	}
+
+	update.Password = "new-pwd"
+	update.Version = 2
+	check(t, s.User.Update(ctx, "u1", update))
+	item, err = s.User.Get(ctx, "u1")
+	check(t, err)
+	if item.Password != "new-pwd" || item.Version != 3 {
+		t.Fatalf("password not updated: %+v", item)
+	}
+
+	check(t, s.User.UpdateStatus(ctx, "u1", 1))
+	check(t, s.User.UpdatePassword(ctx, "u1", "pwd"))
+	item, err = s.User.Get(ctx, "u1")
+	check(t, err)
+	if item.Status != 1 || item.Password != "pwd" || item.Version != 5 {
+		t.Fatalf("unexpected user status or password: %+v", item)
+	}
+
+	check(t, s.User.Delete(ctx, "u1"))
+	item, err = s.User.Get(ctx, "u1")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("expected nil for deleted user, got %+v", item)
+	}
+}
+
+func testUserQuery(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createUsers(t, s,
+		newUser("u1", "admin", "Administrator", 1, "r1", "r2"),
+		newUser("u2", "tom", "Tom Admin", 1, "r2"),
+		newUser("u3", "jerry", "Jerry", 2, "r3"),
+		newUser("u4", "guest", "Guest", 1),
+	)
+
+	tests := []struct {
+		name     string
+		params   schema.UserQueryParam
+		expected []string
+	}{
+		{"all", schema.UserQueryParam{}, []string{"u4", "u3", "u2", "u1"}},
+		{"record ids", schema.UserQueryParam{RecordIDs: []string{"u1", "u3"}}, []string{"u3", "u1"}},
+		{"user name", schema.UserQueryParam{UserName: "tom"}, []string{"u2"}},
+		{"like user name", schema.UserQueryParam{LikeUserName: "E"}, []string{"u4", "u3"}},
+		{"like real name", schema.UserQueryParam{LikeRealName: "admin"}, []string{"u2", "u1"}},
+		{"status", schema.UserQueryParam{Status: 2}, []string{"u3"}},
+		{"role ids", schema.UserQueryParam{RoleIDs: []string{"r2", "r3"}}, []string{"u3", "u2", "u1"}},
+		{"combined", schema.UserQueryParam{RoleIDs: []string{"r2"}, LikeRealName: "tom"}, []string{"u2"}},
+	}
+	for _, item := range tests {
+		result, err := s.User.Query(ctx, item.params)
+		check(t, err)
+		expectIDs(t, item.name, true, userIDs(result.Data), item.expected...)
+	}
+
+	result, err := s.User.Query(ctx, schema.UserQueryParam{RoleIDs: []string{"r1", "r2"}}, schema.UserQueryOptions{
+		PageParam:    &schema.PaginationParam{PageIndex: 2, PageSize: 1},
+		IncludeRoles: true,
+	})
+	check(t, err)
+	if result.PageResult.Total != 2 {
+		t.Fatalf("unexpected total: %d", result.PageResult.Total)
+	}
+	expectIDs(t, "page", true, userIDs(result.Data), "u1")
+	expectIDs(t, "page roles", false, userRoleIDs(result.Data[0]), "r1", "r2")
+
+	// 不允许查询敏感字段
+	_, err = s.User.Query(ctx, schema.UserQueryParam{}, schema.UserQueryOptions{
+		QuerySpec: &schema.QuerySpec{Filters: []*schema.QueryFilter{{Field: "password", Operator: schema.QueryOpEQ, Values: []string{"pwd-u1"}}}},
+	})
+	expectError(t, errors.ErrInvalidQueryField, err)
+
+	result, err = s.User.Query(ctx, schema.UserQueryParam{}, schema.UserQueryOptions{
+		CursorParam: &schema.CursorParam{Limit: 3},
+		QuerySpec:   &schema.QuerySpec{Sorts: []*schema.QuerySort{{Field: "user_name"}}},
+	})
+	check(t, err)
+	expectIDs(t, "cursor", true, userIDs(result.Data), "u1", "u4", "u3")
+	if result.PageResult.Cursor.Next == "" {
+		t.Fatal("expected next cursor")
+	}
+}
+
+func testUserSoftDelete(t *testing.T, s *Store) {
+	ctx := context.Background()
+	createUsers(t, s,
+		newUser("u1", "admin", "Admin", 1, "r1", "r2"),
+		newUser("u2", "tom", "Tom", 1, "r1"),
+		newUser("u3", "jerry", "Jerry", 1, "r1"),
+	)
+
+	check(t, s.User.Delete(ctx, "u1"))
+	tick()
+	check(t, s.User.Delete(ctx, "u2"))
+
+	result, err := s.User.Query(ctx, schema.UserQueryParam{RoleIDs: []string{"r1"}})
+	check(t, err)
+	expectIDs(t, "undeleted", true, userIDs(result.Data), "u3")
+
+	result, err = s.User.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "deleted", true, userIDs(result.Data), "u2", "u1")
+
+	item, err := s.User.GetDeleted(ctx, "u1")
+	check(t, err)
+	if item == nil || item.DeletedAt == nil || item.UserName != "admin" {
+		t.Fatalf("unexpected deleted user: %+v", item)
+	}
+
+	// 恢复时一同恢复角色授权
+	check(t, s.User.Restore(ctx, "u1"))
+	item, err = s.User.Get(ctx, "u1", schema.UserQueryOptions{IncludeRoles: true})
+	check(t, err)
+	if item == nil || item.Version != 2 {
+		t.Fatalf("unexpected restored user: %+v", item)
+	}
+	expectIDs(t, "restored roles", false, userRoleIDs(item), "r1", "r2")
+
+	check(t, s.User.Purge(ctx, "u2"))
+	item, err = s.User.GetDeleted(ctx, "u2")
+	check(t, err)
+	if item != nil {
+		t.Fatalf("user not purged: %+v", item)
+	}
+
+	check(t, s.User.Delete(ctx, "u1"))
+	tick()
+	before := time.Now()
+	tick()
+	check(t, s.User.Delete(ctx, "u3"))
+
+	check(t, s.User.PurgeBefore(ctx, before))
+	result, err = s.User.QueryDeleted(ctx)
+	check(t, err)
+	expectIDs(t, "purge before", true, userIDs(result.Data), "u3")
+}
@@ -1,8 +1,18 @@
 package app
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
+	"github.com/wanhello/iris-admin/pkg/cache"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/logger"
+
 	"github.com/google/gops/agent"
+	"go.uber.org/dig"
 )
 
 // InitMonitor 初始化服务监控
@@ -16,3 +26,61 @@ func InitMonitor() error {
 	return nil
 }
 
+// InitMetrics 初始化prometheus指标服务(使用独立的监听地址，不经过/api路由的认证及权限校验)
+// 启用查询缓存时同时导出缓存的命中、未命中及失效次数
+func InitMetrics(ctx context.Context, container *dig.Container) (func(), error) {
+	cfg := config.GetGlobalConfig()
+	if !cfg.Metrics.Enable {
+		return nil, nil
+	}
+
+	if cfg.Store == "gorm" {
+		err := container.Invoke(func(db *gormplus.DB) error {
+			return metrics.RegisterGorm(db)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Cache.Enable {
+		err := container.Invoke(func(c *cache.Cache) error {
+			return metrics.RegisterCache(c)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	path := cfg.Metrics.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, metrics.Handler())
+
+	addr := cfg.Metrics.Addr
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		logger.Printf(ctx, "指标服务开始启动，地址监听在：[%s]", addr)
+		err := srv.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			logger.Errorf(ctx, err.Error())
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.Errorf(ctx, err.Error())
+		}
+	}, nil
+}
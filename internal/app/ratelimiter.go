@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/pkg/logger"
+	"github.com/wanhello/iris-admin/pkg/ratelimit"
+	"github.com/wanhello/iris-admin/pkg/redisplus"
+
+	"go.uber.org/dig"
+)
+
+// InitRateLimiter 初始化请求频率限制(未启用时注入nil，中间件不限制请求)
+func InitRateLimiter(container *dig.Container) (func(), error) {
+	cfg := config.GetGlobalConfig().RateLimiter
+	if !cfg.Enable {
+		return nil, container.Provide(func() *ratelimit.Limiter {
+			return nil
+		})
+	}
+
+	var limiter *ratelimit.Limiter
+	switch cfg.Store {
+	case "redis":
+		var cli *redisplus.Client
+		err := container.Invoke(func(c *redisplus.Client) {
+			cli = c.WithPrefix(cfg.RedisPrefix)
+		})
+		if err != nil {
+			return nil, err
+		}
+		rs := ratelimit.NewRedisStore(cli)
+
+		// redis不可用时临时使用内存存储
+		opts := []ratelimit.Option{
+			ratelimit.SetFallback(ratelimit.NewMemoryStore()),
+			ratelimit.SetErrorHandler(func(err error) {
+				logger.Warnf(context.Background(), "请求频率限制存储发生错误，临时使用内存存储：%s", err.Error())
+			}),
+		}
+		if cfg.RedisRetry > 0 {
+			opts = append(opts, ratelimit.SetRetryInterval(time.Duration(cfg.RedisRetry)*time.Second))
+		}
+		limiter = ratelimit.New(rs, opts...)
+	default:
+		limiter = ratelimit.New(ratelimit.NewMemoryStore())
+	}
+
+	err := container.Provide(func() *ratelimit.Limiter {
+		return limiter
+	})
+	if err != nil {
+		limiter.Close()
+		return nil, err
+	}
+
+	return func() {
+		limiter.Close()
+	}, nil
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/pkg/logger"
+
+	"go.uber.org/dig"
+)
+
+// InitRecycle 初始化回收站定时清理(彻底删除超过保留天数的已删除数据)
+func InitRecycle(ctx context.Context, container *dig.Container) func() {
+	c := config.GetGlobalConfig().Recycle
+	if !c.Enable || c.RetentionDays <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(c.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purgeRecycle(ctx, container, c.RetentionDays)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// 彻底删除超过保留天数的已删除数据
+func purgeRecycle(ctx context.Context, container *dig.Container, retentionDays int) {
+	deletedAt := time.Now().AddDate(0, 0, -retentionDays)
+
+	err := container.Invoke(func(demo bll.IDemo, menu bll.IMenu, role bll.IRole, user bll.IUser) error {
+		purges := []func(context.Context, time.Time) error{
+			demo.PurgeBefore,
+			menu.PurgeBefore,
+			role.PurgeBefore,
+			user.PurgeBefore,
+		}
+
+		for _, purge := range purges {
+			err := purge(ctx, deletedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf(ctx, "清理回收站数据发生错误：%s", err.Error())
+	}
+}
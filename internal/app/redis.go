@@ -0,0 +1,44 @@
+package app
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
+	"github.com/wanhello/iris-admin/pkg/redisplus"
+
+	"go.uber.org/dig"
+)
+
+// 查询缓存或请求频率限制是否使用redis存储
+func useSharedRedis(cfg *config.Config) bool {
+	return (cfg.Cache.Enable && cfg.Cache.Store == "redis") ||
+		(cfg.RateLimiter.Enable && cfg.RateLimiter.Store == "redis")
+}
+
+// InitRedis 初始化查询缓存及请求频率限制共用的redis客户端(各模块使用不同的键名前缀，都未使用redis时不注入)
+func InitRedis(container *dig.Container) (func(), error) {
+	cfg := config.GetGlobalConfig()
+	if !useSharedRedis(cfg) {
+		return nil, nil
+	}
+
+	cli := redisplus.New(&redisplus.Config{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	if cfg.Tracing.Enable {
+		cli.AddHook(tracing.NewRedisHook())
+	}
+
+	err := container.Provide(func() *redisplus.Client {
+		return cli
+	})
+	if err != nil {
+		cli.Close()
+		return nil, err
+	}
+
+	return func() {
+		cli.Close()
+	}, nil
+}
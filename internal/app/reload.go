@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/pkg/logger"
+)
+
+// Reload 重新加载配置文件(收到SIGHUP信号或配置文件变更时执行，校验失败时保持原配置)
+func Reload(ctx context.Context, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return reloadConfig(ctx, o)
+}
+
+func reloadConfig(ctx context.Context, o options) error {
+	cfg, err := config.ParseConfig(o.configFiles()...)
+	if err != nil {
+		return err
+	}
+	applyOptions(cfg, &o)
+
+	result, err := config.ReloadGlobalConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Applied) > 0 {
+		logger.Printf(ctx, "配置重新加载完成，已生效的配置项：[%s]", strings.Join(result.Applied, ","))
+	} else {
+		logger.Printf(ctx, "配置重新加载完成，没有可在线更新的配置项变更")
+	}
+	if len(result.Restart) > 0 {
+		logger.Warnf(ctx, "以下配置项需要重启服务后生效：[%s]", strings.Join(result.Restart, ","))
+	}
+	return nil
+}
+
+// InitConfigWatch 监听配置文件变更(按间隔检查文件的修改时间，任一文件变更后重新加载)
+func InitConfigWatch(ctx context.Context, o options) func() {
+	cfg := config.GetGlobalConfig().Reload
+	if !cfg.Watch {
+		return nil
+	}
+
+	// 取所有配置文件中最近的修改时间
+	modTime := func() time.Time {
+		var t time.Time
+		for _, name := range o.configFiles() {
+			fi, err := os.Stat(name)
+			if err != nil {
+				return time.Time{}
+			}
+			if fi.ModTime().After(t) {
+				t = fi.ModTime()
+			}
+		}
+		return t
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		last := modTime()
+		ticker := time.NewTicker(time.Duration(cfg.WatchInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			t := modTime()
+			if t.IsZero() || t.Equal(last) {
+				continue
+			}
+			last = t
+
+			logger.Printf(ctx, "配置文件[%s]发生变更，重新加载配置", o.ConfigFile)
+			if err := reloadConfig(ctx, o); err != nil {
+				logger.Errorf(ctx, "重新加载配置发生错误：%s", err.Error())
+			}
+		}
+	}()
+
+	return cancel
+}
@@ -3,9 +3,12 @@ package api
 import (
 	"github.com/casbin/casbin"
 	"github.com/kataras/iris"
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
 	"github.com/wanhello/iris-admin/internal/app/middleware"
 	"github.com/wanhello/iris-admin/internal/app/routers/api/ctl"
 	"github.com/wanhello/iris-admin/pkg/auth"
+	"github.com/wanhello/iris-admin/pkg/ratelimit"
 
 	"go.uber.org/dig"
 
@@ -22,15 +25,25 @@ func RegisterRouter(app *iris.Application, container *dig.Container) error {
 	return container.Invoke(func(
 		a auth.Auther,
 		e *casbin.Enforcer,
+		limiter *ratelimit.Limiter,
+		bUser bll.IUser,
 		cDemo *ctl.Demo,
 		cLogin *ctl.Login,
 		cMenu *ctl.Menu,
+		cRecycle *ctl.Recycle,
 		cRole *ctl.Role,
+		cSearch *ctl.Search,
 		cUser *ctl.User,
+		// generator:ctl
 	) error {
 
 		g := app.Party("/api")
 
+		// Cookie令牌的CSRF校验
+		if config.GetGlobalConfig().AuthCookie.Enable {
+			g.Use(middleware.CSRFMiddleware())
+		}
+
 		// 用户身份授权
 		g.Use(middleware.UserAuthMiddleware(
 			a,
@@ -40,16 +53,24 @@ func RegisterRouter(app *iris.Application, container *dig.Container) error {
 			),
 		))
 
+		// 用户及角色的IP访问控制
+		if config.GetGlobalConfig().IPAccess.Enable {
+			g.Use(middleware.UserIPAccessMiddleware(bUser))
+		}
+
 		// casbin权限校验中间件
 		g.Use(middleware.CasbinMiddleware(e,
 			middleware.AllowMethodAndPathPrefixSkipper(
 				middleware.JoinRouter("GET", "/api/v1/pub"),
 				middleware.JoinRouter("POST", "/api/v1/pub"),
+				middleware.JoinRouter("PUT", "/api/v1/pub"),
+				// 检索结果按数据类型校验权限
+				middleware.JoinRouter("GET", "/api/v1/search"),
 			),
 		))
 
 		// 请求频率限制中间件
-		g.Use(middleware.RateLimiterMiddleware())
+		g.Use(middleware.RateLimiterMiddleware(limiter, e))
 
 		v1 := g.Party("/v1")
 		{
@@ -72,35 +93,62 @@ func RegisterRouter(app *iris.Application, container *dig.Container) error {
 
 			// 注册/api/v1/demos
 			v1.Get("/demos", cDemo.Query)
-			v1.Get("/demos/:id", cDemo.Get)
+			v1.Get("/demos/{id}", cDemo.Get)
 			v1.Post("/demos", cDemo.Create)
-			v1.Put("/demos/:id", cDemo.Update)
-			v1.Delete("/demos/:id", cDemo.Delete)
-			v1.Patch("/demos/:id/enable", cDemo.Enable)
-			v1.Patch("/demos/:id/disable", cDemo.Disable)
+			v1.Put("/demos/{id}", cDemo.Update)
+			v1.Delete("/demos/{id}", cDemo.Delete)
+			v1.Patch("/demos/{id}/enable", cDemo.Enable)
+			v1.Patch("/demos/{id}/disable", cDemo.Disable)
+			v1.Post("/demos/batch/delete", cDemo.BatchDelete)
+			v1.Post("/demos/batch/enable", cDemo.BatchEnable)
+			v1.Post("/demos/batch/disable", cDemo.BatchDisable)
 
 			// 注册/api/v1/menus
 			v1.Get("/menus", cMenu.Query)
-			v1.Get("/menus/:id", cMenu.Get)
+			v1.Get("/menus/{id}", cMenu.Get)
 			v1.Post("/menus", cMenu.Create)
-			v1.Put("/menus/:id", cMenu.Update)
-			v1.Delete("/menus/:id", cMenu.Delete)
+			v1.Put("/menus/{id}", cMenu.Update)
+			v1.Delete("/menus/{id}", cMenu.Delete)
 
 			// 注册/api/v1/roles
 			v1.Get("/roles", cRole.Query)
-			v1.Get("/roles/:id", cRole.Get)
+			v1.Get("/roles/{id}", cRole.Get)
 			v1.Post("/roles", cRole.Create)
-			v1.Put("/roles/:id", cRole.Update)
-			v1.Delete("/roles/:id", cRole.Delete)
+			v1.Put("/roles/{id}", cRole.Update)
+			v1.Delete("/roles/{id}", cRole.Delete)
+			v1.Post("/roles/batch/delete", cRole.BatchDelete)
+
+			// 注册/api/v1/search
+			v1.Get("/search", cSearch.Query)
 
 			// 注册/api/v1/users
 			v1.Get("/users", cUser.Query)
-			v1.Get("/users/:id", cUser.Get)
+			v1.Get("/users/{id}", cUser.Get)
 			v1.Post("/users", cUser.Create)
-			v1.Put("/users/:id", cUser.Update)
-			v1.Delete("/users/:id", cUser.Delete)
-			v1.Patch("/users/:id/enable", cUser.Enable)
-			v1.Patch("/users/:id/disable", cUser.Disable)
+			v1.Put("/users/{id}", cUser.Update)
+			v1.Delete("/users/{id}", cUser.Delete)
+			v1.Patch("/users/{id}/enable", cUser.Enable)
+			v1.Patch("/users/{id}/disable", cUser.Disable)
+			v1.Post("/users/batch/delete", cUser.BatchDelete)
+			v1.Post("/users/batch/enable", cUser.BatchEnable)
+			v1.Post("/users/batch/disable", cUser.BatchDisable)
+			v1.Post("/users/batch/roles", cUser.BatchAssignRoles)
+
+			// 注册/api/v1/recycle
+			v1.Get("/recycle/demos", cRecycle.QueryDemo)
+			v1.Patch("/recycle/demos/{id}/restore", cRecycle.RestoreDemo)
+			v1.Delete("/recycle/demos/{id}", cRecycle.PurgeDemo)
+			v1.Get("/recycle/menus", cRecycle.QueryMenu)
+			v1.Patch("/recycle/menus/{id}/restore", cRecycle.RestoreMenu)
+			v1.Delete("/recycle/menus/{id}", cRecycle.PurgeMenu)
+			v1.Get("/recycle/roles", cRecycle.QueryRole)
+			v1.Patch("/recycle/roles/{id}/restore", cRecycle.RestoreRole)
+			v1.Delete("/recycle/roles/{id}", cRecycle.PurgeRole)
+			v1.Get("/recycle/users", cRecycle.QueryUser)
+			v1.Patch("/recycle/users/{id}/restore", cRecycle.RestoreUser)
+			v1.Delete("/recycle/users/{id}", cRecycle.PurgeUser)
+
+			// generator:router
 		}
 
 		return nil
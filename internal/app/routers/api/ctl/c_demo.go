@@ -45,6 +45,12 @@ func (a *Demo) Query(c iris.Context) {
 // @Param code query string false "编号"
 // @Param name query string false "名称"
 // @Param status query int false "状态(1:启用 2:停用)"
+// @Param sort query string false "排序字段(多个以英文逗号分隔，字段前加-表示降序，例如：-created_at,name)"
+// @Param fields query string false "选择字段(多个以英文逗号分隔)"
+// @Param filter query string false "过滤条件(格式：字段:操作符:值，操作符：eq/ne/in/like/gte/lte/between，可指定多个)"
+// @Param cursor query string false "分页游标(指定时使用游标分页，首次查询为空)"
+// @Param limit query int false "游标分页的查询条数(最大1000)" 10
+// @Param total query string false "游标分页的总数查询方式(none:不查询 exact:精确查询 estimate:估算)"
 // @Success 200 []schema.Demo "查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
 // @Failure 400 schema.HTTPError "{error:{code:0,message:未知的查询类型}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
@@ -56,15 +62,23 @@ func (a *Demo) QueryPage(c iris.Context) {
 	params.LikeName = c.URLParam("name")
 	params.Status = util.S(c.URLParam("status")).DefaultInt(0)
 
+	spec, err := irisplus.GetQuerySpec(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
 	result, err := a.DemoBll.Query(irisplus.NewContext(c), params, schema.DemoQueryOptions{
-		PageParam: irisplus.GetPaginationParam(c),
+		PageParam:   irisplus.GetPaginationParam(c),
+		CursorParam: irisplus.GetCursorParam(c),
+		QuerySpec:   spec,
 	})
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
 
-	irisplus.ResPage(c, result.Data, result.PageResult)
+	irisplus.ResPageFields(c, result.Data, result.PageResult, spec.Fields)
 }
 
 // Get 查询指定数据
@@ -77,11 +91,12 @@ func (a *Demo) QueryPage(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router GET /api/v1/demos/{id}
 func (a *Demo) Get(c iris.Context) {
-	item, err := a.DemoBll.Get(irisplus.NewContext(c), c.URLParam("id"))
+	item, err := a.DemoBll.Get(irisplus.NewContext(c), c.Params().Get("id"))
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, item.Version)
 	irisplus.ResSuccess(c, item)
 }
 
@@ -107,6 +122,7 @@ func (a *Demo) Create(c iris.Context) {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem)
 }
 
@@ -114,10 +130,13 @@ func (a *Demo) Create(c iris.Context) {
 // @Summary 更新数据
 // @Param Authorization header string false "Bearer 用户令牌"
 // @Param id path string true "记录ID"
+// @Param If-Match header string false "数据版本号(ETag)"
 // @Param body body schema.Demo true
 // @Success 200 schema.Demo
 // @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 409 schema.HTTPError "{error:{code:0,message:资源已被修改，请刷新后重试}}"
+// @Failure 412 schema.HTTPError "{error:{code:0,message:资源版本不匹配}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PUT /api/v1/demos/{id}
 func (a *Demo) Update(c iris.Context) {
@@ -127,11 +146,24 @@ func (a *Demo) Update(c iris.Context) {
 		return
 	}
 
-	nitem, err := a.DemoBll.Update(irisplus.NewContext(c), c.URLParam("id"), item)
+	ifMatch, err := irisplus.GetIfMatchVersion(c)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
+	} else if ifMatch > 0 {
+		item.Version = ifMatch
 	}
+
+	nitem, err := a.DemoBll.Update(irisplus.NewContext(c), c.Params().Get("id"), item)
+	if err != nil {
+		// 通过If-Match指定的版本号不一致时响应412
+		if ifMatch > 0 && err == errors.ErrResourceConflict {
+			err = errors.ErrPreconditionFailed
+		}
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem)
 }
 
@@ -144,7 +176,7 @@ func (a *Demo) Update(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router DELETE /api/v1/demos/{id}
 func (a *Demo) Delete(c iris.Context) {
-	err := a.DemoBll.Delete(irisplus.NewContext(c), c.URLParam("id"))
+	err := a.DemoBll.Delete(irisplus.NewContext(c), c.Params().Get("id"))
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -161,7 +193,7 @@ func (a *Demo) Delete(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PATCH /api/v1/demos/{id}/enable
 func (a *Demo) Enable(c iris.Context) {
-	err := a.DemoBll.UpdateStatus(irisplus.NewContext(c), c.URLParam("id"), 1)
+	err := a.DemoBll.UpdateStatus(irisplus.NewContext(c), c.Params().Get("id"), 1)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -178,7 +210,7 @@ func (a *Demo) Enable(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PATCH /api/v1/demos/{id}/disable
 func (a *Demo) Disable(c iris.Context) {
-	err := a.DemoBll.UpdateStatus(irisplus.NewContext(c), c.URLParam("id"), 2)
+	err := a.DemoBll.UpdateStatus(irisplus.NewContext(c), c.Params().Get("id"), 2)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -186,3 +218,75 @@ func (a *Demo) Disable(c iris.Context) {
 	irisplus.ResOK(c)
 }
 
+
+// BatchDelete 批量删除数据
+// @Summary 批量删除数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/demos/batch/delete
+func (a *Demo) BatchDelete(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.DemoBll.BatchDelete(irisplus.NewContext(c), params)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
+
+// BatchEnable 批量启用数据
+// @Summary 批量启用数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/demos/batch/enable
+func (a *Demo) BatchEnable(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.DemoBll.BatchUpdateStatus(irisplus.NewContext(c), params, 1)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
+
+// BatchDisable 批量禁用数据
+// @Summary 批量禁用数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/demos/batch/disable
+func (a *Demo) BatchDisable(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.DemoBll.BatchUpdateStatus(irisplus.NewContext(c), params, 2)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/wanhello/iris-admin/internal/app/bll"
 	"github.com/wanhello/iris-admin/internal/app/config"
 	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/metrics"
 	"github.com/wanhello/iris-admin/internal/app/schema"
 	"github.com/wanhello/iris-admin/internal/app/irisplus"
 	"github.com/wanhello/iris-admin/pkg/logger"
@@ -84,11 +85,13 @@ func (a *Login) Login(c iris.Context) {
 	}
 
 	if !captcha.VerifyString(item.CaptchaID, item.CaptchaCode) {
+		metrics.ObserveLogin(errors.ErrLoginInvalidVerifyCode)
 		irisplus.ResError(c, errors.ErrLoginInvalidVerifyCode)
 		return
 	}
 
 	user, err := a.LoginBll.Verify(irisplus.NewContext(c), item.UserName, item.Password)
+	metrics.ObserveLogin(err)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -105,6 +108,10 @@ func (a *Login) Login(c iris.Context) {
 	}
 
 	logger.StartSpan(irisplus.NewContext(c), logger.SetSpanTitle("用户登录"), logger.SetSpanFuncName("Login")).Infof("登入系统")
+	if irisplus.SetTokenCookie(c, tokenInfo.AccessToken, tokenInfo.ExpiresAt) {
+		// 令牌仅通过HttpOnly Cookie下发，响应中不返回(防止脚本读取令牌)
+		tokenInfo.AccessToken = ""
+	}
 	irisplus.ResSuccess(c, tokenInfo)
 }
 
@@ -123,6 +130,7 @@ func (a *Login) Logout(c iris.Context) {
 		}
 		logger.StartSpan(irisplus.NewContext(c), logger.SetSpanTitle("用户登出"), logger.SetSpanFuncName("Logout")).Infof("登出系统")
 	}
+	irisplus.ClearTokenCookie(c)
 	irisplus.ResOK(c)
 }
 
@@ -139,6 +147,10 @@ func (a *Login) RefreshToken(c iris.Context) {
 		irisplus.ResError(c, err)
 		return
 	}
+	if irisplus.SetTokenCookie(c, tokenInfo.AccessToken, tokenInfo.ExpiresAt) {
+		// 令牌仅通过HttpOnly Cookie下发，响应中不返回(防止脚本读取令牌)
+		tokenInfo.AccessToken = ""
+	}
 	irisplus.ResSuccess(c, tokenInfo)
 }
 
@@ -45,6 +45,12 @@ func (a *Menu) Query(c iris.Context) {
 // @Param name query string false "名称"
 // @Param hidden query int false "隐藏菜单(0:不隐藏 1:隐藏)"
 // @Param parent_id query string false "父级ID"
+// @Param sort query string false "排序字段(多个以英文逗号分隔，字段前加-表示降序，例如：-created_at,name)"
+// @Param fields query string false "选择字段(多个以英文逗号分隔)"
+// @Param filter query string false "过滤条件(格式：字段:操作符:值，操作符：eq/ne/in/like/gte/lte/between，可指定多个)"
+// @Param cursor query string false "分页游标(指定时使用游标分页，首次查询为空)"
+// @Param limit query int false "游标分页的查询条数(最大1000)" 10
+// @Param total query string false "游标分页的总数查询方式(none:不查询 exact:精确查询 estimate:估算)"
 // @Success 200 []schema.Menu "分页查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
 // @Failure 400 schema.HTTPError "{error:{code:0,message:未知的查询类型}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
@@ -65,14 +71,22 @@ func (a *Menu) QueryPage(c iris.Context) {
 		}
 	}
 
+	spec, err := irisplus.GetQuerySpec(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
 	result, err := a.MenuBll.Query(irisplus.NewContext(c), params, schema.MenuQueryOptions{
-		PageParam: irisplus.GetPaginationParam(c),
+		PageParam:   irisplus.GetPaginationParam(c),
+		CursorParam: irisplus.GetCursorParam(c),
+		QuerySpec:   spec,
 	})
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
-	irisplus.ResPage(c, result.Data, result.PageResult)
+	irisplus.ResPageFields(c, result.Data, result.PageResult, spec.Fields)
 }
 
 // QueryTree 查询菜单树
@@ -108,7 +122,7 @@ func (a *Menu) QueryTree(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router GET /api/v1/menus/{id}
 func (a *Menu) Get(c iris.Context) {
-	item, err := a.MenuBll.Get(irisplus.NewContext(c), c.URLParam("id"), schema.MenuQueryOptions{
+	item, err := a.MenuBll.Get(irisplus.NewContext(c), c.Params().Get("id"), schema.MenuQueryOptions{
 		IncludeActions:   true,
 		IncludeResources: true,
 	})
@@ -116,6 +130,7 @@ func (a *Menu) Get(c iris.Context) {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, item.Version)
 	irisplus.ResSuccess(c, item)
 }
 
@@ -141,6 +156,7 @@ func (a *Menu) Create(c iris.Context) {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem)
 }
 
@@ -148,10 +164,13 @@ func (a *Menu) Create(c iris.Context) {
 // @Summary 更新数据
 // @Param Authorization header string false "Bearer 用户令牌"
 // @Param id path string true "记录ID"
+// @Param If-Match header string false "数据版本号(ETag)"
 // @Param body body schema.Menu true
 // @Success 200 schema.Menu
 // @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 409 schema.HTTPError "{error:{code:0,message:资源已被修改，请刷新后重试}}"
+// @Failure 412 schema.HTTPError "{error:{code:0,message:资源版本不匹配}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PUT /api/v1/menus/{id}
 func (a *Menu) Update(c iris.Context) {
@@ -161,11 +180,24 @@ func (a *Menu) Update(c iris.Context) {
 		return
 	}
 
-	nitem, err := a.MenuBll.Update(irisplus.NewContext(c), c.URLParam("id"), item)
+	ifMatch, err := irisplus.GetIfMatchVersion(c)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
+	} else if ifMatch > 0 {
+		item.Version = ifMatch
+	}
+
+	nitem, err := a.MenuBll.Update(irisplus.NewContext(c), c.Params().Get("id"), item)
+	if err != nil {
+		// 通过If-Match指定的版本号不一致时响应412
+		if ifMatch > 0 && err == errors.ErrResourceConflict {
+			err = errors.ErrPreconditionFailed
+		}
+		irisplus.ResError(c, err)
+		return
 	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem)
 }
 
@@ -178,7 +210,7 @@ func (a *Menu) Update(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router DELETE /api/v1/menus/{id}
 func (a *Menu) Delete(c iris.Context) {
-	err := a.MenuBll.Delete(irisplus.NewContext(c), c.URLParam("id"))
+	err := a.MenuBll.Delete(irisplus.NewContext(c), c.Params().Get("id"))
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -0,0 +1,266 @@
+package ctl
+
+import (
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+
+	"github.com/kataras/iris"
+)
+
+// NewRecycle 创建回收站控制器
+func NewRecycle(
+	bDemo bll.IDemo,
+	bMenu bll.IMenu,
+	bRole bll.IRole,
+	bUser bll.IUser,
+) *Recycle {
+	return &Recycle{
+		DemoBll: bDemo,
+		MenuBll: bMenu,
+		RoleBll: bRole,
+		UserBll: bUser,
+	}
+}
+
+// Recycle 回收站
+// @Name Recycle
+// @Description 回收站管理接口(已删除数据的查询、恢复及彻底删除)
+type Recycle struct {
+	DemoBll bll.IDemo
+	MenuBll bll.IMenu
+	RoleBll bll.IRole
+	UserBll bll.IUser
+}
+
+// QueryDemo 查询已删除的demo数据
+// @Summary 查询已删除的demo数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param current query int true "分页索引" 1
+// @Param pageSize query int true "分页大小" 10
+// @Success 200 []schema.Demo "查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/recycle/demos
+func (a *Recycle) QueryDemo(c iris.Context) {
+	result, err := a.DemoBll.QueryDeleted(irisplus.NewContext(c), schema.DemoQueryOptions{
+		PageParam: irisplus.GetPaginationParam(c),
+	})
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResPage(c, result.Data, result.PageResult)
+}
+
+// RestoreDemo 恢复已删除的demo数据
+// @Summary 恢复已删除的demo数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.Demo
+// @Failure 400 schema.HTTPError "{error:{code:0,message:资源已经存在}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PATCH /api/v1/recycle/demos/{id}/restore
+func (a *Recycle) RestoreDemo(c iris.Context) {
+	item, err := a.DemoBll.Restore(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, item)
+}
+
+// PurgeDemo 彻底删除demo数据
+// @Summary 彻底删除demo数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router DELETE /api/v1/recycle/demos/{id}
+func (a *Recycle) PurgeDemo(c iris.Context) {
+	err := a.DemoBll.Purge(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
+
+// QueryMenu 查询已删除的菜单数据
+// @Summary 查询已删除的菜单数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param current query int true "分页索引" 1
+// @Param pageSize query int true "分页大小" 10
+// @Success 200 []schema.Menu "查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/recycle/menus
+func (a *Recycle) QueryMenu(c iris.Context) {
+	result, err := a.MenuBll.QueryDeleted(irisplus.NewContext(c), schema.MenuQueryOptions{
+		PageParam: irisplus.GetPaginationParam(c),
+	})
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResPage(c, result.Data, result.PageResult)
+}
+
+// RestoreMenu 恢复已删除的菜单数据
+// @Summary 恢复已删除的菜单数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.Menu
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的父级节点}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PATCH /api/v1/recycle/menus/{id}/restore
+func (a *Recycle) RestoreMenu(c iris.Context) {
+	item, err := a.MenuBll.Restore(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, item)
+}
+
+// PurgeMenu 彻底删除菜单数据
+// @Summary 彻底删除菜单数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router DELETE /api/v1/recycle/menus/{id}
+func (a *Recycle) PurgeMenu(c iris.Context) {
+	err := a.MenuBll.Purge(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
+
+// QueryRole 查询已删除的角色数据
+// @Summary 查询已删除的角色数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param current query int true "分页索引" 1
+// @Param pageSize query int true "分页大小" 10
+// @Success 200 []schema.Role "查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/recycle/roles
+func (a *Recycle) QueryRole(c iris.Context) {
+	result, err := a.RoleBll.QueryDeleted(irisplus.NewContext(c), schema.RoleQueryOptions{
+		PageParam: irisplus.GetPaginationParam(c),
+	})
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResPage(c, result.Data, result.PageResult)
+}
+
+// RestoreRole 恢复已删除的角色数据
+// @Summary 恢复已删除的角色数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.Role
+// @Failure 400 schema.HTTPError "{error:{code:0,message:资源已经存在}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PATCH /api/v1/recycle/roles/{id}/restore
+func (a *Recycle) RestoreRole(c iris.Context) {
+	item, err := a.RoleBll.Restore(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, item)
+}
+
+// PurgeRole 彻底删除角色数据
+// @Summary 彻底删除角色数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router DELETE /api/v1/recycle/roles/{id}
+func (a *Recycle) PurgeRole(c iris.Context) {
+	err := a.RoleBll.Purge(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
+
+// QueryUser 查询已删除的用户数据
+// @Summary 查询已删除的用户数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param current query int true "分页索引" 1
+// @Param pageSize query int true "分页大小" 10
+// @Success 200 []schema.User "查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/recycle/users
+func (a *Recycle) QueryUser(c iris.Context) {
+	result, err := a.UserBll.QueryDeleted(irisplus.NewContext(c), schema.UserQueryOptions{
+		PageParam: irisplus.GetPaginationParam(c),
+	})
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	for _, item := range result.Data {
+		item.CleanSecure()
+	}
+	irisplus.ResPage(c, result.Data, result.PageResult)
+}
+
+// RestoreUser 恢复已删除的用户数据
+// @Summary 恢复已删除的用户数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.User
+// @Failure 400 schema.HTTPError "{error:{code:0,message:资源已经存在}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router PATCH /api/v1/recycle/users/{id}/restore
+func (a *Recycle) RestoreUser(c iris.Context) {
+	item, err := a.UserBll.Restore(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, item.CleanSecure())
+}
+
+// PurgeUser 彻底删除用户数据
+// @Summary 彻底删除用户数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param id path string true "记录ID"
+// @Success 200 schema.HTTPStatus "{status:OK}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router DELETE /api/v1/recycle/users/{id}
+func (a *Recycle) PurgeUser(c iris.Context) {
+	err := a.UserBll.Purge(irisplus.NewContext(c), c.Params().Get("id"))
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResOK(c)
+}
@@ -42,6 +42,12 @@ func (a *Role) Query(c iris.Context) {
 // @Param current query int true "分页索引" 1
 // @Param pageSize query int true "分页大小" 10
 // @Param name query string false "角色名称(模糊查询)"
+// @Param sort query string false "排序字段(多个以英文逗号分隔，字段前加-表示降序，例如：-created_at,name)"
+// @Param fields query string false "选择字段(多个以英文逗号分隔)"
+// @Param filter query string false "过滤条件(格式：字段:操作符:值，操作符：eq/ne/in/like/gte/lte/between，可指定多个)"
+// @Param cursor query string false "分页游标(指定时使用游标分页，首次查询为空)"
+// @Param limit query int false "游标分页的查询条数(最大1000)" 10
+// @Param total query string false "游标分页的总数查询方式(none:不查询 exact:精确查询 estimate:估算)"
 // @Success 200 []schema.Role "分页查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
 // @Failure 400 schema.HTTPError "{error:{code:0,message:未知的查询类型}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
@@ -51,14 +57,22 @@ func (a *Role) QueryPage(c iris.Context) {
 	var params schema.RoleQueryParam
 	params.LikeName = c.URLParam("name")
 
+	spec, err := irisplus.GetQuerySpec(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
 	result, err := a.RoleBll.Query(irisplus.NewContext(c), params, schema.RoleQueryOptions{
-		PageParam: irisplus.GetPaginationParam(c),
+		PageParam:   irisplus.GetPaginationParam(c),
+		CursorParam: irisplus.GetCursorParam(c),
+		QuerySpec:   spec,
 	})
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
-	irisplus.ResPage(c, result.Data, result.PageResult)
+	irisplus.ResPageFields(c, result.Data, result.PageResult, spec.Fields)
 }
 
 // QuerySelect 查询选择数据
@@ -88,13 +102,14 @@ func (a *Role) QuerySelect(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router GET /api/v1/roles/{id}
 func (a *Role) Get(c iris.Context) {
-	item, err := a.RoleBll.Get(irisplus.NewContext(c), c.URLParam("id"), schema.RoleQueryOptions{
+	item, err := a.RoleBll.Get(irisplus.NewContext(c), c.Params().Get("id"), schema.RoleQueryOptions{
 		IncludeMenus: true,
 	})
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, item.Version)
 	irisplus.ResSuccess(c, item)
 }
 
@@ -121,6 +136,7 @@ func (a *Role) Create(c iris.Context) {
 		return
 	}
 
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem)
 }
 
@@ -128,10 +144,13 @@ func (a *Role) Create(c iris.Context) {
 // @Summary 更新数据
 // @Param Authorization header string false "Bearer 用户令牌"
 // @Param id path string true "记录ID"
+// @Param If-Match header string false "数据版本号(ETag)"
 // @Param body body schema.Role true
 // @Success 200 schema.Role
 // @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 409 schema.HTTPError "{error:{code:0,message:资源已被修改，请刷新后重试}}"
+// @Failure 412 schema.HTTPError "{error:{code:0,message:资源版本不匹配}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PUT /api/v1/roles/{id}
 func (a *Role) Update(c iris.Context) {
@@ -141,11 +160,24 @@ func (a *Role) Update(c iris.Context) {
 		return
 	}
 
-	nitem, err := a.RoleBll.Update(irisplus.NewContext(c), c.URLParam("id"), item)
+	ifMatch, err := irisplus.GetIfMatchVersion(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	} else if ifMatch > 0 {
+		item.Version = ifMatch
+	}
+
+	nitem, err := a.RoleBll.Update(irisplus.NewContext(c), c.Params().Get("id"), item)
 	if err != nil {
+		// 通过If-Match指定的版本号不一致时响应412
+		if ifMatch > 0 && err == errors.ErrResourceConflict {
+			err = errors.ErrPreconditionFailed
+		}
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem)
 }
 
@@ -158,10 +190,34 @@ func (a *Role) Update(c iris.Context) {
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router DELETE /api/v1/roles/{id}
 func (a *Role) Delete(c iris.Context) {
-	err := a.RoleBll.Delete(irisplus.NewContext(c), c.URLParam("id"))
+	err := a.RoleBll.Delete(irisplus.NewContext(c), c.Params().Get("id"))
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
 	irisplus.ResOK(c)
 }
+
+// BatchDelete 批量删除数据
+// @Summary 批量删除数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/roles/batch/delete
+func (a *Role) BatchDelete(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.RoleBll.BatchDelete(irisplus.NewContext(c), params)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
@@ -0,0 +1,62 @@
+package ctl
+
+import (
+	"strings"
+
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/errors"
+	"github.com/wanhello/iris-admin/internal/app/irisplus"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+
+	"github.com/kataras/iris"
+)
+
+// NewSearch 创建全文检索控制器
+func NewSearch(bSearch bll.ISearch) *Search {
+	return &Search{
+		SearchBll: bSearch,
+	}
+}
+
+// Search 全文检索
+// @Name Search
+// @Description 全文检索接口
+type Search struct {
+	SearchBll bll.ISearch
+}
+
+// Query 检索数据
+// @Summary 检索数据(按数据类型分组，仅返回有查询权限的数据类型)
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param q query string true "检索关键字"
+// @Param types query string false "数据类型(多个以英文逗号分隔，支持：demo/menu/role/user，为空时检索全部类型)"
+// @Param limit query int false "每种数据类型返回的最大结果数量" 10
+// @Success 200 []schema.SearchGroup "查询结果：{list:检索结果}"
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router GET /api/v1/search
+func (a *Search) Query(c iris.Context) {
+	params := schema.SearchParam{
+		Query: c.URLParamTrim("q"),
+		Limit: c.URLParamIntDefault("limit", config.GetGlobalConfig().Search.Limit),
+	}
+	if params.Query == "" {
+		irisplus.ResError(c, errors.ErrInvalidRequestParameter)
+		return
+	}
+
+	for _, typ := range strings.Split(c.URLParam("types"), ",") {
+		if typ = strings.TrimSpace(typ); typ != "" {
+			params.Types = append(params.Types, typ)
+		}
+	}
+
+	groups, err := a.SearchBll.Search(irisplus.NewContext(c), params)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResList(c, groups)
+}
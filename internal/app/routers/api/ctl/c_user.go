@@ -29,7 +29,7 @@ type User struct {
 }
 
 // Query 查询数据
-func (a *User) Query(c *iris.Context) {
+func (a *User) Query(c iris.Context) {
 	switch c.URLParam("q") {
 	case "page":
 		a.QueryPage(c)
@@ -47,12 +47,18 @@ func (a *User) Query(c *iris.Context) {
 // @Param real_name query string false "真实姓名(模糊查询)"
 // @Param role_ids query string false "角色ID(多个以英文逗号分隔)"
 // @Param status query int false "状态(1:启用 2:停用)"
+// @Param sort query string false "排序字段(多个以英文逗号分隔，字段前加-表示降序，例如：-created_at,name)"
+// @Param fields query string false "选择字段(多个以英文逗号分隔)"
+// @Param filter query string false "过滤条件(格式：字段:操作符:值，操作符：eq/ne/in/like/gte/lte/between，可指定多个)"
+// @Param cursor query string false "分页游标(指定时使用游标分页，首次查询为空)"
+// @Param limit query int false "游标分页的查询条数(最大1000)" 10
+// @Param total query string false "游标分页的总数查询方式(none:不查询 exact:精确查询 estimate:估算)"
 // @Success 200 []schema.UserShow "分页查询结果：{list:列表数据,pagination:{current:页索引,pageSize:页大小,total:总数量}}"
 // @Failure 400 schema.HTTPError "{error:{code:0,message:未知的查询类型}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router GET /api/v1/users?q=page
-func (a *User) QueryPage(c *iris.Context) {
+func (a *User) QueryPage(c iris.Context) {
 	var params schema.UserQueryParam
 	params.LikeUserName = c.URLParam("user_name")
 	params.LikeRealName = c.URLParam("real_name")
@@ -64,15 +70,23 @@ func (a *User) QueryPage(c *iris.Context) {
 		params.RoleIDs = strings.Split(v, ",")
 	}
 
+	spec, err := irisplus.GetQuerySpec(c)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
 	result, err := a.UserBll.QueryShow(irisplus.NewContext(c), params, schema.UserQueryOptions{
 		IncludeRoles: true,
 		PageParam:    irisplus.GetPaginationParam(c),
+		CursorParam:  irisplus.GetCursorParam(c),
+		QuerySpec:    spec,
 	})
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
-	irisplus.ResPage(c, result.Data, result.PageResult)
+	irisplus.ResPageFields(c, result.Data, result.PageResult, spec.Fields)
 }
 
 // Get 查询指定数据
@@ -85,14 +99,15 @@ func (a *User) QueryPage(c *iris.Context) {
 // @Failure 404 schema.HTTPError "{error:{code:0,message:资源不存在}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router GET /api/v1/users/{id}
-func (a *User) Get(c *iris.Context) {
-	item, err := a.UserBll.Get(irisplus.NewContext(c), c.Param("id"), schema.UserQueryOptions{
+func (a *User) Get(c iris.Context) {
+	item, err := a.UserBll.Get(irisplus.NewContext(c), c.Params().Get("id"), schema.UserQueryOptions{
 		IncludeRoles: true,
 	})
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, item.Version)
 	irisplus.ResSuccess(c, item.CleanSecure())
 }
 
@@ -105,7 +120,7 @@ func (a *User) Get(c *iris.Context) {
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router POST /api/v1/users
-func (a *User) Create(c *iris.Context) {
+func (a *User) Create(c iris.Context) {
 	var item schema.User
 	if err := irisplus.ParseJSON(c, &item); err != nil {
 		irisplus.ResError(c, err)
@@ -118,6 +133,7 @@ func (a *User) Create(c *iris.Context) {
 		irisplus.ResError(c, err)
 		return
 	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem.CleanSecure())
 }
 
@@ -125,24 +141,40 @@ func (a *User) Create(c *iris.Context) {
 // @Summary 更新数据
 // @Param Authorization header string false "Bearer 用户令牌"
 // @Param id path string true "记录ID"
+// @Param If-Match header string false "数据版本号(ETag)"
 // @Param body body schema.User true
 // @Success 200 schema.User
 // @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 409 schema.HTTPError "{error:{code:0,message:资源已被修改，请刷新后重试}}"
+// @Failure 412 schema.HTTPError "{error:{code:0,message:资源版本不匹配}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PUT /api/v1/users/{id}
-func (a *User) Update(c *iris.Context) {
+func (a *User) Update(c iris.Context) {
 	var item schema.User
 	if err := irisplus.ParseJSON(c, &item); err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
 
-	nitem, err := a.UserBll.Update(irisplus.NewContext(c), c.Param("id"), item)
+	ifMatch, err := irisplus.GetIfMatchVersion(c)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
+	} else if ifMatch > 0 {
+		item.Version = ifMatch
 	}
+
+	nitem, err := a.UserBll.Update(irisplus.NewContext(c), c.Params().Get("id"), item)
+	if err != nil {
+		// 通过If-Match指定的版本号不一致时响应412
+		if ifMatch > 0 && err == errors.ErrResourceConflict {
+			err = errors.ErrPreconditionFailed
+		}
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.SetETag(c, nitem.Version)
 	irisplus.ResSuccess(c, nitem.CleanSecure())
 }
 
@@ -154,8 +186,8 @@ func (a *User) Update(c *iris.Context) {
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router DELETE /api/v1/users/{id}
-func (a *User) Delete(c *iris.Context) {
-	err := a.UserBll.Delete(irisplus.NewContext(c), c.Param("id"))
+func (a *User) Delete(c iris.Context) {
+	err := a.UserBll.Delete(irisplus.NewContext(c), c.Params().Get("id"))
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -171,8 +203,8 @@ func (a *User) Delete(c *iris.Context) {
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PATCH /api/v1/users/{id}/enable
-func (a *User) Enable(c *iris.Context) {
-	err := a.UserBll.UpdateStatus(irisplus.NewContext(c), c.Param("id"), 1)
+func (a *User) Enable(c iris.Context) {
+	err := a.UserBll.UpdateStatus(irisplus.NewContext(c), c.Params().Get("id"), 1)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
@@ -188,11 +220,107 @@ func (a *User) Enable(c *iris.Context) {
 // @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
 // @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
 // @Router PATCH /api/v1/users/{id}/disable
-func (a *User) Disable(c *iris.Context) {
-	err := a.UserBll.UpdateStatus(irisplus.NewContext(c), c.Param("id"), 2)
+func (a *User) Disable(c iris.Context) {
+	err := a.UserBll.UpdateStatus(irisplus.NewContext(c), c.Params().Get("id"), 2)
 	if err != nil {
 		irisplus.ResError(c, err)
 		return
 	}
 	irisplus.ResOK(c)
 }
+
+// BatchDelete 批量删除数据
+// @Summary 批量删除数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/users/batch/delete
+func (a *User) BatchDelete(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.UserBll.BatchDelete(irisplus.NewContext(c), params)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
+
+// BatchEnable 批量启用数据
+// @Summary 批量启用数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/users/batch/enable
+func (a *User) BatchEnable(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.UserBll.BatchUpdateStatus(irisplus.NewContext(c), params, 1)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
+
+// BatchDisable 批量禁用数据
+// @Summary 批量禁用数据
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的请求参数}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/users/batch/disable
+func (a *User) BatchDisable(c iris.Context) {
+	var params schema.BatchParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.UserBll.BatchUpdateStatus(irisplus.NewContext(c), params, 2)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
+
+// BatchAssignRoles 批量分配角色
+// @Summary 批量分配角色
+// @Param Authorization header string false "Bearer 用户令牌"
+// @Param body body schema.BatchUserRoleParam true
+// @Success 200 schema.BatchResult
+// @Failure 400 schema.HTTPError "{error:{code:0,message:无效的角色}}"
+// @Failure 401 schema.HTTPError "{error:{code:0,message:未授权}}"
+// @Failure 500 schema.HTTPError "{error:{code:0,message:服务器错误}}"
+// @Router POST /api/v1/users/batch/roles
+func (a *User) BatchAssignRoles(c iris.Context) {
+	var params schema.BatchUserRoleParam
+	if err := irisplus.ParseJSON(c, &params); err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+
+	result, err := a.UserBll.BatchAssignRoles(irisplus.NewContext(c), params)
+	if err != nil {
+		irisplus.ResError(c, err)
+		return
+	}
+	irisplus.ResSuccess(c, result)
+}
@@ -14,7 +14,10 @@ func Inject(container *dig.Container) error {
 	container.Provide(NewDemo)
 	container.Provide(NewLogin)
 	container.Provide(NewMenu)
+	container.Provide(NewRecycle)
 	container.Provide(NewRole)
+	container.Provide(NewSearch)
 	container.Provide(NewUser)
+	// generator:inject
 	return nil
 }
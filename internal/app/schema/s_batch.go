@@ -0,0 +1,59 @@
+package schema
+
+// BatchParam 批量操作参数
+type BatchParam struct {
+	RecordIDs       []string `json:"record_ids" binding:"required,gt=0" swaggo:"true,记录ID列表"`
+	ContinueOnError bool     `json:"continue_on_error" swaggo:"false,是否逐项执行(true:逐项执行并返回每项的执行结果 false:全部成功或全部回滚)"`
+}
+
+// BatchUserRoleParam 批量分配用户角色参数
+type BatchUserRoleParam struct {
+	BatchParam
+	RoleIDs []string `json:"role_ids" binding:"required,gt=0" swaggo:"true,角色ID列表(在用户已有角色的基础上追加)"`
+}
+
+// BatchResult 批量操作结果
+type BatchResult struct {
+	Total   int                `json:"total" swaggo:"true,总数量"`
+	Success int                `json:"success" swaggo:"true,成功数量"`
+	Failure int                `json:"failure" swaggo:"true,失败数量"`
+	Items   []*BatchResultItem `json:"items" swaggo:"true,执行结果列表"`
+}
+
+// BatchResultItem 批量操作的数据项执行结果
+type BatchResultItem struct {
+	RecordID string `json:"record_id" swaggo:"true,记录ID"`
+	Success  bool   `json:"success" swaggo:"true,是否执行成功"`
+	Message  string `json:"message,omitempty" swaggo:"false,错误信息"`
+}
+
+// AddSuccess 添加执行成功的数据项
+func (a *BatchResult) AddSuccess(recordID string) {
+	a.Total++
+	a.Success++
+	a.Items = append(a.Items, &BatchResultItem{
+		RecordID: recordID,
+		Success:  true,
+	})
+}
+
+// AddFailure 添加执行失败的数据项
+func (a *BatchResult) AddFailure(recordID, message string) {
+	a.Total++
+	a.Failure++
+	a.Items = append(a.Items, &BatchResultItem{
+		RecordID: recordID,
+		Message:  message,
+	})
+}
+
+// SuccessIDs 获取执行成功的记录ID列表
+func (a *BatchResult) SuccessIDs() []string {
+	var ids []string
+	for _, item := range a.Items {
+		if item.Success {
+			ids = append(ids, item.RecordID)
+		}
+	}
+	return ids
+}
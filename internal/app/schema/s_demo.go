@@ -5,13 +5,15 @@ import "time"
 
 // Demo demo对象
 type Demo struct {
-	RecordID  string    `json:"record_id" swaggo:"false,记录ID"`
-	Code      string    `json:"code" binding:"required" swaggo:"true,编号"`
-	Name      string    `json:"name" binding:"required" swaggo:"true,名称"`
-	Memo      string    `json:"memo" swaggo:"false,备注"`
-	Status    int       `json:"status" binding:"required,max=2,min=1" swaggo:"true,状态(1:启用 2:停用)"`
-	Creator   string    `json:"creator" swaggo:"false,创建者"`
-	CreatedAt time.Time `json:"created_at" swaggo:"false,创建时间"`
+	RecordID  string     `json:"record_id" swaggo:"false,记录ID"`
+	Code      string     `json:"code" binding:"required" swaggo:"true,编号"`
+	Name      string     `json:"name" binding:"required" swaggo:"true,名称"`
+	Memo      string     `json:"memo" swaggo:"false,备注"`
+	Status    int        `json:"status" binding:"required,max=2,min=1" swaggo:"true,状态(1:启用 2:停用)"`
+	Creator   string     `json:"creator" swaggo:"false,创建者"`
+	Version   int        `json:"version" swaggo:"false,版本号"`
+	CreatedAt time.Time  `json:"created_at" swaggo:"false,创建时间"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" swaggo:"false,删除时间"`
 }
 
 // DemoQueryParam 查询条件
@@ -24,7 +26,9 @@ type DemoQueryParam struct {
 
 // DemoQueryOptions demo对象查询可选参数项
 type DemoQueryOptions struct {
-	PageParam *PaginationParam // 分页参数
+	PageParam   *PaginationParam // 分页参数
+	CursorParam *CursorParam     // 游标分页参数(指定时忽略分页参数)
+	QuerySpec   *QuerySpec       // 查询规格(排序、字段选择及过滤条件)
 }
 
 // DemoQueryResult demo对象查询结果
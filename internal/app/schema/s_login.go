@@ -29,7 +29,7 @@ type LoginCaptcha struct {
 
 // LoginTokenInfo 登录令牌信息
 type LoginTokenInfo struct {
-	AccessToken string `json:"access_token" swaggo:"true,访问令牌"`
+	AccessToken string `json:"access_token,omitempty" swaggo:"false,访问令牌(启用Cookie令牌时不返回)"`
 	TokenType   string `json:"token_type" swaggo:"true,令牌类型"`
 	ExpiresAt   int64  `json:"expires_at" swaggo:"true,令牌到期时间"`
 }
@@ -16,7 +16,9 @@ type Menu struct {
 	ParentID   string        `json:"parent_id" swaggo:"false,父级ID"`
 	ParentPath string        `json:"parent_path" swaggo:"false,父级路径"`
 	Creator    string        `json:"creator" swaggo:"false,创建者"`
+	Version    int           `json:"version" swaggo:"false,版本号"`
 	CreatedAt  time.Time     `json:"created_at" swaggo:"false,创建时间"`
+	DeletedAt  *time.Time    `json:"deleted_at,omitempty" swaggo:"false,删除时间"`
 	Actions    MenuActions   `json:"actions" swaggo:"false,动作列表"`
 	Resources  MenuResources `json:"resources" swaggo:"false,资源列表"`
 }
@@ -47,8 +49,10 @@ type MenuQueryParam struct {
 // MenuQueryOptions 查询可选参数项
 type MenuQueryOptions struct {
 	PageParam        *PaginationParam // 分页参数
+	CursorParam      *CursorParam     // 游标分页参数(指定时忽略分页参数)
 	IncludeActions   bool             // 包含动作列表
 	IncludeResources bool             // 包含资源列表
+	QuerySpec        *QuerySpec       // 查询规格(排序、字段选择及过滤条件)
 }
 
 // MenuQueryResult 查询结果
@@ -0,0 +1,41 @@
+package schema
+
+// 定义查询过滤条件的操作符
+const (
+	QueryOpEQ      = "eq"      // 等于
+	QueryOpNE      = "ne"      // 不等于
+	QueryOpIN      = "in"      // 包含(多个值以英文逗号分隔)
+	QueryOpLike    = "like"    // 模糊匹配
+	QueryOpGTE     = "gte"     // 大于等于
+	QueryOpLTE     = "lte"     // 小于等于
+	QueryOpBetween = "between" // 区间(两个值以英文逗号分隔)
+)
+
+// QuerySpec 通用查询规格(排序、字段选择及过滤条件)
+type QuerySpec struct {
+	Sorts   []*QuerySort   // 排序字段列表
+	Fields  []string       // 选择字段列表
+	Filters []*QueryFilter // 过滤条件列表
+}
+
+// QuerySort 排序字段
+type QuerySort struct {
+	Field string // 字段名
+	Desc  bool   // 是否降序
+}
+
+// QueryFilter 过滤条件
+type QueryFilter struct {
+	Field    string   // 字段名
+	Operator string   // 操作符
+	Values   []string // 值列表
+}
+
+// IsValidQueryOperator 检查是否是有效的操作符
+func IsValidQueryOperator(op string) bool {
+	switch op {
+	case QueryOpEQ, QueryOpNE, QueryOpIN, QueryOpLike, QueryOpGTE, QueryOpLTE, QueryOpBetween:
+		return true
+	}
+	return false
+}
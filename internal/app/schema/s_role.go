@@ -4,13 +4,16 @@ import "time"
 
 // Role 角色对象
 type Role struct {
-	RecordID  string    `json:"record_id" swaggo:"false,记录ID"`
-	Name      string    `json:"name" binding:"required" swaggo:"true,角色名称"`
-	Sequence  int       `json:"sequence" swaggo:"false,排序值"`
-	Memo      string    `json:"memo" swaggo:"false,备注"`
-	Creator   string    `json:"creator" swaggo:"false,创建者"`
-	CreatedAt time.Time `json:"created_at" swaggo:"false,创建时间"`
-	Menus     RoleMenus `json:"menus" binding:"required,gt=0" swaggo:"false,菜单权限"`
+	RecordID     string     `json:"record_id" swaggo:"false,记录ID"`
+	Name         string     `json:"name" binding:"required" swaggo:"true,角色名称"`
+	Sequence     int        `json:"sequence" swaggo:"false,排序值"`
+	Memo         string     `json:"memo" swaggo:"false,备注"`
+	AllowedCIDRs []string   `json:"allowed_cidrs" swaggo:"false,允许访问的IP地址范围(为空时不限制)"`
+	Creator      string     `json:"creator" swaggo:"false,创建者"`
+	Version      int        `json:"version" swaggo:"false,版本号"`
+	CreatedAt    time.Time  `json:"created_at" swaggo:"false,创建时间"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" swaggo:"false,删除时间"`
+	Menus        RoleMenus  `json:"menus" binding:"required,gt=0" swaggo:"false,菜单权限"`
 }
 
 // RoleMenu 角色菜单对象
@@ -31,7 +34,9 @@ type RoleQueryParam struct {
 // RoleQueryOptions 查询可选参数项
 type RoleQueryOptions struct {
 	PageParam    *PaginationParam // 分页参数
+	CursorParam  *CursorParam     // 游标分页参数(指定时忽略分页参数)
 	IncludeMenus bool             // 包含菜单权限
+	QuerySpec    *QuerySpec       // 查询规格(排序、字段选择及过滤条件)
 }
 
 // RoleQueryResult 查询结果
@@ -0,0 +1,43 @@
+package schema
+
+// 定义全文检索的数据类型
+const (
+	SearchTypeDemo = "demo" // demo
+	SearchTypeMenu = "menu" // 菜单
+	SearchTypeRole = "role" // 角色
+	SearchTypeUser = "user" // 用户
+)
+
+// SearchTypes 全部的检索数据类型
+var SearchTypes = []string{SearchTypeUser, SearchTypeRole, SearchTypeMenu, SearchTypeDemo}
+
+// SearchDocument 检索文档
+type SearchDocument struct {
+	Type     string   // 数据类型
+	RecordID string   // 记录ID
+	Title    string   // 标题
+	Contents []string // 检索内容
+}
+
+// SearchParam 检索条件
+type SearchParam struct {
+	Query string   // 检索关键字
+	Types []string // 数据类型(为空时检索全部类型)
+	Limit int      // 每种数据类型返回的最大数量
+}
+
+// SearchResult 检索结果(按数据类型分组)
+type SearchResult map[string][]*SearchItem
+
+// SearchItem 检索结果项
+type SearchItem struct {
+	RecordID string  `json:"record_id" swaggo:"true,记录ID"`
+	Title    string  `json:"title" swaggo:"true,标题"`
+	Score    float64 `json:"score" swaggo:"true,相关度得分"`
+}
+
+// SearchGroup 检索结果分组
+type SearchGroup struct {
+	Type  string        `json:"type" swaggo:"true,数据类型(user:用户 role:角色 menu:菜单 demo:demo)"`
+	Items []*SearchItem `json:"items" swaggo:"true,检索结果项"`
+}
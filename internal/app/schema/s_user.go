@@ -6,16 +6,19 @@ import (
 
 // User 用户对象
 type User struct {
-	RecordID  string    `json:"record_id" swaggo:"false,记录ID"`
-	UserName  string    `json:"user_name" binding:"required" swaggo:"true,用户名"`
-	RealName  string    `json:"real_name" binding:"required" swaggo:"true,真实姓名"`
-	Password  string    `json:"password" swaggo:"false,密码"`
-	Phone     string    `json:"phone" swaggo:"false,手机号"`
-	Email     string    `json:"email" swaggo:"false,邮箱"`
-	Status    int       `json:"status" binding:"required,max=2,min=1" swaggo:"true,用户状态(1:启用 2:停用)"`
-	Creator   string    `json:"creator" swaggo:"false,创建者"`
-	CreatedAt time.Time `json:"created_at" swaggo:"false,创建时间"`
-	Roles     UserRoles `json:"roles" binding:"required,gt=0" swaggo:"true,角色授权"`
+	RecordID     string     `json:"record_id" swaggo:"false,记录ID"`
+	UserName     string     `json:"user_name" binding:"required" swaggo:"true,用户名"`
+	RealName     string     `json:"real_name" binding:"required" swaggo:"true,真实姓名"`
+	Password     string     `json:"password" swaggo:"false,密码"`
+	Phone        string     `json:"phone" swaggo:"false,手机号"`
+	Email        string     `json:"email" swaggo:"false,邮箱"`
+	Status       int        `json:"status" binding:"required,max=2,min=1" swaggo:"true,用户状态(1:启用 2:停用)"`
+	AllowedCIDRs []string   `json:"allowed_cidrs" swaggo:"false,允许访问的IP地址范围(为空时不限制)"`
+	Creator      string     `json:"creator" swaggo:"false,创建者"`
+	Version      int        `json:"version" swaggo:"false,版本号"`
+	CreatedAt    time.Time  `json:"created_at" swaggo:"false,创建时间"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" swaggo:"false,删除时间"`
+	Roles        UserRoles  `json:"roles" binding:"required,gt=0" swaggo:"true,角色授权"`
 }
 
 // CleanSecure 清理安全数据
@@ -31,6 +34,7 @@ type UserRole struct {
 
 // UserQueryParam 查询条件
 type UserQueryParam struct {
+	RecordIDs    []string // 记录ID列表
 	UserName     string   // 用户名
 	LikeUserName string   // 用户名(模糊查询)
 	LikeRealName string   // 真实姓名(模糊查询)
@@ -41,7 +45,9 @@ type UserQueryParam struct {
 // UserQueryOptions 查询可选参数项
 type UserQueryOptions struct {
 	PageParam    *PaginationParam // 分页参数
+	CursorParam  *CursorParam     // 游标分页参数(指定时忽略分页参数)
 	IncludeRoles bool             // 包含角色权限
+	QuerySpec    *QuerySpec       // 查询规格(排序、字段选择及过滤条件)
 }
 
 // UserQueryResult 查询结果
@@ -20,6 +20,7 @@ type HTTPStatus struct {
 type HTTPList struct {
 	List       interface{}     `json:"list"`
 	Pagination *HTTPPagination `json:"pagination,omitempty"`
+	Cursor     *HTTPCursor     `json:"cursor,omitempty"`
 }
 
 // HTTPPagination HTTP分页数据
@@ -29,6 +30,15 @@ type HTTPPagination struct {
 	PageSize int `json:"pageSize"`
 }
 
+// HTTPCursor HTTP游标分页数据
+type HTTPCursor struct {
+	Next      string `json:"next,omitempty"`
+	Prev      string `json:"prev,omitempty"`
+	Limit     int    `json:"limit"`
+	Total     *int   `json:"total,omitempty"`
+	Estimated bool   `json:"estimated,omitempty"`
+}
+
 // PaginationParam 分页查询条件
 type PaginationParam struct {
 	PageIndex int // 页索引
@@ -37,5 +47,29 @@ type PaginationParam struct {
 
 // PaginationResult 分页查询结果
 type PaginationResult struct {
-	Total int // 总数据条数
+	Total  int           // 总数据条数
+	Cursor *CursorResult // 游标分页结果(仅游标分页时有值)
+}
+
+// 定义游标分页查询总数据条数的方式
+const (
+	CursorTotalNone     = "none"     // 不查询
+	CursorTotalExact    = "exact"    // 精确查询
+	CursorTotalEstimate = "estimate" // 估算(忽略查询条件，不支持估算的数据库使用精确查询)
+)
+
+// CursorParam 游标分页查询条件
+type CursorParam struct {
+	Cursor string // 游标(为空时从第一条数据开始查询)
+	Limit  int    // 查询数据条数
+	Total  string // 总数据条数的查询方式
+}
+
+// CursorResult 游标分页查询结果
+type CursorResult struct {
+	Next      string // 下一页游标(没有下一页时为空)
+	Prev      string // 上一页游标(没有上一页时为空)
+	Limit     int    // 查询数据条数
+	Counted   bool   // 是否查询了总数据条数
+	Estimated bool   // 总数据条数是否是估算值
 }
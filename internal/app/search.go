@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
+	ifulltext "github.com/wanhello/iris-admin/internal/app/model/impl/fulltext"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"go.uber.org/dig"
+)
+
+// InitSearch 初始化全文检索
+func InitSearch(container *dig.Container) error {
+	cfg := config.GetGlobalConfig()
+	if cfg.Search.Engine == "db" && cfg.Store == "gorm" {
+		return container.Invoke(func(db *gormplus.DB) error {
+			return gorm.InjectSearch(container, db)
+		})
+	}
+	return ifulltext.Inject(container)
+}
+
+// RebuildSearch 重建全文检索索引(仅内嵌全文索引需要重建)
+func RebuildSearch(ctx context.Context, container *dig.Container) error {
+	if config.GetGlobalConfig().Search.Engine == "db" {
+		return nil
+	}
+
+	return container.Invoke(func(s bll.ISearch) error {
+		return s.Rebuild(ctx)
+	})
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/pkg/secret"
+)
+
+// SecretCommand 执行敏感信息命令
+// 支持的命令：
+//
+//	genkey <file>  生成主密钥文件(文件已存在时返回错误)
+//	encrypt        使用master_key_file指定的主密钥加密标准输入的内容，输出可用于配置项的enc:值
+func SecretCommand(w io.Writer, r io.Reader, args []string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(args) == 0 {
+		return errors.New("请指定命令：genkey <file>|encrypt")
+	}
+
+	switch args[0] {
+	case "genkey":
+		if len(args) < 2 {
+			return errors.New("请指定主密钥文件")
+		}
+		key, err := secret.GenerateKey()
+		if err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(args[1], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(f, secret.EncodeKey(key)); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case "encrypt":
+		cfg, err := config.ParseConfig(o.configFiles()...)
+		if err != nil {
+			return err
+		}
+		if cfg.MasterKeyFile == "" {
+			return secret.ErrNoKey
+		}
+		key, err := secret.ReadKeyFile(cfg.MasterKeyFile)
+		if err != nil {
+			return err
+		}
+
+		buf, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		sealed, err := secret.Seal(key, []byte(strings.TrimRight(string(buf), "\r\n")))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, sealed)
+		return err
+	}
+	return fmt.Errorf("未知的命令：%s", args[0])
+}
@@ -1,14 +1,25 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/bolt"
 	"github.com/wanhello/iris-admin/internal/app/model/impl/gorm"
+	"github.com/wanhello/iris-admin/internal/app/model/impl/mongo"
+	"github.com/wanhello/iris-admin/pkg/boltplus"
 	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/logger"
+	"github.com/wanhello/iris-admin/pkg/mongoplus"
 
+	jgorm "github.com/jinzhu/gorm"
 	"go.uber.org/dig"
 
 )
@@ -30,9 +41,13 @@ func InitStore(container *dig.Container) (func(), error) {
 		}
 
 		gorm.SetTablePrefix(cfg.Gorm.TablePrefix)
-		err = gorm.AutoMigrate(db)
-		if err != nil {
-			return nil, err
+		if cfg.Gorm.AutoMigrate {
+			err = gorm.AutoMigrate(db)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			checkMigrations(db)
 		}
 
 		// 注入DB
@@ -41,6 +56,63 @@ func InitStore(container *dig.Container) (func(), error) {
 		})
 
 		gorm.Inject(container)
+	case "mongo":
+		db, err := mongoplus.New(&mongoplus.Config{
+			URI:      cfg.Mongo.URI,
+			Database: cfg.Mongo.Database,
+			Timeout:  time.Duration(cfg.Mongo.Timeout) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		storeCall = func() {
+			db.Close()
+		}
+
+		if !db.Transaction() {
+			logger.Warnf(context.Background(), "mongo数据库不支持事务(单节点部署)，事务中的操作将直接执行")
+		}
+
+		mongo.SetCollectionPrefix(cfg.Mongo.CollectionPrefix)
+		err = mongo.CreateIndexes(context.Background(), db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		// 注入DB
+		container.Provide(func() *mongoplus.DB {
+			return db
+		})
+
+		mongo.Inject(container)
+	case "bolt":
+		db, err := boltplus.New(&boltplus.Config{
+			Path:    cfg.Bolt.Path,
+			Timeout: time.Duration(cfg.Bolt.Timeout) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		storeCall = func() {
+			db.Close()
+		}
+
+		bolt.SetBucketPrefix(cfg.Bolt.BucketPrefix)
+		err = bolt.CreateBuckets(db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		// 注入DB
+		container.Provide(func() *boltplus.DB {
+			return db
+		})
+
+		bolt.Inject(container)
 	default:
 		return nil, errors.New("unknown store")
 	}
@@ -48,6 +120,23 @@ func InitStore(container *dig.Container) (func(), error) {
 	return storeCall, nil
 }
 
+// checkMigrations 检查是否存在未执行的数据库迁移
+func checkMigrations(db *gormplus.DB) {
+	ctx := context.Background()
+	m, err := gorm.NewMigrator(db, config.GetGlobalConfig().Gorm.DBType)
+	if err != nil {
+		logger.Errorf(ctx, "检查数据库迁移发生错误：%s", err.Error())
+		return
+	}
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "检查数据库迁移发生错误：%s", err.Error())
+	} else if len(pending) > 0 {
+		logger.Warnf(ctx, "存在%d个未执行的数据库迁移，请使用migrate up执行迁移", len(pending))
+	}
+}
+
 // initGorm 实例化gorm存储
 func initGorm() (*gormplus.DB, error) {
 	cfg := config.GetGlobalConfig()
@@ -67,11 +156,31 @@ func initGorm() (*gormplus.DB, error) {
 
 	return gormplus.New(&gormplus.Config{
 		Debug:        cfg.Gorm.Debug,
+		Logger:       gormLogger{},
 		DBType:       cfg.Gorm.DBType,
 		DSN:          dsn,
 		MaxIdleConns: cfg.Gorm.MaxIdleConns,
 		MaxLifetime:  cfg.Gorm.MaxLifetime,
 		MaxOpenConns: cfg.Gorm.MaxOpenConns,
+
+		Replicas:             cfg.Gorm.Replicas,
+		ReplicaCheckInterval: cfg.Gorm.ReplicaCheckInterval,
+		ReplicaMaxLag:        cfg.Gorm.ReplicaMaxLag,
 	})
 }
 
+// 去除gorm日志中的终端颜色
+var ansiColorRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// gormLogger 将gorm的日志(调试模式的SQL及错误)输出到日志模块，以隐藏其中的敏感信息
+type gormLogger struct{}
+
+func (gormLogger) Print(v ...interface{}) {
+	msg := strings.TrimSpace(ansiColorRegexp.ReplaceAllString(fmt.Sprintln(jgorm.LogFormatter(v...)...), ""))
+	span := logger.StartSpan(context.Background(), logger.SetSpanTitle("gorm"))
+	if len(v) > 0 && v[0] == "sql" {
+		span.Printf("%s", msg)
+		return
+	}
+	span.Errorf("%s", msg)
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/tracing"
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+	"github.com/wanhello/iris-admin/pkg/logger"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/dig"
+)
+
+// InitTracing 初始化链路追踪(在数据初始化之前注册数据库操作的跟踪)
+func InitTracing(ctx context.Context, container *dig.Container, version string) (func(), error) {
+	cfg := config.GetGlobalConfig()
+	c := cfg.Tracing
+	if !c.Enable {
+		return nil, nil
+	}
+
+	var file *os.File
+	var exporter sdktrace.SpanExporter
+	switch c.Exporter {
+	case "otlp":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.Endpoint)}
+		if c.URLPath != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(c.URLPath))
+		}
+		if c.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		exporter = exp
+	case "stdout":
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		exporter = exp
+	case "file":
+		os.MkdirAll(filepath.Dir(c.OutputFile), 0777)
+
+		f, err := os.OpenFile(c.OutputFile, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0666)
+		if err != nil {
+			return nil, err
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		exporter = exp
+		file = f
+	default:
+		return nil, errors.New("unknown tracing exporter")
+	}
+
+	serviceName := c.ServiceName
+	if serviceName == "" {
+		serviceName = "iris-admin"
+	}
+	res := resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Store == "gorm" {
+		err := container.Invoke(func(db *gormplus.DB) {
+			tracing.RegisterGorm(db)
+		})
+		if err != nil {
+			provider.Shutdown(ctx)
+			return nil, err
+		}
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		// 关闭时导出队列中剩余的跟踪数据
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.Errorf(ctx, err.Error())
+		}
+		if file != nil {
+			file.Close()
+		}
+	}, nil
+}
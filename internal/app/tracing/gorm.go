@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const gormSpanKey = "tracing:span"
+
+// RegisterGorm 注册数据库操作的跟踪(包括从库，仅在请求的跟踪单元中记录)
+func RegisterGorm(db *gormplus.DB) {
+	db.Each(func(name string, gdb *gorm.DB) {
+		registerGormCallbacks(name, gdb)
+	})
+}
+
+func registerGormCallbacks(name string, db *gorm.DB) {
+	system := gormSystem(db.Dialect().GetName())
+
+	cb := db.Callback()
+	cb.Create().Before("gorm:begin_transaction").Register("tracing:before_create", gormBefore(name, system, "create"))
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("tracing:after_create", gormAfter)
+	cb.Update().Before("gorm:begin_transaction").Register("tracing:before_update", gormBefore(name, system, "update"))
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("tracing:after_update", gormAfter)
+	cb.Delete().Before("gorm:begin_transaction").Register("tracing:before_delete", gormBefore(name, system, "delete"))
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("tracing:after_delete", gormAfter)
+	cb.Query().Before("gorm:query").Register("tracing:before_query", gormBefore(name, system, "query"))
+	cb.Query().After("gorm:after_query").Register("tracing:after_query", gormAfter)
+	cb.RowQuery().Before("gorm:row_query").Register("tracing:before_row_query", gormBefore(name, system, "row_query"))
+	cb.RowQuery().After("gorm:row_query").Register("tracing:after_row_query", gormAfter)
+}
+
+func gormSystem(dialect string) attribute.KeyValue {
+	switch dialect {
+	case "mysql":
+		return semconv.DBSystemMySQL
+	case "postgres":
+		return semconv.DBSystemPostgreSQL
+	case "sqlite3":
+		return semconv.DBSystemSqlite
+	}
+	return semconv.DBSystemKey.String(dialect)
+}
+
+func gormBefore(name string, system attribute.KeyValue, operation string) func(*gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		table := ""
+		if scope.Value != nil {
+			table = scope.TableName()
+		}
+
+		spanName := "db." + operation
+		if table != "" {
+			spanName += " " + table
+		}
+
+		_, span, ok := StartChild(gormplus.ScopeContext(scope), spanName,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				system,
+				semconv.DBOperationName(operation),
+				semconv.DBCollectionName(table),
+				attribute.String("db.connection", name),
+			),
+		)
+		if ok {
+			scope.InstanceSet(gormSpanKey, span)
+		}
+	}
+}
+
+func gormAfter(scope *gorm.Scope) {
+	v, ok := scope.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+
+	// 语句中的参数以占位符记录，不记录参数值
+	span.SetAttributes(semconv.DBQueryText(scope.SQL))
+
+	err := scope.DB().Error
+	if err == gorm.ErrRecordNotFound {
+		err = nil
+	}
+	End(span, err)
+}
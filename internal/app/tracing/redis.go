@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewRedisHook 创建redis命令的跟踪钩子(仅在请求的跟踪单元中记录，不记录命令参数)
+func NewRedisHook() redis.Hook {
+	return redisHook{}
+}
+
+type redisHook struct{}
+
+type redisSpanKey struct{}
+
+func (redisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return redisStart(ctx, "redis."+cmd.Name(), cmd.Name()), nil
+}
+
+func (redisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	redisEnd(ctx, cmd.Err())
+	return ctx, nil
+}
+
+func (redisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	names := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = cmd.Name()
+	}
+	return redisStart(ctx, "redis.pipeline", strings.Join(names, " ")), nil
+}
+
+func (redisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	var err error
+	for _, cmd := range cmds {
+		if cerr := cmd.Err(); cerr != nil && cerr != redis.Nil {
+			err = cerr
+			break
+		}
+	}
+	redisEnd(ctx, err)
+	return ctx, nil
+}
+
+func redisStart(ctx context.Context, spanName, operation string) context.Context {
+	sctx, span, ok := StartChild(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			semconv.DBOperationName(operation),
+		),
+	)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(sctx, redisSpanKey{}, span)
+}
+
+func redisEnd(ctx context.Context, err error) {
+	span, ok := ctx.Value(redisSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if err == redis.Nil {
+		span.SetAttributes(attribute.Bool("db.redis.nil", true))
+		err = nil
+	}
+	End(span, err)
+}
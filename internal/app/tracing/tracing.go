@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 链路追踪的组件名称
+const instrumentationName = "github.com/wanhello/iris-admin"
+
+// Tracer 获取链路追踪器(未启用链路追踪时为空实现)
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 开始一个跟踪单元
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// StartChild 开始一个子跟踪单元(上下文中不存在跟踪单元时不记录，避免产生孤立的跟踪)
+func StartChild(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span, bool) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil, false
+	}
+	ctx, span := Start(ctx, name, opts...)
+	return ctx, span, true
+}
+
+// End 结束跟踪单元(发生错误时记录错误及状态)
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
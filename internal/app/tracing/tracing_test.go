@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wanhello/iris-admin/pkg/gormplus"
+
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type tracingItem struct {
+	ID   int
+	Name string
+}
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		tp.Shutdown(context.Background())
+	})
+	return sr
+}
+
+func findAttr(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestRegisterGorm(t *testing.T) {
+	sr := setupRecorder(t)
+
+	db, err := gormplus.New(&gormplus.Config{
+		DBType:       "sqlite3",
+		DSN:          ":memory:",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	RegisterGorm(db)
+
+	// 不存在父级跟踪单元时不记录
+	if err := db.AutoMigrate(new(tracingItem)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&tracingItem{Name: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n := len(sr.Ended()); n != 0 {
+		t.Fatalf("expected no spans without parent, got %d", n)
+	}
+
+	ctx, parent := Start(context.Background(), "parent")
+	var list []*tracingItem
+	err = db.WithContext(ctx).Where("name=?", "a").Find(&list).Error
+	parent.End()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("unexpected result %v: %v", list, err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "db.query tracing_items" {
+		t.Fatalf("unexpected span name %q", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("expected db span to be a child of the parent span")
+	}
+	if v, ok := findAttr(span.Attributes(), "db.query.text"); !ok || v.AsString() == "" {
+		t.Fatal("expected db.query.text attribute")
+	}
+	if v, _ := findAttr(span.Attributes(), "db.connection"); v.AsString() != "primary" {
+		t.Fatalf("unexpected db.connection %q", v.AsString())
+	}
+}
+
+func TestRedisHook(t *testing.T) {
+	sr := setupRecorder(t)
+	hook := NewRedisHook()
+
+	cmd := redis.NewStringCmd("get", "key")
+	ctx, _ := hook.BeforeProcess(context.Background(), cmd)
+	hook.AfterProcess(ctx, cmd)
+	if n := len(sr.Ended()); n != 0 {
+		t.Fatalf("expected no spans without parent, got %d", n)
+	}
+
+	pctx, parent := Start(context.Background(), "parent")
+	ctx, _ = hook.BeforeProcess(pctx, cmd)
+	hook.AfterProcess(ctx, cmd)
+	parent.End()
+
+	spans := sr.Ended()
+	if len(spans) != 2 || spans[0].Name() != "redis.get" {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+	if spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("expected redis span to be a child of the parent span")
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/wanhello/iris-admin/internal/app/config"
+	"github.com/wanhello/iris-admin/internal/app/health"
 	"github.com/wanhello/iris-admin/internal/app/middleware"
 	"github.com/wanhello/iris-admin/internal/app/routers/api"
+	"github.com/wanhello/iris-admin/pkg/ipfilter"
 	"github.com/wanhello/iris-admin/pkg/logger"
 
 	"github.com/kataras/iris"
@@ -19,43 +21,82 @@ import (
 
 
 // InitWeb 初始化web引擎
-func InitWeb(container *dig.Container) iris.Application {
+func InitWeb(container *dig.Container) *iris.Application {
 	cfg := config.GetGlobalConfig()
-	iris.SetMode(cfg.RunMode)
 
 	app := iris.New()
-	app.NoMethod(middleware.NoMethodHandler())
-	app.NoRoute(middleware.NoRouteHandler())
+	app.Configure(iris.WithFireMethodNotAllowed)
+	if cfg.RunMode == "debug" {
+		app.Logger().SetLevel("debug")
+	} else {
+		app.Logger().SetLevel("warn")
+	}
 
 	apiPrefixes := []string{"/api/"}
 
+	// 客户端IP(仅信任来自可信代理的X-Forwarded-For请求头)
+	app.Use(middleware.ClientIPMiddleware())
+
+	// 链路追踪(在跟踪ID之前，未指定请求ID时使用链路追踪的跟踪ID)
+	if cfg.Tracing.Enable {
+		app.Use(middleware.TracingMiddleware(middleware.AllowPathPrefixNoSkipper(apiPrefixes...)))
+	}
+
 	// 跟踪ID
 	app.Use(middleware.TraceMiddleware(middleware.AllowPathPrefixNoSkipper(apiPrefixes...)))
 
 	// 访问日志
 	app.Use(middleware.LoggerMiddleware(middleware.AllowPathPrefixNoSkipper(apiPrefixes...)))
 
+	// 请求指标
+	if cfg.Metrics.Enable {
+		app.Use(middleware.MetricsMiddleware(middleware.AllowPathPrefixNoSkipper(apiPrefixes...)))
+	}
+
 	// 崩溃恢复
 	app.Use(middleware.RecoveryMiddleware())
 
+	// 安全响应头
+	if cfg.SecurityHeaders.Enable {
+		app.Use(middleware.SecurityHeadersMiddleware())
+	}
+
+	// IP访问控制(存活及就绪检查不限制)
+	if cfg.IPAccess.Enable {
+		err := container.Invoke(func(countries ipfilter.CountryLookup) {
+			app.Use(middleware.IPAccessMiddleware(countries, middleware.AllowPathPrefixSkipper("/healthz", "/readyz")))
+		})
+		handleError(err)
+	}
+
 	// 跨域请求
 	if cfg.CORS.Enable {
 		app.Use(middleware.CORSMiddleware())
 	}
 
+	// 存活及就绪检查(不经过/api路由的认证及权限校验)
+	err := container.Invoke(func(checker *health.Checker) {
+		app.Get("/healthz", iris.FromStd(checker.LivenessHandler()))
+		app.Get("/readyz", iris.FromStd(checker.ReadinessHandler()))
+	})
+	handleError(err)
+
 	// 注册/api路由
-	err := api.RegisterRouter(app, container)
+	err = api.RegisterRouter(app, container)
 	handleError(err)
 
 	// swagger文档
 	if dir := cfg.Swagger; dir != "" {
-		app.Static("/swagger", dir)
+		app.StaticWeb("/swagger", dir)
 	}
 
-	// 静态站点
+	// 未匹配的路由(配置静态站点时，非/api路由使用静态站点处理)
+	notFound := []iris.Handler{middleware.NoRouteHandler()}
 	if dir := cfg.WWW; dir != "" {
-		app.Use(middleware.WWWMiddleware(dir))
+		notFound = append([]iris.Handler{middleware.WWWMiddleware(dir, middleware.AllowPathPrefixSkipper(apiPrefixes...))}, notFound...)
 	}
+	app.OnErrorCode(http.StatusNotFound, notFound...)
+	app.OnErrorCode(http.StatusMethodNotAllowed, middleware.NoMethodHandler())
 
 	return app
 }
@@ -63,10 +104,21 @@ func InitWeb(container *dig.Container) iris.Application {
 // InitHTTPServer 初始化http服务
 func InitHTTPServer(ctx context.Context, container *dig.Container) func() {
 	cfg := config.GetGlobalConfig().HTTP
+	hcfg := config.GetGlobalConfig().Health
+
+	var checker *health.Checker
+	err := container.Invoke(func(c *health.Checker) {
+		checker = c
+	})
+	handleError(err)
+
+	app := InitWeb(container)
+	handleError(app.Build())
+
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 	srv := &http.Server{
 		Addr:         addr,
-		Handler:      InitWeb(container),
+		Handler:      app,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
@@ -79,8 +131,15 @@ func InitHTTPServer(ctx context.Context, container *dig.Container) func() {
 			logger.Errorf(ctx, err.Error())
 		}
 	}()
+	checker.SetReady(true)
 
 	return func() {
+		// 就绪检查置为失败，等待负载均衡停止转发请求后再关闭
+		checker.SetReady(false)
+		if d := hcfg.ShutdownDelay; d > 0 {
+			time.Sleep(time.Second * time.Duration(d))
+		}
+
 		ctx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(cfg.ShutdownTimeout))
 		defer cancel()
 
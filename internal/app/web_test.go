@@ -0,0 +1,271 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/internal/app/bll"
+	"github.com/wanhello/iris-admin/internal/app/config"
+	icontext "github.com/wanhello/iris-admin/internal/app/context"
+	"github.com/wanhello/iris-admin/internal/app/schema"
+	"github.com/wanhello/iris-admin/pkg/util"
+
+	"github.com/LyricTian/captcha"
+	"github.com/LyricTian/captcha/store"
+	"go.uber.org/dig"
+)
+
+// 测试用户的默认密码
+const testPassword = "123456"
+
+// 使用可读取验证码的内存存储(需要在生成验证码之前设定)
+var captchaStore = initTestCaptcha()
+
+func initTestCaptcha() store.Store {
+	s := store.NewMemoryStore(time.Minute, captcha.Expiration)
+	captcha.SetCustomStore(s)
+	return s
+}
+
+// testServer 测试服务(内存sqlite存储、内存令牌存储及casbin权限校验)
+type testServer struct {
+	handler   http.Handler
+	container *dig.Container
+}
+
+// newTestServer 创建测试服务(每次创建使用独立的存储，opts用于修改测试配置)
+func newTestServer(t *testing.T, opts ...func(*config.Config)) *testServer {
+	t.Helper()
+	err := config.LoadGlobalConfig("../../configs/config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.GetGlobalConfig()
+	cfg.RunMode = "test"
+	cfg.CasbinModelConf = "../../configs/model.conf"
+	cfg.EnableCasbin = true
+	cfg.AllowInitMenu = true
+	cfg.WWW = ""
+	cfg.Swagger = ""
+	cfg.Store = "gorm"
+	cfg.Gorm.Debug = false
+	cfg.Gorm.DBType = "sqlite3"
+	cfg.Gorm.AutoMigrate = true
+	cfg.Gorm.Replicas = nil
+	// 内存数据库只允许一个连接且不能过期，否则每个连接会打开不同的数据库
+	cfg.Gorm.MaxOpenConns = 1
+	cfg.Gorm.MaxIdleConns = 1
+	cfg.Gorm.MaxLifetime = 0
+	cfg.Sqlite3.Path = ":memory:"
+	cfg.JWTAuth.Store = "file"
+	cfg.JWTAuth.FilePath = ":memory:"
+	cfg.Captcha.Store = "memory"
+	cfg.RateLimiter.Enable = false
+	cfg.Cache.Enable = false
+	cfg.Search.Engine = "memory"
+	cfg.Recycle.Enable = false
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	container, call := BuildContainer()
+	t.Cleanup(call)
+
+	ctx := icontext.NewPrimary(context.Background())
+	if err := InitData(ctx, container); err != nil {
+		t.Fatal(err)
+	}
+	if err := RebuildSearch(ctx, container); err != nil {
+		t.Fatal(err)
+	}
+
+	healthCall, err := InitHealth(container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(healthCall)
+
+	app := InitWeb(container)
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testServer{
+		handler:   app,
+		container: container,
+	}
+}
+
+// request 发起请求(body不为空时以JSON格式提交)
+func (s *testServer) request(t *testing.T, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf []byte
+	if body != nil {
+		b, err := util.JSONMarshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf = b
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(buf))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.serve(req)
+}
+
+// requestFrom 发起经过代理转发的请求(对端地址为httptest的默认地址192.0.2.1)
+func (s *testServer) requestFrom(t *testing.T, method, path, token, forwardedFor string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.serve(req)
+}
+
+// serve 处理请求
+func (s *testServer) serve(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.handler.ServeHTTP(w, req)
+	return w
+}
+
+// expectStatus 检查响应状态码
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, w.Code, w.Body.String())
+	}
+}
+
+// decodeJSON 解析响应JSON
+func decodeJSON(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := util.JSONUnmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response %q: %s", w.Body.String(), err.Error())
+	}
+}
+
+// captcha 获取验证码ID及验证码
+func (s *testServer) captcha(t *testing.T) (string, string) {
+	t.Helper()
+	w := s.request(t, http.MethodGet, "/api/v1/pub/login/captchaid", "", nil)
+	expectStatus(t, w, http.StatusOK)
+
+	var item schema.LoginCaptcha
+	decodeJSON(t, w, &item)
+
+	digits := captchaStore.Get(item.CaptchaID, false)
+	code := make([]byte, len(digits))
+	for i, d := range digits {
+		code[i] = '0' + d
+	}
+	return item.CaptchaID, string(code)
+}
+
+// loginRequest 发起登录请求(密码使用md5加密)
+func (s *testServer) loginRequest(t *testing.T, userName, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	captchaID, code := s.captcha(t)
+	return s.request(t, http.MethodPost, "/api/v1/pub/login", "", schema.LoginParam{
+		UserName:    userName,
+		Password:    util.MD5HashString(password),
+		CaptchaID:   captchaID,
+		CaptchaCode: code,
+	})
+}
+
+// login 用户登录并返回访问令牌
+func (s *testServer) login(t *testing.T, userName, password string) string {
+	t.Helper()
+	w := s.loginRequest(t, userName, password)
+	expectStatus(t, w, http.StatusOK)
+
+	var info schema.LoginTokenInfo
+	decodeJSON(t, w, &info)
+	return info.AccessToken
+}
+
+// loginRoot 使用root用户登录
+func (s *testServer) loginRoot(t *testing.T) string {
+	t.Helper()
+	root := config.GetGlobalConfig().Root
+	return s.login(t, root.UserName, root.Password)
+}
+
+// loginAs 创建拥有指定权限的用户并登录
+func (s *testServer) loginAs(t *testing.T, userName string, perms ...string) string {
+	t.Helper()
+	s.createUser(t, userName, perms...)
+	return s.login(t, userName, testPassword)
+}
+
+// createUser 创建拥有指定权限的菜单、角色及用户(权限格式：请求方式 请求路径，例如：GET /api/v1/demos)
+func (s *testServer) createUser(t *testing.T, userName string, perms ...string) *schema.User {
+	t.Helper()
+	var user *schema.User
+	err := s.container.Invoke(func(bMenu bll.IMenu, bRole bll.IRole, bUser bll.IUser) error {
+		ctx := icontext.NewPrimary(context.Background())
+
+		menu := schema.Menu{Name: userName, Sequence: 1, Hidden: 1}
+		roleMenu := &schema.RoleMenu{}
+		for i, perm := range perms {
+			fields := strings.Fields(perm)
+			if len(fields) != 2 {
+				return fmt.Errorf("invalid permission: %s", perm)
+			}
+
+			code := fmt.Sprintf("res%d", i)
+			menu.Resources = append(menu.Resources, &schema.MenuResource{
+				Code:   code,
+				Name:   perm,
+				Method: fields[0],
+				Path:   fields[1],
+			})
+			roleMenu.Resources = append(roleMenu.Resources, code)
+		}
+
+		mitem, err := bMenu.Create(ctx, menu)
+		if err != nil {
+			return err
+		}
+		roleMenu.MenuID = mitem.RecordID
+
+		ritem, err := bRole.Create(ctx, schema.Role{
+			Name:     userName,
+			Sequence: 1,
+			Menus:    schema.RoleMenus{roleMenu},
+		})
+		if err != nil {
+			return err
+		}
+
+		user, err = bUser.Create(ctx, schema.User{
+			UserName: userName,
+			RealName: userName,
+			Password: util.MD5HashString(testPassword),
+			Status:   1,
+			Roles:    schema.UserRoles{{RoleID: ritem.RecordID}},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
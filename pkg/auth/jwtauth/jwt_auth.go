@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/wanhello/iris-admin/pkg/auth"
+	"github.com/wanhello/iris-admin/pkg/util"
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
@@ -28,6 +29,7 @@ type options struct {
 	keyfunc       jwt.Keyfunc
 	expired       int
 	tokenType     string
+	revokedFunc   func(tokenString string)
 }
 
 // Option 定义参数项
@@ -61,6 +63,13 @@ func SetExpired(expired int) Option {
 	}
 }
 
+// SetRevokedFunc 设定使用已注销令牌时的回调函数
+func SetRevokedFunc(fn func(tokenString string)) Option {
+	return func(o *options) {
+		o.revokedFunc = fn
+	}
+}
+
 // New 创建认证实例
 func New(store Storer, opts ...Option) *JWTAuth {
 	o := defaultOptions
@@ -69,7 +78,8 @@ func New(store Storer, opts ...Option) *JWTAuth {
 	}
 
 	return &JWTAuth{
-		opts: &o,
+		opts:  &o,
+		store: store,
 	}
 }
 
@@ -84,7 +94,9 @@ func (a *JWTAuth) GenerateToken(userID string) (auth.TokenInfo, error) {
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(a.opts.expired) * time.Second).Unix()
 
+	// 令牌ID保证同一秒内为同一用户生成的令牌不同(注销其中一个不影响其他令牌)
 	token := jwt.NewWithClaims(a.opts.signingMethod, &jwt.StandardClaims{
+		Id:        util.MustUUID(),
 		IssuedAt:  now.Unix(),
 		ExpiresAt: expiresAt,
 		NotBefore: now.Unix(),
@@ -107,7 +119,7 @@ func (a *JWTAuth) GenerateToken(userID string) (auth.TokenInfo, error) {
 // 解析令牌
 func (a *JWTAuth) parseToken(tokenString string) (*jwt.StandardClaims, error) {
 	token, _ := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, a.opts.keyfunc)
-	if !token.Valid {
+	if token == nil || !token.Valid {
 		return nil, auth.ErrInvalidToken
 	}
 
@@ -147,6 +159,9 @@ func (a *JWTAuth) ParseUserID(tokenString string) (string, error) {
 		if err != nil {
 			return err
 		} else if exists {
+			if fn := a.opts.revokedFunc; fn != nil {
+				fn(tokenString)
+			}
 			return auth.ErrInvalidToken
 		}
 		return nil
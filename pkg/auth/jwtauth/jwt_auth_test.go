@@ -0,0 +1,70 @@
+package jwtauth
+
+import (
+	"testing"
+
+	"github.com/wanhello/iris-admin/pkg/auth"
+	"github.com/wanhello/iris-admin/pkg/auth/jwtauth/store/buntdb"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func TestDestroyToken(t *testing.T) {
+	store, err := buntdb.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var revoked []string
+	key := []byte("test")
+	a := New(store,
+		SetSigningKey(key),
+		SetKeyfunc(func(*jwt.Token) (interface{}, error) {
+			return key, nil
+		}),
+		SetRevokedFunc(func(tokenString string) {
+			revoked = append(revoked, tokenString)
+		}),
+	)
+	defer a.Release()
+
+	tokenInfo, err := a.GenerateToken("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := tokenInfo.GetAccessToken()
+
+	userID, err := a.ParseUserID(token)
+	if err != nil || userID != "u1" {
+		t.Fatalf("unexpected user id %q: %v", userID, err)
+	}
+
+	// 同一秒内重新生成的令牌不受注销影响
+	other, err := a.GenerateToken("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.GetAccessToken() == token {
+		t.Fatal("expected distinct tokens")
+	}
+
+	if err := a.DestroyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = a.ParseUserID(token)
+	if err != auth.ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken for destroyed token, got %v", err)
+	}
+	if len(revoked) != 1 || revoked[0] != token {
+		t.Fatalf("unexpected revoked tokens: %v", revoked)
+	}
+	if _, err := a.ParseUserID(other.GetAccessToken()); err != nil {
+		t.Fatalf("unexpected error for other token: %v", err)
+	}
+
+	_, err = a.ParseUserID("invalid")
+	if err != auth.ErrInvalidToken || len(revoked) != 1 {
+		t.Fatalf("unexpected result for invalid token: %v, %v", err, revoked)
+	}
+}
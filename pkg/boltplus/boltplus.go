@@ -0,0 +1,95 @@
+package boltplus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// ErrTxNotWritable 在只读事务中执行写操作
+var ErrTxNotWritable = errors.New("tx not writable")
+
+// Config 配置参数
+type Config struct {
+	Path    string        // 数据文件路径
+	Timeout time.Duration // 获取文件锁的超时时间(默认1秒，数据文件同时只允许一个进程打开)
+}
+
+// New 创建DB实例(数据文件所在目录不存在时自动创建)
+func New(c *Config) (*DB, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+
+	err := os.MkdirAll(filepath.Dir(c.Path), 0777)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := bbolt.Open(c.Path, 0600, &bbolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(db), nil
+}
+
+// Wrap 包装bolt数据库
+func Wrap(db *bbolt.DB) *DB {
+	return &DB{DB: db}
+}
+
+// DB bolt扩展DB
+type DB struct {
+	*bbolt.DB
+}
+
+// CreateBuckets 创建存储桶(已存在时忽略)
+func (d *DB) CreateBuckets(names ...[]byte) error {
+	return d.Update(func(tx *bbolt.Tx) error {
+		for _, name := range names {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Exec 执行存储操作(tx不为空时在tx中执行，否则开启新的事务执行)
+// bolt同时只允许一个读写事务，在读写事务中不能再开启新的读写事务，因此事务中的操作必须使用tx执行
+func (d *DB) Exec(tx *bbolt.Tx, writable bool, fn func(*bbolt.Tx) error) error {
+	if tx != nil {
+		if writable && !tx.Writable() {
+			return ErrTxNotWritable
+		}
+		return fn(tx)
+	}
+
+	if writable {
+		return d.Update(fn)
+	}
+	return d.View(fn)
+}
+
+// Scan 遍历存储桶中的数据(存储桶不存在时忽略)
+func Scan(tx *bbolt.Tx, name []byte, fn func(k, v []byte) error) error {
+	b := tx.Bucket(name)
+	if b == nil {
+		return nil
+	}
+	return b.ForEach(fn)
+}
+
+// Count 获取存储桶中的数据条数(存储桶不存在时返回0)
+func Count(tx *bbolt.Tx, name []byte) int {
+	b := tx.Bucket(name)
+	if b == nil {
+		return 0
+	}
+	return b.Stats().KeyN
+}
@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Store 缓存存储接口
+type Store interface {
+	// 获取缓存数据
+	Get(key string) ([]byte, bool, error)
+	// 设定缓存数据(expiration为0时不过期)
+	Set(key string, value []byte, expiration time.Duration) error
+	// 获取命名空间的版本号
+	Version(namespace string) (int64, error)
+	// 递增命名空间的版本号(命名空间下已有的缓存数据全部失效)
+	IncrVersion(namespace string) error
+	// 关闭存储
+	Close() error
+}
+
+// 支持附加上下文的缓存存储(如redis存储，上下文用于链路追踪)
+type contextStore interface {
+	WithContext(ctx context.Context) Store
+}
+
+type options struct {
+	expiration   time.Duration
+	errorHandler func(error)
+}
+
+var defaultOptions = options{
+	expiration: time.Minute,
+}
+
+// Option 定义配置项
+type Option func(*options)
+
+// SetExpiration 设定缓存过期时间
+func SetExpiration(expiration time.Duration) Option {
+	return func(o *options) {
+		o.expiration = expiration
+	}
+}
+
+// SetErrorHandler 设定缓存存储的错误处理(存储发生错误时直接查询数据，不影响业务)
+func SetErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = fn
+	}
+}
+
+// New 创建缓存实例
+func New(store Store, opts ...Option) *Cache {
+	o := defaultOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Cache{
+		store: store,
+		opts:  o,
+		stats: make(map[string]*counter),
+	}
+}
+
+// Cache 查询缓存
+// 缓存键由命名空间、命名空间版本号及查询键组成，递增命名空间的版本号即可使该命名空间下的缓存全部失效；
+// 缓存数据以json格式存储，每次读取都会得到新的数据副本
+type Cache struct {
+	store Store
+	opts  options
+	lock  sync.RWMutex
+	stats map[string]*counter
+}
+
+type counter struct {
+	hits          int64
+	misses        int64
+	invalidations int64
+}
+
+// Stats 缓存命中统计
+type Stats struct {
+	Namespace     string  // 命名空间
+	Hits          int64   // 命中次数
+	Misses        int64   // 未命中次数
+	Invalidations int64   // 失效次数(仅统计本实例发起的失效)
+	HitRate       float64 // 命中率
+}
+
+// Load 加载缓存数据(v为接收数据的指针)
+// 缓存未命中时调用fn将数据查询到v中，并写入缓存(nil数据不缓存)；
+// 命名空间的版本号在查询数据之前获取，查询期间发生的失效不会被覆盖
+func (c *Cache) Load(ctx context.Context, namespace, key string, v interface{}, fn func() error) error {
+	store := c.getStore(ctx)
+	version, err := store.Version(namespace)
+	if err != nil {
+		c.handleError(err)
+		return fn()
+	}
+
+	ckey := fmt.Sprintf("%s:%d:%s", namespace, version, key)
+	data, ok, err := store.Get(ckey)
+	if err != nil {
+		c.handleError(err)
+	} else if ok {
+		if err := json.Unmarshal(data, v); err == nil {
+			c.counter(namespace).hit()
+			return nil
+		}
+	}
+
+	c.counter(namespace).miss()
+	err = fn()
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(v)
+	if err != nil {
+		c.handleError(err)
+		return nil
+	} else if string(data) == "null" {
+		return nil
+	}
+
+	if err := store.Set(ckey, data, c.opts.expiration); err != nil {
+		c.handleError(err)
+	}
+	return nil
+}
+
+// Invalidate 使指定命名空间的缓存失效
+func (c *Cache) Invalidate(ctx context.Context, namespaces ...string) error {
+	store := c.getStore(ctx)
+	for _, ns := range namespaces {
+		err := store.IncrVersion(ns)
+		if err != nil {
+			return err
+		}
+		c.counter(ns).invalidate()
+	}
+	return nil
+}
+
+// Stats 获取缓存命中统计(按命名空间排序)
+func (c *Cache) Stats() []*Stats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	result := make([]*Stats, 0, len(c.stats))
+	for ns, item := range c.stats {
+		s := &Stats{
+			Namespace:     ns,
+			Hits:          atomic.LoadInt64(&item.hits),
+			Misses:        atomic.LoadInt64(&item.misses),
+			Invalidations: atomic.LoadInt64(&item.invalidations),
+		}
+		if total := s.Hits + s.Misses; total > 0 {
+			s.HitRate = float64(s.Hits) / float64(total)
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result
+}
+
+// Close 关闭缓存存储
+func (c *Cache) Close() error {
+	return c.store.Close()
+}
+
+func (c *Cache) getStore(ctx context.Context) Store {
+	if s, ok := c.store.(contextStore); ok && ctx != nil {
+		return s.WithContext(ctx)
+	}
+	return c.store
+}
+
+func (c *Cache) handleError(err error) {
+	if c.opts.errorHandler != nil {
+		c.opts.errorHandler(err)
+	}
+}
+
+func (c *Cache) counter(namespace string) *counter {
+	c.lock.RLock()
+	item, ok := c.stats[namespace]
+	c.lock.RUnlock()
+	if ok {
+		return item
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if item, ok := c.stats[namespace]; ok {
+		return item
+	}
+	item = new(counter)
+	c.stats[namespace] = item
+	return item
+}
+
+func (a *counter) hit() {
+	atomic.AddInt64(&a.hits, 1)
+}
+
+func (a *counter) miss() {
+	atomic.AddInt64(&a.misses, 1)
+}
+
+func (a *counter) invalidate() {
+	atomic.AddInt64(&a.invalidations, 1)
+}
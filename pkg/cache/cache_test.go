@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type testItem struct {
+	Name  string
+	Roles []string
+}
+
+func TestCacheLoad(t *testing.T) {
+	c := New(NewMemoryStore(10))
+	calls := 0
+	load := func() (*testItem, error) {
+		var item *testItem
+		err := c.Load(context.Background(), "user", "get:1", &item, func() error {
+			calls++
+			item = &testItem{Name: "admin", Roles: []string{}}
+			return nil
+		})
+		return item, err
+	}
+
+	for i := 0; i < 3; i++ {
+		item, err := load()
+		if err != nil || item == nil || item.Name != "admin" || item.Roles == nil {
+			t.Fatalf("Not expected value:%v,%v", item, err)
+		}
+		item.Name = "changed"
+	}
+	if calls != 1 {
+		t.Errorf("Not expected calls:%d", calls)
+	}
+
+	err := c.Invalidate(context.Background(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	load()
+	if calls != 2 {
+		t.Errorf("Not expected calls:%d", calls)
+	}
+
+	stats := c.Stats()
+	if len(stats) != 1 || stats[0].Hits != 2 || stats[0].Misses != 2 || stats[0].Invalidations != 1 || stats[0].HitRate != 0.5 {
+		t.Errorf("Not expected stats:%+v", stats[0])
+	}
+}
+
+func TestCacheLoadNil(t *testing.T) {
+	c := New(NewMemoryStore(10))
+	calls := 0
+	for i := 0; i < 2; i++ {
+		var item *testItem
+		c.Load(context.Background(), "user", "get:2", &item, func() error {
+			calls++
+			return nil
+		})
+	}
+	if calls != 2 {
+		t.Errorf("Not expected calls:%d", calls)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore(2)
+	s.Set("a", []byte("1"), 0)
+	s.Set("b", []byte("2"), 0)
+	s.Get("a")
+	s.Set("c", []byte("3"), 0)
+	if _, ok, _ := s.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if _, ok, _ := s.Get("a"); !ok {
+		t.Error("Expected a to be cached")
+	}
+
+	s.Set("d", []byte("4"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := s.Get("d"); ok {
+		t.Error("Expected d to be expired")
+	}
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// NewMemoryStore 创建基于内存的LRU缓存存储(size为最大缓存数据条数)
+// 内存存储仅在当前进程内有效，多实例部署时各实例的缓存失效互不通知，请使用redis存储
+func NewMemoryStore(size int) *MemoryStore {
+	if size <= 0 {
+		size = 10000
+	}
+	return &MemoryStore{
+		size:     size,
+		items:    make(map[string]*list.Element),
+		list:     list.New(),
+		versions: make(map[string]int64),
+	}
+}
+
+// MemoryStore 内存LRU缓存存储
+type MemoryStore struct {
+	lock     sync.Mutex
+	size     int
+	items    map[string]*list.Element
+	list     *list.List
+	versions map[string]int64
+}
+
+type memoryItem struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// Get 获取缓存数据
+func (a *MemoryStore) Get(key string) ([]byte, bool, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	e, ok := a.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := e.Value.(*memoryItem)
+	if !item.expireAt.IsZero() && time.Now().After(item.expireAt) {
+		a.remove(e)
+		return nil, false, nil
+	}
+	a.list.MoveToFront(e)
+	return item.value, true, nil
+}
+
+// Set 设定缓存数据
+func (a *MemoryStore) Set(key string, value []byte, expiration time.Duration) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	item := &memoryItem{
+		key:   key,
+		value: value,
+	}
+	if expiration > 0 {
+		item.expireAt = time.Now().Add(expiration)
+	}
+
+	if e, ok := a.items[key]; ok {
+		e.Value = item
+		a.list.MoveToFront(e)
+		return nil
+	}
+
+	a.items[key] = a.list.PushFront(item)
+	for a.list.Len() > a.size {
+		a.remove(a.list.Back())
+	}
+	return nil
+}
+
+// Version 获取命名空间的版本号
+func (a *MemoryStore) Version(namespace string) (int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.versions[namespace], nil
+}
+
+// IncrVersion 递增命名空间的版本号(旧版本的缓存数据不再被访问，由LRU淘汰或过期清理)
+func (a *MemoryStore) IncrVersion(namespace string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.versions[namespace]++
+	return nil
+}
+
+// Close 关闭存储
+func (a *MemoryStore) Close() error {
+	return nil
+}
+
+func (a *MemoryStore) remove(e *list.Element) {
+	a.list.Remove(e)
+	delete(a.items, e.Value.(*memoryItem).key)
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/wanhello/iris-admin/pkg/redisplus"
+
+	"github.com/go-redis/redis"
+)
+
+// NewRedisStore 创建基于redis的缓存存储
+// 缓存数据及命名空间版本号都存储在redis中，多实例部署时任一实例使缓存失效对所有实例立即生效
+func NewRedisStore(cli *redisplus.Client) *RedisStore {
+	return &RedisStore{
+		cli: cli,
+	}
+}
+
+// RedisStore redis缓存存储
+type RedisStore struct {
+	cli *redisplus.Client
+}
+
+// WithContext 附加上下文(redis命令使用该上下文执行，供钩子函数获取，如链路追踪)
+func (a *RedisStore) WithContext(ctx context.Context) Store {
+	return &RedisStore{
+		cli: a.cli.WithContext(ctx),
+	}
+}
+
+// Get 获取缓存数据
+func (a *RedisStore) Get(key string) ([]byte, bool, error) {
+	data, err := a.cli.Get(a.cli.Key(key)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set 设定缓存数据
+func (a *RedisStore) Set(key string, value []byte, expiration time.Duration) error {
+	return a.cli.Set(a.cli.Key(key), value, expiration).Err()
+}
+
+// Version 获取命名空间的版本号
+func (a *RedisStore) Version(namespace string) (int64, error) {
+	v, err := a.cli.Get(a.versionKey(namespace)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return v, err
+}
+
+// IncrVersion 递增命名空间的版本号(旧版本的缓存数据由redis过期清理)
+func (a *RedisStore) IncrVersion(namespace string) error {
+	return a.cli.Incr(a.versionKey(namespace)).Err()
+}
+
+// Close 关闭存储(redis客户端可能被多个存储共用，由创建方关闭)
+func (a *RedisStore) Close() error {
+	return nil
+}
+
+func (a *RedisStore) versionKey(namespace string) string {
+	return a.cli.Key("version:" + namespace)
+}
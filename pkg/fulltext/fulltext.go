@@ -0,0 +1,241 @@
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Hit 检索命中的文档
+type Hit struct {
+	ID    string  // 文档ID
+	Title string  // 文档标题
+	Score float64 // 相关度得分
+}
+
+type docKey struct {
+	typ string
+	id  string
+}
+
+type document struct {
+	title string
+	terms map[string]int
+}
+
+// NewIndex 创建内存全文索引
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[docKey]*document),
+		postings: make(map[string]map[docKey]int),
+	}
+}
+
+// Index 内存全文索引(倒排索引，支持中文的二元分词及英文的前缀匹配)
+type Index struct {
+	lock     sync.RWMutex
+	docs     map[docKey]*document
+	postings map[string]map[docKey]int
+}
+
+// Add 添加文档(文档已存在时替换)
+func (a *Index) Add(typ, id, title string, texts ...string) {
+	key := docKey{typ: typ, id: id}
+	doc := &document{
+		title: title,
+		terms: make(map[string]int),
+	}
+	for _, term := range Tokenize(strings.Join(append([]string{title}, texts...), " ")) {
+		doc.terms[term]++
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.remove(key)
+	a.docs[key] = doc
+	for term, tf := range doc.terms {
+		p, ok := a.postings[term]
+		if !ok {
+			p = make(map[docKey]int)
+			a.postings[term] = p
+		}
+		p[key] = tf
+	}
+}
+
+// Remove 删除文档
+func (a *Index) Remove(typ string, ids ...string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, id := range ids {
+		a.remove(docKey{typ: typ, id: id})
+	}
+}
+
+func (a *Index) remove(key docKey) {
+	doc, ok := a.docs[key]
+	if !ok {
+		return
+	}
+
+	for term := range doc.terms {
+		p := a.postings[term]
+		delete(p, key)
+		if len(p) == 0 {
+			delete(a.postings, term)
+		}
+	}
+	delete(a.docs, key)
+}
+
+// Count 获取文档数量
+func (a *Index) Count() int {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return len(a.docs)
+}
+
+// Search 检索文档(所有检索词都匹配时命中)，按文档类型分组返回，
+// 每种类型最多返回limit条(小于等于0时不限制)，types为空时检索全部类型
+func (a *Index) Search(query string, types []string, limit int) map[string][]*Hit {
+	queryTerms := Tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	typeSet := make(map[string]bool)
+	for _, typ := range types {
+		typeSet[typ] = true
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	total := float64(len(a.docs))
+	var scores map[docKey]float64
+	for i, qt := range queryTerms {
+		matched := make(map[docKey]float64)
+		for term, p := range a.postings {
+			if term != qt && !(isPrefixTerm(qt) && strings.HasPrefix(term, qt)) {
+				continue
+			}
+
+			idf := 1 + math.Log(total/float64(len(p)))
+			for key, tf := range p {
+				if len(typeSet) > 0 && !typeSet[key.typ] {
+					continue
+				}
+				if i > 0 {
+					if _, ok := scores[key]; !ok {
+						continue
+					}
+				}
+				matched[key] += float64(tf) * idf
+			}
+		}
+
+		if i > 0 {
+			for key, score := range scores {
+				if v, ok := matched[key]; ok {
+					matched[key] = v + score
+				}
+			}
+		}
+		scores = matched
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	result := make(map[string][]*Hit)
+	for key, score := range scores {
+		result[key.typ] = append(result[key.typ], &Hit{
+			ID:    key.id,
+			Title: a.docs[key].title,
+			Score: score,
+		})
+	}
+
+	for typ, hits := range result {
+		sort.Slice(hits, func(i, j int) bool {
+			if hits[i].Score == hits[j].Score {
+				return hits[i].ID < hits[j].ID
+			}
+			return hits[i].Score > hits[j].Score
+		})
+		if limit > 0 && len(hits) > limit {
+			result[typ] = hits[:limit]
+		}
+	}
+	return result
+}
+
+// Tokenize 分词(英文及数字按单词切分并转为小写，中日韩文字使用二元分词)
+func Tokenize(s string) []string {
+	var terms []string
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			terms = append(terms, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		switch len(cjk) {
+		case 0:
+		case 1:
+			terms = append(terms, string(cjk))
+		default:
+			for i := 0; i < len(cjk)-1; i++ {
+				terms = append(terms, string(cjk[i:i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range s {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return terms
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// 非中日韩文字及单个中日韩文字的检索词使用前缀匹配
+func isPrefixTerm(term string) bool {
+	runes := []rune(term)
+	if len(runes) == 1 {
+		return true
+	}
+
+	for _, r := range runes {
+		if isCJK(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package fulltext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	terms := Tokenize("Admin 系统管理员, root-01")
+	expected := []string{"admin", "系统", "统管", "管理", "理员", "root", "01"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("Not expected value:%v", terms)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("user", "u1", "admin", "系统管理员", "admin@example.com")
+	idx.Add("user", "u2", "tom", "普通用户")
+	idx.Add("role", "r1", "管理员", "拥有全部权限")
+
+	result := idx.Search("管理员", nil, 0)
+	if len(result["user"]) != 1 || result["user"][0].ID != "u1" ||
+		len(result["role"]) != 1 || result["role"][0].ID != "r1" {
+		t.Errorf("Not expected value:%v", result)
+	}
+
+	result = idx.Search("adm 管理", []string{"user"}, 0)
+	if len(result) != 1 || len(result["user"]) != 1 || result["user"][0].Title != "admin" {
+		t.Errorf("Not expected value:%v", result)
+	}
+
+	idx.Add("user", "u1", "admin", "超级用户")
+	if result = idx.Search("管理员", []string{"user"}, 0); len(result) != 0 {
+		t.Errorf("Not expected value:%v", result)
+	}
+
+	result = idx.Search("用户", nil, 1)
+	if len(result["user"]) != 1 {
+		t.Errorf("Not expected value:%v", result)
+	}
+
+	idx.Remove("role", "r1")
+	if result = idx.Search("权限", nil, 0); len(result) != 0 || idx.Count() != 2 {
+		t.Errorf("Not expected value:%v", result)
+	}
+}
@@ -1,6 +1,9 @@
 package gormplus
 
 import (
+	"context"
+	"database/sql"
+	"strconv"
 	"time"
 
 	"github.com/jinzhu/gorm"
@@ -11,46 +14,134 @@ import (
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 )
 
+// Logger 定义gorm的日志输出(调试模式的SQL日志及错误日志)
+type Logger interface {
+	Print(v ...interface{})
+}
+
 // Config 配置参数
 type Config struct {
 	Debug        bool
+	Logger       Logger // 日志输出(默认输出到标准输出)
 	DBType       string
 	DSN          string
 	MaxLifetime  int
 	MaxOpenConns int
 	MaxIdleConns int
+
+	Replicas             []string // 从库连接串(指定时启用读写分离)
+	ReplicaCheckInterval int      // 从库健康检查的间隔时间(单位秒，默认10秒)
+	ReplicaMaxLag        int      // 从库允许的最大复制延迟(单位秒，超过时暂停使用该从库，0表示不检查延迟)
+	ReplicaLagFunc       LagFunc  // 自定义从库复制延迟的查询方法(默认根据数据库类型查询)
 }
 
 // New 创建DB实例
 func New(c *Config) (*DB, error) {
-	db, err := gorm.Open(c.DBType, c.DSN)
+	db, err := open(c, c.DSN)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(c.Replicas) == 0 {
+		return &DB{DB: db}, nil
+	}
+
+	replicas, err := newReplicaSet(c)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DB{DB: db, replicas: replicas}, nil
+}
+
+func open(c *Config, dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(c.DBType, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Logger != nil {
+		db.SetLogger(c.Logger)
+	}
 	if c.Debug {
 		db = db.Debug()
 	}
 
 	err = db.DB().Ping()
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	db.DB().SetMaxIdleConns(c.MaxIdleConns)
 	db.DB().SetMaxOpenConns(c.MaxOpenConns)
 	db.DB().SetConnMaxLifetime(time.Duration(c.MaxLifetime) * time.Second)
-	return &DB{db}, nil
+	return db, nil
 }
 
 // Wrap 包装gorm
 func Wrap(db *gorm.DB) *DB {
-	return &DB{db}
+	return &DB{DB: db}
 }
 
 // DB gorm扩展DB
 type DB struct {
 	*gorm.DB
+	replicas *replicaSet
+}
+
+// Replica 获取可用的从库(轮询选择健康的从库，没有可用的从库时返回主库)
+func (d *DB) Replica() *DB {
+	if d.replicas != nil {
+		if db := d.replicas.pick(); db != nil {
+			return &DB{DB: db}
+		}
+	}
+	return d
+}
+
+// ReplicaStatus 获取从库的健康状态
+func (d *DB) ReplicaStatus() []*ReplicaStatus {
+	if d.replicas == nil {
+		return nil
+	}
+	return d.replicas.status()
+}
+
+// Each 遍历主库及所有从库的连接(名称为primary或replica-序号)
+func (d *DB) Each(fn func(name string, db *gorm.DB)) {
+	fn("primary", d.DB)
+	if d.replicas != nil {
+		for _, r := range d.replicas.replicas {
+			fn("replica-"+strconv.Itoa(r.index), r.db)
+		}
+	}
+}
+
+// 存储上下文的键(gorm设置项)
+const contextKey = "gormplus:context"
+
+// WithContext 附加上下文(供回调函数获取，如链路追踪)
+func (d *DB) WithContext(ctx context.Context) *DB {
+	return &DB{DB: d.DB.Set(contextKey, ctx), replicas: d.replicas}
+}
+
+// ScopeContext 获取附加到当前操作的上下文(未附加时返回context.Background())
+func ScopeContext(scope *gorm.Scope) context.Context {
+	if v, ok := scope.Get(contextKey); ok {
+		if ctx, ok := v.(context.Context); ok && ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// Close 关闭数据库连接(包括从库)
+func (d *DB) Close() error {
+	if d.replicas != nil {
+		d.replicas.close()
+	}
+	return d.DB.Close()
 }
 
 // FindPage 查询分页数据
@@ -82,6 +173,32 @@ func (d *DB) FindPage(db *gorm.DB, pageIndex, pageSize int, out interface{}) (in
 	return count, nil
 }
 
+// EstimateCount 估算数据表的总数据条数(忽略查询条件)
+// 仅支持mysql及postgres(从数据库的统计信息中读取)，其它数据库使用db精确查询，
+// estimated返回总数据条数是否是估算值
+func (d *DB) EstimateCount(db *gorm.DB, tableName string) (count int, estimated bool, err error) {
+	var query string
+	switch db.Dialect().GetName() {
+	case "mysql":
+		query = "SELECT IFNULL(TABLE_ROWS,0) FROM information_schema.TABLES WHERE TABLE_SCHEMA=DATABASE() AND TABLE_NAME=?"
+	case "postgres":
+		query = "SELECT CAST(reltuples AS BIGINT) FROM pg_class WHERE relname=?"
+	default:
+		err = db.Count(&count).Error
+		return
+	}
+
+	err = d.DB.Raw(query, tableName).Row().Scan(&count)
+	if err == sql.ErrNoRows {
+		err = db.Count(&count).Error
+		return
+	} else if err != nil {
+		return
+	}
+	estimated = true
+	return
+}
+
 // FindOne 查询单条数据
 func (d *DB) FindOne(db *gorm.DB, out interface{}) (bool, error) {
 	result := db.First(out)
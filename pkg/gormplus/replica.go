@@ -0,0 +1,229 @@
+package gormplus
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// LagFunc 查询从库的复制延迟
+type LagFunc func(db *sql.DB) (time.Duration, error)
+
+// ReplicaStatus 从库健康状态
+type ReplicaStatus struct {
+	Index     int           // 从库序号(与配置的顺序一致)
+	Healthy   bool          // 是否可用
+	Lag       time.Duration // 复制延迟
+	Error     string        // 不可用的原因
+	CheckedAt time.Time     // 检查时间
+}
+
+type replica struct {
+	index  int
+	db     *gorm.DB
+	lock   sync.RWMutex
+	status ReplicaStatus
+}
+
+func (r *replica) healthy() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.status.Healthy
+}
+
+// 从库集合(定时检查从库的连通性及复制延迟，不可用的从库暂停使用，恢复后重新加入)
+type replicaSet struct {
+	replicas []*replica
+	next     uint32
+	lagFunc  LagFunc
+	maxLag   time.Duration
+	stop     chan struct{}
+	once     sync.Once
+}
+
+func newReplicaSet(c *Config) (*replicaSet, error) {
+	s := &replicaSet{
+		lagFunc: c.ReplicaLagFunc,
+		maxLag:  time.Duration(c.ReplicaMaxLag) * time.Second,
+		stop:    make(chan struct{}),
+	}
+	if s.lagFunc == nil {
+		s.lagFunc = defaultLagFunc(c.DBType)
+	}
+
+	for i, dsn := range c.Replicas {
+		db, err := open(c, dsn)
+		if err != nil {
+			s.closeDB()
+			return nil, fmt.Errorf("连接从库[%d]发生错误：%s", i, err.Error())
+		}
+		s.replicas = append(s.replicas, &replica{
+			index:  i,
+			db:     db,
+			status: ReplicaStatus{Index: i},
+		})
+	}
+
+	s.check()
+
+	interval := time.Duration(c.ReplicaCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	go s.run(interval)
+	return s, nil
+}
+
+func (s *replicaSet) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.check()
+		}
+	}
+}
+
+// 检查所有从库的健康状态
+func (s *replicaSet) check() {
+	for _, r := range s.replicas {
+		status := ReplicaStatus{
+			Index:     r.index,
+			Healthy:   true,
+			CheckedAt: time.Now(),
+		}
+
+		if err := r.db.DB().Ping(); err != nil {
+			status.Healthy = false
+			status.Error = err.Error()
+		} else if s.maxLag > 0 && s.lagFunc != nil {
+			lag, err := s.lagFunc(r.db.DB())
+			if err != nil {
+				status.Healthy = false
+				status.Error = err.Error()
+			} else {
+				status.Lag = lag
+				if lag > s.maxLag {
+					status.Healthy = false
+					status.Error = fmt.Sprintf("复制延迟(%s)超过允许的最大值(%s)", lag, s.maxLag)
+				}
+			}
+		}
+
+		r.lock.Lock()
+		r.status = status
+		r.lock.Unlock()
+	}
+}
+
+// 轮询选择健康的从库
+func (s *replicaSet) pick() *gorm.DB {
+	n := len(s.replicas)
+	start := int(atomic.AddUint32(&s.next, 1))
+	for i := 0; i < n; i++ {
+		r := s.replicas[(start+i)%n]
+		if r.healthy() {
+			return r.db
+		}
+	}
+	return nil
+}
+
+func (s *replicaSet) status() []*ReplicaStatus {
+	result := make([]*ReplicaStatus, len(s.replicas))
+	for i, r := range s.replicas {
+		r.lock.RLock()
+		status := r.status
+		r.lock.RUnlock()
+		result[i] = &status
+	}
+	return result
+}
+
+func (s *replicaSet) close() {
+	s.once.Do(func() {
+		close(s.stop)
+		s.closeDB()
+	})
+}
+
+func (s *replicaSet) closeDB() {
+	for _, r := range s.replicas {
+		r.db.Close()
+	}
+}
+
+// 根据数据库类型获取默认的复制延迟查询方法
+func defaultLagFunc(dbType string) LagFunc {
+	switch dbType {
+	case "mysql":
+		return mysqlLag
+	case "postgres":
+		return postgresLag
+	}
+	return nil
+}
+
+// mysql从库的复制延迟(SHOW SLAVE STATUS中的Seconds_Behind_Master)
+func mysqlLag(db *sql.DB) (time.Duration, error) {
+	rows, err := db.Query("SHOW SLAVE STATUS")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	err = rows.Scan(dest...)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, col := range columns {
+		if col != "Seconds_Behind_Master" {
+			continue
+		} else if values[i] == nil {
+			return 0, errors.New("从库复制已停止")
+		}
+
+		seconds, err := strconv.ParseInt(string(values[i]), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, nil
+}
+
+// postgres从库的复制延迟(已接收的日志全部重放时视为没有延迟)
+func postgresLag(db *sql.DB) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	err := db.QueryRow(`SELECT CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END`).Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
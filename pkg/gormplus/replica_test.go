@@ -0,0 +1,73 @@
+package gormplus
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplica(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gormplus")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	primary := filepath.Join(dir, "primary.db")
+	secondary := filepath.Join(dir, "replica.db")
+	for name, dsn := range map[string]string{"primary": primary, "replica": secondary} {
+		db, err := sql.Open("sqlite3", dsn)
+		assert.Nil(t, err)
+		_, err = db.Exec("CREATE TABLE t_node (name VARCHAR(20))")
+		assert.Nil(t, err)
+		_, err = db.Exec("INSERT INTO t_node (name) VALUES (?)", name)
+		assert.Nil(t, err)
+		db.Close()
+	}
+
+	var lag int64
+	db, err := New(&Config{
+		DBType:        "sqlite3",
+		DSN:           primary,
+		MaxOpenConns:  1,
+		Replicas:      []string{secondary},
+		ReplicaMaxLag: 1,
+		ReplicaLagFunc: func(*sql.DB) (time.Duration, error) {
+			return time.Duration(atomic.LoadInt64(&lag)), nil
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	node := func(db *DB) string {
+		var name string
+		assert.Nil(t, db.Raw("SELECT name FROM t_node").Row().Scan(&name))
+		return name
+	}
+
+	assert.Equal(t, "primary", node(db))
+	assert.Equal(t, "replica", node(db.Replica()))
+
+	// 复制延迟超过最大值时使用主库
+	atomic.StoreInt64(&lag, int64(5*time.Second))
+	db.replicas.check()
+	assert.Equal(t, "primary", node(db.Replica()))
+
+	status := db.ReplicaStatus()
+	assert.Len(t, status, 1)
+	assert.False(t, status[0].Healthy)
+	assert.Equal(t, 5*time.Second, status[0].Lag)
+
+	atomic.StoreInt64(&lag, 0)
+	db.replicas.check()
+	assert.Equal(t, "replica", node(db.Replica()))
+
+	// 没有从库时返回主库
+	assert.Equal(t, "primary", node(Wrap(db.DB).Replica()))
+}
@@ -0,0 +1,128 @@
+package ipfilter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CountryLookup IP地址所属国家/地区查询(可替换为其他IP地理位置库的实现)
+type CountryLookup interface {
+	// 获取IP地址所属国家/地区的ISO 3166-1两位字母代码(大写)，未知时返回空字符串
+	Country(ip net.IP) string
+}
+
+// 国家/地区的IP地址范围(地址统一为16字节格式)
+type countryRange struct {
+	start   net.IP
+	end     net.IP
+	country string
+}
+
+// CountryTable 按IP地址范围查询国家/地区(范围不允许重叠)
+type CountryTable struct {
+	ranges []countryRange
+}
+
+// LoadCountryFile 从CSV文件加载国家/地区数据(格式见ParseCountryCSV)
+func LoadCountryFile(name string) (*CountryTable, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseCountryCSV(f)
+}
+
+// ParseCountryCSV 解析CSV格式的国家/地区数据
+// 每行为"CIDR,国家代码"或"起始IP,结束IP,国家代码"(兼容db-ip的免费国家数据库)，#开头的行为注释
+func ParseCountryCSV(r io.Reader) (*CountryTable, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var ranges []countryRange
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		var item countryRange
+		switch len(record) {
+		case 2:
+			ipnet, err := ParseCIDR(record[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			item.start, item.end = rangeOf(ipnet)
+		case 3:
+			item.start = net.ParseIP(strings.TrimSpace(record[0])).To16()
+			item.end = net.ParseIP(strings.TrimSpace(record[1])).To16()
+			if item.start == nil || item.end == nil || bytes.Compare(item.start, item.end) > 0 {
+				return nil, fmt.Errorf("line %d: invalid ip range %q - %q", line, record[0], record[1])
+			}
+		default:
+			return nil, fmt.Errorf("line %d: expected 2 or 3 fields, got %d", line, len(record))
+		}
+
+		item.country = strings.ToUpper(strings.TrimSpace(record[len(record)-1]))
+		if len(item.country) != 2 {
+			return nil, fmt.Errorf("line %d: invalid country code %q", line, record[len(record)-1])
+		}
+		ranges = append(ranges, item)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return bytes.Compare(ranges[i].start, ranges[j].start) < 0
+	})
+	for i := 1; i < len(ranges); i++ {
+		if bytes.Compare(ranges[i].start, ranges[i-1].end) <= 0 {
+			return nil, fmt.Errorf("overlapping ip ranges %s - %s and %s - %s",
+				ranges[i-1].start, ranges[i-1].end, ranges[i].start, ranges[i].end)
+		}
+	}
+	return &CountryTable{ranges: ranges}, nil
+}
+
+// 获取IP地址范围的起始及结束地址
+func rangeOf(ipnet *net.IPNet) (net.IP, net.IP) {
+	start := ipnet.IP.Mask(ipnet.Mask)
+	end := make(net.IP, len(start))
+	for i := range start {
+		end[i] = start[i] | ^ipnet.Mask[i]
+	}
+	return start.To16(), end.To16()
+}
+
+// Country 获取IP地址所属国家/地区的代码(未知时返回空字符串)
+func (a *CountryTable) Country(ip net.IP) string {
+	ip = ip.To16()
+	if ip == nil {
+		return ""
+	}
+
+	// 查找起始地址不大于ip的最后一个范围
+	i := sort.Search(len(a.ranges), func(i int) bool {
+		return bytes.Compare(a.ranges[i].start, ip) > 0
+	}) - 1
+	if i < 0 || bytes.Compare(ip, a.ranges[i].end) > 0 {
+		return ""
+	}
+	return a.ranges[i].country
+}
+
+// Len 获取IP地址范围的数量
+func (a *CountryTable) Len() int {
+	return len(a.ranges)
+}
@@ -0,0 +1,109 @@
+package ipfilter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// List IP地址范围列表
+type List []*net.IPNet
+
+// ParseCIDRs 解析IP地址范围列表(支持CIDR格式及单个IP地址)
+func ParseCIDRs(values []string) (List, error) {
+	list := make(List, 0, len(values))
+	for _, v := range values {
+		ipnet, err := ParseCIDR(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, ipnet)
+	}
+	return list, nil
+}
+
+// ParseCIDR 解析IP地址范围(单个IP地址转换为仅包含该地址的范围)
+func ParseCIDR(value string) (*net.IPNet, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		_, ipnet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q", value)
+		}
+		return ipnet, nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip address %q", value)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Contains 检查IP地址是否在范围列表内
+func (a List) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range a {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsString 检查IP地址(字符串格式)是否在范围列表内
+func (a List) ContainsString(ip string) bool {
+	return a.Contains(net.ParseIP(ip))
+}
+
+// ClientIP 获取客户端IP地址
+// 仅当直接连接的对端为可信代理时使用X-Forwarded-For请求头，从右向左跳过可信代理，
+// 取第一个非可信代理的地址(请求头中更靠左的地址可由客户端伪造，不予采用)；
+// 所有地址均为可信代理时取最左侧的地址
+func ClientIP(r *http.Request, trusted List) string {
+	remote := RemoteIP(r)
+	if len(trusted) == 0 || !trusted.ContainsString(remote) {
+		return remote
+	}
+
+	var hops []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		for _, v := range strings.Split(h, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				hops = append(hops, v)
+			}
+		}
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(hops[i])
+		if ip == nil {
+			// 无法解析的地址之前的内容不可信
+			break
+		}
+		client = ip.String()
+		if !trusted.Contains(ip) {
+			break
+		}
+	}
+	return client
+}
+
+// RemoteIP 获取直接连接的对端IP地址
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(r.RemoteAddr)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return host
+}
@@ -0,0 +1,115 @@
+package ipfilter
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseCIDRs(t *testing.T) {
+	list, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ip, expected := range map[string]bool{
+		"10.1.2.3":        true,
+		"11.0.0.1":        false,
+		"192.168.1.10":    true,
+		"192.168.1.11":    false,
+		"2001:db8::1":     true,
+		"2001:db9::1":     false,
+		"::1":             true,
+		"::ffff:10.0.0.1": true,
+		"invalid":         false,
+	} {
+		if list.ContainsString(ip) != expected {
+			t.Errorf("%s: expected %v", ip, expected)
+		}
+	}
+
+	if _, err := ParseCIDRs([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected error for invalid cidr")
+	}
+	if _, err := ParseCIDRs([]string{"10.0.0"}); err == nil {
+		t.Error("expected error for invalid ip")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, _ := ParseCIDRs([]string{"10.0.0.0/8"})
+
+	for _, c := range []struct {
+		remote   string
+		xff      []string
+		trusted  List
+		expected string
+	}{
+		// 未配置可信代理时忽略请求头
+		{"1.1.1.1:1234", []string{"2.2.2.2"}, nil, "1.1.1.1"},
+		// 对端不是可信代理时忽略请求头
+		{"1.1.1.1:1234", []string{"2.2.2.2"}, trusted, "1.1.1.1"},
+		{"10.0.0.1:1234", []string{"2.2.2.2"}, trusted, "2.2.2.2"},
+		{"10.0.0.1:1234", nil, trusted, "10.0.0.1"},
+		// 客户端伪造的地址在最左侧，取第一个非可信代理的地址
+		{"10.0.0.1:1234", []string{"9.9.9.9, 2.2.2.2, 10.0.0.2"}, trusted, "2.2.2.2"},
+		{"10.0.0.1:1234", []string{"9.9.9.9", "2.2.2.2"}, trusted, "2.2.2.2"},
+		{"10.0.0.1:1234", []string{"10.0.0.3, 10.0.0.2"}, trusted, "10.0.0.3"},
+		{"10.0.0.1:1234", []string{"2.2.2.2, garbage, 10.0.0.2"}, trusted, "10.0.0.2"},
+		{"[::1]:1234", nil, trusted, "::1"},
+	} {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = c.remote
+		for _, v := range c.xff {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if ip := ClientIP(r, c.trusted); ip != c.expected {
+			t.Errorf("%s %v: expected %s, got %s", c.remote, c.xff, c.expected, ip)
+		}
+	}
+}
+
+func TestCountryTable(t *testing.T) {
+	table, err := ParseCountryCSV(strings.NewReader(`# cidr,country
+203.0.113.0/24,cn
+198.51.100.0,198.51.100.127,US
+2001:db8::/32,JP
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Len() != 3 {
+		t.Fatalf("expected 3 ranges, got %d", table.Len())
+	}
+	for ip, expected := range map[string]string{
+		"203.0.113.0":         "CN",
+		"203.0.113.255":       "CN",
+		"203.0.114.0":         "",
+		"198.51.100.127":      "US",
+		"198.51.100.128":      "",
+		"::ffff:198.51.100.1": "US",
+		"2001:db8::1":         "JP",
+		"2001:db9::1":         "",
+		"1.1.1.1":             "",
+	} {
+		if country := table.Country(net.ParseIP(ip)); country != expected {
+			t.Errorf("%s: expected %q, got %q", ip, expected, country)
+		}
+	}
+	if table.Country(nil) != "" {
+		t.Error("expected unknown country for nil ip")
+	}
+
+	for _, data := range []string{
+		"203.0.113.0/24,CN\n203.0.113.128/25,US\n",
+		"203.0.113.0/33,CN\n",
+		"198.51.100.9,198.51.100.1,US\n",
+		"203.0.113.0/24,CHN\n",
+		"203.0.113.0/24\n",
+	} {
+		if _, err := ParseCountryCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
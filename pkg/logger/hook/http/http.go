@@ -0,0 +1,327 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 定义日志服务的接口格式
+const (
+	FormatElasticsearch = "elasticsearch"
+	FormatLoki          = "loki"
+)
+
+// Config 配置参数
+type Config struct {
+	URL           string            // 日志服务地址(elasticsearch为_bulk接口，loki为/loki/api/v1/push接口)
+	Format        string            // 接口格式(elasticsearch/loki)
+	Index         string            // elasticsearch索引名称
+	Labels        map[string]string // loki日志流标签(level标签按日志级别自动添加)
+	Username      string            // 基本认证用户名
+	Password      string            // 基本认证密码
+	BatchSize     int               // 每批发送的最大日志数量
+	FlushInterval time.Duration     // 定时发送的间隔
+	MaxBuffer     int               // 缓冲区的最大日志数量(超出后丢弃新的日志)
+	MaxRetries    int               // 发送失败后的最大重试次数
+	RetryWait     time.Duration     // 首次重试的等待时长(每次重试后加倍)
+	Timeout       time.Duration     // 单次请求的超时时长
+	Client        *http.Client      // 自定义http客户端(为空时使用Timeout创建)
+	OnDrop        func(n int)       // 丢弃日志时的回调(缓冲区已满或重试后仍发送失败)
+}
+
+type encodeFunc func(c *Config, entries []*logrus.Entry) (contentType string, body []byte, err error)
+
+// New 创建批量发送日志到http服务的钩子实例
+func New(c *Config) (*Hook, error) {
+	var encode encodeFunc
+	switch c.Format {
+	case FormatElasticsearch:
+		if c.Index == "" {
+			return nil, errors.New("elasticsearch index is required")
+		}
+		encode = encodeElasticsearch
+	case FormatLoki:
+		encode = encodeLoki
+	default:
+		return nil, fmt.Errorf("unknown format: %s", c.Format)
+	}
+
+	cfg := *c
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxBuffer <= 0 {
+		cfg.MaxBuffer = 10000
+	}
+	if cfg.MaxBuffer < cfg.BatchSize {
+		cfg.MaxBuffer = cfg.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.RetryWait <= 0 {
+		cfg.RetryWait = time.Second
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	h := &Hook{
+		c:      &cfg,
+		encode: encode,
+		flush:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	h.wg.Add(1)
+	go h.run()
+	return h, nil
+}
+
+// Hook 批量发送日志到http服务的钩子(兼容elasticsearch bulk及loki push接口)
+type Hook struct {
+	c       *Config
+	encode  encodeFunc
+	lock    sync.Mutex
+	buf     []*logrus.Entry
+	dropped uint64
+	flush   chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+// Exec 将日志写入缓冲区(缓冲区已满时丢弃)
+func (h *Hook) Exec(entry *logrus.Entry) error {
+	h.lock.Lock()
+	if len(h.buf) >= h.c.MaxBuffer {
+		h.lock.Unlock()
+		h.drop(1)
+		return nil
+	}
+	h.buf = append(h.buf, entry)
+	full := len(h.buf) >= h.c.BatchSize
+	h.lock.Unlock()
+
+	if full {
+		select {
+		case h.flush <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Dropped 获取已丢弃的日志数量
+func (h *Hook) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+func (h *Hook) drop(n int) {
+	atomic.AddUint64(&h.dropped, uint64(n))
+	if h.c.OnDrop != nil {
+		h.c.OnDrop(n)
+	}
+}
+
+func (h *Hook) run() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.c.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.sendAll()
+		case <-h.flush:
+			h.sendAll()
+		case <-h.done:
+			h.sendAll()
+			return
+		}
+	}
+}
+
+// 按批次发送缓冲区中的所有日志
+func (h *Hook) sendAll() {
+	h.lock.Lock()
+	entries := h.buf
+	h.buf = nil
+	h.lock.Unlock()
+
+	for len(entries) > 0 {
+		n := h.c.BatchSize
+		if n > len(entries) {
+			n = len(entries)
+		}
+		if err := h.send(entries[:n]); err != nil {
+			h.drop(n)
+			fmt.Fprintf(os.Stderr, "[logrus-hook] http: dropped %d entries: %s\n", n, err.Error())
+		}
+		entries = entries[n:]
+	}
+}
+
+// 发送一批日志(网络错误、429及5xx响应时按指数退避重试)
+func (h *Hook) send(entries []*logrus.Entry) error {
+	contentType, body, err := h.encode(h.c, entries)
+	if err != nil {
+		return err
+	}
+
+	wait := h.c.RetryWait
+	for i := 0; ; i++ {
+		retry, err := h.post(contentType, body)
+		if err == nil || !retry || i >= h.c.MaxRetries {
+			return err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+func (h *Hook) post(contentType string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, h.c.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if h.c.Username != "" || h.c.Password != "" {
+		req.SetBasicAuth(h.c.Username, h.c.Password)
+	}
+
+	resp, err := h.c.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return true, fmt.Errorf("%s: %s", resp.Status, data)
+	} else if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("%s: %s", resp.Status, data)
+	}
+
+	// elasticsearch批量写入时，部分日志写入失败也会响应200(不重试，避免重复写入成功的日志)
+	if h.c.Format == FormatElasticsearch {
+		var result struct {
+			Errors bool `json:"errors"`
+		}
+		if json.Unmarshal(data, &result) == nil && result.Errors {
+			return false, errors.New("elasticsearch bulk request has failed items")
+		}
+	}
+	return false, nil
+}
+
+// Close 发送缓冲区中剩余的日志并关闭钩子
+func (h *Hook) Close() error {
+	h.once.Do(func() {
+		close(h.done)
+	})
+	h.wg.Wait()
+	return nil
+}
+
+// 获取日志内容(包含日志级别、消息及所有字段)
+func entryFields(entry *logrus.Entry) map[string]interface{} {
+	fields := make(map[string]interface{}, len(entry.Data)+2)
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		fields[k] = v
+	}
+	fields["level"] = entry.Level.String()
+	fields["message"] = entry.Message
+	return fields
+}
+
+// elasticsearch bulk接口格式(每条日志为一行操作及一行文档)
+func encodeElasticsearch(c *Config, entries []*logrus.Entry) (string, []byte, error) {
+	action, err := json.Marshal(map[string]interface{}{
+		"index": map[string]string{"_index": c.Index},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		fields := entryFields(entry)
+		fields["@timestamp"] = entry.Time.Format(time.RFC3339Nano)
+		doc, err := json.Marshal(fields)
+		if err != nil {
+			return "", nil, err
+		}
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(doc)
+		buf.WriteByte('\n')
+	}
+	return "application/x-ndjson", buf.Bytes(), nil
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// loki push接口格式(按日志级别分为多个日志流，日志行为JSON内容)
+func encodeLoki(c *Config, entries []*logrus.Entry) (string, []byte, error) {
+	streams := make(map[logrus.Level]*lokiStream)
+	var levels []logrus.Level
+
+	sorted := make([]*logrus.Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	for _, entry := range sorted {
+		s, ok := streams[entry.Level]
+		if !ok {
+			labels := make(map[string]string, len(c.Labels)+1)
+			for k, v := range c.Labels {
+				labels[k] = v
+			}
+			labels["level"] = entry.Level.String()
+			s = &lokiStream{Stream: labels}
+			streams[entry.Level] = s
+			levels = append(levels, entry.Level)
+		}
+
+		line, err := json.Marshal(entryFields(entry))
+		if err != nil {
+			return "", nil, err
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), string(line)})
+	}
+
+	result := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, level := range levels {
+		result.Streams = append(result.Streams, streams[level])
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return "", nil, err
+	}
+	return "application/json", body, nil
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newEntry(level logrus.Level, msg string, fields logrus.Fields) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	entry.Time = time.Now()
+	entry.Level = level
+	entry.Message = msg
+	entry.Data = fields
+	return entry
+}
+
+func TestElasticsearch(t *testing.T) {
+	var lock sync.Mutex
+	var docs []map[string]interface{}
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 第一次请求响应503，验证重试
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("unexpected content type: %s", r.Header.Get("Content-Type"))
+		}
+		if u, p, _ := r.BasicAuth(); u != "elastic" || p != "secret" {
+			t.Errorf("unexpected basic auth: %s:%s", u, p)
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		scanner := bufio.NewScanner(r.Body)
+		for i := 0; scanner.Scan(); i++ {
+			var m map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+			if i%2 == 0 {
+				if index := m["index"].(map[string]interface{})["_index"]; index != "logs" {
+					t.Errorf("unexpected index: %v", index)
+				}
+				continue
+			}
+			docs = append(docs, m)
+		}
+		w.Write([]byte(`{"errors":false}`))
+	}))
+	defer srv.Close()
+
+	h, err := New(&Config{
+		URL:           srv.URL,
+		Format:        FormatElasticsearch,
+		Index:         "logs",
+		Username:      "elastic",
+		Password:      "secret",
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		MaxRetries:    2,
+		RetryWait:     time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Exec(newEntry(logrus.InfoLevel, "foo", logrus.Fields{"user_id": "1"}))
+	h.Exec(newEntry(logrus.ErrorLevel, "bar", logrus.Fields{"error": errors.New("failed")}))
+	h.Exec(newEntry(logrus.WarnLevel, "baz", nil))
+	h.Close()
+
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 documents, got %d", len(docs))
+	}
+	if docs[0]["message"] != "foo" || docs[0]["user_id"] != "1" || docs[0]["@timestamp"] == nil {
+		t.Errorf("unexpected document: %v", docs[0])
+	}
+	if docs[1]["level"] != "error" || docs[1]["error"] != "failed" {
+		t.Errorf("unexpected document: %v", docs[1])
+	}
+	if h.Dropped() != 0 {
+		t.Errorf("expected no dropped entries, got %d", h.Dropped())
+	}
+}
+
+func TestLoki(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	h, err := New(&Config{
+		URL:           srv.URL,
+		Format:        FormatLoki,
+		Labels:        map[string]string{"app": "iris-admin"},
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Exec(newEntry(logrus.InfoLevel, "foo", nil))
+	h.Exec(newEntry(logrus.ErrorLevel, "bar", nil))
+	h.Exec(newEntry(logrus.InfoLevel, "baz", nil))
+	h.Close()
+
+	var result struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Streams) != 2 {
+		t.Fatalf("expected 2 streams, got %s", body)
+	}
+	s := result.Streams[0]
+	if s.Stream["app"] != "iris-admin" || s.Stream["level"] != "info" || len(s.Values) != 2 {
+		t.Errorf("unexpected stream: %s", body)
+	}
+	if !bytes.Contains([]byte(s.Values[1][1]), []byte(`"message":"baz"`)) {
+		t.Errorf("unexpected line: %s", s.Values[1][1])
+	}
+}
+
+func TestDropped(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var dropped int64
+	h, err := New(&Config{
+		URL:           srv.URL,
+		Format:        FormatLoki,
+		BatchSize:     10,
+		MaxBuffer:     10,
+		FlushInterval: time.Hour,
+		MaxRetries:    3,
+		OnDrop: func(n int) {
+			atomic.AddInt64(&dropped, int64(n))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 阻止发送，使缓冲区写满
+	h.lock.Lock()
+	h.buf = make([]*logrus.Entry, 10)
+	for i := range h.buf {
+		h.buf[i] = newEntry(logrus.InfoLevel, "foo", nil)
+	}
+	h.lock.Unlock()
+	h.Exec(newEntry(logrus.InfoLevel, "overflow", nil))
+	if h.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped entry, got %d", h.Dropped())
+	}
+
+	// 4xx响应不重试，整批丢弃
+	h.Close()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+	if h.Dropped() != 11 || atomic.LoadInt64(&dropped) != 11 {
+		t.Errorf("expected 11 dropped entries, got %d/%d", h.Dropped(), dropped)
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !windows && !plan9
+
+package syslog
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var facilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"lpr":      syslog.LOG_LPR,
+	"news":     syslog.LOG_NEWS,
+	"uucp":     syslog.LOG_UUCP,
+	"cron":     syslog.LOG_CRON,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"ftp":      syslog.LOG_FTP,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+// Config 配置参数
+type Config struct {
+	Network  string // 网络类型(udp/tcp，为空时连接本机的syslog服务)
+	Addr     string // syslog服务地址
+	Tag      string // 日志标识
+	Facility string // 日志设施(如：user/daemon/local0)
+}
+
+// New 创建基于syslog的钩子实例
+func New(c *Config) (*Hook, error) {
+	facility, ok := facilities[strings.ToLower(c.Facility)]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility: %s", c.Facility)
+	}
+
+	w, err := syslog.Dial(c.Network, c.Addr, facility|syslog.LOG_INFO, c.Tag)
+	if err != nil {
+		return nil, err
+	}
+	return &Hook{
+		w: w,
+	}, nil
+}
+
+// Hook syslog日志钩子
+type Hook struct {
+	w *syslog.Writer
+}
+
+// Exec 执行日志写入(按日志级别对应syslog的严重程度)
+func (h *Hook) Exec(entry *logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\n")
+
+	switch entry.Level {
+	case logrus.PanicLevel:
+		return h.w.Emerg(line)
+	case logrus.FatalLevel:
+		return h.w.Crit(line)
+	case logrus.ErrorLevel:
+		return h.w.Err(line)
+	case logrus.WarnLevel:
+		return h.w.Warning(line)
+	case logrus.InfoLevel:
+		return h.w.Info(line)
+	default:
+		return h.w.Debug(line)
+	}
+}
+
+// Close 关闭钩子
+func (h *Hook) Close() error {
+	return h.w.Close()
+}
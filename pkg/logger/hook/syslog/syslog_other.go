@@ -0,0 +1,35 @@
+//go:build windows || plan9
+
+package syslog
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Config 配置参数
+type Config struct {
+	Network  string // 网络类型(udp/tcp，为空时连接本机的syslog服务)
+	Addr     string // syslog服务地址
+	Tag      string // 日志标识
+	Facility string // 日志设施(如：user/daemon/local0)
+}
+
+// New 当前平台不支持syslog
+func New(c *Config) (*Hook, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+// Hook syslog日志钩子
+type Hook struct{}
+
+// Exec 执行日志写入
+func (h *Hook) Exec(entry *logrus.Entry) error {
+	return nil
+}
+
+// Close 关闭钩子
+func (h *Hook) Close() error {
+	return nil
+}
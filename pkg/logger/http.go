@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactHeader 获取隐藏敏感信息后的请求头副本(names为请求头名称，不区分大小写)
+func RedactHeader(h http.Header, names []string) http.Header {
+	result := make(http.Header, len(h))
+	for k, v := range h {
+		result[k] = v
+		for _, name := range names {
+			if strings.EqualFold(k, name) {
+				result[k] = []string{redactedValue}
+				break
+			}
+		}
+	}
+	return result
+}
+
+// RedactJSON 隐藏JSON内容中的敏感字段
+// fields为字段名(匹配任意层级的字段)或以"."连接的字段路径(从根节点开始匹配，忽略数组)，不区分大小写
+func RedactJSON(body []byte, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		return body, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if !redactValue(v, "", fields) {
+		return body, nil
+	}
+	return json.Marshal(v)
+}
+
+// 隐藏匹配的字段，返回是否存在匹配的字段
+func redactValue(v interface{}, path string, fields []string) bool {
+	var redacted bool
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, item := range vv {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if matchField(k, p, fields) {
+				vv[k] = redactedValue
+				redacted = true
+				continue
+			}
+			if redactValue(item, p, fields) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, item := range vv {
+			if redactValue(item, path, fields) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+func matchField(key, path string, fields []string) bool {
+	for _, f := range fields {
+		if strings.Contains(f, ".") {
+			if strings.EqualFold(f, path) {
+				return true
+			}
+		} else if strings.EqualFold(f, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Truncate 截断超过最大长度(单位字节)的内容，max<=0时不截断
+func Truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+
+	n := max
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes)", s[:n], len(s))
+}
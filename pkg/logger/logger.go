@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // 定义键名
@@ -169,11 +170,18 @@ func StartSpan(ctx context.Context, opts ...SpanOption) *Entry {
 		opt(&o)
 	}
 
+	traceID, spanID := FromTraceIDContext(ctx), FromSpanIDContext(ctx)
+
+	// 启用链路追踪时使用当前跟踪单元的跟踪ID及跟踪单元ID
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		traceID, spanID = sc.TraceID().String(), sc.SpanID().String()
+	}
+
 	fields := map[string]interface{}{
 		StartedAtKey:    time.Now(),
 		UserIDKey:       FromUserIDContext(ctx),
-		TraceIDKey:      FromTraceIDContext(ctx),
-		SpanIDKey:       FromSpanIDContext(ctx),
+		TraceIDKey:      traceID,
+		SpanIDKey:       spanID,
 		SpanTitleKey:    o.Title,
 		SpanFunctionKey: o.FuncName,
 		VersionKey:      version,
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 隐藏敏感信息后的值
+const redactedValue = "******"
+
+var secrets atomic.Value
+
+func init() {
+	// 最先执行的钩子，使后续的钩子(如写入数据库)及日志输出都不包含敏感信息
+	logrus.AddHook(redactHook{})
+}
+
+// SetSecrets 设定需要在日志中隐藏的敏感信息(如密码、签名密钥)
+func SetSecrets(values ...string) {
+	var list []string
+	for _, v := range values {
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	secrets.Store(list)
+}
+
+// Redact 隐藏字符串中的敏感信息
+func Redact(s string) string {
+	list, _ := secrets.Load().([]string)
+	for _, v := range list {
+		if strings.Contains(s, v) {
+			s = strings.Replace(s, v, redactedValue, -1)
+		}
+	}
+	return s
+}
+
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	if list, _ := secrets.Load().([]string); len(list) == 0 {
+		return nil
+	}
+
+	entry.Message = Redact(entry.Message)
+	for k, v := range entry.Data {
+		switch vv := v.(type) {
+		case string:
+			entry.Data[k] = Redact(vv)
+		case error:
+			if s := Redact(vv.Error()); s != vv.Error() {
+				entry.Data[k] = s
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	prev := logrus.StandardLogger().Out
+	SetOutput(&buf)
+	defer SetOutput(prev)
+	SetSecrets("s3cr3t", "")
+	defer SetSecrets()
+
+	StartSpan(context.Background()).
+		WithField("dsn", "root:s3cr3t@tcp(127.0.0.1:3306)/db").
+		WithField("error", errors.New("connect s3cr3t failed")).
+		Errorf("open %s failed", "root:s3cr3t@tcp")
+
+	out := buf.String()
+	if strings.Contains(out, "s3cr3t") || !strings.Contains(out, redactedValue) {
+		t.Fatalf("secret not redacted: %s", out)
+	}
+}
+
+func TestRedactHTTP(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer token")
+	h.Set("Content-Type", "application/json")
+	rh := RedactHeader(h, []string{"authorization"})
+	if rh.Get("Authorization") != redactedValue || rh.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected header %v", rh)
+	}
+	if h.Get("Authorization") != "Bearer token" {
+		t.Fatal("RedactHeader should not modify the original header")
+	}
+
+	body := []byte(`{"user_name":"root","password":"x","list":[{"Password":"y","id":1}],"user":{"token":"z"},"token":"keep"}`)
+	out, err := RedactJSON(body, []string{"password", "user.token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	for _, leaked := range []string{`"x"`, `"y"`, `"z"`} {
+		if strings.Contains(s, leaked) {
+			t.Fatalf("field not redacted: %s", s)
+		}
+	}
+	if !strings.Contains(s, `"keep"`) || !strings.Contains(s, `"id":1`) {
+		t.Fatalf("unexpected redacted body %s", s)
+	}
+
+	if _, err := RedactJSON([]byte(`{"password":`), []string{"password"}); err == nil {
+		t.Fatal("expected error for invalid json")
+	}
+
+	if v := Truncate("中文内容", 4); v != "中...(truncated, 12 bytes)" {
+		t.Fatalf("unexpected truncated value %q", v)
+	}
+	if v := Truncate("abc", 0); v != "abc" {
+		t.Fatalf("unexpected value %q", v)
+	}
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 定义按时间切割日志文件的周期
+const (
+	RotateHourly = "hourly"
+	RotateDaily  = "daily"
+)
+
+// RotateConfig 日志文件切割配置
+type RotateConfig struct {
+	Filename   string // 日志文件路径
+	MaxSize    int    // 单个日志文件的最大大小(单位MB，超出后切割)
+	MaxBackups int    // 保留的历史日志文件数量(0表示不限制)
+	MaxAge     int    // 历史日志文件的保留天数(0表示不限制)
+	Compress   bool   // 是否使用gzip压缩历史日志文件
+	LocalTime  bool   // 历史日志文件名是否使用本地时间(默认使用UTC时间)
+	RotateTime string // 按时间切割的周期(hourly/daily，为空时只按大小切割)
+}
+
+// NewRotateFile 创建按大小及时间切割的日志文件
+func NewRotateFile(c *RotateConfig) *RotateFile {
+	f := &RotateFile{
+		Logger: &lumberjack.Logger{
+			Filename:   c.Filename,
+			MaxSize:    c.MaxSize,
+			MaxBackups: c.MaxBackups,
+			MaxAge:     c.MaxAge,
+			Compress:   c.Compress,
+			LocalTime:  c.LocalTime,
+		},
+		done: make(chan struct{}),
+	}
+
+	var period time.Duration
+	switch c.RotateTime {
+	case RotateHourly:
+		period = time.Hour
+	case RotateDaily:
+		period = 24 * time.Hour
+	}
+	if period > 0 {
+		f.wg.Add(1)
+		go f.rotateEvery(period, c.LocalTime)
+	}
+	return f
+}
+
+// RotateFile 按大小及时间切割的日志文件(实现io.WriteCloser)
+type RotateFile struct {
+	*lumberjack.Logger
+	done chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// 在每个周期的整点切割日志文件
+func (f *RotateFile) rotateEvery(period time.Duration, localTime bool) {
+	defer f.wg.Done()
+
+	for {
+		now := time.Now()
+		if !localTime {
+			now = now.UTC()
+		}
+		timer := time.NewTimer(nextRotateTime(now, period).Sub(now))
+
+		select {
+		case <-f.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			f.Rotate()
+		}
+	}
+}
+
+// 获取下一次切割的时间(按天切割时为次日零点)
+func nextRotateTime(now time.Time, period time.Duration) time.Time {
+	if period >= 24*time.Hour {
+		y, m, d := now.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	}
+	return now.Truncate(period).Add(period)
+}
+
+// Close 停止按时间切割并关闭日志文件
+func (f *RotateFile) Close() error {
+	f.once.Do(func() {
+		close(f.done)
+	})
+	f.wg.Wait()
+	return f.Logger.Close()
+}
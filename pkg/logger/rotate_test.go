@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNextRotateTime(t *testing.T) {
+	now := time.Date(2019, 8, 31, 23, 15, 30, 0, time.UTC)
+
+	if next := nextRotateTime(now, time.Hour); !next.Equal(time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected hourly rotate time: %s", next)
+	}
+	if next := nextRotateTime(now.Add(-time.Hour), 24*time.Hour); !next.Equal(time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected daily rotate time: %s", next)
+	}
+}
+
+func TestRotateFile(t *testing.T) {
+	dir := t.TempDir()
+	f := NewRotateFile(&RotateConfig{
+		Filename:   filepath.Join(dir, "app.log"),
+		MaxSize:    1,
+		RotateTime: RotateDaily,
+	})
+
+	if _, err := f.Write([]byte("foo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("bar\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 log files, got %d", len(files))
+	}
+}
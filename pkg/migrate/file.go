@@ -0,0 +1,68 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// 迁移文件名(版本号_名称.up.sql或版本号_名称.down.sql)
+var fileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load 从目录中加载迁移定义(按版本号从小到大排列)
+// 每个版本由升级脚本"版本号_名称.up.sql"及回滚脚本"版本号_名称.down.sql"组成，回滚脚本可以省略；
+// 不符合命名规则的文件会被忽略
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	for _, entry := range entries {
+		m := fileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的迁移文件[%s]：%s", entry.Name(), err.Error())
+		}
+
+		item, ok := items[version]
+		if !ok {
+			item = &Migration{Version: version, Name: m[2]}
+			items[version] = item
+		} else if item.Name != m[2] {
+			return nil, fmt.Errorf("迁移版本[%d]的文件名称不一致：%s/%s", version, item.Name, m[2])
+		}
+
+		buf, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if m[3] == "up" {
+			item.Up = string(buf)
+			hasUp[version] = true
+		} else {
+			item.Down = string(buf)
+		}
+	}
+
+	result := make([]*Migration, 0, len(items))
+	for version, item := range items {
+		if !hasUp[version] {
+			return nil, fmt.Errorf("迁移版本[%d]缺少升级脚本", version)
+		}
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
@@ -0,0 +1,406 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 定义错误
+var (
+	ErrLockTimeout = errors.New("获取迁移锁超时(如果确认没有其他进程正在执行迁移，请清理迁移锁)")
+)
+
+// Migration 迁移定义
+type Migration struct {
+	Version int64  // 版本号(按版本号从小到大顺序执行)
+	Name    string // 迁移名称
+	Up      string // 升级脚本(多条语句以英文分号结尾分隔)
+	Down    string // 回滚脚本(多条语句以英文分号结尾分隔)
+}
+
+// Status 迁移状态
+type Status struct {
+	Version   int64      // 版本号
+	Name      string     // 迁移名称
+	Applied   bool       // 是否已执行
+	AppliedAt *time.Time // 执行时间
+	Missing   bool       // 已执行但迁移定义不存在
+}
+
+type options struct {
+	table       string
+	lockTimeout time.Duration
+}
+
+var defaultOptions = options{
+	table:       "schema_migrations",
+	lockTimeout: time.Minute,
+}
+
+// Option 定义配置项
+type Option func(*options)
+
+// SetTable 设定记录迁移版本的表名
+func SetTable(table string) Option {
+	return func(o *options) {
+		o.table = table
+	}
+}
+
+// SetLockTimeout 设定获取迁移锁的超时时间
+func SetLockTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.lockTimeout = timeout
+	}
+}
+
+// New 创建迁移实例(dialect支持：mysql/postgres/sqlite3)
+func New(db *sql.DB, dialect string, migrations []*Migration, opts ...Option) (*Migrator, error) {
+	o := defaultOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	list := make([]*Migration, len(migrations))
+	copy(list, migrations)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+
+	for i, item := range list {
+		if item.Version <= 0 {
+			return nil, fmt.Errorf("无效的迁移版本号：%d", item.Version)
+		} else if i > 0 && list[i-1].Version == item.Version {
+			return nil, fmt.Errorf("重复的迁移版本号：%d", item.Version)
+		}
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		opts:       o,
+		migrations: list,
+	}, nil
+}
+
+// Migrator 数据库版本迁移
+// 已执行的版本记录在迁移表中，执行迁移前会获取迁移锁(mysql使用GET_LOCK，postgres使用advisory lock，
+// 其他数据库使用锁表)，防止多个进程同时执行迁移
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	opts       options
+	migrations []*Migration
+}
+
+// Up 执行未执行的迁移(steps小于等于0时执行全部)，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context, steps int) ([]*Migration, error) {
+	var result []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range m.migrations {
+			if steps > 0 && len(result) >= steps {
+				break
+			} else if _, ok := applied[item.Version]; ok {
+				continue
+			}
+
+			err := m.exec(ctx, conn, item, item.Up, true)
+			if err != nil {
+				return err
+			}
+			result = append(result, item)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Down 回滚已执行的迁移(按版本号从大到小，steps小于等于0时回滚全部)，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	var result []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			item := m.migrations[i]
+			if steps > 0 && len(result) >= steps {
+				break
+			} else if _, ok := applied[item.Version]; !ok {
+				continue
+			}
+
+			err := m.exec(ctx, conn, item, item.Down, false)
+			if err != nil {
+				return err
+			}
+			result = append(result, item)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Status 查询迁移状态(按版本号从小到大排列)
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = m.createTable(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Status
+	for _, item := range m.migrations {
+		status := &Status{
+			Version: item.Version,
+			Name:    item.Name,
+		}
+		if s, ok := applied[item.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = s.AppliedAt
+			delete(applied, item.Version)
+		}
+		result = append(result, status)
+	}
+
+	for _, s := range applied {
+		s.Missing = true
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// Pending 查询未执行的迁移
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]bool)
+	for _, s := range status {
+		applied[s.Version] = s.Applied
+	}
+
+	var result []*Migration
+	for _, item := range m.migrations {
+		if !applied[item.Version] {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// 执行迁移脚本并记录版本(up为true时写入版本记录，否则删除版本记录)
+// 注意：mysql的DDL语句会隐式提交事务，迁移脚本执行失败时可能需要手动处理已执行的语句
+func (m *Migrator) exec(ctx context.Context, conn *sql.Conn, item *Migration, script string, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range SplitStatements(script) {
+		_, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("执行迁移[%d_%s]发生错误：%s", item.Version, item.Name, err.Error())
+		}
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx,
+			m.rebind(fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (?, ?, ?)", m.opts.table)),
+			item.Version, item.Name, time.Now())
+	} else {
+		_, err = tx.ExecContext(ctx,
+			m.rebind(fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.opts.table)),
+			item.Version)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)",
+		m.opts.table))
+	return err
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]*Status, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, name, applied_at FROM %s", m.opts.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]*Status)
+	for rows.Next() {
+		var (
+			s         Status
+			appliedAt time.Time
+		)
+		err := rows.Scan(&s.Version, &s.Name, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		s.Applied = true
+		s.AppliedAt = &appliedAt
+		result[s.Version] = &s
+	}
+	return result, rows.Err()
+}
+
+// 在获取迁移锁的连接上执行
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = m.createTable(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn(conn)
+}
+
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	name := m.opts.table + "_lock"
+	deadline := time.Now().Add(m.opts.lockTimeout)
+
+	switch m.dialect {
+	case "mysql":
+		var ok sql.NullInt64
+		err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, int(m.opts.lockTimeout.Seconds())).Scan(&ok)
+		if err != nil {
+			return nil, err
+		} else if ok.Int64 != 1 {
+			return nil, ErrLockTimeout
+		}
+		return func() {
+			conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", name)
+		}, nil
+	case "postgres":
+		key := int64(crc32.ChecksumIEEE([]byte(name)))
+		for {
+			var ok bool
+			err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				break
+			} else if time.Now().After(deadline) {
+				return nil, ErrLockTimeout
+			}
+			time.Sleep(time.Second)
+		}
+		return func() {
+			conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		}, nil
+	}
+
+	// 不支持会话锁的数据库使用锁表(主键冲突表示锁已被占用)
+	_, err := conn.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (id INTEGER NOT NULL PRIMARY KEY, locked_at TIMESTAMP NOT NULL)", name))
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		_, err := conn.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (id, locked_at) VALUES (1, ?)", name), time.Now())
+		if err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			return nil, ErrLockTimeout
+		}
+		time.Sleep(time.Second)
+	}
+	return func() {
+		conn.ExecContext(context.Background(), fmt.Sprintf("DELETE FROM %s WHERE id = 1", name))
+	}, nil
+}
+
+// 转换占位符(postgres使用$n)
+func (m *Migrator) rebind(query string) string {
+	if m.dialect != "postgres" {
+		return query
+	}
+
+	var buf strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			buf.WriteString(fmt.Sprintf("$%d", n))
+			continue
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// SplitStatements 拆分迁移脚本(以行尾的英文分号分隔语句，忽略空行及注释行)
+func SplitStatements(script string) []string {
+	var (
+		result []string
+		lines  []string
+	)
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		lines = append(lines, line)
+		if strings.HasSuffix(trimmed, ";") {
+			stmt := strings.TrimSpace(strings.Join(lines, "\n"))
+			result = append(result, strings.TrimSuffix(stmt, ";"))
+			lines = lines[:0]
+		}
+	}
+
+	if len(lines) > 0 {
+		result = append(result, strings.TrimSpace(strings.Join(lines, "\n")))
+	}
+	return result
+}
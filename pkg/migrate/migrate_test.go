@@ -0,0 +1,133 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrations = []*Migration{
+	{
+		Version: 2,
+		Name:    "add_memo",
+		Up:      "ALTER TABLE t_demo ADD COLUMN memo VARCHAR(200);",
+		Down: `
+-- sqlite不支持删除列，重建数据表
+CREATE TABLE t_demo_bak (id INTEGER PRIMARY KEY, name VARCHAR(50));
+INSERT INTO t_demo_bak SELECT id, name FROM t_demo;
+DROP TABLE t_demo;
+ALTER TABLE t_demo_bak RENAME TO t_demo;`,
+	},
+	{
+		Version: 1,
+		Name:    "init",
+		Up:      "CREATE TABLE t_demo (id INTEGER PRIMARY KEY, name VARCHAR(50));",
+		Down:    "DROP TABLE t_demo;",
+	},
+}
+
+func TestSplitStatements(t *testing.T) {
+	stmts := SplitStatements(`
+-- comment
+CREATE TABLE a (
+	id INTEGER
+);
+
+CREATE INDEX idx_a ON a(id);
+DROP TABLE b`)
+	assert.Equal(t, []string{"CREATE TABLE a (\n\tid INTEGER\n)", "CREATE INDEX idx_a ON a(id)", "DROP TABLE b"}, stmts)
+}
+
+func TestLoad(t *testing.T) {
+	items, err := Load(fstest.MapFS{
+		"0002_add_name.up.sql":  {Data: []byte("ALTER TABLE t ADD name text;")},
+		"0001_init.up.sql":      {Data: []byte("CREATE TABLE t (id int);")},
+		"0001_init.down.sql":    {Data: []byte("DROP TABLE t;")},
+		"README.md":             {Data: []byte("ignored")},
+		"0003_other.sql":        {Data: []byte("ignored")},
+		"0004_ignored.up.sql/a": {Data: []byte("ignored")},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	if !assert.Len(t, items, 2) {
+		return
+	}
+	assert.Equal(t, &Migration{Version: 1, Name: "init", Up: "CREATE TABLE t (id int);", Down: "DROP TABLE t;"}, items[0])
+	assert.Equal(t, &Migration{Version: 2, Name: "add_name", Up: "ALTER TABLE t ADD name text;"}, items[1])
+
+	_, err = Load(fstest.MapFS{
+		"0001_init.up.sql":    {Data: []byte("")},
+		"0001_other.down.sql": {Data: []byte("")},
+	})
+	assert.NotNil(t, err)
+
+	_, err = Load(fstest.MapFS{
+		"0001_init.down.sql": {Data: []byte("")},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestMigrator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "migrate.db"))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	m, err := New(db, "sqlite3", testMigrations)
+	assert.Nil(t, err)
+
+	result, err := m.Up(ctx, 1)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.EqualValues(t, 1, result[0].Version)
+
+	pending, err := m.Pending(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+
+	result, err = m.Up(ctx, 0)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.EqualValues(t, 2, result[0].Version)
+
+	_, err = db.Exec("INSERT INTO t_demo (id, name, memo) VALUES (1, 'foo', 'bar')")
+	assert.Nil(t, err)
+
+	status, err := m.Status(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, status, 2)
+	assert.True(t, status[0].Applied && status[1].Applied)
+
+	result, err = m.Down(ctx, 1)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.EqualValues(t, 2, result[0].Version)
+
+	var name string
+	err = db.QueryRow("SELECT name FROM t_demo WHERE id = 1").Scan(&name)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", name)
+
+	result, err = m.Down(ctx, 0)
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+
+	status, err = m.Status(ctx)
+	assert.Nil(t, err)
+	assert.False(t, status[0].Applied || status[1].Applied)
+
+	_, err = New(db, "sqlite3", append(testMigrations, &Migration{Version: 1}))
+	assert.NotNil(t, err)
+}
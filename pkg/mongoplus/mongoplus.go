@@ -0,0 +1,146 @@
+package mongoplus
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Config 配置参数
+type Config struct {
+	URI      string        // 连接串
+	Database string        // 数据库名称
+	Timeout  time.Duration // 连接超时时间(默认10秒)
+}
+
+// New 创建DB实例(连接后检查数据库是否支持事务)
+func New(c *Config) (*DB, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// 时间使用本地时区解码，与gorm存储的查询结果保持一致
+	opts := options.Client().
+		ApplyURI(c.URI).
+		SetConnectTimeout(timeout).
+		SetServerSelectionTimeout(timeout).
+		SetBSONOptions(&options.BSONOptions{UseLocalTimeZone: true})
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db := Wrap(client.Database(c.Database))
+	db.transaction, err = supportsTransaction(ctx, db.Database)
+	if err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return db, nil
+}
+
+// 检查数据库是否支持事务(仅副本集及分片集群支持事务，单节点部署不支持)
+func supportsTransaction(ctx context.Context, db *mongo.Database) (bool, error) {
+	var result struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := db.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result)
+	if err != nil {
+		return false, err
+	}
+	return result.SetName != "" || result.Msg == "isdbgrid", nil
+}
+
+// Wrap 包装mongo数据库(默认不使用事务)
+func Wrap(db *mongo.Database) *DB {
+	return &DB{Database: db}
+}
+
+// DB mongo扩展DB
+type DB struct {
+	*mongo.Database
+	transaction bool
+}
+
+// SetTransaction 设定是否使用事务
+func (d *DB) SetTransaction(transaction bool) {
+	d.transaction = transaction
+}
+
+// Transaction 是否使用事务(不支持事务时会话中的操作直接执行)
+func (d *DB) Transaction() bool {
+	return d.transaction
+}
+
+// Close 关闭数据库连接
+func (d *DB) Close() error {
+	return d.Client().Disconnect(context.Background())
+}
+
+// FindPage 查询分页数据
+// pageSize或pageIndex小于0时仅查询总数据条数
+func (d *DB) FindPage(ctx context.Context, coll *mongo.Collection, filter interface{}, sort interface{}, pageIndex, pageSize int, out interface{}) (int, error) {
+	count, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	} else if count == 0 {
+		return 0, nil
+	}
+
+	// 如果分页大小小于0或者分页索引小于0，则不查询数据
+	if pageSize < 0 || pageIndex < 0 {
+		return int(count), nil
+	}
+
+	opts := options.Find().SetSort(sort)
+	if pageIndex > 0 {
+		opts.SetSkip(int64((pageIndex - 1) * pageSize))
+	}
+	if pageSize > 0 {
+		opts.SetLimit(int64(pageSize))
+	}
+
+	err = d.Find(ctx, coll, filter, opts, out)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// Find 查询数据列表
+func (d *DB) Find(ctx context.Context, coll *mongo.Collection, filter interface{}, opts *options.FindOptions, out interface{}) error {
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	return cur.All(ctx, out)
+}
+
+// FindOne 查询单条数据
+func (d *DB) FindOne(ctx context.Context, coll *mongo.Collection, filter interface{}, out interface{}) (bool, error) {
+	err := coll.FindOne(ctx, filter).Decode(out)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// EstimateCount 估算集合的总数据条数(忽略查询条件，从集合的元数据中读取)
+func (d *DB) EstimateCount(ctx context.Context, coll *mongo.Collection) (int, error) {
+	count, err := coll.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// 清理过期限流数据的间隔
+const sweepInterval = time.Minute
+
+// NewMemoryStore 创建基于内存的限流存储
+// 内存存储仅在当前进程内有效，多实例部署时各实例分别限流，请使用redis存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items: make(map[string]*memoryItem),
+		now:   time.Now,
+	}
+}
+
+// MemoryStore 内存限流存储
+type MemoryStore struct {
+	lock    sync.Mutex
+	items   map[string]*memoryItem
+	sweepAt time.Time
+	now     func() time.Time
+}
+
+type memoryItem struct {
+	tat      int64 // 令牌桶额度完全恢复的理论时间
+	window   int64 // 滑动窗口的当前窗口序号
+	cur      int64 // 当前窗口的请求数量
+	prev     int64 // 前一窗口的请求数量
+	expireAt time.Time
+}
+
+// Allow 按限流规则消耗一次请求额度
+func (a *MemoryStore) Allow(key string, limit Limit) (*Result, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	t := a.now()
+	a.sweep(t)
+
+	item, ok := a.items[key]
+	if !ok {
+		item = new(memoryItem)
+		a.items[key] = item
+	}
+
+	now := t.UnixNano() / int64(time.Microsecond)
+	if limit.Algorithm == TokenBucket {
+		tat, result := tokenBucket(item.tat, now, limit)
+		item.tat = tat
+		item.expireAt = t.Add(result.ResetAfter)
+		return result, nil
+	}
+
+	period := int64(limit.Period / time.Microsecond)
+	window := now / period
+	if item.window != window {
+		if item.window == window-1 {
+			item.prev = item.cur
+		} else {
+			item.prev = 0
+		}
+		item.cur = 0
+		item.window = window
+	}
+
+	result := slidingWindow(item.prev, item.cur, now-window*period, limit)
+	if result.Allowed {
+		item.cur++
+	}
+	item.expireAt = t.Add(result.ResetAfter + limit.Period)
+	return result, nil
+}
+
+// 定时清理过期的限流数据
+func (a *MemoryStore) sweep(now time.Time) {
+	if now.Before(a.sweepAt) {
+		return
+	}
+	a.sweepAt = now.Add(sweepInterval)
+
+	for key, item := range a.items {
+		if now.After(item.expireAt) {
+			delete(a.items, key)
+		}
+	}
+}
+
+// Close 关闭存储
+func (a *MemoryStore) Close() error {
+	return nil
+}
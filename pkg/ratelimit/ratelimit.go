@@ -0,0 +1,227 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// 定义限流算法
+const (
+	TokenBucket   = "token_bucket"   // 令牌桶(允许突发请求，额度按固定速率恢复)
+	SlidingWindow = "sliding_window" // 滑动窗口(按前一窗口的请求数量加权估算当前窗口的请求数量)
+)
+
+// 定义错误
+var (
+	// ErrInvalidLimit 无效的限流规则
+	ErrInvalidLimit = errors.New("invalid rate limit")
+	// ErrStoreUnavailable 存储不可用(严格模式的限流规则在重试间隔内返回)
+	ErrStoreUnavailable = errors.New("rate limit store unavailable")
+)
+
+// Limit 限流规则
+type Limit struct {
+	Algorithm string        // 限流算法(token_bucket/sliding_window)
+	Rate      int64         // 每个周期允许的请求数量
+	Period    time.Duration // 周期
+	Burst     int64         // 令牌桶容量(为0时与Rate相同，滑动窗口算法不使用)
+	Strict    bool          // 严格模式(存储发生错误时不使用后备存储，返回错误)
+}
+
+func (l Limit) burst() int64 {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// Result 限流结果
+type Result struct {
+	Allowed    bool          // 是否允许请求
+	Limit      int64         // 允许的请求数量(令牌桶为容量)
+	Remaining  int64         // 剩余的请求数量
+	RetryAfter time.Duration // 请求被拒绝时，距离允许下一次请求的时长
+	ResetAfter time.Duration // 距离额度恢复的时长
+}
+
+// Store 限流存储接口
+type Store interface {
+	// 按限流规则消耗一次请求额度
+	Allow(key string, limit Limit) (*Result, error)
+	// 关闭存储
+	Close() error
+}
+
+// 支持附加上下文的限流存储(如redis存储，上下文用于链路追踪)
+type contextStore interface {
+	WithContext(ctx context.Context) Store
+}
+
+// 存储发生错误后直接使用后备存储的默认时长
+const defaultRetryInterval = 5 * time.Second
+
+type options struct {
+	fallback      Store
+	retryInterval time.Duration
+	errorHandler  func(error)
+}
+
+// Option 定义配置项
+type Option func(*options)
+
+// SetFallback 设定后备存储(存储发生错误时使用，如redis不可用时使用内存存储，各实例分别限流)
+func SetFallback(store Store) Option {
+	return func(o *options) {
+		o.fallback = store
+	}
+}
+
+// SetRetryInterval 设定存储发生错误后直接使用后备存储的时长(期间不再访问存储，到期后重新尝试，默认5秒)
+func SetRetryInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.retryInterval = interval
+	}
+}
+
+// SetErrorHandler 设定存储的错误处理(存储从正常变为异常时调用一次，恢复后再次发生错误时重新调用)
+func SetErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.errorHandler = fn
+	}
+}
+
+// New 创建限流实例
+func New(store Store, opts ...Option) *Limiter {
+	o := options{
+		retryInterval: defaultRetryInterval,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Limiter{
+		store: store,
+		opts:  o,
+		now:   time.Now,
+	}
+}
+
+// Limiter 请求频率限制
+type Limiter struct {
+	store   Store
+	opts    options
+	failing int32
+	retryAt int64 // 重新尝试存储的时间(单位纳秒，存储发生错误后在此之前直接使用后备存储)
+	now     func() time.Time
+}
+
+// Allow 按限流规则消耗一次请求额度(不同算法的限流数据分别存储)
+// 存储发生错误时使用后备存储(在重试间隔内不再访问存储)，没有后备存储或严格模式时返回错误
+func (a *Limiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if limit.Rate <= 0 || limit.Period <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	switch limit.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return nil, ErrInvalidLimit
+	}
+	key = limit.Algorithm + ":" + key
+
+	if a.opts.fallback != nil && a.now().UnixNano() < atomic.LoadInt64(&a.retryAt) {
+		if limit.Strict {
+			return nil, ErrStoreUnavailable
+		}
+		return a.opts.fallback.Allow(key, limit)
+	}
+
+	result, err := a.getStore(ctx).Allow(key, limit)
+	if err == nil {
+		atomic.StoreInt32(&a.failing, 0)
+		return result, nil
+	}
+
+	if atomic.CompareAndSwapInt32(&a.failing, 0, 1) && a.opts.errorHandler != nil {
+		a.opts.errorHandler(err)
+	}
+	if a.opts.fallback != nil {
+		atomic.StoreInt64(&a.retryAt, a.now().Add(a.opts.retryInterval).UnixNano())
+		if !limit.Strict {
+			return a.opts.fallback.Allow(key, limit)
+		}
+	}
+	return nil, err
+}
+
+// Close 关闭限流存储
+func (a *Limiter) Close() error {
+	if a.opts.fallback != nil {
+		a.opts.fallback.Close()
+	}
+	return a.store.Close()
+}
+
+func (a *Limiter) getStore(ctx context.Context) Store {
+	if s, ok := a.store.(contextStore); ok && ctx != nil {
+		return s.WithContext(ctx)
+	}
+	return a.store
+}
+
+// 令牌桶算法(GCRA)，tat为额度完全恢复的理论时间，时间单位为微秒
+// 返回新的tat(请求被拒绝时不变)
+func tokenBucket(tat, now int64, limit Limit) (int64, *Result) {
+	interval := int64(limit.Period/time.Microsecond) / limit.Rate
+	if interval <= 0 {
+		interval = 1
+	}
+	burst := limit.burst()
+
+	if tat < now {
+		tat = now
+	}
+	newTAT := tat + interval
+	allowAt := newTAT - interval*burst
+	if diff := now - allowAt; diff < 0 {
+		return tat, &Result{
+			Allowed:    false,
+			Limit:      burst,
+			RetryAfter: time.Duration(-diff) * time.Microsecond,
+			ResetAfter: time.Duration(tat-now) * time.Microsecond,
+		}
+	}
+
+	return newTAT, &Result{
+		Allowed:    true,
+		Limit:      burst,
+		Remaining:  (now - allowAt) / interval,
+		ResetAfter: time.Duration(newTAT-now) * time.Microsecond,
+	}
+}
+
+// 滑动窗口算法，prev及cur为前一窗口及当前窗口的请求数量，elapsed为当前窗口已经过的时长，时间单位为微秒
+func slidingWindow(prev, cur, elapsed int64, limit Limit) *Result {
+	period := int64(limit.Period / time.Microsecond)
+	count := float64(prev)*float64(period-elapsed)/float64(period) + float64(cur)
+
+	result := &Result{
+		Limit:      limit.Rate,
+		ResetAfter: time.Duration(period-elapsed) * time.Microsecond,
+	}
+	if count+1 > float64(limit.Rate) {
+		// 当前窗口已达上限时等待下一窗口，否则等待前一窗口的权重降低到允许一次请求
+		retry := period - elapsed
+		if cur+1 <= limit.Rate && prev > 0 {
+			retry = int64(float64(period)*(1-float64(limit.Rate-cur-1)/float64(prev))) - elapsed + 1
+		}
+		result.RetryAfter = time.Duration(retry) * time.Microsecond
+		return result
+	}
+
+	result.Allowed = true
+	result.Remaining = int64(float64(limit.Rate) - count - 1)
+	return result
+}
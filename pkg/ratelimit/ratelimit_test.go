@@ -0,0 +1,222 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wanhello/iris-admin/pkg/redisplus"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limiter := New(store)
+
+	limit := Limit{Algorithm: TokenBucket, Rate: 10, Period: time.Second, Burst: 3}
+	for i := int64(0); i < 3; i++ {
+		r, err := limiter.Allow(context.Background(), "foo", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.Allowed || r.Limit != 3 || r.Remaining != 2-i {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+	}
+
+	r, _ := limiter.Allow(context.Background(), "foo", limit)
+	if r.Allowed || r.RetryAfter != 100*time.Millisecond || r.ResetAfter != 300*time.Millisecond {
+		t.Fatalf("expected rejection, got %+v", r)
+	}
+
+	// 额度按速率恢复
+	now = now.Add(100 * time.Millisecond)
+	if r, _ := limiter.Allow(context.Background(), "foo", limit); !r.Allowed || r.Remaining != 0 {
+		t.Fatalf("expected allowed after refill, got %+v", r)
+	}
+	if r, _ := limiter.Allow(context.Background(), "bar", limit); !r.Allowed || r.Remaining != 2 {
+		t.Fatalf("expected separate bucket per key, got %+v", r)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	now := time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limiter := New(store)
+
+	limit := Limit{Algorithm: SlidingWindow, Rate: 4, Period: time.Minute}
+	for i := 0; i < 4; i++ {
+		if r, _ := limiter.Allow(context.Background(), "foo", limit); !r.Allowed {
+			t.Fatalf("expected allowed %d, got %+v", i, r)
+		}
+	}
+	r, _ := limiter.Allow(context.Background(), "foo", limit)
+	if r.Allowed || r.Remaining != 0 || r.RetryAfter != time.Minute {
+		t.Fatalf("expected rejection, got %+v", r)
+	}
+
+	// 下一窗口的前一半时间内，前一窗口的请求数量按剩余比例计入
+	now = now.Add(90 * time.Second)
+	r, _ = limiter.Allow(context.Background(), "foo", limit)
+	if !r.Allowed || r.Remaining != 1 {
+		t.Fatalf("expected weighted count, got %+v", r)
+	}
+	limiter.Allow(context.Background(), "foo", limit)
+	r, _ = limiter.Allow(context.Background(), "foo", limit)
+	if r.Allowed || r.RetryAfter != 15*time.Second+time.Microsecond {
+		t.Fatalf("expected rejection until previous window decays, got %+v", r)
+	}
+}
+
+type errorStore struct{}
+
+func (errorStore) Allow(key string, limit Limit) (*Result, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func (errorStore) Close() error {
+	return nil
+}
+
+func TestFallback(t *testing.T) {
+	limit := Limit{Algorithm: TokenBucket, Rate: 1, Period: time.Minute}
+	if _, err := New(errorStore{}).Allow(context.Background(), "foo", limit); err == nil {
+		t.Fatal("expected store error without fallback")
+	}
+
+	var errs int
+	limiter := New(errorStore{},
+		SetFallback(NewMemoryStore()),
+		SetErrorHandler(func(err error) { errs++ }),
+	)
+	for i := 0; i < 3; i++ {
+		r, err := limiter.Allow(context.Background(), "foo", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Allowed != (i == 0) {
+			t.Fatalf("unexpected fallback result %d: %+v", i, r)
+		}
+	}
+	if errs != 1 {
+		t.Fatalf("expected error handler to be called once, got %d", errs)
+	}
+}
+
+type flakyStore struct {
+	calls int
+	err   error
+}
+
+func (a *flakyStore) Allow(key string, limit Limit) (*Result, error) {
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &Result{Allowed: true, Limit: limit.Rate}, nil
+}
+
+func (a *flakyStore) Close() error {
+	return nil
+}
+
+// 存储发生错误后在重试间隔内直接使用后备存储
+func TestFallbackRetryInterval(t *testing.T) {
+	now := time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC)
+	store := &flakyStore{err: errors.New("store unavailable")}
+	limiter := New(store,
+		SetFallback(NewMemoryStore()),
+		SetRetryInterval(10*time.Second),
+	)
+	limiter.now = func() time.Time { return now }
+
+	limit := Limit{Algorithm: TokenBucket, Rate: 100, Period: time.Minute}
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Allow(context.Background(), "foo", limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.calls != 1 {
+		t.Fatalf("expected store to be skipped during retry interval, got %d calls", store.calls)
+	}
+
+	// 到期后重新尝试，仍然失败时重新计算重试间隔
+	now = now.Add(10 * time.Second)
+	limiter.Allow(context.Background(), "foo", limit)
+	limiter.Allow(context.Background(), "foo", limit)
+	if store.calls != 2 {
+		t.Fatalf("expected one retry after interval, got %d calls", store.calls)
+	}
+
+	// 存储恢复后不再使用后备存储
+	store.err = nil
+	now = now.Add(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.Allow(context.Background(), "foo", limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.calls != 4 {
+		t.Fatalf("expected store to be used after recovery, got %d calls", store.calls)
+	}
+}
+
+// 严格模式的限流规则不使用后备存储
+func TestStrictLimit(t *testing.T) {
+	store := &flakyStore{err: errors.New("store unavailable")}
+	limiter := New(store, SetFallback(NewMemoryStore()))
+
+	strict := Limit{Algorithm: TokenBucket, Rate: 100, Period: time.Minute, Strict: true}
+	if _, err := limiter.Allow(context.Background(), "foo", strict); err != store.err {
+		t.Fatalf("expected store error, got %v", err)
+	}
+	// 重试间隔内不再访问存储
+	if _, err := limiter.Allow(context.Background(), "foo", strict); err != ErrStoreUnavailable {
+		t.Fatalf("expected store unavailable, got %v", err)
+	}
+	if store.calls != 1 {
+		t.Fatalf("expected store to be skipped during retry interval, got %d calls", store.calls)
+	}
+
+	// 其他限流规则仍然使用后备存储
+	limit := Limit{Algorithm: TokenBucket, Rate: 100, Period: time.Minute}
+	if _, err := limiter.Allow(context.Background(), "foo", limit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewRedisStore(redisplus.New(&redisplus.Config{Addr: mr.Addr(), KeyPrefix: "rl:"}))
+	limiter := New(store)
+	defer limiter.Close()
+
+	for _, algorithm := range []string{TokenBucket, SlidingWindow} {
+		limit := Limit{Algorithm: algorithm, Rate: 2, Period: time.Minute}
+		for i := int64(0); i < 2; i++ {
+			r, err := limiter.Allow(context.Background(), "foo", limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.Allowed || r.Limit != 2 || r.Remaining != 1-i {
+				t.Fatalf("%s: unexpected result %d: %+v", algorithm, i, r)
+			}
+		}
+
+		r, err := limiter.Allow(context.Background(), "foo", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Allowed || r.RetryAfter <= 0 || r.RetryAfter > time.Minute {
+			t.Fatalf("%s: expected rejection, got %+v", algorithm, r)
+		}
+		if !mr.Exists("rl:" + algorithm + ":foo") {
+			t.Fatalf("%s: expected key in redis", algorithm)
+		}
+	}
+}
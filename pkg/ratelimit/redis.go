@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wanhello/iris-admin/pkg/redisplus"
+
+	"github.com/go-redis/redis"
+)
+
+// 令牌桶算法(GCRA)，使用redis服务器时间，时间单位为微秒
+var tokenBucketScript = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call("GET", key))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - interval * burst
+local diff = now - allow_at
+if diff < 0 then
+	return {0, 0, -diff, tat - now}
+end
+
+redis.call("SET", key, string.format("%d", new_tat), "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor(diff / interval), 0, new_tat - now}
+`)
+
+// 滑动窗口算法(当前窗口序号及前后两个窗口的请求数量存储在同一个hash中)，时间单位为微秒
+var slidingWindowScript = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = math.floor(now / period)
+local elapsed = now - window * period
+
+local data = redis.call("HMGET", key, "w", "c", "p")
+local cur = tonumber(data[2]) or 0
+local prev = tonumber(data[3]) or 0
+local w = tonumber(data[1])
+if w ~= window then
+	if w == window - 1 then
+		prev = cur
+	else
+		prev = 0
+	end
+	cur = 0
+end
+
+local count = prev * (period - elapsed) / period + cur
+if count + 1 > rate then
+	local retry = period - elapsed
+	if cur + 1 <= rate and prev > 0 then
+		retry = math.floor(period * (1 - (rate - cur - 1) / prev)) - elapsed + 1
+	end
+	return {0, 0, retry, period - elapsed}
+end
+
+redis.call("HMSET", key, "w", string.format("%d", window), "c", cur + 1, "p", prev)
+redis.call("PEXPIRE", key, math.ceil((period * 2 - elapsed) / 1000))
+return {1, math.floor(rate - count - 1), 0, period - elapsed}
+`)
+
+// NewRedisStore 创建基于redis的限流存储
+// 限流数据存储在redis中并使用redis服务器时间，多实例部署时共享请求额度
+func NewRedisStore(cli *redisplus.Client) *RedisStore {
+	return &RedisStore{
+		cli: cli,
+	}
+}
+
+// RedisStore redis限流存储
+type RedisStore struct {
+	cli *redisplus.Client
+}
+
+// WithContext 附加上下文(redis命令使用该上下文执行，供钩子函数获取，如链路追踪)
+func (a *RedisStore) WithContext(ctx context.Context) Store {
+	return &RedisStore{
+		cli: a.cli.WithContext(ctx),
+	}
+}
+
+// Allow 按限流规则消耗一次请求额度
+func (a *RedisStore) Allow(key string, limit Limit) (*Result, error) {
+	var (
+		v   interface{}
+		err error
+	)
+
+	keys := []string{a.cli.Key(key)}
+	period := int64(limit.Period / time.Microsecond)
+	resultLimit := limit.Rate
+	if limit.Algorithm == TokenBucket {
+		interval := period / limit.Rate
+		if interval <= 0 {
+			interval = 1
+		}
+		resultLimit = limit.burst()
+		v, err = tokenBucketScript.Run(a.cli, keys, interval, resultLimit).Result()
+	} else {
+		v, err = slidingWindowScript.Run(a.cli, keys, limit.Rate, period).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := v.([]interface{})
+	if !ok || len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", v)
+	}
+	ints := make([]int64, len(values))
+	for i, item := range values {
+		n, ok := item.(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected rate limit script result: %v", v)
+		}
+		ints[i] = n
+	}
+
+	return &Result{
+		Allowed:    ints[0] == 1,
+		Limit:      resultLimit,
+		Remaining:  ints[1],
+		RetryAfter: time.Duration(ints[2]) * time.Microsecond,
+		ResetAfter: time.Duration(ints[3]) * time.Microsecond,
+	}, nil
+}
+
+// Close 关闭存储(redis客户端可能被多个存储共用，由创建方关闭)
+func (a *RedisStore) Close() error {
+	return nil
+}
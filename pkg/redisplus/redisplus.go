@@ -0,0 +1,55 @@
+package redisplus
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+)
+
+// Config 配置参数
+type Config struct {
+	Addr      string
+	DB        int
+	Password  string
+	KeyPrefix string // 键名前缀
+}
+
+// New 创建redis客户端
+func New(c *Config) *Client {
+	cli := redis.NewClient(&redis.Options{
+		Addr:     c.Addr,
+		DB:       c.DB,
+		Password: c.Password,
+	})
+	return &Client{
+		Client: cli,
+		prefix: c.KeyPrefix,
+	}
+}
+
+// Client redis扩展客户端(键名统一附加前缀)
+type Client struct {
+	*redis.Client
+	prefix string
+}
+
+// WithContext 附加上下文(redis命令使用该上下文执行，供钩子函数获取，如链路追踪)
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{
+		Client: c.Client.WithContext(ctx),
+		prefix: c.prefix,
+	}
+}
+
+// WithPrefix 使用其他键名前缀(共用连接，如多个模块共用一个redis客户端)
+func (c *Client) WithPrefix(prefix string) *Client {
+	return &Client{
+		Client: c.Client,
+		prefix: prefix,
+	}
+}
+
+// Key 获取附加前缀的键名
+func (c *Client) Key(key string) string {
+	return c.prefix + key
+}
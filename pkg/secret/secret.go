@@ -0,0 +1,149 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// 定义引用前缀
+const (
+	FilePrefix      = "file://" // 从文件读取(例如：file:///run/secrets/db_password)
+	EnvPrefix       = "env:"    // 从环境变量读取(例如：env:DB_PASSWORD)
+	EncryptedPrefix = "enc:"    // 使用主密钥加密的值(由Seal生成)
+)
+
+// KeySize 主密钥长度(AES-256)
+const KeySize = 32
+
+// 定义错误
+var (
+	ErrNoKey      = errors.New("secret: master key not configured")
+	ErrInvalidKey = errors.New("secret: invalid master key")
+	ErrDecrypt    = errors.New("secret: decryption failed")
+)
+
+// IsRef 是否为敏感信息引用
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, FilePrefix) ||
+		strings.HasPrefix(value, EnvPrefix) ||
+		strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// NewResolver 创建敏感信息引用解析(key为主密钥，未使用加密值时可以为nil)
+func NewResolver(key []byte) *Resolver {
+	return &Resolver{key: key}
+}
+
+// Resolver 敏感信息引用解析
+type Resolver struct {
+	key []byte
+}
+
+// Resolve 解析引用的值(不是引用时返回原值)
+func (a *Resolver) Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, FilePrefix):
+		buf, err := ioutil.ReadFile(strings.TrimPrefix(value, FilePrefix))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(buf), "\r\n"), nil
+	case strings.HasPrefix(value, EnvPrefix):
+		name := strings.TrimPrefix(value, EnvPrefix)
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret: environment variable %s not set", name)
+		}
+		return v, nil
+	case strings.HasPrefix(value, EncryptedPrefix):
+		if a.key == nil {
+			return "", ErrNoKey
+		}
+		buf, err := Open(a.key, value)
+		if err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+	return value, nil
+}
+
+// GenerateKey 生成随机的主密钥
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EncodeKey 编码主密钥(十六进制，用于写入密钥文件)
+func EncodeKey(key []byte) string {
+	return hex.EncodeToString(key)
+}
+
+// ReadKeyFile 读取主密钥文件
+func ReadKeyFile(name string) ([]byte, error) {
+	buf, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(buf)))
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Seal 使用主密钥加密(AES-GCM)，返回带有enc:前缀的值
+func Seal(key, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	buf := gcm.Seal(nonce, nonce, plaintext, nil)
+	return EncryptedPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Open 使用主密钥解密Seal生成的值
+func Open(key []byte, sealed string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(sealed, EncryptedPrefix))
+	if err != nil || len(buf) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := gcm.Open(nil, buf[:gcm.NonceSize()], buf[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
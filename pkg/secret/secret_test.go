@@ -0,0 +1,60 @@
+package secret
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolver(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "master.key")
+	if err := ioutil.WriteFile(keyFile, []byte(EncodeKey(key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	key, err = ReadKeyFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := Seal(key, []byte("db-password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pwFile := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(pwFile, []byte("file-password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRET_TEST_PASSWORD", "env-password")
+
+	r := NewResolver(key)
+	for value, expected := range map[string]string{
+		"plain":                    "plain",
+		FilePrefix + pwFile:        "file-password",
+		"env:SECRET_TEST_PASSWORD": "env-password",
+		sealed:                     "db-password",
+	} {
+		v, err := r.Resolve(value)
+		if err != nil || v != expected {
+			t.Fatalf("Resolve(%q) = %q, %v; expected %q", value, v, err, expected)
+		}
+	}
+
+	if _, err := r.Resolve("env:SECRET_TEST_NOT_SET"); err == nil {
+		t.Fatal("expected error for unset environment variable")
+	}
+	if _, err := NewResolver(nil).Resolve(sealed); err != ErrNoKey {
+		t.Fatalf("expected ErrNoKey, got %v", err)
+	}
+
+	other, _ := GenerateKey()
+	if _, err := Open(other, sealed); err != ErrDecrypt {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// MustRandomToken 创建随机令牌，如果发生错误则抛出panic
+func MustRandomToken(size int) string {
+	v, err := NewRandomToken(size)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// NewRandomToken 创建随机令牌(size为随机字节数，返回十六进制字符串)
+func NewRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,2 +0,0 @@
--- Create a database
-CREATE DATABASE `iris_admin` DEFAULT CHARACTER SET = `utf8mb4`;
\ No newline at end of file
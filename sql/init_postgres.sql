@@ -1,3 +0,0 @@
--- Create a database
-create database iris_admin with encoding = 'UTF8' LC_CTYPE = 'en_US.UTF-8' LC_COLLATE = 'en_US.UTF-8' template = template1;
-